@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+)
+
+// accessEntryService lists and describes EKS access entries; implemented by aws.EKSService
+type accessEntryService interface {
+	ListJITAccessEntries(ctx context.Context, clusterName string) ([]string, error)
+	DescribeAccessEntry(ctx context.Context, clusterName, principalArn string) (*aws.AccessEntry, error)
+}
+
+// newAccessEntryService creates the EKS client used by the access subcommands
+var newAccessEntryService = func(region string) (accessEntryService, error) {
+	return aws.NewEKSService(region)
+}
+
+var accessCmd = &cobra.Command{
+	Use:   "access",
+	Short: "Inspect the EKS access entries created for JIT access",
+	Long:  `Inspect the EKS access entries created for JIT access without the AWS console.`,
+}
+
+var accessListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the JIT access entries on a cluster",
+	RunE:  runAccessList,
+}
+
+var accessDescribeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Describe one access entry on a cluster",
+	RunE:  runAccessDescribe,
+}
+
+func init() {
+	rootCmd.AddCommand(accessCmd)
+	accessCmd.AddCommand(accessListCmd, accessDescribeCmd)
+
+	accessCmd.PersistentFlags().String("aws-region", "us-east-1", "AWS region of the cluster")
+	accessDescribeCmd.Flags().String("principal", "", "Principal ARN of the access entry")
+
+	for _, c := range []*cobra.Command{accessListCmd, accessDescribeCmd} {
+		c.Flags().String("cluster", "", "EKS cluster name")
+		if err := c.MarkFlagRequired("cluster"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marking cluster flag required: %v\n", err)
+		}
+	}
+	if err := accessDescribeCmd.MarkFlagRequired("principal"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking principal flag required: %v\n", err)
+	}
+}
+
+func accessService(cmd *cobra.Command) (accessEntryService, string, error) {
+	cluster, _ := cmd.Flags().GetString("cluster")
+	region, _ := cmd.Flags().GetString("aws-region")
+
+	svc, err := newAccessEntryService(region)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create EKS client: %w", err)
+	}
+	return svc, cluster, nil
+}
+
+func runAccessList(cmd *cobra.Command, _ []string) error {
+	svc, cluster, err := accessService(cmd)
+	if err != nil {
+		return err
+	}
+
+	principals, err := svc.ListJITAccessEntries(cmd.Context(), cluster)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(principals) == 0 {
+		fmt.Fprintf(out, "No JIT access entries on %s\n", cluster)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PRINCIPAL\tUSERNAME\tGROUPS\tCREATED")
+	for _, principal := range principals {
+		entry, describeErr := svc.DescribeAccessEntry(cmd.Context(), cluster, principal)
+		if describeErr != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\n", principal)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", principal, orDash(entry.Username),
+			orDash(strings.Join(entry.Groups, ",")), formatEntryTime(entry.CreatedAt))
+	}
+	return w.Flush()
+}
+
+func runAccessDescribe(cmd *cobra.Command, _ []string) error {
+	svc, cluster, err := accessService(cmd)
+	if err != nil {
+		return err
+	}
+	principal, _ := cmd.Flags().GetString("principal")
+
+	entry, err := svc.DescribeAccessEntry(cmd.Context(), cluster, principal)
+	if err != nil {
+		return err
+	}
+
+	return writeAccessEntry(cmd.OutOrStdout(), entry)
+}
+
+// writeAccessEntry prints one access entry as a two-column table, tags sorted by key
+func writeAccessEntry(out io.Writer, entry *aws.AccessEntry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Cluster:\t%s\n", entry.ClusterName)
+	fmt.Fprintf(w, "Principal:\t%s\n", entry.PrincipalArn)
+	fmt.Fprintf(w, "Username:\t%s\n", orDash(entry.Username))
+	fmt.Fprintf(w, "Groups:\t%s\n", orDash(strings.Join(entry.Groups, ",")))
+	fmt.Fprintf(w, "Created:\t%s\n", formatEntryTime(entry.CreatedAt))
+	fmt.Fprintf(w, "Modified:\t%s\n", formatEntryTime(entry.ModifiedAt))
+
+	keys := make([]string, 0, len(entry.Tags))
+	for key := range entry.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintln(w, "Tags:\t")
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s\t%s\n", key, entry.Tags[key])
+	}
+	return w.Flush()
+}
+
+func formatEntryTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
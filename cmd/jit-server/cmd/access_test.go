@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+)
+
+type fakeAccessEntryService struct {
+	region  string
+	entries map[string]*aws.AccessEntry
+}
+
+func (f *fakeAccessEntryService) ListJITAccessEntries(_ context.Context, clusterName string) ([]string, error) {
+	var principals []string
+	for principal, entry := range f.entries {
+		if entry.ClusterName == clusterName {
+			principals = append(principals, principal)
+		}
+	}
+	return principals, nil
+}
+
+func (f *fakeAccessEntryService) DescribeAccessEntry(
+	_ context.Context, clusterName, principalArn string,
+) (*aws.AccessEntry, error) {
+	entry, ok := f.entries[principalArn]
+	if !ok || entry.ClusterName != clusterName {
+		return nil, fmt.Errorf("access entry %s not found", principalArn)
+	}
+	return entry, nil
+}
+
+const testPrincipal = "arn:aws:sts::123456789012:assumed-role/JITAccessRole/jit-u123-prod-east-1"
+
+func runAccessCommand(t *testing.T, args ...string) (string, *fakeAccessEntryService, error) {
+	t.Helper()
+
+	svc := &fakeAccessEntryService{entries: map[string]*aws.AccessEntry{
+		testPrincipal: {
+			ClusterName:  "prod-east-1",
+			PrincipalArn: testPrincipal,
+			Username:     "jit:alice@company.com",
+			Groups:       []string{"jit-edit"},
+			CreatedAt:    time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC),
+			Tags:         map[string]string{"Purpose": "JITAccess", "RequestName": "jit-u123-1718020800"},
+		},
+	}}
+	original := newAccessEntryService
+	newAccessEntryService = func(region string) (accessEntryService, error) {
+		svc.region = region
+		return svc, nil
+	}
+	t.Cleanup(func() { newAccessEntryService = original })
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	err := rootCmd.Execute()
+	return out.String(), svc, err
+}
+
+func TestAccessListCommand(t *testing.T) {
+	out, svc, err := runAccessCommand(t, "access", "list", "--cluster", "prod-east-1", "--aws-region", "us-west-2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if svc.region != "us-west-2" {
+		t.Errorf("Expected the EKS client for us-west-2, got %q", svc.region)
+	}
+	for _, want := range []string{
+		"PRINCIPAL", testPrincipal, "jit:alice@company.com", "jit-edit", "2024-06-10T12:00:00Z",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestAccessListCommandNoEntries(t *testing.T) {
+	out, _, err := runAccessCommand(t, "access", "list", "--cluster", "dev-west-2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out, "No JIT access entries on dev-west-2") {
+		t.Errorf("Expected an empty listing, got:\n%s", out)
+	}
+}
+
+func TestAccessDescribeCommand(t *testing.T) {
+	out, _, err := runAccessCommand(t, "access", "describe", "--cluster", "prod-east-1", "--principal", testPrincipal)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Principal:", testPrincipal,
+		"jit:alice@company.com",
+		"Purpose", "JITAccess",
+		"RequestName", "jit-u123-1718020800",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestAccessDescribeCommandUnknownPrincipal(t *testing.T) {
+	_, _, err := runAccessCommand(t, "access", "describe",
+		"--cluster", "prod-east-1", "--principal", "arn:aws:iam::123456789012:role/Unknown")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
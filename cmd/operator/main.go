@@ -4,6 +4,8 @@ import (
 	"context"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -14,9 +16,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/aws"
 	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/eventhook"
 	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+	"github.com/rebelopsio/jit-bot/pkg/models"
 	"github.com/rebelopsio/jit-bot/pkg/monitoring"
+	"github.com/rebelopsio/jit-bot/pkg/slack"
 	"github.com/rebelopsio/jit-bot/pkg/telemetry"
 	webhookpkg "github.com/rebelopsio/jit-bot/pkg/webhook"
 )
@@ -41,6 +47,51 @@ func main() {
 	var enableTracing bool
 	var tracingExporter string
 	var tracingEndpoint string
+	var tracingSampleRates string
+	var maxReasonLengths string
+	var accessSchedulesFile string
+	var metricsUserLabel string
+	var maxActiveSessions int
+	var maxProvisioningAttempts int
+	var webhookMaxBodyBytes int64
+	var slackNotifierConfigFile string
+	var approvalDelegationsFile string
+	var clusterGroupsFile string
+	var killSwitchNamespace string
+	var eventWebhookURL string
+	var stepDownScheduleFile string
+	var autoApprovalRulesFile string
+	var denyRulesFile string
+	var ticketPoliciesFile string
+	var requireProdSlackChannel bool
+	var requireVerifiedEmail bool
+	var namespaceApproversFile string
+	var namespacePrefixesFile string
+	var sensitiveNamespaces string
+	var defaultProductionApprovers string
+	var requireNamespacesEnvironments string
+	var clusterRegistryFile string
+	var permissionPoliciesFile string
+	var allowedRegions string
+	var propagatedMetadataKeys string
+	var skipMutationServiceAccounts string
+	var requesterServiceAccounts string
+	var maxSessionPolicySize int
+	var trustedOperators string
+	var checkClusters bool
+	var userHoldsFile string
+	var recordGrantSummary bool
+	var canonicalDurations bool
+	var durationApprovalTiers bool
+	var allowSecretLikeReasons bool
+	var riskWeightsFile string
+	var revokeArchivedChannels bool
+	var revalidateActiveSessions bool
+	var revokePolicyViolations bool
+	var minApprovals string
+	var maxScheduleAhead time.Duration
+	var maxDuration time.Duration
+	var webhookCertWait time.Duration
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -49,10 +100,118 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&awsRegion, "aws-region", "", "AWS region for accessing AWS services.")
 	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to.")
+	flag.Int64Var(&webhookMaxBodyBytes, "webhook-max-body-bytes", webhookpkg.DefaultMaxBodyBytes,
+		"Maximum size in bytes of an admission request body.")
 	flag.StringVar(&certDir, "cert-dir", "", "The directory that contains the webhook server certificates.")
+	flag.DurationVar(&webhookCertWait, "webhook-cert-wait", webhookpkg.DefaultCertWait,
+		"How long to wait at startup for the webhook certificates to appear in --cert-dir (0 = don't wait).")
 	flag.BoolVar(&enableTracing, "enable-tracing", false, "Enable OpenTelemetry tracing.")
 	flag.StringVar(&tracingExporter, "tracing-exporter", "jaeger", "Tracing exporter (jaeger, otlp).")
 	flag.StringVar(&tracingEndpoint, "tracing-endpoint", "", "Tracing endpoint URL.")
+	flag.StringVar(&tracingSampleRates, "tracing-sample-rates",
+		telemetry.FormatSampleRates(telemetry.DefaultSampleRates),
+		"Comma-separated environment=rate fractions of traces to sample, e.g. production=0.01,staging=0.05. "+
+			"Other environments sample 0.1.")
+	flag.StringVar(&metricsUserLabel, "metrics-user-label", "raw",
+		"How user IDs appear in metric labels (raw, hashed, dropped).")
+	flag.IntVar(&maxActiveSessions, "max-active-sessions", 0,
+		"Maximum simultaneous active sessions per user, counting sessions granted on their behalf (0 = unlimited).")
+	flag.IntVar(&maxProvisioningAttempts, "max-provisioning-attempts", controller.DefaultMaxProvisioningAttempts,
+		"Failed access job creations after which a request is moved to the Failed phase.")
+	flag.StringVar(&accessSchedulesFile, "access-schedules", "",
+		"Path to a JSON file of per-cluster access schedules (business hours).")
+	flag.StringVar(&denyRulesFile, "deny-rules", "",
+		"Path to a JSON file of permission/duration combinations to reject outright.")
+	flag.StringVar(&ticketPoliciesFile, "ticket-policies", "",
+		"Path to a JSON file of per-environment ticket reference patterns required in request reasons.")
+	flag.BoolVar(&requireProdSlackChannel, "require-prod-slack-channel", false,
+		"Deny production requests that do not record the Slack channel they were made from.")
+	flag.BoolVar(&requireVerifiedEmail, "require-verified-email", false,
+		"Deny elevated permissions to requesters whose email is not marked verified.")
+	flag.StringVar(&namespaceApproversFile, "namespace-approvers", "",
+		"Path to a JSON file mapping namespaces to the approver teams that own them.")
+	flag.StringVar(&namespacePrefixesFile, "namespace-prefixes", "",
+		"Path to a JSON file mapping teams to the namespace prefixes they may request.")
+	flag.StringVar(&defaultProductionApprovers, "default-production-approvers",
+		strings.Join(webhookpkg.DefaultProductionApprovers, ","),
+		"Comma-separated approver teams assigned to production requests that no approver policy matched.")
+	flag.StringVar(&sensitiveNamespaces, "sensitive-namespaces",
+		strings.Join(webhookpkg.DefaultSensitiveNamespaces, ","),
+		"Comma-separated namespaces whose requests always need platform and security approval, even in development.")
+	flag.StringVar(&requireNamespacesEnvironments, "require-namespaces-environments", "",
+		"Comma-separated environments (e.g. production) where elevated requests other than cluster-admin "+
+			"must list namespaces instead of applying cluster-wide.")
+	flag.StringVar(&permissionPoliciesFile, "permission-policies", "",
+		"Path to a JSON file of extra requestable permissions and the EKS access policies they grant.")
+	flag.StringVar(&allowedRegions, "allowed-regions", "",
+		"Comma-separated AWS regions the operator serves; requests for clusters elsewhere are denied. "+
+			"Empty allows all regions.")
+	flag.StringVar(&propagatedMetadataKeys, "propagate-metadata-keys", "",
+		"Comma-separated label and annotation keys copied from access requests onto their jobs and secrets.")
+	flag.StringVar(&skipMutationServiceAccounts, "skip-mutation-service-accounts", "",
+		"Comma-separated service accounts (system:serviceaccount:<namespace>:<name>) allowed to skip "+
+			"request mutation with the jit.rebelops.io/skip-mutation annotation.")
+	flag.StringVar(&requesterServiceAccounts, "requester-service-accounts", "",
+		"Comma-separated service accounts (system:serviceaccount:<namespace>:<name>), such as the Slack bot's, "+
+			"trusted to file requests for the spec.userID they name. "+
+			"Only their requests count for --trusted-operators.")
+	flag.IntVar(&maxSessionPolicySize, "max-session-policy-size", aws.MaxSessionPolicySize,
+		"Largest AssumeRole session policy, in characters, the webhook admits; larger requests are denied. "+
+			"Values above the 2048-character AWS limit use that limit.")
+	flag.StringVar(&trustedOperators, "trusted-operators", "",
+		"Comma-separated Slack user IDs whose own requests are approved without approvers (audited).")
+	flag.StringVar(&clusterRegistryFile, "cluster-registry", "",
+		"Path to a clusters.yaml file used to fill in the AWS account and region of requests that only name a cluster.")
+	flag.StringVar(&userHoldsFile, "user-holds", "",
+		"Path to a JSON list of Slack user IDs whose non-emergency requests are denied.")
+	flag.StringVar(&riskWeightsFile, "risk-weights", "",
+		"Path to a JSON file overriding the weights used to compute each request's risk score.")
+	flag.BoolVar(&recordGrantSummary, "record-grant-summary", true,
+		"Record the access policies, scope and Kubernetes groups granted in each JITAccessJob's status.")
+	flag.BoolVar(&durationApprovalTiers, "duration-approval-tiers", false,
+		"Scale the approval quorum with the requested duration: none under 1h, one under 8h, two beyond. "+
+			"The quorum never falls below the approvers a request's policy lists.")
+	flag.BoolVar(&canonicalDurations, "canonical-durations", true,
+		"Rewrite request durations to a canonical form (e.g. 90m to 1h30m) in the mutating webhook.")
+	flag.BoolVar(&allowSecretLikeReasons, "allow-secret-like-reasons", false,
+		"Admit request reasons that look like they contain AWS keys, JWTs or other high-entropy tokens.")
+	flag.StringVar(&maxReasonLengths, "max-reason-lengths", "",
+		"Comma-separated environment=length limits on request reasons, e.g. production=2000,development=300. "+
+			"Other environments allow 500 characters.")
+	flag.BoolVar(&checkClusters, "check-clusters", false,
+		"Describe each cluster in the cluster registry at startup and mark unreachable ones unhealthy.")
+	flag.DurationVar(&maxDuration, "max-duration", webhookpkg.DefaultMaxDuration,
+		"Longest access duration a request may ask for (requests accept w, d, h, m and s units).")
+	flag.DurationVar(&maxScheduleAhead, "max-schedule-ahead", webhookpkg.DefaultMaxScheduleAhead,
+		"Furthest ahead a request's notBefore start time may be.")
+	flag.BoolVar(&revokeArchivedChannels, "revoke-archived-channels", false,
+		"Revoke active requests whose Slack channel has been archived (requires SLACK_BOT_TOKEN).")
+	flag.BoolVar(&revalidateActiveSessions, "revalidate-active-sessions", false,
+		"Periodically re-check active requests against the current permission and cluster policy and "+
+			"flag those it would now deny.")
+	flag.BoolVar(&revokePolicyViolations, "revoke-policy-violations", false,
+		"Revoke active requests that violate the current policy instead of flagging them "+
+			"(requires --revalidate-active-sessions).")
+	flag.StringVar(&minApprovals, "min-approvals", "production=1",
+		"Comma-separated environment=count approval floors the operator enforces whatever approvers or "+
+			"quorum a request lists, e.g. production=2,staging=1.")
+	flag.StringVar(&slackNotifierConfigFile, "slack-notifier-config", "",
+		"Path to a JSON file mapping approver teams to Slack groups; enables approver notifications "+
+			"(requires SLACK_BOT_TOKEN).")
+	flag.StringVar(&approvalDelegationsFile, "approval-delegations", "",
+		"Path to a JSON list of out-of-office approvers, their delegates and the window the delegation applies.")
+	flag.StringVar(&clusterGroupsFile, "cluster-groups", "",
+		"Path to a JSON file of named cluster groups that one request can target together.")
+	flag.StringVar(&killSwitchNamespace, "kill-switch-namespace", "",
+		"Namespace of the "+controller.DefaultKillSwitchName+" ConfigMap that, while engaged, revokes all JIT access "+
+			"and denies new requests. Empty disables the kill switch.")
+	flag.StringVar(&stepDownScheduleFile, "step-down-schedule", "",
+		"Path to a JSON file of permissions, such as cluster-admin, that are narrowed partway through a session.")
+	flag.StringVar(&autoApprovalRulesFile, "auto-approval-rules", "",
+		"Path to a JSON list of named CEL expressions; requests matching any of them are approved without approvers.")
+	flag.StringVar(&eventWebhookURL, "event-webhook-url", "",
+		"URL that receives a signed POST for every approved, denied, granted, revoked and expired request "+
+			"(requires "+eventhook.SecretEnv+"). Empty disables lifecycle event delivery.")
 
 	opts := zap.Options{
 		Development: true,
@@ -62,36 +221,55 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	sampleRates, err := telemetry.ParseSampleRates(tracingSampleRates)
+	if err != nil {
+		setupLog.Error(err, "invalid --tracing-sample-rates")
+		os.Exit(1)
+	}
+
+	reasonLengths, err := webhookpkg.ParseReasonLengths(maxReasonLengths)
+	if err != nil {
+		setupLog.Error(err, "invalid --max-reason-lengths")
+		os.Exit(1)
+	}
+
+	approvalFloors, err := controller.ParseMinApprovals(minApprovals)
+	if err != nil {
+		setupLog.Error(err, "invalid --min-approvals")
+		os.Exit(1)
+	}
+
 	// Initialize monitoring
 	monitoringConfig := monitoring.Config{
 		MetricsEnabled: true,
 		MetricsPort:    8080,
 		HealthPort:     8081,
+		UserLabel:      metricsUserLabel,
 		Tracing: telemetry.TracingConfig{
 			Enabled:     enableTracing,
 			Exporter:    tracingExporter,
 			Endpoint:    tracingEndpoint,
 			ServiceName: "jit-operator",
 			Environment: getEnvironment(),
-			SampleRate:  getSampleRate(),
+			SampleRates: sampleRates,
 		},
 	}
 
 	monitor := monitoring.NewMonitor(monitoringConfig)
-	ctx := context.Background()
 
-	// Start monitor and set up cleanup, but don't defer if we might exit early
+	// The manager runs on the same context, so monitoring stops when it does
+	ctx := ctrl.SetupSignalHandler()
 	if err := monitor.Start(ctx); err != nil {
 		setupLog.Error(err, "unable to start monitoring")
 		os.Exit(1)
 	}
 
-	// Set up cleanup function for later deferred call
-	cleanup := func() {
-		if err := monitor.Stop(ctx); err != nil {
+	// Setup failures below return rather than exit, so the monitoring servers are closed
+	defer func() {
+		if err := monitor.Stop(context.Background()); err != nil {
 			setupLog.Error(err, "failed to stop monitoring")
 		}
-	}
+	}()
 
 	// Configure webhook server options
 	webhookOpts := ctrl.Options{
@@ -104,15 +282,20 @@ func main() {
 		}),
 	}
 
+	// The kill switch is shared by the request controller and the validating webhook. Its ConfigMap
+	// is the only one the operator caches.
+	var killSwitch *controller.KillSwitch
+	if killSwitchNamespace != "" {
+		killSwitch = &controller.KillSwitch{Namespace: killSwitchNamespace}
+		webhookOpts.Cache.ByObject = killSwitch.CacheByObject()
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), webhookOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		return
 	}
 
-	// Now that basic setup is successful, establish cleanup for monitoring
-	defer cleanup()
-
 	// Initialize AWS services
 	if awsRegion == "" {
 		awsRegion = os.Getenv("AWS_REGION")
@@ -121,20 +304,258 @@ func main() {
 		}
 	}
 
+	// The permission set is shared by the admission webhooks and access entry creation
+	var permissionPolicies aws.PermissionPolicies
+	if permissionPoliciesFile != "" {
+		permissionPolicies, err = aws.LoadPermissionPolicies(permissionPoliciesFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load permission policies")
+			return
+		}
+	}
+
+	// The region allowlist is shared by the validating webhook and the access manager
+	regions := aws.NewAllowedRegions(splitList(allowedRegions))
+
 	accessManager, err := kubernetes.NewAccessManager(awsRegion)
 	if err != nil {
 		setupLog.Error(err, "unable to create access manager")
 		return
 	}
+	if err = accessManager.SetAllowedRegions(regions); err != nil {
+		setupLog.Error(err, "access manager region is not allowed")
+		return
+	}
+	accessManager.SetPermissionPolicies(permissionPolicies)
 
 	// Initialize RBAC
 	rbac := auth.NewRBAC([]string{})
 
+	var approvalDelegations controller.ApprovalDelegations
+	if approvalDelegationsFile != "" {
+		approvalDelegations, err = controller.LoadApprovalDelegations(approvalDelegationsFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load approval delegations")
+			return
+		}
+	}
+
+	var clusterGroups controller.ClusterGroups
+	if clusterGroupsFile != "" {
+		clusterGroups, err = controller.LoadClusterGroups(clusterGroupsFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load cluster groups")
+			return
+		}
+	}
+
+	var stepDowns controller.StepDownSchedule
+	if stepDownScheduleFile != "" {
+		stepDowns, err = controller.LoadStepDownSchedule(stepDownScheduleFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load step-down schedule")
+			return
+		}
+	}
+
+	var autoApprovalRules *controller.AutoApprovalRules
+	if autoApprovalRulesFile != "" {
+		autoApprovalRules, err = controller.LoadAutoApprovalRules(autoApprovalRulesFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load auto-approval rules")
+			return
+		}
+	}
+
+	// Approver notifications are optional
+	var notifier controller.ApprovalNotifier
+	if slackNotifierConfigFile != "" {
+		notifierConfig, loadErr := slack.LoadNotifierConfig(slackNotifierConfigFile)
+		if loadErr != nil {
+			setupLog.Error(loadErr, "unable to load slack notifier config")
+			return
+		}
+		slackNotifier := slack.NewApprovalNotifier(os.Getenv("SLACK_BOT_TOKEN"), *notifierConfig)
+		slackNotifier.EnableDelegations(approvalDelegations)
+		notifier = slackNotifier
+	}
+
+	// Revoking sessions from archived channels is opt-in
+	var archivedChannels controller.ChannelArchiveChecker
+	if revokeArchivedChannels {
+		archivedChannels = slack.NewChannelChecker(os.Getenv("SLACK_BOT_TOKEN"))
+	}
+
+	// Lifecycle events are posted to an outbound webhook only when configured
+	var eventNotifier controller.EventNotifier
+	if eventWebhookURL != "" {
+		eventHook, hookErr := eventhook.NewNotifier(eventhook.Config{
+			URL:    eventWebhookURL,
+			Secret: os.Getenv(eventhook.SecretEnv),
+		})
+		if hookErr != nil {
+			setupLog.Error(hookErr, "unable to create event webhook notifier")
+			return
+		}
+		if err = mgr.Add(eventHook); err != nil {
+			setupLog.Error(err, "unable to add event webhook notifier to manager")
+			return
+		}
+		eventNotifier = eventHook
+	}
+
+	// Setup webhooks
+	var accessSchedules map[string]*models.AccessSchedule
+	if accessSchedulesFile != "" {
+		accessSchedules, err = webhookpkg.LoadAccessSchedules(accessSchedulesFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load access schedules")
+			return
+		}
+	}
+
+	var denyRules []webhookpkg.DenyRule
+	if denyRulesFile != "" {
+		denyRules, err = webhookpkg.LoadDenyRules(denyRulesFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load deny rules")
+			return
+		}
+	}
+
+	var ticketPolicies map[string]*webhookpkg.TicketPolicy
+	if ticketPoliciesFile != "" {
+		ticketPolicies, err = webhookpkg.LoadTicketPolicies(ticketPoliciesFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load ticket policies")
+			return
+		}
+	}
+
+	var namespaceApprovers map[string][]string
+	if namespaceApproversFile != "" {
+		namespaceApprovers, err = webhookpkg.LoadNamespaceApprovers(namespaceApproversFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load namespace approvers")
+			return
+		}
+	}
+
+	var namespacePrefixes map[string][]string
+	if namespacePrefixesFile != "" {
+		namespacePrefixes, err = webhookpkg.LoadNamespacePrefixes(namespacePrefixesFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load namespace prefixes")
+			return
+		}
+	}
+
+	var heldUsers map[string]bool
+	if userHoldsFile != "" {
+		heldUsers, err = webhookpkg.LoadUserHolds(userHoldsFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load user holds")
+			return
+		}
+	}
+
+	var riskWeights *webhookpkg.RiskWeights
+	if riskWeightsFile != "" {
+		riskWeights, err = webhookpkg.LoadRiskWeights(riskWeightsFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load risk weights")
+			return
+		}
+	}
+
+	var clusterRegistry map[string]webhookpkg.RegisteredCluster
+	if clusterRegistryFile != "" {
+		clusterRegistry, err = webhookpkg.LoadClusterRegistry(clusterRegistryFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load cluster registry")
+			return
+		}
+	}
+
+	// Cluster checks only warn, so startup does not wait on them
+	if checkClusters && len(clusterRegistry) > 0 {
+		clusters := make([]*models.Cluster, 0, len(clusterRegistry))
+		for _, cluster := range clusterRegistry {
+			clusters = append(clusters, cluster.Config())
+		}
+		go func() {
+			checkCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+			if unhealthy := accessManager.CheckClusters(checkCtx, clusters); len(unhealthy) > 0 {
+				setupLog.Info("Some configured clusters are unreachable", "count", len(unhealthy))
+			}
+		}()
+	}
+
+	webhookOptions := webhookpkg.Options{
+		Schedules:               accessSchedules,
+		RBAC:                    rbac,
+		MaxActiveSessions:       maxActiveSessions,
+		DenyRules:               denyRules,
+		MaxSessionPolicySize:    maxSessionPolicySize,
+		TicketPolicies:          ticketPolicies,
+		RequireProdSlackChannel: requireProdSlackChannel,
+		RequireVerifiedEmail:    requireVerifiedEmail,
+		MaxBodyBytes:            webhookMaxBodyBytes,
+		NamespaceApprovers:      namespaceApprovers,
+		NamespacePrefixes:       namespacePrefixes,
+		Clusters:                clusterRegistry,
+		ClusterConfigs:          webhookpkg.ClusterConfigs(clusterRegistry),
+		ClusterGroups:           clusterGroups,
+		KillSwitch:              killSwitch,
+		HeldUsers:               heldUsers,
+		RiskWeights:             riskWeights,
+		CanonicalDurations:      canonicalDurations,
+		MaxScheduleAhead:        maxScheduleAhead,
+		MaxDuration:             maxDuration,
+		ReasonValidator: webhookpkg.DefaultReasonValidator{
+			AllowSecretLikeReasons: allowSecretLikeReasons,
+			MaxLengths:             reasonLengths,
+		},
+
+		SkipMutationServiceAccounts:   splitList(skipMutationServiceAccounts),
+		RequesterServiceAccounts:      splitList(requesterServiceAccounts),
+		RequireNamespacesEnvironments: splitList(requireNamespacesEnvironments),
+		SensitiveNamespaces:           splitList(sensitiveNamespaces),
+		DefaultProductionApprovers:    splitList(defaultProductionApprovers),
+		PermissionPolicies:            permissionPolicies,
+		AllowedRegions:                regions,
+	}
+	if durationApprovalTiers {
+		webhookOptions.ApprovalTiers = controller.DefaultApprovalTiers
+	}
+
+	// Active sessions are revalidated against the webhook's policy only when enabled
+	var sessionPolicy controller.SessionPolicyChecker
+	if revalidateActiveSessions {
+		sessionPolicy = webhookpkg.NewSessionPolicy(webhookOptions)
+	}
+
 	// Setup controllers
 	if err = (&controller.JITAccessRequestReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		RBAC:   rbac,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		RBAC:     rbac,
+		Notifier: notifier,
+
+		MaxProvisioningAttempts: maxProvisioningAttempts,
+		PropagatedMetadataKeys:  splitList(propagatedMetadataKeys),
+		TrustedUsers:            splitList(trustedOperators),
+		ArchivedChannels:        archivedChannels,
+		ApprovalDelegations:     approvalDelegations,
+		ClusterGroups:           clusterGroups,
+		KillSwitch:              killSwitch,
+		EventNotifier:           eventNotifier,
+		AutoApprovalRules:       autoApprovalRules,
+		SessionPolicy:           sessionPolicy,
+		RevokePolicyViolations:  revokePolicyViolations,
+		MinApprovals:            approvalFloors,
+		ClusterEnvironments:     webhookpkg.ClusterEnvironments(clusterRegistry),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "JITAccessRequest")
 		return
@@ -144,13 +565,27 @@ func main() {
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
 		AccessManager: accessManager,
+
+		PropagatedMetadataKeys: splitList(propagatedMetadataKeys),
+		RecordGrantSummary:     recordGrantSummary,
+		StepDowns:              stepDowns,
+		ClusterEnvironments:    webhookpkg.ClusterEnvironments(clusterRegistry),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "JITAccessJob")
 		return
 	}
 
-	// Setup webhooks
-	if err = webhookpkg.SetupWebhookWithManager(mgr); err != nil {
+	// cert-manager may not have mounted the serving certificate yet on first start
+	webhookCertDir := webhookpkg.CertDirOrDefault(certDir)
+	if webhookCertWait > 0 {
+		setupLog.Info("waiting for webhook certificates", "certDir", webhookCertDir, "timeout", webhookCertWait)
+		if err = webhookpkg.WaitForCerts(ctx, webhookCertDir, webhookCertWait, 0); err != nil {
+			setupLog.Error(err, "webhook certificates are not available")
+			return
+		}
+	}
+
+	if err = webhookpkg.SetupWebhookWithManager(mgr, webhookOptions); err != nil {
 		setupLog.Error(err, "unable to setup webhooks")
 		return
 	}
@@ -164,14 +599,29 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		return
 	}
+	if err = mgr.AddReadyzCheck("webhook-certs", webhookpkg.CertsChecker(webhookCertDir)); err != nil {
+		setupLog.Error(err, "unable to set up webhook certificate check")
+		return
+	}
 
 	setupLog.Info("starting manager")
-	if err = mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err = mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		return
 	}
 }
 
+// splitList parses a comma-separated flag value, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvironment() string {
 	if env := os.Getenv("ENVIRONMENT"); env != "" {
 		return env
@@ -181,15 +631,3 @@ func getEnvironment() string {
 	}
 	return "development"
 }
-
-func getSampleRate() float64 {
-	env := getEnvironment()
-	switch env {
-	case "production":
-		return 0.01 // 1% sampling in production
-	case "staging":
-		return 0.05 // 5% sampling in staging
-	default:
-		return 0.1 // 10% sampling in development
-	}
-}
@@ -24,8 +24,12 @@ type ServerConfig struct {
 }
 
 type SlackConfig struct {
-	Token         string `mapstructure:"token"`
-	SigningSecret string `mapstructure:"signingSecret"`
+	Token         string            `mapstructure:"token"`
+	SigningSecret string            `mapstructure:"signingSecret"`
+	ResponseTypes map[string]string `mapstructure:"responseTypes"` // subcommand -> ephemeral or in_channel
+
+	// RequestNamespace is where the operator keeps JITAccessRequests; setting it enables /jit creds
+	RequestNamespace string `mapstructure:"requestNamespace"`
 }
 
 type AWSConfig struct {
@@ -33,11 +37,27 @@ type AWSConfig struct {
 	AccountIDs       []string `mapstructure:"accountIds"`
 	SAMLProviderArn  string   `mapstructure:"samlProviderArn"`
 	EKSClusterPrefix string   `mapstructure:"eksClusterPrefix"`
+	AllowedRegions   []string `mapstructure:"allowedRegions"` // empty allows every region
 }
 
 type AccessConfig struct {
-	MaxDuration      time.Duration `mapstructure:"maxDuration"`
-	ApprovalRequired bool          `mapstructure:"approvalRequired"`
+	MaxDuration        time.Duration       `mapstructure:"maxDuration"`
+	ApprovalRequired   bool                `mapstructure:"approvalRequired"`
+	MaxActiveSessions  int                 `mapstructure:"maxActiveSessions"`  // 0 = unlimited
+	NamespaceApprovers map[string][]string `mapstructure:"namespaceApprovers"` // namespace -> owning teams
+
+	// KubeconfigDownloadTTL returns kubeconfigs as one-time download links valid this long (0 = inline)
+	KubeconfigDownloadTTL time.Duration `mapstructure:"kubeconfigDownloadTTL"`
+	// KubeconfigDownloadNamespace is where kubeconfigs awaiting download are held as Secrets
+	KubeconfigDownloadNamespace string `mapstructure:"kubeconfigDownloadNamespace"`
+
+	// RevokeConfirmationWindow requires a second admin to confirm, within this window, an admin's
+	// revocation of another user's access (0 = revoke immediately)
+	RevokeConfirmationWindow time.Duration `mapstructure:"revokeConfirmationWindow"`
+
+	// CredentialDelivery is how granted credentials reach the user: response (returned by the API),
+	// slack-dm (sent to the grantee as a Slack direct message) or none
+	CredentialDelivery string `mapstructure:"credentialDelivery"`
 }
 
 type LogConfig struct {
@@ -46,8 +66,12 @@ type LogConfig struct {
 }
 
 type AuthConfig struct {
-	AdminUsers []string `mapstructure:"adminUsers"`
-	Approvers  []string `mapstructure:"approvers"`
+	AdminUsers   []string      `mapstructure:"adminUsers"`
+	Approvers    []string      `mapstructure:"approvers"`
+	RoleCacheTTL time.Duration `mapstructure:"roleCacheTTL"` // 0 looks up roles on every check
+
+	// TrustedOperators mirrors the operator's --trusted-operators flag for effective access reports
+	TrustedOperators []string `mapstructure:"trustedOperators"`
 }
 
 func LoadFromViper() (*Config, error) {
@@ -75,6 +99,13 @@ func setDefaults() {
 
 	viper.SetDefault("access.maxDuration", "1h")
 	viper.SetDefault("access.approvalRequired", true)
+	viper.SetDefault("access.maxActiveSessions", 0)
+	viper.SetDefault("access.kubeconfigDownloadTTL", 0)
+	viper.SetDefault("access.kubeconfigDownloadNamespace", "jit-system")
+	viper.SetDefault("access.revokeConfirmationWindow", 0)
+	viper.SetDefault("access.credentialDelivery", "response")
+
+	viper.SetDefault("auth.roleCacheTTL", 0)
 
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
@@ -93,6 +124,20 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("server.port is required")
 	}
 
+	for subcommand, responseType := range cfg.Slack.ResponseTypes {
+		if responseType != "ephemeral" && responseType != "in_channel" {
+			return fmt.Errorf("slack.responseTypes.%s must be ephemeral or in_channel, got %q",
+				subcommand, responseType)
+		}
+	}
+
+	switch cfg.Access.CredentialDelivery {
+	case "", "response", "slack-dm", "none":
+	default:
+		return fmt.Errorf("access.credentialDelivery must be response, slack-dm or none, got %q",
+			cfg.Access.CredentialDelivery)
+	}
+
 	return nil
 }
 
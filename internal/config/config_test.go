@@ -128,6 +128,28 @@ func TestLoadFromViperValidation(t *testing.T) {
 			expectError: true,
 			errorMsg:    "slack.signingSecret is required",
 		},
+		{
+			name: "invalid slack response type",
+			setupViper: func() {
+				viper.Reset()
+				viper.Set("slack.token", "test-token")
+				viper.Set("slack.signingSecret", "test-secret")
+				viper.Set("slack.responseTypes", map[string]string{"request": "broadcast"})
+			},
+			expectError: true,
+			errorMsg:    `slack.responseTypes.request must be ephemeral or in_channel, got "broadcast"`,
+		},
+		{
+			name: "invalid credential delivery",
+			setupViper: func() {
+				viper.Reset()
+				viper.Set("slack.token", "test-token")
+				viper.Set("slack.signingSecret", "test-secret")
+				viper.Set("access.credentialDelivery", "vault")
+			},
+			expectError: true,
+			errorMsg:    `access.credentialDelivery must be response, slack-dm or none, got "vault"`,
+		},
 		{
 			name: "valid config",
 			setupViper: func() {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,16 +10,31 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/aws"
 	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
 	"github.com/rebelopsio/jit-bot/pkg/models"
 	"github.com/rebelopsio/jit-bot/pkg/store"
 )
 
+// accessProvisioner grants and revokes cluster access; implemented by kubernetes.AccessManager
+type accessProvisioner interface {
+	GrantAccess(ctx context.Context, req kubernetes.GrantAccessRequest) (*kubernetes.AccessCredentials, error)
+	RevokeAccess(
+		ctx context.Context, clusterAccess *models.ClusterAccess, cluster *models.Cluster, jitRoleArn string,
+	) error
+	CleanupExpiredAccess(ctx context.Context, clusterName string) error
+}
+
 type AccessHandler struct {
-	rbac          *auth.RBAC
-	store         *store.MemoryStore
-	accessManager *kubernetes.AccessManager
-	region        string
+	rbac              *auth.RBAC
+	store             *store.MemoryStore
+	accessManager     accessProvisioner
+	region            string
+	maxActiveSessions int
+	downloads         *kubeconfigDownloads // nil returns kubeconfigs inline
+	deliverer         CredentialDeliverer  // nil returns credentials in the response
+
+	revokeConfirmations *revokeConfirmations // nil revokes others' access on one admin's request
 }
 
 type GrantAccessRequest struct {
@@ -31,13 +47,17 @@ type GrantAccessRequest struct {
 	Reason        string   `json:"reason"`
 	JITRoleArn    string   `json:"jit_role_arn"`
 	AssumeRoleArn string   `json:"assume_role_arn,omitempty"`
+	RequestName   string   `json:"request_name,omitempty"`
 }
 
 type AccessResponse struct {
 	AccessID             string    `json:"access_id"`
 	ClusterName          string    `json:"cluster_name"`
 	UserID               string    `json:"user_id"`
-	KubeConfig           string    `json:"kubeconfig"`
+	KubeConfig           string    `json:"kubeconfig,omitempty"`
+	KubeConfigURL        string    `json:"kubeconfig_url,omitempty"`
+	KubeConfigURLExpires time.Time `json:"kubeconfig_url_expires_at,omitzero"`
+	CredentialDelivery   string    `json:"credential_delivery,omitempty"` // set when credentials are sent elsewhere
 	ClusterEndpoint      string    `json:"cluster_endpoint"`
 	ExpiresAt            time.Time `json:"expires_at"`
 	TemporaryCredentials struct {
@@ -48,25 +68,46 @@ type AccessResponse struct {
 	} `json:"temporary_credentials"`
 }
 
+// RevokeAccessRequest identifies the access to revoke by access_id or, when that is not known,
+// by the name of the JITAccessRequest it was granted for
 type RevokeAccessRequest struct {
-	AccessID string `json:"access_id"`
+	AccessID    string `json:"access_id,omitempty"`
+	RequestName string `json:"request_name,omitempty"`
+}
+
+// PendingRevokeResponse is returned when an admin's revocation of another user's access is held
+// until a second admin confirms it by repeating the revoke before ExpiresAt
+type PendingRevokeResponse struct {
+	AccessID    string    `json:"access_id"`
+	Status      string    `json:"status"`
+	RequestedBy string    `json:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
 
+// NewAccessHandler creates an access handler. Clusters outside allowedRegions are refused, an
+// empty list allowing every region. maxActiveSessions caps the simultaneous active sessions per
+// user; zero means unlimited.
 func NewAccessHandler(
 	rbac *auth.RBAC,
 	store *store.MemoryStore,
 	region string,
+	allowedRegions []string,
+	maxActiveSessions int,
 ) (*AccessHandler, error) {
 	accessManager, err := kubernetes.NewAccessManager(region)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create access manager: %w", err)
 	}
+	if err := accessManager.SetAllowedRegions(aws.NewAllowedRegions(allowedRegions)); err != nil {
+		return nil, fmt.Errorf("failed to create access manager: %w", err)
+	}
 
 	return &AccessHandler{
-		rbac:          rbac,
-		store:         store,
-		accessManager: accessManager,
-		region:        region,
+		rbac:              rbac,
+		store:             store,
+		accessManager:     accessManager,
+		region:            region,
+		maxActiveSessions: maxActiveSessions,
 	}, nil
 }
 
@@ -100,6 +141,20 @@ func (h *AccessHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Enforce the per-user active session cap
+	if h.maxActiveSessions > 0 {
+		active, countErr := h.store.CountActiveAccesses(req.UserID)
+		if countErr != nil {
+			http.Error(w, countErr.Error(), http.StatusInternalServerError)
+			return
+		}
+		if active >= h.maxActiveSessions {
+			http.Error(w, fmt.Sprintf("user %s already has %d active sessions (maximum %d)",
+				req.UserID, active, h.maxActiveSessions), http.StatusForbidden)
+			return
+		}
+	}
+
 	// Get cluster information
 	cluster, err := h.store.GetCluster(req.ClusterID)
 	if err != nil {
@@ -107,6 +162,32 @@ func (h *AccessHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject a second concurrent session for the same user and cluster; extend or revoke the active one
+	existing, err := h.store.GetActiveAccessForUserCluster(req.UserID, cluster.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		http.Error(w, fmt.Sprintf("user %s already has active access %s to cluster %s",
+			req.UserID, existing.ID, cluster.Name), http.StatusConflict)
+		return
+	}
+
+	// Enforce the cluster-wide active session cap; the grant can be retried once a session ends
+	if cluster.MaxActiveSessions > 0 {
+		active, countErr := h.store.CountActiveClusterAccesses(cluster.ID)
+		if countErr != nil {
+			http.Error(w, countErr.Error(), http.StatusInternalServerError)
+			return
+		}
+		if active >= cluster.MaxActiveSessions {
+			http.Error(w, fmt.Sprintf("cluster %s already has %d active sessions (maximum %d); try again later",
+				cluster.Name, active, cluster.MaxActiveSessions), http.StatusTooManyRequests)
+			return
+		}
+	}
+
 	// Parse duration
 	duration, err := time.ParseDuration(req.Duration)
 	if err != nil {
@@ -147,6 +228,8 @@ func (h *AccessHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
 		RequestedAt: time.Now(),
 		ExpiresAt:   &expiresAt,
 		Reason:      req.Reason,
+		Permissions: req.Permissions,
+		RequestName: req.RequestName,
 	}
 
 	// Grant actual access through AWS
@@ -170,6 +253,10 @@ func (h *AccessHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	grantedAt := time.Now()
+	clusterAccess.GrantedAt = &grantedAt
+	clusterAccess.SessionName = credentials.SessionName
+
 	// Store the access record
 	if storeErr := h.store.CreateClusterAccess(clusterAccess); storeErr != nil {
 		// Log error but don't fail the request since AWS access was already granted
@@ -187,15 +274,32 @@ func (h *AccessHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
 		AccessID:        accessID,
 		ClusterName:     cluster.Name,
 		UserID:          req.UserID,
-		KubeConfig:      credentials.KubeConfig,
 		ClusterEndpoint: credentials.ClusterEndpoint,
 		ExpiresAt:       credentials.ExpiresAt,
 	}
 
-	response.TemporaryCredentials.AccessKeyID = credentials.TemporaryCredentials.AccessKeyID
-	response.TemporaryCredentials.SecretAccessKey = credentials.TemporaryCredentials.SecretAccessKey
-	response.TemporaryCredentials.SessionToken = credentials.TemporaryCredentials.SessionToken
-	response.TemporaryCredentials.Expiration = credentials.TemporaryCredentials.Expiration
+	if deliverErr := h.credentialDeliverer().Deliver(ctx, clusterAccess, credentials, &response); deliverErr != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("access granted but failed to deliver credentials: %v", deliverErr),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// Hand out a one-time link instead of the kubeconfig when downloads are enabled. The
+	// kubeconfig embeds the temporary credentials, so they leave the response too.
+	if h.downloads != nil && response.KubeConfig != "" {
+		token, expiresAt, tokenErr := h.downloads.issue(ctx, accessID, credentials.KubeConfig)
+		if tokenErr != nil {
+			http.Error(w, tokenErr.Error(), http.StatusInternalServerError)
+			return
+		}
+		response.KubeConfig = ""
+		response.TemporaryCredentials = AccessResponse{}.TemporaryCredentials
+		response.KubeConfigURL = kubeconfigDownloadURL(accessID, token)
+		response.KubeConfigURLExpires = expiresAt
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
@@ -218,18 +322,53 @@ func (h *AccessHandler) RevokeAccess(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get access record
-	clusterAccess, err := h.store.GetClusterAccess(req.AccessID)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("access record not found: %s", req.AccessID), http.StatusNotFound)
+	var clusterAccess *models.ClusterAccess
+	var err error
+	switch {
+	case req.AccessID != "":
+		clusterAccess, err = h.store.GetClusterAccess(req.AccessID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("access record not found: %s", req.AccessID), http.StatusNotFound)
+			return
+		}
+	case req.RequestName != "":
+		clusterAccess, err = h.store.GetAccessByRequestName(req.RequestName)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("no access record for request: %s", req.RequestName), http.StatusNotFound)
+			return
+		}
+	default:
+		http.Error(w, "missing required field: access_id or request_name", http.StatusBadRequest)
 		return
 	}
 
 	// Check permissions - user can revoke their own access or admins can revoke any
+	revokedBy := userID
 	if clusterAccess.UserID != userID {
 		if permErr := h.rbac.ValidatePermission(userID, auth.PermissionRevokeAccess); permErr != nil {
 			http.Error(w, permErr.Error(), http.StatusForbidden)
 			return
 		}
+
+		if h.revokeConfirmations != nil {
+			firstAdmin, expiresAt, confirmErr := h.revokeConfirmations.confirm(clusterAccess.ID, userID)
+			if confirmErr != nil {
+				http.Error(w, confirmErr.Error(), http.StatusConflict)
+				return
+			}
+			if firstAdmin == "" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusAccepted)
+				_ = json.NewEncoder(w).Encode(PendingRevokeResponse{
+					AccessID:    clusterAccess.ID,
+					Status:      "pending_confirmation",
+					RequestedBy: userID,
+					ExpiresAt:   expiresAt,
+				})
+				return
+			}
+			revokedBy = firstAdmin + "," + userID
+		}
 	}
 
 	// Get cluster information
@@ -258,6 +397,7 @@ func (h *AccessHandler) RevokeAccess(w http.ResponseWriter, r *http.Request) {
 	clusterAccess.Status = models.AccessStatusRevoked
 	clusterAccess.RevokedAt = &time.Time{}
 	*clusterAccess.RevokedAt = time.Now()
+	clusterAccess.RevokedBy = revokedBy
 
 	if updateErr := h.store.UpdateClusterAccess(clusterAccess); updateErr != nil {
 		// Log error but don't fail since AWS access was revoked
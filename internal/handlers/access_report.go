@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// AccessReport summarizes the access requested within a window for compliance reviews
+type AccessReport struct {
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	Totals      AccessReportCounts
+	Clusters    []ClusterReport
+	Denials     []*models.ClusterAccess
+	// Sessions are the requests that were granted, whether still active, expired or revoked
+	Sessions []*models.ClusterAccess
+}
+
+// AccessReportCounts counts requests by outcome
+type AccessReportCounts struct {
+	Requests int
+	Granted  int
+	Denied   int
+	Revoked  int
+	Pending  int
+}
+
+// ClusterReport is one cluster's share of an access report
+type ClusterReport struct {
+	ClusterID string
+	AccessReportCounts
+}
+
+func (c *AccessReportCounts) add(access *models.ClusterAccess) {
+	c.Requests++
+	switch {
+	case access.Status == models.AccessStatusDenied:
+		c.Denied++
+	case accessGranted(access):
+		c.Granted++
+		if access.Status == models.AccessStatusRevoked {
+			c.Revoked++
+		}
+	default:
+		c.Pending++
+	}
+}
+
+// accessGranted reports whether access was handed out, including sessions that have since ended
+func accessGranted(access *models.ClusterAccess) bool {
+	switch access.Status {
+	case models.AccessStatusActive, models.AccessStatusExpired, models.AccessStatusRevoked:
+		return true
+	}
+	return false
+}
+
+// buildAccessReport summarizes the accesses requested within [from, to)
+func buildAccessReport(accesses []*models.ClusterAccess, from, to, now time.Time) *AccessReport {
+	report := &AccessReport{From: from, To: to, GeneratedAt: now}
+
+	clusters := make(map[string]*ClusterReport)
+	for _, access := range accesses {
+		if access.RequestedAt.Before(from) || !access.RequestedAt.Before(to) {
+			continue
+		}
+
+		report.Totals.add(access)
+		cluster, ok := clusters[access.ClusterID]
+		if !ok {
+			cluster = &ClusterReport{ClusterID: access.ClusterID}
+			clusters[access.ClusterID] = cluster
+		}
+		cluster.add(access)
+
+		if access.Status == models.AccessStatusDenied {
+			report.Denials = append(report.Denials, access)
+		} else if accessGranted(access) {
+			report.Sessions = append(report.Sessions, access)
+		}
+	}
+
+	for _, cluster := range clusters {
+		report.Clusters = append(report.Clusters, *cluster)
+	}
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		return report.Clusters[i].ClusterID < report.Clusters[j].ClusterID
+	})
+
+	byRequestedAt := func(list []*models.ClusterAccess) {
+		sort.Slice(list, func(i, j int) bool { return list[i].RequestedAt.Before(list[j].RequestedAt) })
+	}
+	byRequestedAt(report.Denials)
+	byRequestedAt(report.Sessions)
+
+	return report
+}
+
+func formatReportTime(t any) string {
+	switch v := t.(type) {
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04 UTC")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format("2006-01-02 15:04 UTC")
+	}
+	return ""
+}
+
+// accessReportTemplate renders a self-contained page that prints cleanly to PDF from a browser
+var accessReportTemplate = template.Must(template.New("access-report").Funcs(template.FuncMap{
+	"time": formatReportTime,
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>JIT Access Report {{time .From}} to {{time .To}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; margin: 2em; color: #222; }
+h1 { font-size: 20px; } h2 { font-size: 16px; margin-top: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 4px 6px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+tr { page-break-inside: avoid; }
+@media print { body { margin: 0; } h2 { page-break-after: avoid; } }
+</style>
+</head>
+<body>
+<h1>JIT Access Report</h1>
+<p>Window: {{time .From}} to {{time .To}}. Generated {{time .GeneratedAt}}.</p>
+
+<section id="totals">
+<h2>Totals</h2>
+<table>
+<tr><th>Requests</th><th>Granted</th><th>Denied</th><th>Revoked</th><th>Pending</th></tr>
+<tr><td>{{.Totals.Requests}}</td><td>{{.Totals.Granted}}</td><td>{{.Totals.Denied}}</td>` +
+	`<td>{{.Totals.Revoked}}</td><td>{{.Totals.Pending}}</td></tr>
+</table>
+</section>
+
+<section id="clusters">
+<h2>Clusters</h2>
+{{if .Clusters}}<table>
+<tr><th>Cluster</th><th>Requests</th><th>Granted</th><th>Denied</th><th>Revoked</th><th>Pending</th></tr>
+{{range .Clusters}}<tr><td>{{.ClusterID}}</td><td>{{.Requests}}</td><td>{{.Granted}}</td><td>{{.Denied}}</td>` +
+	`<td>{{.Revoked}}</td><td>{{.Pending}}</td></tr>
+{{end}}</table>{{else}}<p>No access was requested in this window.</p>{{end}}
+</section>
+
+<section id="denials">
+<h2>Denials</h2>
+{{if .Denials}}<table>
+<tr><th>Requested</th><th>User</th><th>Cluster</th><th>Permissions</th><th>Reason</th></tr>
+{{range .Denials}}<tr><td>{{time .RequestedAt}}</td><td>{{.UserID}} {{.UserEmail}}</td><td>{{.ClusterID}}</td>` +
+	`<td>{{join .Permissions ", "}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>{{else}}<p>No requests were denied in this window.</p>{{end}}
+</section>
+
+<section id="sessions">
+<h2>Sessions</h2>
+{{if .Sessions}}<table>
+<tr><th>User</th><th>Cluster</th><th>Permissions</th><th>Status</th><th>Granted</th><th>Expires</th>` +
+	`<th>Revoked</th><th>Reason</th></tr>
+{{range .Sessions}}<tr><td>{{.UserID}} {{.UserEmail}}</td><td>{{.ClusterID}}</td>` +
+	`<td>{{join .Permissions ", "}}</td><td>{{.Status}}</td><td>{{time .GrantedAt}}</td><td>{{time .ExpiresAt}}</td>` +
+	`<td>{{time .RevokedAt}}{{with .RevokedBy}} by {{.}}{{end}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>{{else}}<p>No sessions were granted in this window.</p>{{end}}
+</section>
+</body>
+</html>
+`))
+
+// AccessReport serves an HTML summary of the access requested within [from, to): totals, a
+// per-cluster breakdown, denials and granted sessions. Browsers can print it to PDF.
+func (h *AccessHandler) AccessReport(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-Slack-User-Id")
+	if userID == "" {
+		http.Error(w, "missing user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// The report covers the same records as the CSV export
+	if err := h.rbac.ValidatePermission(userID, auth.PermissionExportAccess); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	from, to, err := parseExportWindow(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accessList, err := h.store.ListClusterAccess()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	report := buildAccessReport(accessList, from, to, time.Now())
+
+	var page strings.Builder
+	if err := accessReportTemplate.Execute(&page, report); err != nil {
+		slog.Error("Failed to render access report", "error", err)
+		http.Error(w, "failed to render access report", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("access-report-%s-%s.html", from.Format("20060102"), to.Format("20060102"))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	if _, err := w.Write([]byte(page.String())); err != nil {
+		slog.Error("Failed to write access report", "error", err)
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+func TestAccessReportHTML(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	seedExportAccesses(t, handler)
+
+	denied := &models.ClusterAccess{
+		ID:          "access-denied",
+		UserID:      "user-5",
+		UserEmail:   "user5@company.com",
+		ClusterID:   "cluster-2",
+		Permissions: []string{"admin"},
+		Status:      models.AccessStatusDenied,
+		Reason:      "<script>alert(1)</script>",
+		RequestedAt: time.Date(2024, time.March, 8, 9, 0, 0, 0, time.UTC),
+	}
+	if err := handler.store.CreateAccess(denied); err != nil {
+		t.Fatalf("Failed to create %s: %v", denied.ID, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/access?from=2024-03-02&to=2024-03-15", nil)
+	req.Header.Set("X-Slack-User-Id", "admin1")
+	rr := httptest.NewRecorder()
+
+	handler.AccessReport(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("Expected HTML content type, got %s", contentType)
+	}
+	if disposition := rr.Header().Get("Content-Disposition"); !strings.Contains(disposition,
+		"access-report-20240302-20240315.html") {
+		t.Errorf("Unexpected content disposition: %s", disposition)
+	}
+
+	body := rr.Body.String()
+	for _, section := range []string{`id="totals"`, `id="clusters"`, `id="denials"`, `id="sessions"`} {
+		if !strings.Contains(body, section) {
+			t.Errorf("Expected report to contain section %s", section)
+		}
+	}
+
+	// Two sessions and one denial fall inside the window; access-early and access-late do not
+	totals := "<tr><td>3</td><td>2</td><td>1</td><td>1</td><td>0</td></tr>"
+	if !strings.Contains(body, totals) {
+		t.Errorf("Expected totals row %s in report:\n%s", totals, body)
+	}
+	for _, row := range []string{
+		"<tr><td>cluster-1</td><td>1</td><td>1</td><td>0</td><td>0</td><td>0</td></tr>",
+		"<tr><td>cluster-2</td><td>2</td><td>1</td><td>1</td><td>1</td><td>0</td></tr>",
+	} {
+		if !strings.Contains(body, row) {
+			t.Errorf("Expected cluster row %s in report", row)
+		}
+	}
+
+	if !strings.Contains(body, "user5@company.com") || !strings.Contains(body, "2024-03-08 09:00 UTC") {
+		t.Error("Expected the denial to be listed")
+	}
+	if !strings.Contains(body, "2024-03-12 10:30 UTC by admin1") {
+		t.Error("Expected the revoked session to show who revoked it")
+	}
+	if strings.Contains(body, "access-early") || strings.Contains(body, "after the window") {
+		t.Error("Expected accesses outside the window to be left out")
+	}
+	if strings.Contains(body, "<script>") {
+		t.Error("Expected request reasons to be HTML-escaped")
+	}
+}
+
+func TestAccessReportEmptyWindow(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	seedExportAccesses(t, handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/access?from=2023-01-01&to=2023-02-01", nil)
+	req.Header.Set("X-Slack-User-Id", "admin1")
+	rr := httptest.NewRecorder()
+
+	handler.AccessReport(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "<tr><td>0</td><td>0</td><td>0</td><td>0</td><td>0</td></tr>") {
+		t.Error("Expected zero totals for an empty window")
+	}
+	for _, note := range []string{"No access was requested", "No requests were denied", "No sessions were granted"} {
+		if !strings.Contains(body, note) {
+			t.Errorf("Expected %q in an empty report", note)
+		}
+	}
+}
+
+func TestAccessReportRequiresExportPermission(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.rbac.SetUserRole("approver1", auth.RoleApprover)
+
+	tests := []struct {
+		name   string
+		userID string
+		query  string
+		want   int
+	}{
+		{name: "missing user", query: "from=2024-03-01", want: http.StatusUnauthorized},
+		{name: "approver", userID: "approver1", query: "from=2024-03-01", want: http.StatusForbidden},
+		{
+			name:   "inverted window",
+			userID: "admin1",
+			query:  "from=2024-03-15&to=2024-03-01",
+			want:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/access?"+tt.query, nil)
+			if tt.userID != "" {
+				req.Header.Set("X-Slack-User-Id", tt.userID)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.AccessReport(rr, req)
+
+			if rr.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rr.Code)
+			}
+		})
+	}
+}
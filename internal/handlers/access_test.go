@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+type fakeProvisioner struct {
+	grants  int
+	revokes int
+}
+
+func (f *fakeProvisioner) GrantAccess(
+	_ context.Context, req kubernetes.GrantAccessRequest,
+) (*kubernetes.AccessCredentials, error) {
+	f.grants++
+	return &kubernetes.AccessCredentials{
+		TemporaryCredentials: &aws.Credentials{AccessKeyID: "AKIATEST"},
+		KubeConfig:           "kubeconfig-" + req.ClusterAccess.UserID,
+		ClusterEndpoint:      "https://example.eks.amazonaws.com",
+		ExpiresAt:            *req.ClusterAccess.ExpiresAt,
+		SessionName:          aws.JITSessionName(req.ClusterAccess.UserID, req.Cluster.ID, req.ClusterAccess.RequestedAt),
+	}, nil
+}
+
+func (f *fakeProvisioner) RevokeAccess(
+	context.Context, *models.ClusterAccess, *models.Cluster, string,
+) error {
+	f.revokes++
+	return nil
+}
+
+func (f *fakeProvisioner) CleanupExpiredAccess(context.Context, string) error {
+	return nil
+}
+
+func newTestAccessHandler(t *testing.T, maxActiveSessions int) (*AccessHandler, *store.MemoryStore, *fakeProvisioner) {
+	t.Helper()
+
+	memStore := store.NewMemoryStore()
+	cluster := &models.Cluster{
+		ID:          "cluster-1",
+		Name:        "test-cluster",
+		AWSAccount:  "123456789012",
+		Region:      "us-east-1",
+		MaxDuration: 4 * time.Hour,
+		Enabled:     true,
+	}
+	if err := memStore.CreateCluster(cluster); err != nil {
+		t.Fatalf("Failed to create cluster: %v", err)
+	}
+
+	provisioner := &fakeProvisioner{}
+	handler := &AccessHandler{
+		rbac:              auth.NewRBAC([]string{"admin1"}),
+		store:             memStore,
+		accessManager:     provisioner,
+		region:            "us-east-1",
+		maxActiveSessions: maxActiveSessions,
+	}
+	return handler, memStore, provisioner
+}
+
+func grantAccessRequest(t *testing.T, userID string) *http.Request {
+	t.Helper()
+	return grantClusterAccessRequest(t, userID, "cluster-1")
+}
+
+func grantClusterAccessRequest(t *testing.T, userID, clusterID string) *http.Request {
+	t.Helper()
+
+	body, _ := json.Marshal(GrantAccessRequest{
+		ClusterID: clusterID,
+		UserID:    userID,
+		UserEmail: userID + "@company.com",
+		Duration:  "1h",
+		Reason:    "incident response",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/access/grant", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-User-Id", "admin1")
+	return req
+}
+
+func TestGrantAccessMaxActiveSessions(t *testing.T) {
+	handler, memStore, provisioner := newTestAccessHandler(t, 2)
+
+	expiresAt := time.Now().Add(time.Hour)
+	for _, id := range []string{"access-1", "access-2"} {
+		access := &models.ClusterAccess{
+			ID:        id,
+			ClusterID: "cluster-1",
+			UserID:    "capped-user",
+			Status:    models.AccessStatusActive,
+			ExpiresAt: &expiresAt,
+		}
+		if err := memStore.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create access: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "capped-user"))
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for user at the cap, got %d", http.StatusForbidden, rr.Code)
+	}
+	if !bytes.Contains(rr.Body.Bytes(), []byte("maximum 2")) {
+		t.Errorf("Expected session cap message, got %q", rr.Body.String())
+	}
+	if provisioner.grants != 0 {
+		t.Errorf("Expected no grants for user at the cap, got %d", provisioner.grants)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "other-user"))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d for user below the cap, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if provisioner.grants != 1 {
+		t.Errorf("Expected 1 grant for user below the cap, got %d", provisioner.grants)
+	}
+}
+
+func TestGrantAccessClusterSessionCap(t *testing.T) {
+	tests := []struct {
+		name     string
+		cap      int
+		wantCode int
+	}{
+		{name: "below the cap", cap: 3, wantCode: http.StatusOK},
+		{name: "at the cap", cap: 2, wantCode: http.StatusTooManyRequests},
+		{name: "above the cap", cap: 1, wantCode: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, memStore, provisioner := newTestAccessHandler(t, 0)
+
+			cluster, err := memStore.GetCluster("cluster-1")
+			if err != nil {
+				t.Fatalf("Failed to get cluster: %v", err)
+			}
+			cluster.MaxActiveSessions = tt.cap
+			if err := memStore.UpdateCluster(cluster); err != nil {
+				t.Fatalf("Failed to update cluster: %v", err)
+			}
+
+			expiresAt := time.Now().Add(time.Hour)
+			for _, userID := range []string{"busy-user-1", "busy-user-2"} {
+				access := &models.ClusterAccess{
+					ID:        "access-" + userID,
+					ClusterID: "cluster-1",
+					UserID:    userID,
+					Status:    models.AccessStatusActive,
+					ExpiresAt: &expiresAt,
+				}
+				if err := memStore.CreateAccess(access); err != nil {
+					t.Fatalf("Failed to create access: %v", err)
+				}
+			}
+
+			rr := httptest.NewRecorder()
+			handler.GrantAccess(rr, grantAccessRequest(t, "new-user"))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode == http.StatusOK {
+				if provisioner.grants != 1 {
+					t.Errorf("Expected 1 grant below the cap, got %d", provisioner.grants)
+				}
+				return
+			}
+			if !bytes.Contains(rr.Body.Bytes(), []byte("already has 2 active sessions")) {
+				t.Errorf("Expected cluster cap message, got %q", rr.Body.String())
+			}
+			if provisioner.grants != 0 {
+				t.Errorf("Expected no grants at the cap, got %d", provisioner.grants)
+			}
+		})
+	}
+}
+
+func TestGrantAccessUnlimitedSessions(t *testing.T) {
+	handler, memStore, provisioner := newTestAccessHandler(t, 0)
+
+	for _, clusterID := range []string{"cluster-2", "cluster-3"} {
+		cluster := &models.Cluster{ID: clusterID, Name: clusterID, MaxDuration: 4 * time.Hour, Enabled: true}
+		if err := memStore.CreateCluster(cluster); err != nil {
+			t.Fatalf("Failed to create cluster: %v", err)
+		}
+	}
+
+	for _, clusterID := range []string{"cluster-1", "cluster-2", "cluster-3"} {
+		rr := httptest.NewRecorder()
+		handler.GrantAccess(rr, grantClusterAccessRequest(t, "busy-user", clusterID))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	}
+
+	if provisioner.grants != 3 {
+		t.Errorf("Expected 3 grants without a session cap, got %d", provisioner.grants)
+	}
+}
+
+func TestGrantAccessRejectsConcurrentSession(t *testing.T) {
+	handler, memStore, provisioner := newTestAccessHandler(t, 0)
+
+	rr := httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "busy-user"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "busy-user"))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d for a second session, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+	if provisioner.grants != 1 {
+		t.Errorf("Expected 1 grant, got %d", provisioner.grants)
+	}
+
+	// Once the first session is revoked the user can be granted access again
+	active, err := memStore.GetActiveAccessForUserCluster("busy-user", "cluster-1")
+	if err != nil || active == nil {
+		t.Fatalf("Expected the first session to be active, got %v (%v)", active, err)
+	}
+	active.Status = models.AccessStatusRevoked
+	if err := memStore.UpdateClusterAccess(active); err != nil {
+		t.Fatalf("Failed to revoke access: %v", err)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "busy-user"))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d after revocation, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
+
+func TestGrantAccessIndexesSession(t *testing.T) {
+	handler, memStore, _ := newTestAccessHandler(t, 0)
+
+	rr := httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "session-user"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var response AccessResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	granted, err := memStore.GetAccess(response.AccessID)
+	if err != nil {
+		t.Fatalf("Failed to get granted access: %v", err)
+	}
+	if granted.SessionName == "" {
+		t.Fatal("Expected session name to be recorded on the access record")
+	}
+
+	resolved, err := memStore.GetAccessBySession(granted.SessionName)
+	if err != nil {
+		t.Fatalf("Failed to resolve session %s: %v", granted.SessionName, err)
+	}
+	if resolved.ID != response.AccessID || resolved.UserID != "session-user" {
+		t.Errorf("Session resolved to wrong record: %s (%s)", resolved.ID, resolved.UserID)
+	}
+}
+
+func revokeAccessRequest(t *testing.T, userID string, body RevokeAccessRequest) *http.Request {
+	t.Helper()
+
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/access/revoke", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-User-Id", userID)
+	return req
+}
+
+func TestRevokeAccessByRequestName(t *testing.T) {
+	handler, memStore, _ := newTestAccessHandler(t, 0)
+
+	access := &models.ClusterAccess{
+		ID:          "access-1",
+		RequestName: "jit-U0REQUESTER-1718020800",
+		ClusterID:   "cluster-1",
+		UserID:      "U0REQUESTER",
+		Status:      models.AccessStatusActive,
+	}
+	if err := memStore.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+
+	// Other users need the revoke permission, exactly as when revoking by access ID
+	rr := httptest.NewRecorder()
+	handler.RevokeAccess(rr, revokeAccessRequest(t, "U0OTHER",
+		RevokeAccessRequest{RequestName: "jit-U0REQUESTER-1718020800"}))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for another user, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.RevokeAccess(rr, revokeAccessRequest(t, "admin1",
+		RevokeAccessRequest{RequestName: "jit-U0REQUESTER-1718020800"}))
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+
+	revoked, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if revoked.Status != models.AccessStatusRevoked || revoked.RevokedAt == nil {
+		t.Errorf("Expected access to be revoked, got %s at %v", revoked.Status, revoked.RevokedAt)
+	}
+}
+
+func TestRevokeAccessByUnknownRequestName(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+
+	rr := httptest.NewRecorder()
+	handler.RevokeAccess(rr, revokeAccessRequest(t, "admin1", RevokeAccessRequest{RequestName: "jit-missing"}))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.RevokeAccess(rr, revokeAccessRequest(t, "admin1", RevokeAccessRequest{}))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without an access ID or request name, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
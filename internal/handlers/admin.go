@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
+	"sigs.k8s.io/yaml"
 
 	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
 	"github.com/rebelopsio/jit-bot/pkg/models"
 	"github.com/rebelopsio/jit-bot/pkg/store"
 )
@@ -42,23 +47,12 @@ func (h *AdminHandler) CreateCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cluster := &models.Cluster{
-		ID:                uuid.New().String(),
-		Name:              req.Name,
-		DisplayName:       req.DisplayName,
-		AWSAccount:        req.AWSAccount,
-		Region:            req.Region,
-		Environment:       req.Environment,
-		Tags:              req.Tags,
-		MaxDuration:       req.MaxDuration,
-		RequiredApprovers: req.RequiredApprovers,
-		Enabled:           req.Enabled,
-		CreatedBy:         userID,
+	if err := kubernetes.ValidateUsernameTemplate(req.UsernameTemplate); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	if cluster.MaxDuration == 0 {
-		cluster.MaxDuration = 1 * time.Hour
-	}
+	cluster := newCluster(req, userID)
 
 	if err := h.store.CreateCluster(cluster); err != nil {
 		http.Error(w, err.Error(), http.StatusConflict)
@@ -71,6 +65,103 @@ func (h *AdminHandler) CreateCluster(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// BulkClusterResult reports the outcome of importing a single cluster
+type BulkClusterResult struct {
+	Name   string `json:"name"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkClusterResponse summarizes a bulk cluster import
+type BulkClusterResponse struct {
+	Created    int                 `json:"created"`
+	Duplicates int                 `json:"duplicates"`
+	Failed     int                 `json:"failed"`
+	Results    []BulkClusterResult `json:"results"`
+}
+
+const (
+	bulkStatusCreated   = "created"
+	bulkStatusDuplicate = "duplicate"
+	bulkStatusInvalid   = "invalid"
+	bulkStatusFailed    = "failed"
+)
+
+// BulkCreateClusters imports an array of clusters, given as JSON or YAML. Each cluster
+// is validated and created independently so one bad entry doesn't fail the batch.
+func (h *AdminHandler) BulkCreateClusters(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-Slack-User-Id")
+	if userID == "" {
+		http.Error(w, "missing user ID", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.rbac.ValidatePermission(userID, auth.PermissionManageClusters); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// YAML is a superset of JSON, so both are accepted
+	var reqs []models.Cluster
+	if err := yaml.Unmarshal(body, &reqs); err != nil {
+		http.Error(w, "invalid request body: expected an array of clusters", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.store.ListClusters()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	seen := make(map[string]bool, len(existing)+len(reqs))
+	for _, cluster := range existing {
+		seen[cluster.Name] = true
+	}
+
+	response := BulkClusterResponse{Results: make([]BulkClusterResult, 0, len(reqs))}
+	for _, req := range reqs {
+		result := BulkClusterResult{Name: req.Name}
+
+		switch err := validateClusterConfig(req); {
+		case err != nil:
+			result.Status = bulkStatusInvalid
+			result.Error = err.Error()
+			response.Failed++
+		case seen[req.Name]:
+			result.Status = bulkStatusDuplicate
+			result.Error = fmt.Sprintf("cluster %s already exists", req.Name)
+			response.Duplicates++
+		default:
+			cluster := newCluster(req, userID)
+			if createErr := h.store.CreateCluster(cluster); createErr != nil {
+				result.Status = bulkStatusFailed
+				result.Error = createErr.Error()
+				response.Failed++
+				break
+			}
+			seen[req.Name] = true
+			result.ID = cluster.ID
+			result.Status = bulkStatusCreated
+			response.Created++
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 func (h *AdminHandler) ListClusters(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-Slack-User-Id")
 	if userID == "" {
@@ -150,6 +241,63 @@ func (h *AdminHandler) DeleteCluster(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func newCluster(req models.Cluster, userID string) *models.Cluster {
+	cluster := &models.Cluster{
+		ID:                uuid.New().String(),
+		Name:              req.Name,
+		DisplayName:       req.DisplayName,
+		AWSAccount:        req.AWSAccount,
+		Region:            req.Region,
+		Environment:       req.Environment,
+		Tags:              req.Tags,
+		MaxDuration:       req.MaxDuration,
+		RequiredApprovers: req.RequiredApprovers,
+		AccessSchedule:    req.AccessSchedule,
+		UsernameTemplate:  req.UsernameTemplate,
+		Enabled:           req.Enabled,
+		CreatedBy:         userID,
+	}
+
+	if cluster.MaxDuration == 0 {
+		cluster.MaxDuration = 1 * time.Hour
+	}
+
+	return cluster
+}
+
+var (
+	awsAccountRegex = regexp.MustCompile(`^\d{12}$`)
+	awsRegionRegex  = regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d{1}$`)
+)
+
+func validateClusterConfig(cluster models.Cluster) error {
+	if cluster.Name == "" {
+		return fmt.Errorf("cluster name is required")
+	}
+
+	if !awsAccountRegex.MatchString(cluster.AWSAccount) {
+		return fmt.Errorf("invalid AWS account ID %q: must be 12 digits", cluster.AWSAccount)
+	}
+
+	if !awsRegionRegex.MatchString(cluster.Region) {
+		return fmt.Errorf("invalid AWS region %q", cluster.Region)
+	}
+
+	if cluster.MaxDuration < 0 {
+		return fmt.Errorf("max duration cannot be negative")
+	}
+
+	if cluster.RequiredApprovers < 0 {
+		return fmt.Errorf("required approvers cannot be negative")
+	}
+
+	if err := kubernetes.ValidateUsernameTemplate(cluster.UsernameTemplate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (h *AdminHandler) ManageUser(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-Slack-User-Id")
 	if userID == "" {
@@ -309,3 +309,123 @@ func TestManageUserUnauthorized(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
 	}
 }
+
+func TestBulkCreateClusters(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	memStore := store.NewMemoryStore()
+	handler := NewAdminHandler(rbac, memStore)
+
+	existing := &models.Cluster{
+		ID:         "existing-id",
+		Name:       "existing-cluster",
+		AWSAccount: "123456789012",
+		Region:     "us-east-1",
+	}
+	if err := memStore.CreateCluster(existing); err != nil {
+		t.Fatalf("Failed to seed cluster: %v", err)
+	}
+
+	clusters := []models.Cluster{
+		{Name: "prod-east", AWSAccount: "123456789012", Region: "us-east-1", Enabled: true},
+		{Name: "existing-cluster", AWSAccount: "123456789012", Region: "us-east-1"},
+		{Name: "bad-account", AWSAccount: "1234", Region: "us-east-1"},
+		{Name: "prod-west", AWSAccount: "123456789012", Region: "us-west-2", MaxDuration: 4 * time.Hour},
+		{Name: "prod-east", AWSAccount: "123456789012", Region: "us-east-1"},
+		{Name: "", AWSAccount: "123456789012", Region: "us-east-1"},
+	}
+
+	body, _ := json.Marshal(clusters)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clusters/bulk", bytes.NewReader(body))
+	req.Header.Set("X-Slack-User-Id", "admin1")
+
+	rr := httptest.NewRecorder()
+	handler.BulkCreateClusters(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var response BulkClusterResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Created != 2 || response.Duplicates != 2 || response.Failed != 2 {
+		t.Errorf("Expected 2 created, 2 duplicates, 2 failed; got %d, %d, %d",
+			response.Created, response.Duplicates, response.Failed)
+	}
+
+	expectedStatuses := []string{
+		bulkStatusCreated,
+		bulkStatusDuplicate,
+		bulkStatusInvalid,
+		bulkStatusCreated,
+		bulkStatusDuplicate,
+		bulkStatusInvalid,
+	}
+	if len(response.Results) != len(expectedStatuses) {
+		t.Fatalf("Expected %d results, got %d", len(expectedStatuses), len(response.Results))
+	}
+	for i, expected := range expectedStatuses {
+		if response.Results[i].Status != expected {
+			t.Errorf("Result %d (%s): expected status %s, got %s",
+				i, response.Results[i].Name, expected, response.Results[i].Status)
+		}
+	}
+
+	if response.Results[0].ID == "" {
+		t.Error("Created cluster should report its ID")
+	}
+
+	stored, _ := memStore.ListClusters()
+	if len(stored) != 3 {
+		t.Errorf("Expected 3 clusters in store, got %d", len(stored))
+	}
+}
+
+func TestBulkCreateClustersYAML(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	memStore := store.NewMemoryStore()
+	handler := NewAdminHandler(rbac, memStore)
+
+	body := `
+- name: staging
+  aws_account: "123456789012"
+  region: eu-west-1
+  enabled: true
+- name: dev
+  aws_account: "210987654321"
+  region: eu-west-1
+`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clusters/bulk", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("X-Slack-User-Id", "admin1")
+
+	rr := httptest.NewRecorder()
+	handler.BulkCreateClusters(rr, req)
+
+	var response BulkClusterResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Created != 2 {
+		t.Errorf("Expected 2 created clusters, got %d", response.Created)
+	}
+}
+
+func TestBulkCreateClustersUnauthorized(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	memStore := store.NewMemoryStore()
+	handler := NewAdminHandler(rbac, memStore)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clusters/bulk", bytes.NewReader([]byte("[]")))
+	req.Header.Set("X-Slack-User-Id", "user1")
+
+	rr := httptest.NewRecorder()
+	handler.BulkCreateClusters(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/slack"
+)
+
+// Credential delivery modes selected by access.credentialDelivery
+const (
+	CredentialDeliveryResponse = "response"
+	CredentialDeliverySlackDM  = "slack-dm"
+	CredentialDeliveryNone     = "none"
+)
+
+// CredentialDeliverer hands the credentials of a successful grant to the user. Deliverers that send
+// them somewhere other than the API response leave the response without them.
+type CredentialDeliverer interface {
+	Deliver(
+		ctx context.Context,
+		access *models.ClusterAccess,
+		credentials *kubernetes.AccessCredentials,
+		response *AccessResponse,
+	) error
+}
+
+// NewCredentialDeliverer returns the deliverer for a credential delivery mode; an empty mode
+// returns credentials in the API response
+func NewCredentialDeliverer(mode, slackToken string) (CredentialDeliverer, error) {
+	switch mode {
+	case "", CredentialDeliveryResponse:
+		return responseDeliverer{}, nil
+	case CredentialDeliverySlackDM:
+		return &slackDMDeliverer{messenger: slack.NewDirectMessenger(slackToken)}, nil
+	case CredentialDeliveryNone:
+		return noopDeliverer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential delivery %q", mode)
+	}
+}
+
+// responseDeliverer returns the kubeconfig and temporary credentials in the API response
+type responseDeliverer struct{}
+
+func (responseDeliverer) Deliver(
+	_ context.Context, _ *models.ClusterAccess, credentials *kubernetes.AccessCredentials, response *AccessResponse,
+) error {
+	response.KubeConfig = credentials.KubeConfig
+	response.TemporaryCredentials.AccessKeyID = credentials.TemporaryCredentials.AccessKeyID
+	response.TemporaryCredentials.SecretAccessKey = credentials.TemporaryCredentials.SecretAccessKey
+	response.TemporaryCredentials.SessionToken = credentials.TemporaryCredentials.SessionToken
+	response.TemporaryCredentials.Expiration = credentials.TemporaryCredentials.Expiration
+	return nil
+}
+
+// directMessenger sends Slack direct messages; implemented by slack.DirectMessenger
+type directMessenger interface {
+	SendDirectMessage(ctx context.Context, userID, text string) error
+}
+
+// slackDMDeliverer sends the credentials to the grantee as a Slack direct message, so they never
+// pass through the client that requested the grant
+type slackDMDeliverer struct {
+	messenger directMessenger
+}
+
+func (d *slackDMDeliverer) Deliver(
+	ctx context.Context,
+	access *models.ClusterAccess,
+	credentials *kubernetes.AccessCredentials,
+	response *AccessResponse,
+) error {
+	if err := d.messenger.SendDirectMessage(ctx, access.UserID, credentialMessage(response, credentials)); err != nil {
+		return fmt.Errorf("failed to send credentials to %s: %w", access.UserID, err)
+	}
+	response.CredentialDelivery = CredentialDeliverySlackDM
+	return nil
+}
+
+// credentialMessage formats the grant's kubeconfig and temporary credentials for a direct message
+func credentialMessage(response *AccessResponse, credentials *kubernetes.AccessCredentials) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your access to *%s* is active until %s.\n\n",
+		response.ClusterName, response.ExpiresAt.UTC().Format(time.RFC3339))
+	if credentials.KubeConfig != "" {
+		fmt.Fprintf(&b, "*Kubeconfig*\n```\n%s\n```\n", strings.TrimSpace(credentials.KubeConfig))
+	}
+	if creds := credentials.TemporaryCredentials; creds != nil && creds.AccessKeyID != "" {
+		fmt.Fprintf(&b, "*Temporary AWS credentials* (expire %s)\n```\n", creds.Expiration.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&b, "export AWS_ACCESS_KEY_ID=%s\n", creds.AccessKeyID)
+		fmt.Fprintf(&b, "export AWS_SECRET_ACCESS_KEY=%s\n", creds.SecretAccessKey)
+		fmt.Fprintf(&b, "export AWS_SESSION_TOKEN=%s\n```\n", creds.SessionToken)
+	}
+	return b.String()
+}
+
+// noopDeliverer delivers nothing, for deployments where users obtain credentials out of band,
+// e.g. by assuming the JIT role themselves once their access entry exists
+type noopDeliverer struct{}
+
+func (noopDeliverer) Deliver(
+	_ context.Context, _ *models.ClusterAccess, _ *kubernetes.AccessCredentials, response *AccessResponse,
+) error {
+	response.CredentialDelivery = CredentialDeliveryNone
+	return nil
+}
+
+// SetCredentialDeliverer changes how granted credentials reach the user; nil returns them in the
+// API response
+func (h *AccessHandler) SetCredentialDeliverer(deliverer CredentialDeliverer) {
+	h.deliverer = deliverer
+}
+
+func (h *AccessHandler) credentialDeliverer() CredentialDeliverer {
+	if h.deliverer == nil {
+		return responseDeliverer{}
+	}
+	return h.deliverer
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type recordingMessenger struct {
+	userID string
+	text   string
+	err    error
+}
+
+func (m *recordingMessenger) SendDirectMessage(_ context.Context, userID, text string) error {
+	m.userID = userID
+	m.text = text
+	return m.err
+}
+
+func grantWithDeliverer(t *testing.T, deliverer CredentialDeliverer) (*httptest.ResponseRecorder, AccessResponse) {
+	t.Helper()
+
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.SetCredentialDeliverer(deliverer)
+
+	rr := httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "dm-user"))
+
+	var response AccessResponse
+	if rr.Code == http.StatusOK {
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return rr, response
+}
+
+func TestNewCredentialDeliverer(t *testing.T) {
+	for mode, want := range map[string]CredentialDeliverer{
+		"":                         responseDeliverer{},
+		CredentialDeliveryResponse: responseDeliverer{},
+		CredentialDeliveryNone:     noopDeliverer{},
+	} {
+		deliverer, err := NewCredentialDeliverer(mode, "xoxb-test")
+		if err != nil || deliverer != want {
+			t.Errorf("Expected %T for mode %q, got %T (err %v)", want, mode, deliverer, err)
+		}
+	}
+
+	if deliverer, err := NewCredentialDeliverer(CredentialDeliverySlackDM, "xoxb-test"); err != nil {
+		t.Errorf("Expected a Slack DM deliverer, got error %v", err)
+	} else if _, ok := deliverer.(*slackDMDeliverer); !ok {
+		t.Errorf("Expected a Slack DM deliverer, got %T", deliverer)
+	}
+
+	if _, err := NewCredentialDeliverer("vault", "xoxb-test"); err == nil {
+		t.Error("Expected an error for an unknown delivery mode")
+	}
+}
+
+func TestGrantAccessDeliversInResponseByDefault(t *testing.T) {
+	rr, response := grantWithDeliverer(t, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	if response.KubeConfig != "kubeconfig-dm-user" {
+		t.Errorf("Expected the kubeconfig in the response, got %q", response.KubeConfig)
+	}
+	if response.TemporaryCredentials.AccessKeyID != "AKIATEST" {
+		t.Errorf("Expected temporary credentials in the response, got %q", response.TemporaryCredentials.AccessKeyID)
+	}
+	if response.CredentialDelivery != "" {
+		t.Errorf("Expected no delivery channel for inline credentials, got %q", response.CredentialDelivery)
+	}
+}
+
+func TestGrantAccessDeliversBySlackDM(t *testing.T) {
+	messenger := &recordingMessenger{}
+	rr, response := grantWithDeliverer(t, &slackDMDeliverer{messenger: messenger})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	if messenger.userID != "dm-user" {
+		t.Errorf("Expected credentials to be sent to dm-user, got %q", messenger.userID)
+	}
+	for _, want := range []string{"test-cluster", "kubeconfig-dm-user", "AWS_ACCESS_KEY_ID=AKIATEST"} {
+		if !strings.Contains(messenger.text, want) {
+			t.Errorf("Expected the direct message to contain %q, got %q", want, messenger.text)
+		}
+	}
+
+	if response.KubeConfig != "" || response.TemporaryCredentials.AccessKeyID != "" {
+		t.Error("Expected credentials to be left out of the response")
+	}
+	if response.CredentialDelivery != CredentialDeliverySlackDM {
+		t.Errorf("Expected credential_delivery %q, got %q", CredentialDeliverySlackDM, response.CredentialDelivery)
+	}
+}
+
+func TestGrantAccessSlackDMFailure(t *testing.T) {
+	messenger := &recordingMessenger{err: errors.New("channel_not_found")}
+	rr, _ := grantWithDeliverer(t, &slackDMDeliverer{messenger: messenger})
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "failed to deliver credentials") {
+		t.Errorf("Expected a delivery error, got %q", rr.Body.String())
+	}
+}
+
+func TestGrantAccessNoopDelivery(t *testing.T) {
+	rr, response := grantWithDeliverer(t, noopDeliverer{})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	if response.KubeConfig != "" || response.TemporaryCredentials.AccessKeyID != "" {
+		t.Error("Expected credentials to be left out of the response")
+	}
+	if response.CredentialDelivery != CredentialDeliveryNone {
+		t.Errorf("Expected credential_delivery %q, got %q", CredentialDeliveryNone, response.CredentialDelivery)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// EffectiveAccessResponse is what a user could be granted right now, for auditors
+type EffectiveAccessResponse struct {
+	UserID     string    `json:"user_id"`
+	Role       auth.Role `json:"role"`
+	CanRequest bool      `json:"can_request"`
+	CanApprove bool      `json:"can_approve"`
+	// Trusted users have their own requests approved without approvers, for any permission
+	Trusted bool `json:"trusted"`
+	// BreakGlass users get view-only emergency access immediately; approvers are notified
+	BreakGlass bool                     `json:"break_glass"`
+	Clusters   []ClusterEffectiveAccess `json:"clusters"`
+}
+
+// ClusterEffectiveAccess splits the requestable permissions on one cluster by whether a request
+// at the default duration would be approved without approvers
+type ClusterEffectiveAccess struct {
+	Cluster         string   `json:"cluster"`
+	Environment     string   `json:"environment"`
+	MaxDuration     string   `json:"max_duration"`
+	WithoutApproval []string `json:"without_approval"`
+	WithApproval    []string `json:"with_approval"`
+}
+
+// SetTrustedOperators records the Slack user IDs the operator trusts with its --trusted-operators
+// flag, so effective access reports match what the operator approves
+func (h *RequestHandler) SetTrustedOperators(users []string) {
+	h.trusted = make(map[string]bool, len(users))
+	for _, user := range users {
+		h.trusted[user] = true
+	}
+	h.policy.TrustedUsers = users
+}
+
+// EffectiveAccess reports, for every enabled cluster, which permissions the user could be granted
+// without approval and which need approvers. Users may query themselves; anyone else needs
+// audit access.
+func (h *RequestHandler) EffectiveAccess(w http.ResponseWriter, r *http.Request) {
+	callerID := r.Header.Get("X-Slack-User-Id")
+	if callerID == "" {
+		http.Error(w, "missing user ID", http.StatusUnauthorized)
+		return
+	}
+
+	userID := r.PathValue("id")
+	if userID == "" {
+		http.Error(w, "missing required field: id", http.StatusBadRequest)
+		return
+	}
+
+	if userID != callerID {
+		if err := h.rbac.ValidatePermission(callerID, auth.PermissionViewAuditLog); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	clusters, err := h.store.ListClusters()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+
+	canRequest := h.rbac.UserHasPermission(userID, auth.PermissionCreateRequests)
+	resp := EffectiveAccessResponse{
+		UserID:     userID,
+		Role:       h.rbac.GetUserRole(userID),
+		CanRequest: canRequest,
+		CanApprove: h.rbac.UserHasPermission(userID, auth.PermissionApproveRequests),
+		Trusted:    canRequest && h.trusted[userID],
+		BreakGlass: canRequest,
+		Clusters:   []ClusterEffectiveAccess{},
+	}
+
+	for _, cluster := range clusters {
+		if !cluster.Enabled {
+			continue
+		}
+
+		access := ClusterEffectiveAccess{
+			Cluster:         cluster.Name,
+			MaxDuration:     cluster.MaxDuration.String(),
+			WithoutApproval: []string{},
+			WithApproval:    []string{},
+		}
+		for _, permission := range h.mutator.PermissionPolicies.Names() {
+			preview, previewErr := h.mutator.PreviewRequest(controller.JITAccessRequestSpec{
+				UserID:        userID,
+				TargetCluster: controller.TargetCluster{Name: cluster.Name},
+				Permissions:   []string{permission},
+			})
+			if previewErr != nil {
+				http.Error(w, previewErr.Error(), http.StatusInternalServerError)
+				return
+			}
+			access.Environment = h.mutator.PreviewEnvironment(preview)
+
+			if !canRequest {
+				continue
+			}
+			// Requests filed through the bot are vouched for, and the cluster's own approval count
+			// stands in for the operator's cluster registry
+			preview.Annotations[controller.RequesterVerifiedAnnotation] = "true"
+			requireClusterApprovals(preview, cluster.RequiredApprovers)
+			if h.policy.ApprovedWithoutApprovals(r.Context(), preview) {
+				access.WithoutApproval = append(access.WithoutApproval, permission)
+			} else {
+				access.WithApproval = append(access.WithApproval, permission)
+			}
+		}
+		resp.Clusters = append(resp.Clusters, access)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if encodeErr := json.NewEncoder(w).Encode(resp); encodeErr != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// requireClusterApprovals raises the request's approval quorum to the cluster's required approvers
+func requireClusterApprovals(req *controller.JITAccessRequest, clusterApprovals int) {
+	if clusterApprovals <= 0 {
+		return
+	}
+	required, err := strconv.Atoi(req.Annotations[controller.RequiredApprovalsAnnotation])
+	if err != nil || required < clusterApprovals {
+		req.Annotations[controller.RequiredApprovalsAnnotation] = strconv.Itoa(clusterApprovals)
+	}
+}
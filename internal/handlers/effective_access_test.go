@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+func newEffectiveAccessHandler(t *testing.T) *RequestHandler {
+	t.Helper()
+
+	rbac := auth.NewRBAC([]string{"admin1"})
+	rbac.SetUserRole("approver1", auth.RoleApprover)
+
+	memStore := store.NewMemoryStore()
+	for _, cluster := range []*models.Cluster{
+		{ID: "dev-east-1", Name: "dev-east-1", MaxDuration: 4 * time.Hour, Enabled: true},
+		{ID: "prod-east-1", Name: "prod-east-1", MaxDuration: 2 * time.Hour, RequiredApprovers: 2, Enabled: true},
+		{ID: "prod-west-2", Name: "prod-west-2", MaxDuration: 2 * time.Hour},
+	} {
+		if err := memStore.CreateCluster(cluster); err != nil {
+			t.Fatalf("Failed to create cluster: %v", err)
+		}
+	}
+
+	handler := NewRequestHandler(rbac, memStore, nil)
+	handler.SetTrustedOperators([]string{"U0TRUSTED"})
+	return handler
+}
+
+func getEffectiveAccess(
+	t *testing.T, handler *RequestHandler, callerID, userID string,
+) (*httptest.ResponseRecorder, EffectiveAccessResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+userID+"/effective-access", nil)
+	req.SetPathValue("id", userID)
+	req.Header.Set("X-Slack-User-Id", callerID)
+	rr := httptest.NewRecorder()
+
+	handler.EffectiveAccess(rr, req)
+
+	var resp EffectiveAccessResponse
+	if rr.Code == http.StatusOK {
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return rr, resp
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEffectiveAccessRequester(t *testing.T) {
+	handler := newEffectiveAccessHandler(t)
+
+	rr, resp := getEffectiveAccess(t, handler, "U0REQUESTER", "U0REQUESTER")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	if resp.Role != auth.RoleRequester || !resp.CanRequest || resp.CanApprove || resp.Trusted || !resp.BreakGlass {
+		t.Errorf("Unexpected requester summary: %+v", resp)
+	}
+
+	// Disabled clusters are left out
+	if len(resp.Clusters) != 2 ||
+		resp.Clusters[0].Cluster != "dev-east-1" || resp.Clusters[1].Cluster != "prod-east-1" {
+		t.Fatalf("Expected dev-east-1 and prod-east-1, got %+v", resp.Clusters)
+	}
+
+	// Elevated permissions need an approval even on dev; read-only ones do not
+	dev := resp.Clusters[0]
+	if !containsString(dev.WithoutApproval, "logs") || !containsString(dev.WithApproval, "exec") {
+		t.Errorf("Expected logs without and exec with approval on dev, got %+v", dev)
+	}
+
+	prod := resp.Clusters[1]
+	if prod.Environment != "production" || prod.MaxDuration != "2h0m0s" {
+		t.Errorf("Unexpected cluster details: %+v", prod)
+	}
+	if got := strings.Join(prod.WithoutApproval, ","); got != "view" {
+		t.Errorf("Expected only view without approval, got %s", got)
+	}
+	for _, want := range []string{"admin", "cluster-admin", "edit", "exec"} {
+		if !containsString(prod.WithApproval, want) {
+			t.Errorf("Expected %s to need approval, got %v", want, prod.WithApproval)
+		}
+	}
+}
+
+func TestEffectiveAccessApprover(t *testing.T) {
+	handler := newEffectiveAccessHandler(t)
+
+	// Only auditors can look up other users
+	rr, _ := getEffectiveAccess(t, handler, "U0REQUESTER", "approver1")
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected requesters to be refused other users' access, got %d", rr.Code)
+	}
+
+	rr, resp := getEffectiveAccess(t, handler, "approver1", "approver1")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if resp.Role != auth.RoleApprover || !resp.CanApprove || resp.Trusted {
+		t.Errorf("Unexpected approver summary: %+v", resp)
+	}
+
+	// Approving others' requests grants no extra access of their own
+	for _, cluster := range resp.Clusters {
+		if cluster.Cluster == "prod-east-1" && strings.Join(cluster.WithoutApproval, ",") != "view" {
+			t.Errorf("Expected only view without approval on prod, got %v", cluster.WithoutApproval)
+		}
+	}
+}
+
+func TestEffectiveAccessTrustedUser(t *testing.T) {
+	handler := newEffectiveAccessHandler(t)
+
+	rr, resp := getEffectiveAccess(t, handler, "admin1", "U0TRUSTED")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !resp.Trusted {
+		t.Errorf("Expected U0TRUSTED to be trusted: %+v", resp)
+	}
+
+	for _, cluster := range resp.Clusters {
+		if len(cluster.WithApproval) != 0 {
+			t.Errorf("Expected nothing to need approval on %s, got %v", cluster.Cluster, cluster.WithApproval)
+		}
+		if !containsString(cluster.WithoutApproval, "cluster-admin") {
+			t.Errorf("Expected cluster-admin without approval on %s, got %v", cluster.Cluster, cluster.WithoutApproval)
+		}
+	}
+}
+
+func TestEffectiveAccessMissingCaller(t *testing.T) {
+	handler := newEffectiveAccessHandler(t)
+
+	rr, _ := getEffectiveAccess(t, handler, "", "U0REQUESTER")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// exportFlushInterval is how many CSV rows are written between flushes to the client
+const exportFlushInterval = 100
+
+var exportHeader = []string{
+	"access_id",
+	"user_id",
+	"user_email",
+	"cluster_id",
+	"permissions",
+	"status",
+	"reason",
+	"requested_at",
+	"granted_at",
+	"expires_at",
+	"revoked_at",
+	"revoked_by",
+}
+
+// ExportAccess streams access records requested within [from, to) as CSV for compliance reviews
+func (h *AccessHandler) ExportAccess(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-Slack-User-Id")
+	if userID == "" {
+		http.Error(w, "missing user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// Check permissions - only admins can export access records
+	if err := h.rbac.ValidatePermission(userID, auth.PermissionExportAccess); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "csv" {
+		http.Error(w, fmt.Sprintf("unsupported export format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseExportWindow(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accessList, err := h.store.ListClusterAccess()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sort.Slice(accessList, func(i, j int) bool {
+		return accessList[i].RequestedAt.Before(accessList[j].RequestedAt)
+	})
+
+	filename := fmt.Sprintf("access-%s-%s.csv", from.Format("20060102"), to.Format("20060102"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	flusher, _ := w.(http.Flusher)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportHeader); err != nil {
+		return
+	}
+
+	rows := 0
+	for _, access := range accessList {
+		if access.RequestedAt.Before(from) || !access.RequestedAt.Before(to) {
+			continue
+		}
+
+		if err := writer.Write(exportRow(access)); err != nil {
+			// The response has already started; the client sees a truncated file
+			return
+		}
+
+		rows++
+		if rows%exportFlushInterval == 0 {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+
+	writer.Flush()
+}
+
+func exportRow(access *models.ClusterAccess) []string {
+	row := []string{
+		access.ID,
+		access.UserID,
+		access.UserEmail,
+		access.ClusterID,
+		strings.Join(access.Permissions, ";"),
+		string(access.Status),
+		access.Reason,
+		access.RequestedAt.UTC().Format(time.RFC3339),
+		formatExportTime(access.GrantedAt),
+		formatExportTime(access.ExpiresAt),
+		formatExportTime(access.RevokedAt),
+		access.RevokedBy,
+	}
+	for i, cell := range row {
+		row[i] = csvCell(cell)
+	}
+	return row
+}
+
+// csvCell keeps spreadsheets from evaluating user-supplied text such as reasons as formulas: a
+// cell starting with =, +, -, @, tab or carriage return is prefixed with a single quote
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// parseExportWindow reads the [from, to) window of an export or report; to defaults to now
+func parseExportWindow(query url.Values) (time.Time, time.Time, error) {
+	from, err := parseExportTime(query.Get("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from parameter: %w", err)
+	}
+
+	to := time.Now()
+	if query.Get("to") != "" {
+		to, err = parseExportTime(query.Get("to"))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to parameter: %w", err)
+		}
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+// parseExportTime accepts RFC3339 timestamps or plain dates (YYYY-MM-DD, UTC midnight)
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("value is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 timestamp or YYYY-MM-DD date: %s", value)
+	}
+	return t, nil
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+func seedExportAccesses(t *testing.T, handler *AccessHandler) {
+	t.Helper()
+
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 10, 0, 0, 0, time.UTC) }
+	granted := day(5).Add(5 * time.Minute)
+	expired := day(5).Add(2 * time.Hour)
+	revoked := day(12).Add(30 * time.Minute)
+
+	accesses := []*models.ClusterAccess{
+		{
+			ID:          "access-early",
+			UserID:      "user-1",
+			UserEmail:   "user1@company.com",
+			ClusterID:   "cluster-1",
+			Permissions: []string{"view"},
+			Status:      models.AccessStatusExpired,
+			Reason:      "before the window",
+			RequestedAt: day(1),
+		},
+		{
+			ID:          "access-expired",
+			UserID:      "user-2",
+			UserEmail:   "user2@company.com",
+			ClusterID:   "cluster-1",
+			Permissions: []string{"view", "edit"},
+			Status:      models.AccessStatusExpired,
+			Reason:      "debugging, deploy",
+			RequestedAt: day(5),
+			GrantedAt:   &granted,
+			ExpiresAt:   &expired,
+		},
+		{
+			ID:          "access-revoked",
+			UserID:      "user-3",
+			UserEmail:   "user3@company.com",
+			ClusterID:   "cluster-2",
+			Permissions: []string{"cluster-admin"},
+			Status:      models.AccessStatusRevoked,
+			Reason:      "incident response",
+			RequestedAt: day(12),
+			RevokedAt:   &revoked,
+			RevokedBy:   "admin1",
+		},
+		{
+			ID:          "access-late",
+			UserID:      "user-4",
+			UserEmail:   "user4@company.com",
+			ClusterID:   "cluster-1",
+			Permissions: []string{"view"},
+			Status:      models.AccessStatusActive,
+			Reason:      "after the window",
+			RequestedAt: day(20),
+		},
+	}
+
+	for _, access := range accesses {
+		if err := handler.store.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", access.ID, err)
+		}
+	}
+}
+
+func TestExportAccessCSV(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	seedExportAccesses(t, handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/export?from=2024-03-02&to=2024-03-15&format=csv", nil)
+	req.Header.Set("X-Slack-User-Id", "admin1")
+	rr := httptest.NewRecorder()
+
+	handler.ExportAccess(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "text/csv" {
+		t.Errorf("Expected text/csv content type, got %s", contentType)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+
+	if strings.Join(records[0], ",") != strings.Join(exportHeader, ",") {
+		t.Errorf("Unexpected header: %v", records[0])
+	}
+
+	expired := records[1]
+	if expired[0] != "access-expired" || expired[1] != "user-2" || expired[3] != "cluster-1" {
+		t.Errorf("Unexpected first row: %v", expired)
+	}
+	if expired[4] != "view;edit" {
+		t.Errorf("Expected permissions view;edit, got %s", expired[4])
+	}
+	if expired[6] != "debugging, deploy" {
+		t.Errorf("Expected reason with comma preserved, got %s", expired[6])
+	}
+	if expired[8] != "2024-03-05T10:05:00Z" || expired[9] != "2024-03-05T12:00:00Z" {
+		t.Errorf("Unexpected granted/expires columns: %s, %s", expired[8], expired[9])
+	}
+
+	revoked := records[2]
+	if revoked[0] != "access-revoked" || revoked[5] != string(models.AccessStatusRevoked) {
+		t.Errorf("Unexpected second row: %v", revoked)
+	}
+	if revoked[10] != "2024-03-12T10:30:00Z" || revoked[11] != "admin1" {
+		t.Errorf("Unexpected revoked columns: %s, %s", revoked[10], revoked[11])
+	}
+}
+
+func TestCSVCellNeutralizesFormulas(t *testing.T) {
+	tests := map[string]string{
+		`=HYPERLINK("http://evil.example","x")`: `'=HYPERLINK("http://evil.example","x")`,
+		"+1+1":                                  "'+1+1",
+		"-2+3":                                  "'-2+3",
+		"@SUM(A1:A2)":                           "'@SUM(A1:A2)",
+		"\t=1":                                  "'\t=1",
+		"debugging, deploy":                     "debugging, deploy",
+		"user-2":                                "user-2",
+		"":                                      "",
+	}
+	for value, want := range tests {
+		if got := csvCell(value); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	row := exportRow(&models.ClusterAccess{ID: "access-1", Reason: "=1+1", RevokedBy: "@admin"})
+	if row[6] != "'=1+1" || row[11] != "'@admin" {
+		t.Errorf("Expected exported reason and revoker to be neutralized, got %q and %q", row[6], row[11])
+	}
+}
+
+func TestExportAccessUnauthorized(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.rbac.SetUserRole("approver1", auth.RoleApprover)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access/export?from=2024-03-01", nil)
+	req.Header.Set("X-Slack-User-Id", "approver1")
+	rr := httptest.NewRecorder()
+
+	handler.ExportAccess(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestExportAccessInvalidParameters(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing from", query: "to=2024-03-15"},
+		{name: "malformed from", query: "from=last-week"},
+		{name: "inverted window", query: "from=2024-03-15&to=2024-03-01"},
+		{name: "unsupported format", query: "from=2024-03-01&format=xlsx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/access/export?"+tt.query, nil)
+			req.Header.Set("X-Slack-User-Id", "admin1")
+			rr := httptest.NewRecorder()
+
+			handler.ExportAccess(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// ExtendAccessRequest extends an active access by Extension, e.g. "30m"
+type ExtendAccessRequest struct {
+	AccessID  string `json:"access_id"`
+	Extension string `json:"extension"`
+}
+
+// ExtendAccessResponse reports the new expiry of an extended access
+type ExtendAccessResponse struct {
+	AccessID  string    `json:"access_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ExtendAccess pushes back the expiry of an active access. Users extend their own access; extending
+// someone else's needs the approve permission. The whole session may not exceed the cluster's
+// MaxDuration, and extensions that would leave the access already expired are rejected.
+func (h *AccessHandler) ExtendAccess(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-Slack-User-Id")
+	if userID == "" {
+		http.Error(w, "missing user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req ExtendAccessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.AccessID == "" || req.Extension == "" {
+		http.Error(w, "missing required fields: access_id, extension", http.StatusBadRequest)
+		return
+	}
+
+	extension, err := time.ParseDuration(req.Extension)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid extension format: %s", req.Extension), http.StatusBadRequest)
+		return
+	}
+
+	clusterAccess, err := h.store.GetClusterAccess(req.AccessID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("access record not found: %s", req.AccessID), http.StatusNotFound)
+		return
+	}
+
+	permission := auth.PermissionCreateRequests
+	if clusterAccess.UserID != userID {
+		permission = auth.PermissionApproveRequests
+	}
+	if permErr := h.rbac.ValidatePermission(userID, permission); permErr != nil {
+		http.Error(w, permErr.Error(), http.StatusForbidden)
+		return
+	}
+
+	if clusterAccess.Status != models.AccessStatusActive {
+		http.Error(w, fmt.Sprintf("access %s is %s and cannot be extended", clusterAccess.ID, clusterAccess.Status),
+			http.StatusConflict)
+		return
+	}
+
+	cluster, err := h.store.GetCluster(clusterAccess.ClusterID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cluster not found: %s", clusterAccess.ClusterID), http.StatusNotFound)
+		return
+	}
+
+	now := time.Now()
+	expiresAt, err := clusterAccess.ExtendedExpiry(extension, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start := clusterAccess.RequestedAt
+	if clusterAccess.GrantedAt != nil {
+		start = *clusterAccess.GrantedAt
+	}
+	if cluster.MaxDuration > 0 && expiresAt.Sub(start) > cluster.MaxDuration {
+		http.Error(w, fmt.Sprintf("extended session of %s exceeds cluster limit %s",
+			expiresAt.Sub(start).Round(time.Second), cluster.MaxDuration), http.StatusBadRequest)
+		return
+	}
+
+	clusterAccess.ExpiresAt = &expiresAt
+	if updateErr := h.store.UpdateClusterAccess(clusterAccess); updateErr != nil {
+		http.Error(w, fmt.Sprintf("failed to update access record: %v", updateErr), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if encodeErr := json.NewEncoder(w).Encode(ExtendAccessResponse{
+		AccessID:  clusterAccess.ID,
+		ExpiresAt: expiresAt,
+	}); encodeErr != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+func extendAccessRequest(t *testing.T, userID string, body ExtendAccessRequest) *http.Request {
+	t.Helper()
+
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/access/extend", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-User-Id", userID)
+	return req
+}
+
+func createExtendableAccess(t *testing.T, memStore *store.MemoryStore, grantedAgo, expiresIn time.Duration) {
+	t.Helper()
+
+	grantedAt := time.Now().Add(-grantedAgo)
+	expiresAt := time.Now().Add(expiresIn)
+	access := &models.ClusterAccess{
+		ID:          "access-1",
+		UserID:      "U123456789A",
+		ClusterID:   "cluster-1",
+		Status:      models.AccessStatusActive,
+		RequestedAt: grantedAt,
+		GrantedAt:   &grantedAt,
+		ExpiresAt:   &expiresAt,
+	}
+	if err := memStore.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+}
+
+func TestExtendAccess(t *testing.T) {
+	handler, memStore, _ := newTestAccessHandler(t, 0)
+	createExtendableAccess(t, memStore, time.Hour, 30*time.Minute)
+
+	before := time.Now()
+	rr := httptest.NewRecorder()
+	handler.ExtendAccess(rr, extendAccessRequest(t, "U123456789A",
+		ExtendAccessRequest{AccessID: "access-1", Extension: "1h"}))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var response ExtendAccessResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if want := before.Add(90 * time.Minute); response.ExpiresAt.Sub(want).Abs() > time.Minute {
+		t.Errorf("Expected the access to expire around %s, got %s", want, response.ExpiresAt)
+	}
+
+	access, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if !access.ExpiresAt.Equal(response.ExpiresAt) {
+		t.Errorf("Expected the stored expiry %s to match the response %s", access.ExpiresAt, response.ExpiresAt)
+	}
+}
+
+func TestExtendAccessRejectsPastDatedExtension(t *testing.T) {
+	handler, memStore, _ := newTestAccessHandler(t, 0)
+	createExtendableAccess(t, memStore, 3*time.Hour, -2*time.Hour)
+
+	rr := httptest.NewRecorder()
+	handler.ExtendAccess(rr, extendAccessRequest(t, "U123456789A",
+		ExtendAccessRequest{AccessID: "access-1", Extension: "1h"}))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if !bytes.Contains(rr.Body.Bytes(), []byte("already in the past")) {
+		t.Errorf("Expected a past expiry message, got %q", rr.Body.String())
+	}
+
+	access, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if access.ExpiresAt.After(time.Now()) {
+		t.Errorf("Expected the expiry to be left unchanged, got %s", access.ExpiresAt)
+	}
+}
+
+func TestExtendAccessLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		extension string
+		wantCode  int
+	}{
+		{name: "beyond the cluster limit", userID: "U123456789A", extension: "3h", wantCode: http.StatusBadRequest},
+		{name: "invalid extension", userID: "U123456789A", extension: "soon", wantCode: http.StatusBadRequest},
+		{name: "negative extension", userID: "U123456789A", extension: "-1h", wantCode: http.StatusBadRequest},
+		{name: "another user's access", userID: "U999999999Z", extension: "1h", wantCode: http.StatusForbidden},
+		{name: "admin extends another user's access", userID: "admin1", extension: "1h", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, memStore, _ := newTestAccessHandler(t, 0)
+			createExtendableAccess(t, memStore, time.Hour, 30*time.Minute)
+
+			rr := httptest.NewRecorder()
+			handler.ExtendAccess(rr, extendAccessRequest(t, tt.userID,
+				ExtendAccessRequest{AccessID: "access-1", Extension: tt.extension}))
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
+)
+
+var (
+	errDownloadNotFound = errors.New("download link is invalid or has already been used")
+	errDownloadExpired  = errors.New("download link has expired")
+)
+
+// kubeconfigDownloadType labels the Secrets that hold kubeconfigs awaiting download
+const kubeconfigDownloadType = "kubeconfig-download"
+
+// kubeconfigDownloads holds kubeconfigs behind one-time, short-lived tokens so they never
+// appear inline in API responses or Slack messages. Each kubeconfig is kept in a Secret named
+// after its hashed token, so links survive restarts and work from any replica.
+type kubeconfigDownloads struct {
+	client    k8sclient.Interface
+	namespace string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+func newKubeconfigDownloads(client k8sclient.Interface, namespace string, ttl time.Duration) *kubeconfigDownloads {
+	return &kubeconfigDownloads{
+		client:    client,
+		namespace: namespace,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+// issue stores the kubeconfig and returns the token that releases it once, before expiresAt
+func (d *kubeconfigDownloads) issue(
+	ctx context.Context, accessID, kubeConfig string,
+) (token string, expiresAt time.Time, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate download token: %w", err)
+	}
+	token = hex.EncodeToString(raw)
+
+	now := d.now()
+	d.deleteExpired(ctx, now)
+
+	expiresAt = now.Add(d.ttl)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubeconfigDownloadSecretName(token),
+			Namespace: d.namespace,
+			Labels:    map[string]string{"jit.rebelops.io/type": kubeconfigDownloadType},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"access-id":  []byte(accessID),
+			"kubeconfig": []byte(kubeConfig),
+			"expires-at": []byte(expiresAt.Format(time.RFC3339Nano)),
+		},
+	}
+	if _, err := d.client.CoreV1().Secrets(d.namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store kubeconfig download: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// redeem returns the kubeconfig for a valid token and invalidates the token. The Secret is
+// deleted with a UID precondition so that of two concurrent redemptions only one succeeds.
+func (d *kubeconfigDownloads) redeem(ctx context.Context, accessID, token string) (string, error) {
+	secrets := d.client.CoreV1().Secrets(d.namespace)
+	secret, err := secrets.Get(ctx, kubeconfigDownloadSecretName(token), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", errDownloadNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up kubeconfig download: %w", err)
+	}
+	if string(secret.Data["access-id"]) != accessID {
+		return "", errDownloadNotFound
+	}
+
+	once := metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(secret.UID))}
+	err = secrets.Delete(ctx, secret.Name, once)
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return "", errDownloadNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to invalidate kubeconfig download: %w", err)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339Nano, string(secret.Data["expires-at"]))
+	if err != nil || d.now().After(expiresAt) {
+		return "", errDownloadExpired
+	}
+	return string(secret.Data["kubeconfig"]), nil
+}
+
+// deleteExpired removes downloads whose links have lapsed unredeemed. Failures are logged; an
+// expired Secret left behind is still refused on redemption.
+func (d *kubeconfigDownloads) deleteExpired(ctx context.Context, now time.Time) {
+	secrets := d.client.CoreV1().Secrets(d.namespace)
+	list, err := secrets.List(ctx, metav1.ListOptions{LabelSelector: "jit.rebelops.io/type=" + kubeconfigDownloadType})
+	if err != nil {
+		slog.Error("Failed to list kubeconfig downloads", "error", err)
+		return
+	}
+	for _, secret := range list.Items {
+		expiresAt, err := time.Parse(time.RFC3339Nano, string(secret.Data["expires-at"]))
+		if err == nil && !now.After(expiresAt) {
+			continue
+		}
+		if err := secrets.Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			slog.Error("Failed to delete expired kubeconfig download", "secret", secret.Name, "error", err)
+		}
+	}
+}
+
+// kubeconfigDownloadSecretName derives the Secret name from the token's hash, so the token itself
+// is never stored
+func kubeconfigDownloadSecretName(token string) string {
+	return "jit-kubeconfig-download-" + hashToken(token)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// kubeconfigDownloadURL is where a download token is redeemed
+func kubeconfigDownloadURL(accessID, token string) string {
+	return fmt.Sprintf("/api/v1/access/%s/kubeconfig?token=%s", url.PathEscape(accessID), url.QueryEscape(token))
+}
+
+// EnableKubeconfigDownloads stops returning kubeconfigs and temporary credentials inline from
+// GrantAccess; the response carries a one-time download URL valid for ttl instead, and the
+// kubeconfig, which embeds the credentials, is held in a Secret in namespace until redeemed. A zero
+// ttl keeps credentials inline.
+func (h *AccessHandler) EnableKubeconfigDownloads(client k8sclient.Interface, namespace string, ttl time.Duration) {
+	if ttl <= 0 {
+		h.downloads = nil
+		return
+	}
+	h.downloads = newKubeconfigDownloads(client, namespace, ttl)
+}
+
+// DownloadKubeconfig redeems a one-time download token. The token authorizes the download, so
+// no user header is required; a second or late redemption is rejected.
+func (h *AccessHandler) DownloadKubeconfig(w http.ResponseWriter, r *http.Request) {
+	if h.downloads == nil {
+		http.Error(w, "kubeconfig downloads are not enabled", http.StatusNotFound)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "missing token parameter", http.StatusBadRequest)
+		return
+	}
+
+	kubeConfig, err := h.downloads.redeem(r.Context(), r.PathValue("id"), token)
+	switch {
+	case errors.Is(err, errDownloadExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	case errors.Is(err, errDownloadNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", `attachment; filename="kubeconfig"`)
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte(kubeConfig))
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func grantWithDownloadLink(t *testing.T, handler *AccessHandler, userID string) AccessResponse {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, userID))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var response AccessResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.KubeConfig != "" {
+		t.Errorf("Expected no inline kubeconfig, got %q", response.KubeConfig)
+	}
+	if response.TemporaryCredentials.AccessKeyID != "" {
+		t.Errorf("Expected no inline credentials, got access key %q", response.TemporaryCredentials.AccessKeyID)
+	}
+	if !strings.HasPrefix(response.KubeConfigURL, "/api/v1/access/"+response.AccessID+"/kubeconfig?token=") {
+		t.Fatalf("Expected a download URL for the access, got %q", response.KubeConfigURL)
+	}
+	return response
+}
+
+func redeemDownload(handler *AccessHandler, url string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/access/{id}/kubeconfig", handler.DownloadKubeconfig)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+	return rr
+}
+
+func downloadSecrets(t *testing.T, handler *AccessHandler) []corev1.Secret {
+	t.Helper()
+
+	secrets := handler.downloads.client.CoreV1().Secrets("jit-system")
+	list, err := secrets.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list secrets: %v", err)
+	}
+	return list.Items
+}
+
+func TestKubeconfigDownloadIsHeldInSecret(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.EnableKubeconfigDownloads(fake.NewClientset(), "jit-system", 5*time.Minute)
+
+	response := grantWithDownloadLink(t, handler, "download-user")
+
+	secrets := downloadSecrets(t, handler)
+	if len(secrets) != 1 {
+		t.Fatalf("Expected one secret holding the download, got %d", len(secrets))
+	}
+	token := response.KubeConfigURL[strings.Index(response.KubeConfigURL, "token=")+len("token="):]
+	if strings.Contains(secrets[0].Name, token) {
+		t.Error("Expected the secret name not to reveal the download token")
+	}
+	if got := string(secrets[0].Data["kubeconfig"]); got != "kubeconfig-download-user" {
+		t.Errorf("Expected the secret to hold the granted kubeconfig, got %q", got)
+	}
+
+	// An expired, unredeemed download is swept when the next one is issued
+	handler.downloads.now = func() time.Time { return time.Now().Add(6 * time.Minute) }
+	grantWithDownloadLink(t, handler, "other-download-user")
+	if secrets := downloadSecrets(t, handler); len(secrets) != 1 {
+		t.Errorf("Expected the expired download to be swept, %d secrets remain", len(secrets))
+	}
+}
+
+func TestKubeconfigDownloadRedeemsOnce(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.EnableKubeconfigDownloads(fake.NewClientset(), "jit-system", 5*time.Minute)
+
+	response := grantWithDownloadLink(t, handler, "download-user")
+
+	rr := redeemDownload(handler, response.KubeConfigURL)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if rr.Body.String() != "kubeconfig-download-user" {
+		t.Errorf("Expected the granted kubeconfig, got %q", rr.Body.String())
+	}
+	if rr.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected the kubeconfig not to be cached, got %q", rr.Header().Get("Cache-Control"))
+	}
+	if secrets := downloadSecrets(t, handler); len(secrets) != 0 {
+		t.Errorf("Expected the redeemed download's secret to be deleted, %d remain", len(secrets))
+	}
+
+	// Replaying the link is rejected
+	rr = redeemDownload(handler, response.KubeConfigURL)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d on replay, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestKubeconfigDownloadExpired(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.EnableKubeconfigDownloads(fake.NewClientset(), "jit-system", 5*time.Minute)
+
+	response := grantWithDownloadLink(t, handler, "download-user")
+
+	handler.downloads.now = func() time.Time { return time.Now().Add(6 * time.Minute) }
+	rr := redeemDownload(handler, response.KubeConfigURL)
+	if rr.Code != http.StatusGone {
+		t.Errorf("Expected status %d for an expired link, got %d", http.StatusGone, rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "kubeconfig-download-user") {
+		t.Error("Expected the kubeconfig not to be returned for an expired link")
+	}
+}
+
+func TestKubeconfigDownloadRejectsWrongAccessAndToken(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+	handler.EnableKubeconfigDownloads(fake.NewClientset(), "jit-system", 5*time.Minute)
+
+	response := grantWithDownloadLink(t, handler, "download-user")
+	token := response.KubeConfigURL[strings.Index(response.KubeConfigURL, "token=")+len("token="):]
+	path := "/api/v1/access/" + response.AccessID + "/kubeconfig"
+
+	tests := []struct {
+		name string
+		url  string
+		want int
+	}{
+		{name: "missing token", url: path, want: http.StatusBadRequest},
+		{name: "wrong token", url: path + "?token=guess", want: http.StatusNotFound},
+		{name: "other access", url: "/api/v1/access/other/kubeconfig?token=" + token, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := redeemDownload(handler, tt.url); rr.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rr.Code)
+			}
+		})
+	}
+}
+
+func TestGrantAccessReturnsKubeconfigInlineByDefault(t *testing.T) {
+	handler, _, _ := newTestAccessHandler(t, 0)
+
+	rr := httptest.NewRecorder()
+	handler.GrantAccess(rr, grantAccessRequest(t, "inline-user"))
+
+	var response AccessResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.KubeConfig != "kubeconfig-inline-user" || response.KubeConfigURL != "" {
+		t.Errorf("Expected an inline kubeconfig, got %q and URL %q", response.KubeConfig, response.KubeConfigURL)
+	}
+}
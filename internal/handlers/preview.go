@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+	"github.com/rebelopsio/jit-bot/pkg/webhook"
+)
+
+// PreviewApproversRequest describes a hypothetical access request
+type PreviewApproversRequest struct {
+	Cluster     string   `json:"cluster"`
+	Permissions []string `json:"permissions"`
+	Namespaces  []string `json:"namespaces"`
+	Duration    string   `json:"duration"`
+}
+
+// RequestHandler serves read-only helpers for filing access requests
+type RequestHandler struct {
+	rbac    *auth.RBAC
+	store   *store.MemoryStore
+	mutator *webhook.JITAccessRequestMutator
+	policy  *controller.JITAccessRequestReconciler
+	trusted map[string]bool
+}
+
+// NewRequestHandler creates a request handler that previews approvers using the default approval
+// policy plus the given namespace owners
+func NewRequestHandler(
+	rbac *auth.RBAC, memStore *store.MemoryStore, namespaceApprovers map[string][]string,
+) *RequestHandler {
+	return &RequestHandler{
+		rbac:  rbac,
+		store: memStore,
+		mutator: &webhook.JITAccessRequestMutator{
+			NamespaceApprovers: namespaceApprovers,
+		},
+		policy: &controller.JITAccessRequestReconciler{RBAC: rbac},
+	}
+}
+
+// PreviewApprovers reports which approvers a request would require without creating it
+func (h *RequestHandler) PreviewApprovers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-Slack-User-Id")
+	if userID == "" {
+		http.Error(w, "missing user ID", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.rbac.ValidatePermission(userID, auth.PermissionCreateRequests); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var req PreviewApproversRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Cluster == "" {
+		http.Error(w, "missing required field: cluster", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.mutator.PreviewApprovers(controller.JITAccessRequestSpec{
+		UserID:        userID,
+		TargetCluster: controller.TargetCluster{Name: req.Cluster},
+		Permissions:   req.Permissions,
+		Namespaces:    req.Namespaces,
+		Duration:      req.Duration,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if encodeErr := json.NewEncoder(w).Encode(preview); encodeErr != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+	"github.com/rebelopsio/jit-bot/pkg/webhook"
+)
+
+func TestPreviewApprovers(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	rbac.SetUserRole("requester1", auth.RoleRequester)
+	handler := NewRequestHandler(rbac, store.NewMemoryStore(), nil)
+
+	body, _ := json.Marshal(PreviewApproversRequest{
+		Cluster:     "prod-east-1",
+		Permissions: []string{"exec"},
+		Duration:    "4h",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/requests/preview-approvers", bytes.NewReader(body))
+	req.Header.Set("X-Slack-User-Id", "requester1")
+	rr := httptest.NewRecorder()
+
+	handler.PreviewApprovers(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var preview webhook.ApproverPreview
+	if err := json.NewDecoder(rr.Body).Decode(&preview); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := "platform-team,security-team,sre-team"
+	if got := strings.Join(preview.Approvers, ","); got != expected {
+		t.Errorf("Expected approvers %s, got %s", expected, got)
+	}
+	if preview.Environment != "production" {
+		t.Errorf("Expected production environment, got %s", preview.Environment)
+	}
+	if preview.RequiredApprovals != 3 {
+		t.Errorf("Expected 3 required approvals, got %d", preview.RequiredApprovals)
+	}
+}
+
+func TestPreviewApproversInvalidRequest(t *testing.T) {
+	handler := NewRequestHandler(auth.NewRBAC([]string{"admin1"}), store.NewMemoryStore(), nil)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed body", body: "{"},
+		{name: "missing cluster", body: `{"permissions":["view"]}`},
+		{name: "invalid duration", body: `{"cluster":"prod-east-1","duration":"forever"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/requests/preview-approvers", strings.NewReader(tt.body))
+			req.Header.Set("X-Slack-User-Id", "admin1")
+			rr := httptest.NewRecorder()
+
+			handler.PreviewApprovers(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+		})
+	}
+}
+
+func TestPreviewApproversMissingUser(t *testing.T) {
+	handler := NewRequestHandler(auth.NewRBAC([]string{"admin1"}), store.NewMemoryStore(), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/requests/preview-approvers", strings.NewReader(`{}`))
+	rr := httptest.NewRecorder()
+
+	handler.PreviewApprovers(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errRevokeAwaitingSecondAdmin = errors.New("revocation is awaiting confirmation by a different admin")
+
+// pendingRevoke is an admin's revocation of another user's access awaiting a second admin
+type pendingRevoke struct {
+	requestedBy string
+	expiresAt   time.Time
+}
+
+// revokeConfirmations enforces a two-person rule on admins revoking other users' access: the
+// first admin's revoke is held pending, and only a different admin confirming it within the
+// window carries it out, so no single admin can quietly cut off someone's session
+type revokeConfirmations struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time
+	pending map[string]*pendingRevoke
+}
+
+func newRevokeConfirmations(window time.Duration) *revokeConfirmations {
+	return &revokeConfirmations{
+		window:  window,
+		now:     time.Now,
+		pending: make(map[string]*pendingRevoke),
+	}
+}
+
+// confirm records admin's revoke of accessID. It returns the admin who requested the revoke
+// when admin is a second, different admin confirming it in time; otherwise the revoke is left
+// pending and firstAdmin is empty. Repeating your own pending revoke is an error.
+func (c *revokeConfirmations) confirm(accessID, admin string) (firstAdmin string, expiresAt time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, revoke := range c.pending {
+		if now.After(revoke.expiresAt) {
+			delete(c.pending, key)
+		}
+	}
+
+	if revoke, ok := c.pending[accessID]; ok {
+		if revoke.requestedBy == admin {
+			return "", revoke.expiresAt, errRevokeAwaitingSecondAdmin
+		}
+		delete(c.pending, accessID)
+		return revoke.requestedBy, revoke.expiresAt, nil
+	}
+
+	expiresAt = now.Add(c.window)
+	c.pending[accessID] = &pendingRevoke{requestedBy: admin, expiresAt: expiresAt}
+	return "", expiresAt, nil
+}
+
+// RequireRevokeConfirmation makes an admin's revocation of another user's access wait for a
+// second admin to confirm it within window. Users revoking their own access are unaffected.
+// A zero window carries out admin revocations immediately.
+func (h *AccessHandler) RequireRevokeConfirmation(window time.Duration) {
+	if window <= 0 {
+		h.revokeConfirmations = nil
+		return
+	}
+	h.revokeConfirmations = newRevokeConfirmations(window)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+func newTwoPersonRevokeHandler(t *testing.T) (*AccessHandler, *store.MemoryStore, *fakeProvisioner) {
+	t.Helper()
+
+	handler, memStore, provisioner := newTestAccessHandler(t, 0)
+	handler.rbac.SetUserRole("admin2", auth.RoleAdmin)
+	handler.RequireRevokeConfirmation(10 * time.Minute)
+
+	access := &models.ClusterAccess{
+		ID:        "access-1",
+		ClusterID: "cluster-1",
+		UserID:    "U0REQUESTER",
+		Status:    models.AccessStatusActive,
+	}
+	if err := memStore.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+	return handler, memStore, provisioner
+}
+
+func revokeAs(t *testing.T, handler *AccessHandler, userID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	handler.RevokeAccess(rr, revokeAccessRequest(t, userID, RevokeAccessRequest{AccessID: "access-1"}))
+	return rr
+}
+
+func TestRevokeAccessSingleAdminIsPending(t *testing.T) {
+	handler, memStore, provisioner := newTwoPersonRevokeHandler(t)
+
+	rr := revokeAs(t, handler, "admin1")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+
+	var pending PendingRevokeResponse
+	if err := json.NewDecoder(rr.Body).Decode(&pending); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if pending.Status != "pending_confirmation" || pending.RequestedBy != "admin1" || pending.ExpiresAt.IsZero() {
+		t.Errorf("Unexpected pending revoke: %+v", pending)
+	}
+
+	// The same admin cannot confirm their own revoke
+	rr = revokeAs(t, handler, "admin1")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for a repeated revoke, got %d", http.StatusConflict, rr.Code)
+	}
+
+	access, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if access.Status != models.AccessStatusActive {
+		t.Errorf("Expected access to stay active, got %s", access.Status)
+	}
+	if provisioner.revokes != 0 {
+		t.Errorf("Expected no revocation, got %d", provisioner.revokes)
+	}
+}
+
+func TestRevokeAccessSecondAdminConfirms(t *testing.T) {
+	handler, memStore, provisioner := newTwoPersonRevokeHandler(t)
+
+	if rr := revokeAs(t, handler, "admin1"); rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+
+	rr := revokeAs(t, handler, "admin2")
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+
+	access, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if access.Status != models.AccessStatusRevoked {
+		t.Errorf("Expected access to be revoked, got %s", access.Status)
+	}
+	if access.RevokedBy != "admin1,admin2" {
+		t.Errorf("Expected both admins recorded as revokers, got %q", access.RevokedBy)
+	}
+	if provisioner.revokes != 1 {
+		t.Errorf("Expected 1 revocation, got %d", provisioner.revokes)
+	}
+}
+
+func TestRevokeAccessConfirmationExpires(t *testing.T) {
+	handler, memStore, provisioner := newTwoPersonRevokeHandler(t)
+
+	now := time.Now()
+	handler.revokeConfirmations.now = func() time.Time { return now }
+
+	if rr := revokeAs(t, handler, "admin1"); rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+
+	// A confirmation after the window starts a new pending revoke instead
+	now = now.Add(11 * time.Minute)
+	if rr := revokeAs(t, handler, "admin2"); rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d after the window, got %d: %s", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+
+	access, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if access.Status != models.AccessStatusActive || provisioner.revokes != 0 {
+		t.Errorf("Expected access to stay active, got %s with %d revocations", access.Status, provisioner.revokes)
+	}
+}
+
+func TestRevokeOwnAccessNeedsNoConfirmation(t *testing.T) {
+	handler, memStore, _ := newTwoPersonRevokeHandler(t)
+
+	rr := revokeAs(t, handler, "U0REQUESTER")
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+
+	access, err := memStore.GetAccess("access-1")
+	if err != nil {
+		t.Fatalf("Failed to get access: %v", err)
+	}
+	if access.Status != models.AccessStatusRevoked {
+		t.Errorf("Expected access to be revoked, got %s", access.Status)
+	}
+}
@@ -5,8 +5,15 @@ import (
 	"log/slog"
 	"net/http"
 
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+
 	"github.com/rebelopsio/jit-bot/internal/config"
 	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
 	"github.com/rebelopsio/jit-bot/pkg/slack"
 	"github.com/rebelopsio/jit-bot/pkg/store"
 )
@@ -18,10 +25,22 @@ func NewRouter(cfg *config.Config) (http.Handler, error) {
 	for _, approver := range cfg.Auth.Approvers {
 		rbac.SetUserRole(approver, auth.RoleApprover)
 	}
+	rbac.EnableRoleCache(cfg.Auth.RoleCacheTTL)
 
 	memStore := store.NewMemoryStore()
 	slackMiddleware := slack.NewSlackMiddleware(cfg.Slack.SigningSecret)
 	commandHandler := slack.NewCommandHandler(rbac, memStore)
+	commandHandler.SetResponseTypes(cfg.Slack.ResponseTypes)
+	var operatorRequests client.Client
+	if cfg.Slack.RequestNamespace != "" {
+		requestClient, clientErr := requestClient()
+		if clientErr != nil {
+			return nil, fmt.Errorf("/jit creds needs a Kubernetes client: %w", clientErr)
+		}
+		operatorRequests = requestClient
+		credsHandler := slack.NewK8sCommandHandler(requestClient, rbac, cfg.Slack.RequestNamespace)
+		commandHandler.SetCredentialsHandler(credsHandler)
+	}
 
 	h := &Handler{
 		config: cfg,
@@ -30,17 +49,45 @@ func NewRouter(cfg *config.Config) (http.Handler, error) {
 	}
 
 	adminHandler := NewAdminHandler(rbac, memStore)
+	requestHandler := NewRequestHandler(rbac, memStore, cfg.Access.NamespaceApprovers)
+	requestHandler.SetTrustedOperators(cfg.Auth.TrustedOperators)
 
-	accessHandler, err := NewAccessHandler(rbac, memStore, cfg.AWS.Region)
+	// Clusters outside the allowed regions are refused by the access manager
+	accessHandler, err := NewAccessHandler(
+		rbac, memStore, cfg.AWS.Region, cfg.AWS.AllowedRegions, cfg.Access.MaxActiveSessions,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create access handler: %w", err)
 	}
+	if cfg.Access.KubeconfigDownloadTTL > 0 {
+		kubeClient, clientErr := kubernetesClient()
+		if clientErr != nil {
+			return nil, fmt.Errorf("kubeconfig downloads need a Kubernetes client: %w", clientErr)
+		}
+		accessHandler.EnableKubeconfigDownloads(
+			kubeClient, cfg.Access.KubeconfigDownloadNamespace, cfg.Access.KubeconfigDownloadTTL)
+	}
+	accessHandler.RequireRevokeConfirmation(cfg.Access.RevokeConfirmationWindow)
+
+	deliverer, err := NewCredentialDeliverer(cfg.Access.CredentialDelivery, cfg.Slack.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure credential delivery: %w", err)
+	}
+	accessHandler.SetCredentialDeliverer(deliverer)
+
+	eventHandler, err := NewSlackEventHandler(memStore, cfg.AWS.Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create slack event handler: %w", err)
+	}
+	if operatorRequests != nil {
+		eventHandler.SetRequestClient(operatorRequests, cfg.Slack.RequestNamespace)
+	}
 
 	mux.HandleFunc("/health", h.Health)
 	mux.HandleFunc("/ready", h.Ready)
 
 	slackMux := http.NewServeMux()
-	slackMux.HandleFunc("/slack/events", h.SlackEvents)
+	slackMux.HandleFunc("/slack/events", eventHandler.HandleEvent)
 	slackMux.HandleFunc("/slack/commands", commandHandler.HandleJITCommand)
 
 	mux.Handle("/slack/", slackMiddleware.VerifyRequest(slackMux))
@@ -60,8 +107,32 @@ func NewRouter(cfg *config.Config) (http.Handler, error) {
 		}
 	})
 
+	mux.HandleFunc("/api/v1/clusters/bulk", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		adminHandler.BulkCreateClusters(w, r)
+	})
+
 	mux.HandleFunc("/api/v1/users/role", adminHandler.ManageUser)
 
+	mux.HandleFunc("/api/v1/users/{id}/effective-access", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		requestHandler.EffectiveAccess(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/requests/preview-approvers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		requestHandler.PreviewApprovers(w, r)
+	})
+
 	// Access management endpoints
 	mux.HandleFunc("/api/v1/access/grant", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -79,6 +150,22 @@ func NewRouter(cfg *config.Config) (http.Handler, error) {
 		accessHandler.RevokeAccess(w, r)
 	})
 
+	mux.HandleFunc("/api/v1/access/extend", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		accessHandler.ExtendAccess(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/access/{id}/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		accessHandler.DownloadKubeconfig(w, r)
+	})
+
 	mux.HandleFunc("/api/v1/access", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -95,6 +182,22 @@ func NewRouter(cfg *config.Config) (http.Handler, error) {
 		accessHandler.GetAccessStatus(w, r)
 	})
 
+	mux.HandleFunc("/api/v1/access/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		accessHandler.ExportAccess(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/reports/access", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		accessHandler.AccessReport(w, r)
+	})
+
 	mux.HandleFunc("/api/v1/access/cleanup", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -128,10 +231,31 @@ func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *Handler) SlackEvents(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SlackCommands(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *Handler) SlackCommands(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+// kubernetesClient connects to the cluster the server runs in, or the one in KUBECONFIG
+func kubernetesClient() (k8sclient.Interface, error) {
+	restConfig, err := ctrlconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return k8sclient.NewForConfig(restConfig)
+}
+
+// requestClient reads the operator's JITAccessRequests, jobs and secrets
+func requestClient() (client.Client, error) {
+	restConfig, err := ctrlconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := controller.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{Scheme: scheme})
 }
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+// DeactivatedRevoker is recorded as RevokedBy on access revoked because the Slack user was deactivated
+const DeactivatedRevoker = "slack:user-deactivated"
+
+// userAccessCleaner removes all of a user's cluster access entries; implemented by kubernetes.CleanupService
+type userAccessCleaner interface {
+	CleanupUserAccess(ctx context.Context, userID string) error
+}
+
+// SlackEventHandler handles Slack Events API callbacks. Requests are expected to have passed
+// slack.SlackMiddleware signature verification.
+type SlackEventHandler struct {
+	store   *store.MemoryStore
+	cleaner userAccessCleaner
+
+	// requests and namespace reach the operator's JITAccessRequests; nil leaves them alone
+	requests  client.Client
+	namespace string
+}
+
+type slackEventEnvelope struct {
+	Type      string          `json:"type"`
+	Challenge string          `json:"challenge,omitempty"`
+	Event     json.RawMessage `json:"event,omitempty"`
+}
+
+type slackUserChangeEvent struct {
+	Type string `json:"type"`
+	User struct {
+		ID      string `json:"id"`
+		Deleted bool   `json:"deleted"`
+	} `json:"user"`
+}
+
+func NewSlackEventHandler(store *store.MemoryStore, region string) (*SlackEventHandler, error) {
+	cleanupService, err := kubernetes.NewCleanupService(region, store, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cleanup service: %w", err)
+	}
+
+	return &SlackEventHandler{
+		store:   store,
+		cleaner: cleanupService,
+	}, nil
+}
+
+// SetRequestClient has offboarding also end the user's JITAccessRequests in the namespace where
+// the operator keeps them. The server then needs to list requests and update their status.
+func (h *SlackEventHandler) SetRequestClient(requests client.Client, namespace string) {
+	h.requests = requests
+	h.namespace = namespace
+}
+
+// HandleEvent answers URL verification challenges and revokes the access of users who are
+// deactivated in Slack (a user_change event with deleted set)
+func (h *SlackEventHandler) HandleEvent(w http.ResponseWriter, r *http.Request) {
+	var envelope slackEventEnvelope
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		http.Error(w, "invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	switch envelope.Type {
+	case "url_verification":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(envelope.Challenge))
+		return
+	case "event_callback":
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var event slackUserChangeEvent
+	if err := json.Unmarshal(envelope.Event, &event); err != nil {
+		http.Error(w, "invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	if event.Type != "user_change" || !event.User.Deleted || event.User.ID == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// A failure is returned to Slack so the event is retried
+	if err := h.offboardUser(r.Context(), event.User.ID); err != nil {
+		http.Error(w, fmt.Sprintf("failed to revoke access: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// offboardUser ends the user's operator requests, removes their access entries from every
+// cluster, marks their active access revoked, and denies their outstanding requests. Every step is
+// attempted; the errors are returned together so the event is retried.
+func (h *SlackEventHandler) offboardUser(ctx context.Context, userID string) error {
+	var errs []error
+	if err := h.endUserRequests(ctx, userID); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Records stay active until their access entries are gone, so a retry finds them again
+	if err := h.cleaner.CleanupUserAccess(ctx, userID); err != nil {
+		errs = append(errs, fmt.Errorf("failed to clean up access entries: %w", err))
+		return errors.Join(errs...)
+	}
+
+	accesses, err := h.store.ListUserAccesses(userID)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list user accesses: %w", err))
+		return errors.Join(errs...)
+	}
+
+	now := time.Now()
+	revoked, denied := 0, 0
+	for _, access := range accesses {
+		switch access.Status {
+		case models.AccessStatusActive:
+			access.Status = models.AccessStatusRevoked
+			access.RevokedAt = &now
+			access.RevokedBy = DeactivatedRevoker
+			revoked++
+		case models.AccessStatusPending, models.AccessStatusApproved:
+			access.Status = models.AccessStatusDenied
+			denied++
+		default:
+			continue
+		}
+
+		if err := h.store.UpdateClusterAccess(access); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update access %s: %w", access.ID, err))
+		}
+	}
+
+	slog.Info("Revoked access for deactivated Slack user", "user", userID, "revoked", revoked, "denied", denied)
+	return errors.Join(errs...)
+}
+
+// endUserRequests revokes the user's active JITAccessRequests, whose jobs then remove the access
+// entries, and denies the ones not provisioned yet. Requests filed on the user's behalf count too.
+func (h *SlackEventHandler) endUserRequests(ctx context.Context, userID string) error {
+	if h.requests == nil {
+		return nil
+	}
+
+	var requests controller.JITAccessRequestList
+	if err := h.requests.List(ctx, &requests, client.InNamespace(h.namespace)); err != nil {
+		return fmt.Errorf("failed to list access requests: %w", err)
+	}
+
+	var errs []error
+	revoked, denied := 0, 0
+	for i := range requests.Items {
+		jitReq := &requests.Items[i]
+		if !grantsUser(jitReq, userID) {
+			continue
+		}
+
+		switch jitReq.Status.Phase {
+		case controller.AccessPhaseActive:
+			jitReq.Status.Phase = controller.AccessPhaseRevoked
+			jitReq.Status.Message = "Access revoked because the user was deactivated in Slack"
+			meta.SetStatusCondition(&jitReq.Status.Conditions, metav1.Condition{
+				Type:    "Revoked",
+				Status:  metav1.ConditionTrue,
+				Reason:  "UserDeactivated",
+				Message: "User was deactivated in Slack",
+			})
+			revoked++
+		case "", controller.AccessPhasePending, controller.AccessPhaseApproved, controller.AccessPhaseScheduled:
+			jitReq.Status.Phase = controller.AccessPhaseDenied
+			jitReq.Status.Message = "Request denied because the user was deactivated in Slack"
+			denied++
+		default:
+			continue
+		}
+
+		if err := h.requests.Status().Update(ctx, jitReq); err != nil {
+			errs = append(errs, fmt.Errorf("failed to end access request %s: %w", jitReq.Name, err))
+		}
+	}
+
+	slog.Info("Ended access requests of deactivated Slack user", "user", userID, "revoked", revoked, "denied", denied)
+	return errors.Join(errs...)
+}
+
+// grantsUser reports whether the request was filed by or grants access to the user
+func grantsUser(jitReq *controller.JITAccessRequest, userID string) bool {
+	if jitReq.Spec.UserID == userID {
+		return true
+	}
+	return jitReq.Spec.OnBehalfOf != nil && jitReq.Spec.OnBehalfOf.UserID == userID
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/slack"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+const testSigningSecret = "test-signing-secret"
+
+type fakeUserCleaner struct {
+	users []string
+	err   error
+}
+
+func (f *fakeUserCleaner) CleanupUserAccess(_ context.Context, userID string) error {
+	f.users = append(f.users, userID)
+	return f.err
+}
+
+func newTestSlackEventHandler(t *testing.T) (http.Handler, *store.MemoryStore, *fakeUserCleaner) {
+	t.Helper()
+
+	memStore := store.NewMemoryStore()
+	accesses := []*models.ClusterAccess{
+		{ID: "active-1", UserID: "U0LEAVER", ClusterID: "cluster-1", Status: models.AccessStatusActive},
+		{ID: "pending-1", UserID: "U0LEAVER", ClusterID: "cluster-1", Status: models.AccessStatusPending},
+		{ID: "expired-1", UserID: "U0LEAVER", ClusterID: "cluster-1", Status: models.AccessStatusExpired},
+		{ID: "active-2", UserID: "U0STAYER", ClusterID: "cluster-1", Status: models.AccessStatusActive},
+	}
+	for _, access := range accesses {
+		if err := memStore.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", access.ID, err)
+		}
+	}
+
+	cleaner := &fakeUserCleaner{}
+	handler := &SlackEventHandler{store: memStore, cleaner: cleaner}
+
+	return slack.NewSlackMiddleware(testSigningSecret).VerifyRequest(http.HandlerFunc(handler.HandleEvent)),
+		memStore, cleaner
+}
+
+func signedEventRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSigningSecret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", timestamp, body)))
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func userChangeEvent(userID string, deleted bool) string {
+	return fmt.Sprintf(`{"type":"event_callback","event":{"type":"user_change","user":{"id":%q,"deleted":%t}}}`,
+		userID, deleted)
+}
+
+func accessStatus(t *testing.T, memStore *store.MemoryStore, id string) models.AccessStatus {
+	t.Helper()
+
+	access, err := memStore.GetAccess(id)
+	if err != nil {
+		t.Fatalf("Failed to get %s: %v", id, err)
+	}
+	return access.Status
+}
+
+func TestSlackEventDeactivationRevokesAccess(t *testing.T) {
+	handler, memStore, cleaner := newTestSlackEventHandler(t)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, signedEventRequest(t, userChangeEvent("U0LEAVER", true)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	if len(cleaner.users) != 1 || cleaner.users[0] != "U0LEAVER" {
+		t.Errorf("Expected access entries cleaned up for U0LEAVER, got %v", cleaner.users)
+	}
+
+	active, err := memStore.GetAccess("active-1")
+	if err != nil {
+		t.Fatalf("Failed to get active-1: %v", err)
+	}
+	if active.Status != models.AccessStatusRevoked {
+		t.Errorf("Expected active access to be revoked, got %s", active.Status)
+	}
+	if active.RevokedBy != DeactivatedRevoker || active.RevokedAt == nil {
+		t.Errorf("Expected revocation to be attributed to deactivation, got %q at %v",
+			active.RevokedBy, active.RevokedAt)
+	}
+
+	if got := accessStatus(t, memStore, "pending-1"); got != models.AccessStatusDenied {
+		t.Errorf("Expected pending request to be denied, got %s", got)
+	}
+	if got := accessStatus(t, memStore, "expired-1"); got != models.AccessStatusExpired {
+		t.Errorf("Expected expired access to be unchanged, got %s", got)
+	}
+	if got := accessStatus(t, memStore, "active-2"); got != models.AccessStatusActive {
+		t.Errorf("Expected other users' access to be unchanged, got %s", got)
+	}
+}
+
+func TestSlackEventIgnoresActiveUserChange(t *testing.T) {
+	handler, memStore, cleaner := newTestSlackEventHandler(t)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, signedEventRequest(t, userChangeEvent("U0LEAVER", false)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if len(cleaner.users) != 0 {
+		t.Errorf("Expected no cleanup for a profile update, got %v", cleaner.users)
+	}
+	if got := accessStatus(t, memStore, "active-1"); got != models.AccessStatusActive {
+		t.Errorf("Expected access to be unchanged, got %s", got)
+	}
+}
+
+func TestSlackEventRejectsUnsignedDeactivation(t *testing.T) {
+	handler, memStore, cleaner := newTestSlackEventHandler(t)
+
+	req := signedEventRequest(t, userChangeEvent("U0LEAVER", true))
+	req.Header.Set("X-Slack-Signature", "v0=forged")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+	if len(cleaner.users) != 0 {
+		t.Errorf("Expected no cleanup for an unverified event, got %v", cleaner.users)
+	}
+	if got := accessStatus(t, memStore, "active-1"); got != models.AccessStatusActive {
+		t.Errorf("Expected access to be unchanged, got %s", got)
+	}
+}
+
+func TestSlackEventCleanupFailureIsRetried(t *testing.T) {
+	handler, memStore, cleaner := newTestSlackEventHandler(t)
+	cleaner.err = errors.New("eks unavailable")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, signedEventRequest(t, userChangeEvent("U0LEAVER", true)))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d so Slack retries, got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if got := accessStatus(t, memStore, "active-1"); got != models.AccessStatusActive {
+		t.Errorf("Expected access to stay active until cleanup succeeds, got %s", got)
+	}
+}
+
+func TestSlackEventDeactivationEndsOperatorRequests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := controller.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	newRequest := func(name, userID string, onBehalfOf *controller.Delegate,
+		phase controller.AccessPhase) *controller.JITAccessRequest {
+		return &controller.JITAccessRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "jit-system"},
+			Spec:       controller.JITAccessRequestSpec{UserID: userID, OnBehalfOf: onBehalfOf},
+			Status:     controller.JITAccessRequestStatus{Phase: phase},
+		}
+	}
+	leaver := &controller.Delegate{UserID: "U0LEAVER"}
+	requests := []*controller.JITAccessRequest{
+		newRequest("leaver-active", "U0LEAVER", nil, controller.AccessPhaseActive),
+		newRequest("leaver-pending", "U0LEAVER", nil, controller.AccessPhasePending),
+		newRequest("for-leaver", "U0OPERATOR", leaver, controller.AccessPhaseApproved),
+		newRequest("leaver-expired", "U0LEAVER", nil, controller.AccessPhaseExpired),
+		newRequest("stayer-active", "U0STAYER", nil, controller.AccessPhaseActive),
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, request := range requests {
+		builder = builder.WithObjects(request).WithStatusSubresource(request)
+	}
+	fakeClient := builder.Build()
+
+	memStore := store.NewMemoryStore()
+	cleaner := &fakeUserCleaner{err: errors.New("eks unavailable")}
+	handler := &SlackEventHandler{store: memStore, cleaner: cleaner}
+	handler.SetRequestClient(fakeClient, "jit-system")
+
+	// Operator requests are ended even when the access entry cleanup fails and the event is retried
+	rr := httptest.NewRecorder()
+	slack.NewSlackMiddleware(testSigningSecret).VerifyRequest(http.HandlerFunc(handler.HandleEvent)).
+		ServeHTTP(rr, signedEventRequest(t, userChangeEvent("U0LEAVER", true)))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d so Slack retries, got %d", http.StatusInternalServerError, rr.Code)
+	}
+
+	for name, want := range map[string]controller.AccessPhase{
+		"leaver-active":  controller.AccessPhaseRevoked,
+		"leaver-pending": controller.AccessPhaseDenied,
+		"for-leaver":     controller.AccessPhaseDenied,
+		"leaver-expired": controller.AccessPhaseExpired,
+		"stayer-active":  controller.AccessPhaseActive,
+	} {
+		var got controller.JITAccessRequest
+		key := types.NamespacedName{Namespace: "jit-system", Name: name}
+		if err := fakeClient.Get(context.Background(), key, &got); err != nil {
+			t.Fatalf("Failed to get %s: %v", name, err)
+		}
+		if got.Status.Phase != want {
+			t.Errorf("Expected %s to be %s, got %s", name, want, got.Status.Phase)
+		}
+	}
+}
+
+func TestSlackEventURLVerification(t *testing.T) {
+	handler, _, _ := newTestSlackEventHandler(t)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, signedEventRequest(t, `{"type":"url_verification","challenge":"abc123"}`))
+
+	if rr.Code != http.StatusOK || rr.Body.String() != "abc123" {
+		t.Errorf("Expected challenge to be echoed, got %d %q", rr.Code, rr.Body.String())
+	}
+}
@@ -23,6 +23,8 @@ const (
 	PermissionViewRequests    Permission = "requests:view"
 	PermissionRevokeAccess    Permission = "access:revoke"
 	PermissionViewAuditLog    Permission = "audit:view"
+	PermissionRequestOnBehalf Permission = "requests:on-behalf"
+	PermissionExportAccess    Permission = "access:export"
 )
 
 var rolePermissions = map[Role][]Permission{
@@ -34,6 +36,8 @@ var rolePermissions = map[Role][]Permission{
 		PermissionViewRequests,
 		PermissionRevokeAccess,
 		PermissionViewAuditLog,
+		PermissionRequestOnBehalf,
+		PermissionExportAccess,
 	},
 	RoleApprover: {
 		PermissionApproveRequests,
@@ -41,6 +45,7 @@ var rolePermissions = map[Role][]Permission{
 		PermissionViewRequests,
 		PermissionRevokeAccess,
 		PermissionViewAuditLog,
+		PermissionRequestOnBehalf,
 	},
 	RoleRequester: {
 		PermissionCreateRequests,
@@ -52,6 +57,10 @@ type RBAC struct {
 	mu     sync.RWMutex
 	users  map[string]Role
 	admins []string
+
+	// lookup resolves a role from the backing store; every role check goes through it
+	lookup func(userID string) Role
+	cache  *roleCache // nil looks up every role
 }
 
 func NewRBAC(adminUsers []string) *RBAC {
@@ -59,6 +68,7 @@ func NewRBAC(adminUsers []string) *RBAC {
 		users:  make(map[string]Role),
 		admins: adminUsers,
 	}
+	rbac.lookup = rbac.storedRole
 
 	for _, admin := range adminUsers {
 		rbac.users[admin] = RoleAdmin
@@ -69,11 +79,34 @@ func NewRBAC(adminUsers []string) *RBAC {
 
 func (r *RBAC) SetUserRole(userID string, role Role) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.users[userID] = role
+	cache := r.cache
+	r.mu.Unlock()
+
+	if cache != nil {
+		cache.invalidate(userID)
+	}
 }
 
 func (r *RBAC) GetUserRole(userID string) Role {
+	r.mu.RLock()
+	cache := r.cache
+	r.mu.RUnlock()
+
+	if cache == nil {
+		return r.lookup(userID)
+	}
+
+	role, ok, generation := cache.get(userID)
+	if ok {
+		return role
+	}
+	role = r.lookup(userID)
+	cache.put(userID, role, generation)
+	return role
+}
+
+func (r *RBAC) storedRole(userID string) Role {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// cachedRole is a looked-up role and when it must be looked up again
+type cachedRole struct {
+	role      Role
+	expiresAt time.Time
+}
+
+// roleCache holds looked-up roles for a short TTL so role checks on every command and handler do
+// not each hit the backing store. Invalidations bump the generation, and a lookup that started
+// before one is not cached, so a role change is never hidden behind a stale entry.
+type roleCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	now        func() time.Time
+	entries    map[string]cachedRole
+	generation uint64
+}
+
+func newRoleCache(ttl time.Duration) *roleCache {
+	return &roleCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedRole),
+	}
+}
+
+// get returns the cached role, if still fresh, and the generation to pass to put after a miss
+func (c *roleCache) get(userID string) (Role, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		delete(c.entries, userID)
+		return "", false, c.generation
+	}
+	return entry.role, true, c.generation
+}
+
+// put caches a role looked up at generation, unless an invalidation happened since
+func (c *roleCache) put(userID string, role Role, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	c.entries[userID] = cachedRole{role: role, expiresAt: c.now().Add(c.ttl)}
+}
+
+func (c *roleCache) invalidate(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, userID)
+	c.generation++
+}
+
+// EnableRoleCache caches role lookups for ttl. SetUserRole invalidates the user's entry, so role
+// changes made through the RBAC take effect immediately; changes made directly in a backing store
+// show up once the entry expires. A zero ttl disables the cache.
+func (r *RBAC) EnableRoleCache(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ttl <= 0 {
+		r.cache = nil
+		return
+	}
+	r.cache = newRoleCache(ttl)
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// countingRBAC counts how often roles are looked up in the backing store
+func countingRBAC(ttl time.Duration) (*RBAC, *int) {
+	rbac := NewRBAC([]string{"admin1"})
+	lookups := 0
+	rbac.lookup = func(userID string) Role {
+		lookups++
+		return rbac.storedRole(userID)
+	}
+	rbac.EnableRoleCache(ttl)
+	return rbac, &lookups
+}
+
+func TestRoleCacheHitsWithinTTL(t *testing.T) {
+	rbac, lookups := countingRBAC(time.Minute)
+	now := time.Now()
+	rbac.cache.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		if err := rbac.ValidatePermission("admin1", PermissionManageClusters); err != nil {
+			t.Fatalf("Expected admin1 to manage clusters: %v", err)
+		}
+	}
+	if *lookups != 1 {
+		t.Errorf("Expected 1 lookup within the TTL, got %d", *lookups)
+	}
+
+	// Users without a stored role are cached too
+	rbac.GetUserRole("user123")
+	rbac.GetUserRole("user123")
+	if *lookups != 2 {
+		t.Errorf("Expected 2 lookups, got %d", *lookups)
+	}
+
+	now = now.Add(time.Minute)
+	if role := rbac.GetUserRole("admin1"); role != RoleAdmin {
+		t.Errorf("Expected role %s, got %s", RoleAdmin, role)
+	}
+	if *lookups != 3 {
+		t.Errorf("Expected an expired entry to be looked up again, got %d lookups", *lookups)
+	}
+}
+
+func TestRoleCacheInvalidatedBySetUserRole(t *testing.T) {
+	rbac, lookups := countingRBAC(time.Hour)
+
+	if rbac.IsAdmin("user123") {
+		t.Fatal("Expected user123 not to be an admin")
+	}
+
+	rbac.SetUserRole("user123", RoleAdmin)
+	if !rbac.IsAdmin("user123") {
+		t.Error("Expected the role change to take effect despite the cached role")
+	}
+
+	rbac.SetUserRole("admin1", RoleRequester)
+	if err := rbac.ValidatePermission("admin1", PermissionManageUsers); err == nil {
+		t.Error("Expected a demoted admin to lose permissions immediately")
+	}
+
+	if *lookups != 3 {
+		t.Errorf("Expected each role change to force a lookup, got %d lookups", *lookups)
+	}
+}
+
+func TestRoleCacheSkipsLookupRacingAnInvalidation(t *testing.T) {
+	rbac, _ := countingRBAC(time.Hour)
+
+	// A lookup that read the old role while the role was being changed must not be cached
+	_, _, generation := rbac.cache.get("user123")
+	rbac.SetUserRole("user123", RoleApprover)
+	rbac.cache.put("user123", RoleRequester, generation)
+
+	if role := rbac.GetUserRole("user123"); role != RoleApprover {
+		t.Errorf("Expected role %s, got %s", RoleApprover, role)
+	}
+}
+
+func TestRoleCacheDisabled(t *testing.T) {
+	rbac, lookups := countingRBAC(0)
+
+	rbac.GetUserRole("admin1")
+	rbac.GetUserRole("admin1")
+	if *lookups != 2 {
+		t.Errorf("Expected every lookup to reach the store without a cache, got %d", *lookups)
+	}
+}
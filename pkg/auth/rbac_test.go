@@ -68,6 +68,12 @@ func TestUserHasPermission(t *testing.T) {
 		{"requester1", PermissionCreateRequests, true},
 		{"requester1", PermissionApproveRequests, false},
 		{"requester1", PermissionManageClusters, false},
+		{"admin1", PermissionRequestOnBehalf, true},
+		{"approver1", PermissionRequestOnBehalf, true},
+		{"requester1", PermissionRequestOnBehalf, false},
+		{"admin1", PermissionExportAccess, true},
+		{"approver1", PermissionExportAccess, false},
+		{"requester1", PermissionExportAccess, false},
 		{"unknown", PermissionCreateRequests, true}, // Default role
 		{"unknown", PermissionApproveRequests, false},
 	}
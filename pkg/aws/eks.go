@@ -2,8 +2,12 @@ package aws
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,8 +17,9 @@ import (
 )
 
 type EKSService struct {
-	client *eks.Client
-	region string
+	client      *eks.Client
+	region      string
+	permissions PermissionPolicies
 }
 
 type AccessEntry struct {
@@ -23,6 +28,7 @@ type AccessEntry struct {
 	Username       string
 	Groups         []string
 	AccessPolicies []AccessPolicy
+	InlinePolicy   *InlinePolicy
 	CreatedAt      time.Time
 	ModifiedAt     time.Time
 	Tags           map[string]string
@@ -38,6 +44,62 @@ type AccessScope struct {
 	Namespaces []string
 }
 
+// ResourceScope narrows JIT access to specific Kubernetes API groups, resources and verbs
+type ResourceScope struct {
+	APIGroups  []string `json:"apiGroups,omitempty"`
+	Resources  []string `json:"resources"`
+	Verbs      []string `json:"verbs"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// PermissionSpec scopes one permission level to its own namespaces (empty = the request's) and,
+// when Resources are listed, to those core API group resources only
+type PermissionSpec struct {
+	Level      string   `json:"level"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Resources  []string `json:"resources,omitempty"`
+}
+
+// InlinePolicy is a custom cluster-access policy generated from a ResourceScope.
+// It is bound to the access entry through a Kubernetes group of the same name.
+type InlinePolicy struct {
+	Name       string       `json:"name"`
+	Namespaces []string     `json:"namespaces,omitempty"`
+	Rules      []PolicyRule `json:"rules"`
+}
+
+type PolicyRule struct {
+	APIGroups []string `json:"apiGroups"`
+	Resources []string `json:"resources"`
+	Verbs     []string `json:"verbs"`
+}
+
+var policyNameSanitizer = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// BuildInlinePolicy generates the custom policy granting exactly the requested scope
+func BuildInlinePolicy(username string, scope ResourceScope) InlinePolicy {
+	apiGroups := scope.APIGroups
+	if len(apiGroups) == 0 {
+		// The core API group is addressed by the empty string
+		apiGroups = []string{""}
+	}
+
+	name := policyNameSanitizer.ReplaceAllString(strings.ToLower(username), "-")
+	name = strings.Trim(name, "-")
+
+	return InlinePolicy{
+		Name:       fmt.Sprintf("jit-scoped-%s", name),
+		Namespaces: scope.Namespaces,
+		Rules: []PolicyRule{
+			{
+				APIGroups: apiGroups,
+				Resources: scope.Resources,
+				Verbs:     scope.Verbs,
+			},
+		},
+	}
+}
+
 func NewEKSService(region string) (*EKSService, error) {
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion(region),
@@ -52,6 +114,12 @@ func NewEKSService(region string) (*EKSService, error) {
 	}, nil
 }
 
+// SetPermissionPolicies sets the permissions access entries are built from; nil uses
+// DefaultPermissionPolicies
+func (e *EKSService) SetPermissionPolicies(policies PermissionPolicies) {
+	e.permissions = policies
+}
+
 func (e *EKSService) CreateAccessEntry(ctx context.Context, entry AccessEntry) error {
 	input := &eks.CreateAccessEntryInput{
 		ClusterName:  aws.String(entry.ClusterName),
@@ -64,7 +132,9 @@ func (e *EKSService) CreateAccessEntry(ctx context.Context, entry AccessEntry) e
 		input.KubernetesGroups = entry.Groups
 	}
 
+	start := time.Now()
 	_, err := e.client.CreateAccessEntry(ctx, input)
+	recordAWSCall(serviceEKS, "create_access_entry", e.region, start, err)
 	if err != nil {
 		return fmt.Errorf("failed to create access entry: %w", err)
 	}
@@ -97,7 +167,9 @@ func (e *EKSService) AssociateAccessPolicy(
 		input.AccessScope.Namespaces = policy.AccessScope.Namespaces
 	}
 
+	start := time.Now()
 	_, err := e.client.AssociateAccessPolicy(ctx, input)
+	recordAWSCall(serviceEKS, "associate_access_policy", e.region, start, err)
 	if err != nil {
 		return fmt.Errorf("failed to associate access policy: %w", err)
 	}
@@ -111,7 +183,9 @@ func (e *EKSService) DeleteAccessEntry(ctx context.Context, clusterName, princip
 		PrincipalArn: aws.String(principalArn),
 	}
 
+	start := time.Now()
 	_, err := e.client.DeleteAccessEntry(ctx, input)
+	recordAWSCall(serviceEKS, "delete_access_entry", e.region, start, err)
 	if err != nil {
 		return fmt.Errorf("failed to delete access entry: %w", err)
 	}
@@ -125,7 +199,9 @@ func (e *EKSService) DescribeAccessEntry(ctx context.Context, clusterName, princ
 		PrincipalArn: aws.String(principalArn),
 	}
 
+	start := time.Now()
 	result, err := e.client.DescribeAccessEntry(ctx, input)
+	recordAWSCall(serviceEKS, "describe_access_entry", e.region, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe access entry: %w", err)
 	}
@@ -152,7 +228,9 @@ func (e *EKSService) ListAccessEntries(ctx context.Context, clusterName string)
 	paginator := eks.NewListAccessEntriesPaginator(e.client, input)
 
 	for paginator.HasMorePages() {
+		start := time.Now()
 		page, err := paginator.NextPage(ctx)
+		recordAWSCall(serviceEKS, "list_access_entries", e.region, start, err)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list access entries: %w", err)
 		}
@@ -167,7 +245,9 @@ func (e *EKSService) DescribeCluster(ctx context.Context, clusterName string) (*
 		Name: aws.String(clusterName),
 	}
 
+	start := time.Now()
 	result, err := e.client.DescribeCluster(ctx, input)
+	recordAWSCall(serviceEKS, "describe_cluster", e.region, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe cluster: %w", err)
 	}
@@ -187,54 +267,163 @@ const (
 	AccessScopeNamespace = "namespace"
 )
 
-// CreateJITAccessEntry creates a temporary access entry for JIT access
+// CreateJITAccessEntry creates a temporary access entry for JIT access. When a resource
+// scope is supplied a custom inline policy is used instead of the AWS-managed policies and
+// the permissions' Kubernetes groups, which would widen access past the scope. Permission
+// specs scope their levels individually, see resolvePermissionSpecs.
+// Extra tags, such as cost-allocation tags, are added to the entry's default tags. The entry
+// sent to EKS is returned so callers can record what was granted.
 func (e *EKSService) CreateJITAccessEntry(
 	ctx context.Context,
 	clusterName, principalArn, username string,
 	permissions []string,
+	specs []PermissionSpec,
 	namespaces []string,
-) error {
+	scope *ResourceScope,
+	tags map[string]string,
+) (*AccessEntry, error) {
+	entry := buildJITAccessEntry(
+		e.permissions, clusterName, principalArn, username, permissions, specs, namespaces, scope, tags,
+	)
+	if err := e.CreateAccessEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func buildJITAccessEntry(
+	policies PermissionPolicies,
+	clusterName, principalArn, username string,
+	permissions []string,
+	specs []PermissionSpec,
+	namespaces []string,
+	scope *ResourceScope,
+	tags map[string]string,
+) AccessEntry {
+	entry := AccessEntry{
+		ClusterName:  clusterName,
+		PrincipalArn: principalArn,
+		Username:     username,
+		Tags: map[string]string{
+			"Purpose":      "JITAccess",
+			"CreatedBy":    "jit-server",
+			"Temporary":    "true",
+			"CreatedAt":    time.Now().Format(time.RFC3339),
+			"ExpiresAfter": "8h", // Default expiration hint
+		},
+	}
+	for key, value := range tags {
+		entry.Tags[key] = value
+	}
+
+	if scope != nil {
+		scoped := *scope
+		if len(scoped.Namespaces) == 0 {
+			scoped.Namespaces = namespaces
+		}
+		policy := BuildInlinePolicy(inlinePolicyOwner(username, principalArn), scoped)
+		entry.InlinePolicy = &policy
+		entry.Groups = []string{policy.Name}
+		entry.Tags["InlinePolicy"] = policy.Name
+		return entry
+	}
+
+	if len(specs) > 0 {
+		owner := inlinePolicyOwner(username, principalArn)
+		resolvePermissionSpecs(policies, &entry, owner, permissions, specs, namespaces)
+		return entry
+	}
+
+	entry.AccessPolicies = managedAccessPolicies(policies, permissions, namespaces)
+	entry.Groups = policies.Groups(permissions)
+	return entry
+}
+
+// resolvePermissionSpecs grants each level on its own terms: levels without a spec keep the
+// request namespaces, specs without resources get their level's managed policy scoped to the
+// spec's namespaces, and specs with resources are granted the level's verbs on just those
+// core API group resources through one inline policy, named after owner. Resource specs share the
+// inline policy's namespaces, taken from the first of them.
+func resolvePermissionSpecs(
+	policies PermissionPolicies,
+	entry *AccessEntry, owner string, permissions []string, specs []PermissionSpec, namespaces []string,
+) {
+	structured := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		structured[spec.Level] = true
+	}
+
+	var levels []string
+	for _, permission := range permissions {
+		if !structured[permission] {
+			levels = append(levels, permission)
+		}
+	}
+	if len(levels) > 0 {
+		entry.AccessPolicies = managedAccessPolicies(policies, levels, namespaces)
+	}
+
+	var inline *InlinePolicy
+	for _, spec := range specs {
+		specNamespaces := spec.Namespaces
+		if len(specNamespaces) == 0 {
+			specNamespaces = namespaces
+		}
+
+		if len(spec.Resources) == 0 {
+			managed := managedAccessPolicies(policies, []string{spec.Level}, specNamespaces)
+			entry.AccessPolicies = append(entry.AccessPolicies, managed...)
+			levels = append(levels, spec.Level)
+			continue
+		}
+
+		policy := BuildInlinePolicy(owner, ResourceScope{
+			Resources:  spec.Resources,
+			Verbs:      policies.Verbs(spec.Level),
+			Namespaces: specNamespaces,
+		})
+		if inline == nil {
+			inline = &policy
+			continue
+		}
+		inline.Rules = append(inline.Rules, policy.Rules...)
+	}
+
+	entry.Groups = policies.Groups(levels)
+	if inline != nil {
+		entry.InlinePolicy = inline
+		entry.Groups = append(entry.Groups, inline.Name)
+		entry.Tags["InlinePolicy"] = inline.Name
+	}
+}
+
+// inlinePolicyOwner names inline policies after both the user and their session, so concurrent
+// sessions get their own Kubernetes group and RBAC objects and revoking one leaves the others
+func inlinePolicyOwner(username, principalArn string) string {
+	session := principalArn[strings.LastIndex(principalArn, "/")+1:]
+	sum := sha256.Sum256([]byte(session))
+	return username + "-" + hex.EncodeToString(sum[:4])
+}
+
+// managedAccessPolicies maps permission levels onto AWS-managed access policies
+func managedAccessPolicies(policies PermissionPolicies, permissions []string, namespaces []string) []AccessPolicy {
 	// Determine appropriate policies based on permissions
 	var accessPolicies []AccessPolicy
 
 	for _, permission := range permissions {
-		switch permission {
-		case "view":
-			scope := AccessScope{Type: AccessScopeNamespace, Namespaces: namespaces}
-			if len(namespaces) == 0 {
-				scope.Type = AccessScopeCluster
-			}
-			accessPolicies = append(accessPolicies, AccessPolicy{
-				PolicyArn:   EKSViewerPolicy,
-				AccessScope: scope,
-			})
-		case "edit":
-			scope := AccessScope{Type: AccessScopeNamespace, Namespaces: namespaces}
-			if len(namespaces) == 0 {
-				scope.Type = AccessScopeCluster
-			}
-			accessPolicies = append(accessPolicies, AccessPolicy{
-				PolicyArn:   EKSEditorPolicy,
-				AccessScope: scope,
-			})
-		case "admin", "cluster-admin":
-			accessPolicies = append(accessPolicies, AccessPolicy{
-				PolicyArn: EKSAdminPolicy,
-				AccessScope: AccessScope{
-					Type: AccessScopeCluster,
-				},
-			})
-		case "debug", "logs", "exec", "port-forward":
-			// These require edit permissions as a baseline
-			scope := AccessScope{Type: AccessScopeNamespace, Namespaces: namespaces}
-			if len(namespaces) == 0 {
-				scope.Type = AccessScopeCluster
-			}
-			accessPolicies = append(accessPolicies, AccessPolicy{
-				PolicyArn:   EKSEditorPolicy,
-				AccessScope: scope,
-			})
+		policy, ok := policies.Lookup(permission)
+		if !ok {
+			continue
 		}
+
+		scope := AccessScope{Type: AccessScopeNamespace, Namespaces: namespaces}
+		if policy.ClusterScoped || len(namespaces) == 0 {
+			scope = AccessScope{Type: AccessScopeCluster}
+		}
+		accessPolicies = append(accessPolicies, AccessPolicy{
+			PolicyArn:   policy.PolicyArn,
+			AccessScope: scope,
+		})
 	}
 
 	// If no policies were matched, default to view
@@ -249,21 +438,7 @@ func (e *EKSService) CreateJITAccessEntry(
 		})
 	}
 
-	entry := AccessEntry{
-		ClusterName:    clusterName,
-		PrincipalArn:   principalArn,
-		Username:       username,
-		AccessPolicies: accessPolicies,
-		Tags: map[string]string{
-			"Purpose":      "JITAccess",
-			"CreatedBy":    "jit-server",
-			"Temporary":    "true",
-			"CreatedAt":    time.Now().Format(time.RFC3339),
-			"ExpiresAfter": "8h", // Default expiration hint
-		},
-	}
-
-	return e.CreateAccessEntry(ctx, entry)
+	return accessPolicies
 }
 
 // ListJITAccessEntries lists only JIT-created access entries
@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInlinePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		scope    ResourceScope
+		expected InlinePolicy
+	}{
+		{
+			name:     "core group by default",
+			username: "jit:U1234567890",
+			scope: ResourceScope{
+				Resources: []string{"pods", "pods/log"},
+				Verbs:     []string{"get", "list"},
+			},
+			expected: InlinePolicy{
+				Name: "jit-scoped-jit-u1234567890",
+				Rules: []PolicyRule{
+					{
+						APIGroups: []string{""},
+						Resources: []string{"pods", "pods/log"},
+						Verbs:     []string{"get", "list"},
+					},
+				},
+			},
+		},
+		{
+			name:     "named groups with namespaces",
+			username: "jit:U1234567890",
+			scope: ResourceScope{
+				APIGroups:  []string{"apps"},
+				Resources:  []string{"deployments"},
+				Verbs:      []string{"get", "patch"},
+				Namespaces: []string{"payments"},
+			},
+			expected: InlinePolicy{
+				Name:       "jit-scoped-jit-u1234567890",
+				Namespaces: []string{"payments"},
+				Rules: []PolicyRule{
+					{
+						APIGroups: []string{"apps"},
+						Resources: []string{"deployments"},
+						Verbs:     []string{"get", "patch"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BuildInlinePolicy(tt.username, tt.scope))
+		})
+	}
+}
+
+func TestBuildJITAccessEntry(t *testing.T) {
+	principal := "arn:aws:sts::123456789012:assumed-role/jit/session"
+
+	t.Run("managed policies without scope", func(t *testing.T) {
+		entry := buildJITAccessEntry(nil, "prod", principal, "jit:U1",
+			[]string{"view"}, nil, []string{"default"}, nil, nil)
+
+		assert.Nil(t, entry.InlinePolicy)
+		assert.Empty(t, entry.Groups)
+		require.Len(t, entry.AccessPolicies, 1)
+		assert.Equal(t, EKSViewerPolicy, entry.AccessPolicies[0].PolicyArn)
+		assert.Equal(t, AccessScopeNamespace, entry.AccessPolicies[0].AccessScope.Type)
+	})
+
+	t.Run("inline policy with scope", func(t *testing.T) {
+		scope := &ResourceScope{
+			APIGroups: []string{"batch"},
+			Resources: []string{"jobs"},
+			Verbs:     []string{"get", "delete"},
+		}
+
+		entry := buildJITAccessEntry(nil, "prod", principal, "jit:U1",
+			[]string{"edit"}, nil, []string{"batch-jobs"}, scope, nil)
+
+		assert.Empty(t, entry.AccessPolicies)
+		require.NotNil(t, entry.InlinePolicy)
+		assert.Equal(t, []string{entry.InlinePolicy.Name}, entry.Groups)
+		assert.Equal(t, entry.InlinePolicy.Name, entry.Tags["InlinePolicy"])
+		assert.Equal(t, []string{"batch-jobs"}, entry.InlinePolicy.Namespaces)
+		require.Len(t, entry.InlinePolicy.Rules, 1)
+		assert.Equal(t, []string{"batch"}, entry.InlinePolicy.Rules[0].APIGroups)
+		assert.Equal(t, []string{"jobs"}, entry.InlinePolicy.Rules[0].Resources)
+		assert.Equal(t, []string{"get", "delete"}, entry.InlinePolicy.Rules[0].Verbs)
+	})
+
+	t.Run("permission spec scoped to its own namespaces", func(t *testing.T) {
+		specs := []PermissionSpec{{Level: "edit", Namespaces: []string{"payments"}}}
+
+		entry := buildJITAccessEntry(nil, "prod", principal, "jit:U1",
+			[]string{"view", "edit"}, specs, []string{"default"}, nil, nil)
+
+		assert.Nil(t, entry.InlinePolicy)
+		assert.Equal(t, []AccessPolicy{
+			{
+				PolicyArn:   EKSViewerPolicy,
+				AccessScope: AccessScope{Type: AccessScopeNamespace, Namespaces: []string{"default"}},
+			},
+			{
+				PolicyArn:   EKSEditorPolicy,
+				AccessScope: AccessScope{Type: AccessScopeNamespace, Namespaces: []string{"payments"}},
+			},
+		}, entry.AccessPolicies)
+	})
+
+	t.Run("permission spec limited to resources", func(t *testing.T) {
+		specs := []PermissionSpec{
+			{Level: "edit", Namespaces: []string{"payments"}, Resources: []string{"configmaps", "pods"}},
+		}
+
+		entry := buildJITAccessEntry(nil, "prod", principal, "jit:U1",
+			[]string{"view", "edit"}, specs, nil, nil, nil)
+
+		// The edit level is not granted through its managed policy, which would cover every resource
+		require.Len(t, entry.AccessPolicies, 1)
+		assert.Equal(t, EKSViewerPolicy, entry.AccessPolicies[0].PolicyArn)
+		assert.Equal(t, AccessScopeCluster, entry.AccessPolicies[0].AccessScope.Type)
+
+		require.NotNil(t, entry.InlinePolicy)
+		name := BuildInlinePolicy(inlinePolicyOwner("jit:U1", principal), ResourceScope{}).Name
+		assert.Equal(t, InlinePolicy{
+			Name:       name,
+			Namespaces: []string{"payments"},
+			Rules: []PolicyRule{{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps", "pods"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			}},
+		}, *entry.InlinePolicy)
+		assert.Equal(t, []string{name}, entry.Groups)
+		assert.Equal(t, name, entry.Tags["InlinePolicy"])
+	})
+
+	t.Run("each session gets its own inline policy", func(t *testing.T) {
+		scope := &ResourceScope{Resources: []string{"pods"}, Verbs: []string{"get"}}
+		first := buildJITAccessEntry(nil, "prod", principal, "jit:U1", []string{"view"}, nil, nil, scope, nil)
+		second := buildJITAccessEntry(nil, "prod", principal+"-2", "jit:U1", []string{"view"}, nil, nil, scope, nil)
+
+		assert.True(t, strings.HasPrefix(first.InlinePolicy.Name, "jit-scoped-jit-u1-"))
+		assert.NotEqual(t, first.InlinePolicy.Name, second.InlinePolicy.Name)
+	})
+}
@@ -0,0 +1,28 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicesUseEndpointURLFromEnvironment(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL", "")
+
+	stsService, err := NewSTSService("us-east-1")
+	require.NoError(t, err)
+	assert.Nil(t, stsService.client.Options().BaseEndpoint, "default AWS endpoints are used without an override")
+
+	t.Setenv("AWS_ENDPOINT_URL", "http://localhost:4566")
+
+	stsService, err = NewSTSService("us-east-1")
+	require.NoError(t, err)
+	require.NotNil(t, stsService.client.Options().BaseEndpoint)
+	assert.Equal(t, "http://localhost:4566", *stsService.client.Options().BaseEndpoint)
+
+	eksService, err := NewEKSService("us-east-1")
+	require.NoError(t, err)
+	require.NotNil(t, eksService.client.Options().BaseEndpoint)
+	assert.Equal(t, "http://localhost:4566", *eksService.client.Options().BaseEndpoint)
+}
@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+const (
+	serviceEKS = "eks"
+	serviceSTS = "sts"
+)
+
+// recordAWSCall records the outcome and latency of a single AWS API call. Failed calls are also
+// counted by their AWS error code, or "unknown" when the error did not come from the API.
+func recordAWSCall(service, operation, region string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+
+		errorCode := "unknown"
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			errorCode = apiErr.ErrorCode()
+		}
+		metrics.RecordAWSAPIError(service, operation, errorCode, region)
+	}
+
+	metrics.RecordAWSAPICall(service, operation, status, region, time.Since(start))
+}
@@ -0,0 +1,135 @@
+package aws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func newTestEKSService(t *testing.T, handler http.HandlerFunc) *EKSService {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := eks.New(eks.Options{
+		Region:       "us-test-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+		HTTPClient:   server.Client(),
+	})
+	return &EKSService{client: client, region: "us-test-1"}
+}
+
+func findMetric(t *testing.T, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] == label.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return metric
+			}
+		}
+	}
+	return nil
+}
+
+func describeClusterObservations(t *testing.T) uint64 {
+	t.Helper()
+
+	metric := findMetric(t, "jit_aws_api_duration_seconds", map[string]string{
+		"service": "eks", "operation": "describe_cluster", "region": "us-test-1",
+	})
+	if metric == nil {
+		return 0
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func describeClusterCalls(t *testing.T, status string) float64 {
+	t.Helper()
+
+	metric := findMetric(t, "jit_aws_api_calls_total", map[string]string{
+		"service": "eks", "operation": "describe_cluster", "status": status, "region": "us-test-1",
+	})
+	if metric == nil {
+		return 0
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestDescribeClusterRecordsLatency(t *testing.T) {
+	service := newTestEKSService(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"cluster":{"name":"test-cluster","endpoint":"https://example.eks.amazonaws.com"}}`))
+	})
+
+	observations := describeClusterObservations(t)
+	successes := describeClusterCalls(t, "success")
+
+	cluster, err := service.DescribeCluster(context.Background(), "test-cluster")
+	if err != nil {
+		t.Fatalf("DescribeCluster failed: %v", err)
+	}
+	if aws.ToString(cluster.Name) != "test-cluster" {
+		t.Errorf("Expected test-cluster, got %s", aws.ToString(cluster.Name))
+	}
+
+	if got := describeClusterObservations(t) - observations; got != 1 {
+		t.Errorf("Expected 1 duration observation, got %d", got)
+	}
+	if got := describeClusterCalls(t, "success") - successes; got != 1 {
+		t.Errorf("Expected 1 successful call recorded, got %v", got)
+	}
+}
+
+func TestDescribeClusterRecordsErrorCode(t *testing.T) {
+	service := newTestEKSService(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amzn-Errortype", "ResourceNotFoundException")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"No cluster found for name: missing."}`))
+	})
+
+	errorLabels := map[string]string{
+		"service": "eks", "operation": "describe_cluster",
+		"error_code": "ResourceNotFoundException", "region": "us-test-1",
+	}
+	var before float64
+	if metric := findMetric(t, "jit_aws_api_errors_total", errorLabels); metric != nil {
+		before = metric.GetCounter().GetValue()
+	}
+	failures := describeClusterCalls(t, "error")
+
+	if _, err := service.DescribeCluster(context.Background(), "missing"); err == nil {
+		t.Fatal("Expected DescribeCluster to fail")
+	}
+
+	if got := describeClusterCalls(t, "error") - failures; got != 1 {
+		t.Errorf("Expected 1 failed call recorded, got %v", got)
+	}
+	metric := findMetric(t, "jit_aws_api_errors_total", errorLabels)
+	if metric == nil || metric.GetCounter().GetValue()-before != 1 {
+		t.Error("Expected the AWS error code to be recorded")
+	}
+}
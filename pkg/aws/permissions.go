@@ -0,0 +1,158 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// PermissionPolicy maps a requestable permission onto the AWS-managed EKS access policy it grants
+type PermissionPolicy struct {
+	// PolicyArn is the EKS cluster access policy associated with the access entry
+	PolicyArn string `json:"policyArn"`
+	// ClusterScoped grants the policy cluster-wide even when namespaces are requested
+	ClusterScoped bool `json:"clusterScoped,omitempty"`
+	// Groups are Kubernetes groups added to the access entry, for permissions granted through
+	// in-cluster RBAC bindings rather than (or as well as) the access policy
+	Groups []string `json:"groups,omitempty"`
+}
+
+// PermissionPolicies maps requestable permissions onto the policies they grant. A nil set is the
+// built-in one, see DefaultPermissionPolicies.
+type PermissionPolicies map[string]PermissionPolicy
+
+// DefaultPermissionPolicies returns the built-in permission set
+func DefaultPermissionPolicies() PermissionPolicies {
+	return PermissionPolicies{
+		"view":          {PolicyArn: EKSViewerPolicy},
+		"edit":          {PolicyArn: EKSEditorPolicy},
+		"admin":         {PolicyArn: EKSAdminPolicy, ClusterScoped: true},
+		"cluster-admin": {PolicyArn: EKSAdminPolicy, ClusterScoped: true},
+		// These require edit permissions as a baseline
+		"debug":        {PolicyArn: EKSEditorPolicy},
+		"logs":         {PolicyArn: EKSEditorPolicy},
+		"exec":         {PolicyArn: EKSEditorPolicy},
+		"port-forward": {PolicyArn: EKSEditorPolicy},
+	}
+}
+
+// LoadPermissionPolicies reads extra or overridden permissions from a JSON file keyed by
+// permission name and merges them over DefaultPermissionPolicies
+func LoadPermissionPolicies(path string) (PermissionPolicies, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read permission policies: %w", err)
+	}
+
+	var overrides map[string]PermissionPolicy
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse permission policies: %w", err)
+	}
+
+	policies := DefaultPermissionPolicies()
+	for name, policy := range overrides {
+		if name == "" {
+			return nil, fmt.Errorf("permission name cannot be empty")
+		}
+		if !strings.HasPrefix(policy.PolicyArn, "arn:") {
+			return nil, fmt.Errorf("permission %s: policyArn must be an ARN", name)
+		}
+		for _, group := range policy.Groups {
+			if strings.TrimSpace(group) == "" || strings.HasPrefix(group, "system:") {
+				return nil, fmt.Errorf("permission %s: invalid Kubernetes group %q", name, group)
+			}
+		}
+		policies[name] = policy
+	}
+
+	return policies, nil
+}
+
+func (p PermissionPolicies) orDefault() PermissionPolicies {
+	if p == nil {
+		return DefaultPermissionPolicies()
+	}
+	return p
+}
+
+// Lookup returns the policy granted by a permission and whether it is valid
+func (p PermissionPolicies) Lookup(permission string) (PermissionPolicy, bool) {
+	policy, ok := p.orDefault()[permission]
+	return policy, ok
+}
+
+// Names returns the valid permissions in sorted order
+func (p PermissionPolicies) Names() []string {
+	policies := p.orDefault()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Groups returns the Kubernetes groups mapped to the requested permissions, in request order and
+// without duplicates
+func (p PermissionPolicies) Groups(permissions []string) []string {
+	policies := p.orDefault()
+
+	var groups []string
+	seen := make(map[string]bool)
+	for _, permission := range permissions {
+		for _, group := range policies[permission].Groups {
+			if !seen[group] {
+				seen[group] = true
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+// Level returns the built-in permission whose access a permission grants, so policy keyed on
+// built-in names, such as the checks on cluster-admin, also holds for custom permissions. Built-in
+// permissions that keep their default policy are their own level; anything else is judged by its
+// policy ARN alone, so a custom permission mapped to the admin policy is cluster-admin. Unknown
+// permissions are returned unchanged.
+func (p PermissionPolicies) Level(permission string) string {
+	policy, ok := p.Lookup(permission)
+	if !ok {
+		return permission
+	}
+	if builtin, ok := DefaultPermissionPolicies()[permission]; ok && builtin.PolicyArn == policy.PolicyArn {
+		return permission
+	}
+
+	switch policy.PolicyArn {
+	case EKSAdminPolicy:
+		return "cluster-admin"
+	case EKSEditorPolicy:
+		return "edit"
+	default:
+		return "view"
+	}
+}
+
+// Verbs granted on a permission spec's resources, by the managed policy the level maps to
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	adminVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+)
+
+// Verbs returns the Kubernetes verbs a permission grants when it is limited to specific resources.
+// They follow the level's managed policy; levels mapped to any other policy get read verbs only.
+func (p PermissionPolicies) Verbs(permission string) []string {
+	policy, _ := p.Lookup(permission)
+	switch policy.PolicyArn {
+	case EKSAdminPolicy:
+		return adminVerbs
+	case EKSEditorPolicy:
+		return writeVerbs
+	default:
+		return readVerbs
+	}
+}
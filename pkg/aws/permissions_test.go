@@ -0,0 +1,110 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPermissionPolicies(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "permissions.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{
+		"scale": {"policyArn": "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"},
+		"view": {"policyArn": "arn:aws:eks::aws:cluster-access-policy/AmazonEKSAdminViewPolicy"}
+	}`), 0o600))
+
+	policies, err := LoadPermissionPolicies(valid)
+	require.NoError(t, err)
+	assert.Equal(t, EKSEditorPolicy, policies["scale"].PolicyArn)
+	assert.Equal(t, EKSNamespacePolicy, policies["view"].PolicyArn, "configured permissions override defaults")
+	assert.Equal(t, EKSAdminPolicy, policies["cluster-admin"].PolicyArn, "defaults are kept")
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"scale": {"policyArn": "edit"}}`), 0o600))
+	_, err = LoadPermissionPolicies(invalid)
+	assert.Error(t, err)
+}
+
+func TestCustomPermissionMapsToPolicy(t *testing.T) {
+	policies := DefaultPermissionPolicies()
+	policies["scale"] = PermissionPolicy{PolicyArn: EKSEditorPolicy}
+
+	_, ok := policies.Lookup("scale")
+	assert.True(t, ok)
+	assert.Contains(t, policies.Names(), "scale")
+
+	entry := buildJITAccessEntry(policies, "prod", "arn:aws:sts::123456789012:assumed-role/jit/session", "jit:U1",
+		[]string{"scale"}, nil, []string{"web"}, nil, nil)
+	require.Len(t, entry.AccessPolicies, 1)
+	assert.Equal(t, EKSEditorPolicy, entry.AccessPolicies[0].PolicyArn)
+	assert.Equal(t, AccessScope{Type: AccessScopeNamespace, Namespaces: []string{"web"}},
+		entry.AccessPolicies[0].AccessScope)
+
+	var builtin PermissionPolicies
+	_, ok = builtin.Lookup("scale")
+	assert.False(t, ok, "nil is the default permission set")
+	_, ok = builtin.Lookup("cluster-admin")
+	assert.True(t, ok)
+}
+
+func TestPermissionLevel(t *testing.T) {
+	policies := DefaultPermissionPolicies()
+	policies["superuser"] = PermissionPolicy{PolicyArn: EKSAdminPolicy, ClusterScoped: true}
+	policies["scale"] = PermissionPolicy{PolicyArn: EKSEditorPolicy}
+	policies["audit"] = PermissionPolicy{PolicyArn: EKSViewerPolicy}
+	policies["view"] = PermissionPolicy{PolicyArn: EKSAdminPolicy}
+
+	assert.Equal(t, "cluster-admin", policies.Level("superuser"), "custom names mapped to the admin policy")
+	assert.Equal(t, "edit", policies.Level("scale"))
+	assert.Equal(t, "view", policies.Level("audit"))
+	assert.Equal(t, "admin", policies.Level("admin"), "built-in permissions are their own level")
+	assert.Equal(t, "exec", policies.Level("exec"))
+	assert.Equal(t, "cluster-admin", policies.Level("view"), "remapped built-ins follow their policy")
+	assert.Equal(t, "unknown", policies.Level("unknown"))
+}
+
+func TestPermissionGroupsOnAccessEntry(t *testing.T) {
+	policies := DefaultPermissionPolicies()
+	policies["edit"] = PermissionPolicy{PolicyArn: EKSEditorPolicy, Groups: []string{"jit-editors"}}
+	policies["exec"] = PermissionPolicy{PolicyArn: EKSEditorPolicy, Groups: []string{"jit-editors", "jit-exec"}}
+
+	principal := "arn:aws:sts::123456789012:assumed-role/jit/session"
+
+	entry := buildJITAccessEntry(policies, "prod", principal, "jit:U1",
+		[]string{"exec", "view", "edit"}, nil, nil, nil, nil)
+	assert.Equal(t, []string{"jit-editors", "jit-exec"}, entry.Groups)
+
+	entry = buildJITAccessEntry(policies, "prod", principal, "jit:U1", []string{"view"}, nil, nil, nil, nil)
+	assert.Empty(t, entry.Groups, "permissions without groups add none")
+
+	// A resource scope replaces the permissions' grants, groups included
+	scope := &ResourceScope{Resources: []string{"pods"}, Verbs: []string{"get"}}
+	entry = buildJITAccessEntry(policies, "prod", principal, "jit:U1", []string{"edit"}, nil, nil, scope, nil)
+	assert.Equal(t, []string{entry.InlinePolicy.Name}, entry.Groups)
+}
+
+func TestLoadPermissionPoliciesGroups(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "permissions.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{
+		"edit": {"policyArn": "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy", "groups": ["jit-editors"]}
+	}`), 0o600))
+
+	policies, err := LoadPermissionPolicies(valid)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jit-editors"}, policies["edit"].Groups)
+
+	reserved := filepath.Join(dir, "reserved.json")
+	require.NoError(t, os.WriteFile(reserved, []byte(`{
+		"edit": {"policyArn": "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy",
+			"groups": ["system:masters"]}
+	}`), 0o600))
+	_, err = LoadPermissionPolicies(reserved)
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Redacted replaces secret material in logs
+const Redacted = "[REDACTED]"
+
+// RedactSecret masks a secret value for logging. Empty values stay empty so logs still
+// show whether the secret was set.
+func RedactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// String implements fmt.Stringer so credentials printed with %v or %s are masked
+func (c *Credentials) String() string {
+	if c == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("{AccessKeyID:%s SecretAccessKey:%s SessionToken:%s Expiration:%s}",
+		RedactSecret(c.AccessKeyID), RedactSecret(c.SecretAccessKey), RedactSecret(c.SessionToken),
+		c.Expiration.Format(time.RFC3339))
+}
+
+// LogValue implements slog.LogValuer so credentials logged with slog are masked
+func (c *Credentials) LogValue() slog.Value {
+	if c == nil {
+		return slog.Value{}
+	}
+	return slog.GroupValue(
+		slog.String("accessKeyId", RedactSecret(c.AccessKeyID)),
+		slog.String("secretAccessKey", RedactSecret(c.SecretAccessKey)),
+		slog.String("sessionToken", RedactSecret(c.SessionToken)),
+		slog.Time("expiration", c.Expiration),
+	)
+}
+
+// MarshalLog implements logr.Marshaler so credentials logged through controller-runtime are masked
+func (c *Credentials) MarshalLog() interface{} {
+	if c == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"accessKeyId":     RedactSecret(c.AccessKeyID),
+		"secretAccessKey": RedactSecret(c.SecretAccessKey),
+		"sessionToken":    RedactSecret(c.SessionToken),
+		"expiration":      c.Expiration,
+	}
+}
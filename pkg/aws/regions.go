@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AllowedRegions limits the regions the operator serves; nil allows every region
+type AllowedRegions map[string]bool
+
+// NewAllowedRegions builds an allowlist from region names. Blank entries are ignored, and a list
+// left empty allows every region.
+func NewAllowedRegions(regions []string) AllowedRegions {
+	allowed := make(AllowedRegions, len(regions))
+	for _, region := range regions {
+		if region = strings.ToLower(strings.TrimSpace(region)); region != "" {
+			allowed[region] = true
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+	return allowed
+}
+
+// Check returns an error if region is not in the allowlist
+func (a AllowedRegions) Check(region string) error {
+	if a == nil || a[strings.ToLower(region)] {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(a))
+	for name := range a {
+		allowed = append(allowed, name)
+	}
+	sort.Strings(allowed)
+
+	return fmt.Errorf("region %q is not allowed (allowed: %s)", region, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,24 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedRegionsCheck(t *testing.T) {
+	var unrestricted AllowedRegions
+	assert.NoError(t, unrestricted.Check("ap-south-1"), "every region is allowed without an allowlist")
+
+	allowed := NewAllowedRegions([]string{"us-east-1", " EU-West-1 "})
+
+	assert.NoError(t, allowed.Check("us-east-1"))
+	assert.NoError(t, allowed.Check("eu-west-1"))
+
+	err := allowed.Check("ap-south-1")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "eu-west-1, us-east-1")
+	}
+
+	assert.Nil(t, NewAllowedRegions([]string{""}), "blank entries do not form an allowlist")
+}
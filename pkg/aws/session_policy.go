@@ -0,0 +1,30 @@
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MaxSessionPolicySize is the AWS limit, in characters, on an AssumeRole inline session policy
+// once whitespace is removed
+const MaxSessionPolicySize = 2048
+
+// CheckSessionPolicySize returns an error if the packed policy exceeds limit characters, so an
+// oversized policy fails with a clear message instead of an opaque AssumeRole error. Limits outside
+// 1..MaxSessionPolicySize use the AWS limit.
+func CheckSessionPolicySize(policy string, limit int) error {
+	if limit <= 0 || limit > MaxSessionPolicySize {
+		limit = MaxSessionPolicySize
+	}
+
+	var packed bytes.Buffer
+	if err := json.Compact(&packed, []byte(policy)); err != nil {
+		return fmt.Errorf("invalid session policy: %w", err)
+	}
+
+	if packed.Len() > limit {
+		return fmt.Errorf("session policy is %d characters, exceeding the %d character limit", packed.Len(), limit)
+	}
+	return nil
+}
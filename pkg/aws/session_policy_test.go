@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateJITPolicySizeGuard(t *testing.T) {
+	policy, err := CreateJITPolicy("prod-east-1", "", []string{"view"})
+	require.NoError(t, err)
+	assert.Contains(t, policy, "cluster/prod-east-1")
+
+	_, err = CreateJITPolicy(strings.Repeat("c", MaxSessionPolicySize), "", []string{"view"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeding the 2048 character limit")
+}
+
+func TestCheckSessionPolicySize(t *testing.T) {
+	policy, err := CreateJITPolicy("prod-east-1", "", []string{"view"})
+	require.NoError(t, err)
+
+	// Whitespace is not counted, matching how AWS measures packed policies
+	padded := `{"Version": "2012-10-17",` + strings.Repeat(" ", 3000) + `"Statement": []}`
+	assert.NoError(t, CheckSessionPolicySize(padded, 0))
+
+	err = CheckSessionPolicySize(policy, 200)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeding the 200 character limit")
+
+	// Limits above what AWS accepts fall back to the AWS limit
+	err = CheckSessionPolicySize(`{"Resource":"`+strings.Repeat("c", MaxSessionPolicySize)+`"}`, MaxSessionPolicySize*2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeding the 2048 character limit")
+
+	err = CheckSessionPolicySize(`{"Version":`, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid session policy")
+}
@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 type STSService struct {
@@ -25,6 +27,12 @@ type AssumeRoleInput struct {
 	Tags            []types.Tag
 }
 
+// AssumeRole session duration bounds; STS rejects requests outside them
+const (
+	MinSessionDuration = 15 * time.Minute
+	MaxSessionDuration = 12 * time.Hour
+)
+
 type Credentials struct {
 	AccessKeyID     string
 	SecretAccessKey string
@@ -65,7 +73,9 @@ func (s *STSService) AssumeRole(ctx context.Context, input AssumeRoleInput) (*Cr
 		assumeRoleInput.Tags = input.Tags
 	}
 
+	start := time.Now()
 	result, err := s.client.AssumeRole(ctx, assumeRoleInput)
+	recordAWSCall(serviceSTS, "assume_role", s.region, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to assume role: %w", err)
 	}
@@ -88,7 +98,9 @@ func (s *STSService) AssumeRoleWithWebIdentity(
 		DurationSeconds:  aws.Int32(durationSeconds),
 	}
 
+	start := time.Now()
 	result, err := s.client.AssumeRoleWithWebIdentity(ctx, input)
+	recordAWSCall(serviceSTS, "assume_role_with_web_identity", s.region, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to assume role with web identity: %w", err)
 	}
@@ -102,17 +114,49 @@ func (s *STSService) AssumeRoleWithWebIdentity(
 }
 
 func (s *STSService) GetCallerIdentity(ctx context.Context) (*sts.GetCallerIdentityOutput, error) {
-	return s.client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	start := time.Now()
+	result, err := s.client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	recordAWSCall(serviceSTS, "get_caller_identity", s.region, start, err)
+	return result, err
+}
+
+// clusterTokenPrefix prefixes the bearer tokens EKS accepts for IAM identities
+const clusterTokenPrefix = "k8s-aws-v1."
+
+// ClusterToken returns a bearer token authenticating the operator's own AWS identity to the named
+// EKS cluster's API server: a presigned GetCallerIdentity URL bound to the cluster name
+func (s *STSService) ClusterToken(ctx context.Context, clusterName string) (string, error) {
+	presigner := sts.NewPresignClient(s.client)
+	start := time.Now()
+	req, err := presigner.PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{},
+		func(opts *sts.PresignOptions) {
+			opts.ClientOptions = append(opts.ClientOptions, sts.WithAPIOptions(
+				smithyhttp.AddHeaderValue("x-k8s-aws-id", clusterName),
+				smithyhttp.AddHeaderValue("X-Amz-Expires", "60"),
+			))
+		})
+	recordAWSCall(serviceSTS, "presign_get_caller_identity", s.region, start, err)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign cluster token: %w", err)
+	}
+	return clusterTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(req.URL)), nil
 }
 
 // GenerateJITSessionName creates a unique session name for JIT access
 func GenerateJITSessionName(userID, clusterID string) string {
-	timestamp := time.Now().Format("20060102-150405")
+	return JITSessionName(userID, clusterID, time.Now())
+}
+
+// JITSessionName derives the session name for access requested at requestedAt. The same inputs
+// always produce the same name, so grant and revoke agree on the assumed-role principal.
+func JITSessionName(userID, clusterID string, requestedAt time.Time) string {
+	timestamp := requestedAt.UTC().Format("20060102-150405")
 	return fmt.Sprintf("jit-%s-%s-%s", userID, clusterID, timestamp)
 }
 
-// CreateJITPolicy generates an IAM policy for limited EKS access
-func CreateJITPolicy(clusterName, namespace string, permissions []string) string {
+// CreateJITPolicy generates an IAM policy for limited EKS access. It fails if the policy would
+// exceed the session policy size limit.
+func CreateJITPolicy(clusterName, namespace string, permissions []string) (string, error) {
 	policy := `{
   "Version": "2012-10-17",
   "Statement": [
@@ -133,5 +177,10 @@ func CreateJITPolicy(clusterName, namespace string, permissions []string) string
     }
   ]
 }`
-	return fmt.Sprintf(policy, clusterName)
+	policy = fmt.Sprintf(policy, clusterName)
+
+	if err := CheckSessionPolicySize(policy, MaxSessionPolicySize); err != nil {
+		return "", fmt.Errorf("session policy for cluster %s: %w", clusterName, err)
+	}
+	return policy, nil
 }
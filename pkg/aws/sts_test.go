@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJITSessionName(t *testing.T) {
+	requestedAt := time.Date(2024, time.June, 10, 14, 30, 22, 0, time.UTC)
+
+	name := JITSessionName("U123", "cluster1", requestedAt)
+	assert.Equal(t, "jit-U123-cluster1-20240610-143022", name)
+
+	// The same inputs always produce the same name, regardless of the caller's timezone
+	local := requestedAt.In(time.FixedZone("UTC+9", 9*60*60))
+	assert.Equal(t, name, JITSessionName("U123", "cluster1", local))
+
+	assert.NotEqual(t, name, JITSessionName("U123", "cluster1", requestedAt.Add(time.Second)))
+}
+
+func TestClusterTokenIsBoundToCluster(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	service, err := NewSTSService("us-east-1")
+	require.NoError(t, err)
+
+	token, err := service.ClusterToken(context.Background(), "prod-east-1")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, clusterTokenPrefix))
+
+	presigned, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, clusterTokenPrefix))
+	require.NoError(t, err)
+	assert.Contains(t, string(presigned), "Action=GetCallerIdentity")
+	assert.Contains(t, string(presigned), "x-k8s-aws-id")
+}
@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Cost-allocation tag keys applied to JIT role sessions and EKS access entries
+const (
+	TagCostCenter = "CostCenter"
+	TagTeam       = "Team"
+)
+
+// maxTagValueLength is the longest tag value accepted by both STS session tags and EKS
+const maxTagValueLength = 256
+
+// tagValuePattern is the character set AWS allows in tag values
+var tagValuePattern = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// CostAllocationTags returns the cost-allocation tags for a session, omitting empty values
+func CostAllocationTags(costCenter, team string) map[string]string {
+	tags := make(map[string]string)
+	if costCenter != "" {
+		tags[TagCostCenter] = costCenter
+	}
+	if team != "" {
+		tags[TagTeam] = team
+	}
+	return tags
+}
+
+// ValidateTagValue checks a value against the AWS tag value length and character constraints
+func ValidateTagValue(value string) error {
+	if utf8.RuneCountInString(value) > maxTagValueLength {
+		return fmt.Errorf("must be at most %d characters", maxTagValueLength)
+	}
+	if !tagValuePattern.MatchString(value) {
+		return fmt.Errorf("may only contain letters, numbers, spaces and _.:/=+-@")
+	}
+	return nil
+}
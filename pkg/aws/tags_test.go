@@ -0,0 +1,109 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>AKIDEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func TestCostAllocationTags(t *testing.T) {
+	assert.Equal(t, map[string]string{TagCostCenter: "CC-1234", TagTeam: "payments"},
+		CostAllocationTags("CC-1234", "payments"))
+	assert.Equal(t, map[string]string{TagTeam: "payments"}, CostAllocationTags("", "payments"))
+	assert.Empty(t, CostAllocationTags("", ""))
+}
+
+func TestValidateTagValue(t *testing.T) {
+	assert.NoError(t, ValidateTagValue(""))
+	assert.NoError(t, ValidateTagValue("CC-1234"))
+	assert.NoError(t, ValidateTagValue("Platform Engineering / SRE@eu"))
+	assert.Error(t, ValidateTagValue("payments;drop"))
+	assert.Error(t, ValidateTagValue(strings.Repeat("a", 257)))
+}
+
+func TestAssumeRoleSendsCostAllocationTags(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(assumeRoleResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	service := &STSService{
+		client: sts.New(sts.Options{
+			Region:       "us-test-1",
+			BaseEndpoint: aws.String(server.URL),
+			Credentials:  aws.AnonymousCredentials{},
+			HTTPClient:   server.Client(),
+		}),
+		region: "us-test-1",
+	}
+
+	_, err := service.AssumeRole(context.Background(), AssumeRoleInput{
+		RoleArn:         "arn:aws:iam::123456789012:role/JITAccessRole",
+		SessionName:     "jit-session",
+		DurationSeconds: 3600,
+		Tags: []types.Tag{
+			{Key: aws.String(TagCostCenter), Value: aws.String("CC-1234")},
+			{Key: aws.String(TagTeam), Value: aws.String("payments")},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "AssumeRole", form.Get("Action"))
+	assert.Equal(t, TagCostCenter, form.Get("Tags.member.1.Key"))
+	assert.Equal(t, "CC-1234", form.Get("Tags.member.1.Value"))
+	assert.Equal(t, TagTeam, form.Get("Tags.member.2.Key"))
+	assert.Equal(t, "payments", form.Get("Tags.member.2.Value"))
+}
+
+func TestCreateJITAccessEntrySendsCostAllocationTags(t *testing.T) {
+	var tags map[string]string
+	service := newTestEKSService(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/access-entries") {
+			var input struct {
+				Tags map[string]string `json:"tags"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Errorf("Failed to decode create access entry input: %v", err)
+			}
+			tags = input.Tags
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	entry, err := service.CreateJITAccessEntry(context.Background(), "prod-east-1",
+		"arn:aws:sts::123456789012:assumed-role/JITAccessRole/jit-session", "jit:U1",
+		[]string{"view"}, nil, []string{"default"}, nil, CostAllocationTags("CC-1234", "payments"))
+	require.NoError(t, err)
+	assert.Equal(t, entry.Tags, tags, "the returned entry is the one sent to EKS")
+
+	assert.Equal(t, "CC-1234", tags[TagCostCenter])
+	assert.Equal(t, "payments", tags[TagTeam])
+	assert.Equal(t, "JITAccess", tags["Purpose"])
+}
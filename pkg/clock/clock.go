@@ -0,0 +1,18 @@
+// Package clock abstracts the current time so expiry and other time-based logic can be
+// driven deterministically in tests.
+package clock
+
+import "time"
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock backed by the system time
+type RealClock struct{}
+
+// Now returns time.Now()
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
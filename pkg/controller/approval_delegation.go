@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ApprovalDelegation routes an out-of-office approver's approvals to a delegate between From and
+// Until. The delegate's approval fills the approver's slot only when given inside that window.
+type ApprovalDelegation struct {
+	Approver string    `json:"approver"`
+	Delegate string    `json:"delegate"`
+	From     time.Time `json:"from"`
+	Until    time.Time `json:"until"`
+}
+
+// ApprovalDelegations is the set of configured approver delegations
+type ApprovalDelegations []ApprovalDelegation
+
+// LoadApprovalDelegations reads a JSON list of approver delegations, for example
+// [{"approver": "U0ALICE", "delegate": "U0BOB", "from": "2024-06-01T00:00:00Z", "until": "2024-06-15T00:00:00Z"}]
+func LoadApprovalDelegations(path string) (ApprovalDelegations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approval delegations: %w", err)
+	}
+
+	var delegations ApprovalDelegations
+	if err := json.Unmarshal(data, &delegations); err != nil {
+		return nil, fmt.Errorf("failed to parse approval delegations: %w", err)
+	}
+
+	for i := range delegations {
+		d := &delegations[i]
+		d.Approver = strings.TrimSpace(d.Approver)
+		d.Delegate = strings.TrimSpace(d.Delegate)
+		switch {
+		case d.Approver == "" || d.Delegate == "":
+			return nil, fmt.Errorf("approval delegation %d: approver and delegate are required", i)
+		case d.Approver == d.Delegate:
+			return nil, fmt.Errorf("approval delegation %d: %s cannot delegate to themselves", i, d.Approver)
+		case !d.Until.After(d.From):
+			return nil, fmt.Errorf("approval delegation %d: until must be after from", i)
+		}
+	}
+
+	return delegations, nil
+}
+
+// active reports whether the delegation is in effect at t
+func (d ApprovalDelegation) active(t time.Time) bool {
+	return !t.Before(d.From) && t.Before(d.Until)
+}
+
+// DelegatedApprovers returns the approvers whose slot delegate's approval at t fills
+func (ds ApprovalDelegations) DelegatedApprovers(delegate string, t time.Time) []string {
+	var approvers []string
+	for _, d := range ds {
+		if d.Delegate == delegate && d.active(t) {
+			approvers = append(approvers, d.Approver)
+		}
+	}
+	return approvers
+}
+
+// ActiveDelegates returns the delegates standing in for any of approvers at t, so they can be
+// notified of requests waiting on the approvers they cover
+func (ds ApprovalDelegations) ActiveDelegates(approvers []string, t time.Time) []string {
+	listed := make(map[string]bool, len(approvers))
+	for _, approver := range approvers {
+		listed[approver] = true
+	}
+
+	var delegates []string
+	seen := make(map[string]bool)
+	for _, d := range ds {
+		if listed[d.Approver] && !listed[d.Delegate] && !seen[d.Delegate] && d.active(t) {
+			seen[d.Delegate] = true
+			delegates = append(delegates, d.Delegate)
+		}
+	}
+	return delegates
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJITAccessRequestReconciler_DelegateApprovalWithinWindow(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	r := &JITAccessRequestReconciler{
+		ApprovalDelegations: ApprovalDelegations{
+			{Approver: "U0APPROVER1", Delegate: "U0DELEGATE1", From: from, Until: until},
+		},
+	}
+	approval := func(approver string, at time.Time) Approval {
+		return Approval{Approver: approver, ApprovedAt: metav1.NewTime(at)}
+	}
+
+	tests := []struct {
+		name      string
+		approvals []Approval
+		want      bool
+	}{
+		{
+			name:      "delegate approves during the window",
+			approvals: []Approval{approval("U0DELEGATE1", from.Add(time.Hour)), approval("U0APPROVER2", from)},
+			want:      true,
+		},
+		{
+			name:      "delegate approves before the window",
+			approvals: []Approval{approval("U0DELEGATE1", from.Add(-time.Hour)), approval("U0APPROVER2", from)},
+			want:      false,
+		},
+		{
+			name:      "delegate approves once the window has ended",
+			approvals: []Approval{approval("U0DELEGATE1", until), approval("U0APPROVER2", from)},
+			want:      false,
+		},
+		{
+			name:      "delegate approval does not fill another approver's slot",
+			approvals: []Approval{approval("U0DELEGATE1", from.Add(time.Hour)), approval("U0APPROVER1", from)},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest("test-request", "default", AccessPhasePending)
+			req.Spec.Approvers = []string{"U0APPROVER1", "U0APPROVER2"}
+			req.Status.Approvals = tt.approvals
+
+			assert.Equal(t, tt.want, r.hasRequiredApprovals(req))
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_DelegateApprovalCountsTowardsQuorum(t *testing.T) {
+	now := time.Now()
+	r := &JITAccessRequestReconciler{
+		ApprovalDelegations: ApprovalDelegations{
+			{Approver: "sre-team", Delegate: "U0DELEGATE1", From: now.Add(-time.Hour), Until: now.Add(time.Hour)},
+		},
+	}
+
+	req := createTestRequest("test-request", "default", AccessPhasePending)
+	req.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+	req.Spec.Approvers = []string{"sre-team"}
+	req.Status.Approvals = []Approval{{Approver: "U0DELEGATE1", ApprovedAt: metav1.NewTime(now)}}
+
+	assert.True(t, r.hasRequiredApprovals(req))
+}
+
+func TestJITAccessRequestReconciler_RequesterCannotApproveAsDelegate(t *testing.T) {
+	now := time.Now()
+	r := &JITAccessRequestReconciler{
+		ApprovalDelegations: ApprovalDelegations{
+			{Approver: "sre-team", Delegate: "U0DELEGATE1", From: now.Add(-time.Hour), Until: now.Add(time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		onBehalfOf *Delegate
+	}{
+		{name: "requester is the delegate", userID: "U0DELEGATE1"},
+		{
+			name:       "grantee is the delegate",
+			userID:     "U0MANAGER01",
+			onBehalfOf: &Delegate{UserID: "U0DELEGATE1", Email: "delegate@company.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest("test-request", "default", AccessPhasePending)
+			req.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+			req.Spec.UserID = tt.userID
+			req.Spec.OnBehalfOf = tt.onBehalfOf
+			req.Spec.Approvers = []string{"sre-team"}
+			req.Status.Approvals = []Approval{{Approver: "U0DELEGATE1", ApprovedAt: metav1.NewTime(now)}}
+
+			assert.False(t, r.hasRequiredApprovals(req))
+		})
+	}
+}
+
+func TestLoadApprovalDelegations(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "delegations.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	delegations, err := LoadApprovalDelegations(write(t, `[{"approver": " U0APPROVER1 ", "delegate": "U0DELEGATE1",
+		"from": "2024-06-01T00:00:00Z", "until": "2024-06-15T00:00:00Z"}]`))
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	assert.Equal(t, "U0APPROVER1", delegations[0].Approver)
+	assert.Equal(t, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), delegations[0].Until)
+
+	invalid := map[string]string{
+		"missing delegate": `[{"approver": "U0APPROVER1",
+			"from": "2024-06-01T00:00:00Z", "until": "2024-06-15T00:00:00Z"}]`,
+		"self delegation": `[{"approver": "U0APPROVER1", "delegate": "U0APPROVER1",
+			"from": "2024-06-01T00:00:00Z", "until": "2024-06-15T00:00:00Z"}]`,
+		"empty window": `[{"approver": "U0APPROVER1", "delegate": "U0DELEGATE1",
+			"from": "2024-06-15T00:00:00Z", "until": "2024-06-01T00:00:00Z"}]`,
+		"malformed": `{"approver": "U0APPROVER1"}`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadApprovalDelegations(write(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequiredApprovalsAnnotation records how many approvals a request needs before it is approved
+const RequiredApprovalsAnnotation = "jit.rebelops.io/required-approvals"
+
+// FallbackApproversAnnotation marks requests the mutating webhook assigned the production fallback
+// approvers because no approver policy matched them. Such requests are never auto-approved.
+const FallbackApproversAnnotation = "jit.rebelops.io/fallback-approvers"
+
+// SensitiveNamespacesAnnotation lists the sensitive namespaces, such as kube-system, the mutating
+// webhook found among a request's namespaces. Such requests are never auto-approved and need an
+// approval from one of SensitiveNamespaceApprovers.
+const SensitiveNamespacesAnnotation = "jit.rebelops.io/sensitive-namespaces"
+
+// SensitiveNamespaceApprovers must approve any request touching a sensitive namespace, whatever
+// the environment
+var SensitiveNamespaceApprovers = []string{"platform-team", "security-team"}
+
+// ApprovalPermissionAnnotation records the permission the mutating webhook routed the request's
+// approvals by. Custom permissions are routed by the built-in permission they grant.
+const ApprovalPermissionAnnotation = "jit.rebelops.io/approval-permission"
+
+// commentRequiredPermissions are elevated permissions whose approvals must carry a justifying comment
+var commentRequiredPermissions = map[string]bool{
+	"admin":         true,
+	"cluster-admin": true,
+}
+
+// ApprovalTier requires Approvals approvals for requests shorter than MaxDuration.
+// A zero MaxDuration matches any duration and should be the last tier.
+type ApprovalTier struct {
+	MaxDuration time.Duration
+	Approvals   int
+}
+
+// DefaultApprovalTiers scales the approval burden with the requested duration. Tiers are opt-in:
+// the mutating webhook applies them only when configured, and never below the listed approvers.
+var DefaultApprovalTiers = []ApprovalTier{
+	{MaxDuration: time.Hour, Approvals: 0},
+	{MaxDuration: 8 * time.Hour, Approvals: 1},
+	{MaxDuration: 0, Approvals: 2},
+}
+
+// RequiredApprovalsForDuration returns the approval count of the first tier matching duration
+func RequiredApprovalsForDuration(tiers []ApprovalTier, duration time.Duration) int {
+	for _, tier := range tiers {
+		if tier.MaxDuration == 0 || duration < tier.MaxDuration {
+			return tier.Approvals
+		}
+	}
+	return 0
+}
+
+// requiredApprovals reads the approval quorum set at admission time
+func requiredApprovals(jitReq *JITAccessRequest) (int, bool) {
+	value, ok := jitReq.Annotations[RequiredApprovalsAnnotation]
+	if !ok {
+		return 0, false
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, false
+	}
+	return count, true
+}
+
+// minApprovals returns the approval floor for the environment of the request's target cluster
+func (r *JITAccessRequestReconciler) minApprovals(jitReq *JITAccessRequest) int {
+	return r.MinApprovals[r.clusterEnvironment(jitReq)]
+}
+
+// ParseMinApprovals reads comma-separated environment=count pairs, e.g. production=2,staging=1
+func ParseMinApprovals(value string) (map[string]int, error) {
+	floors := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		env, rawCount, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(env) == "" {
+			return nil, fmt.Errorf("invalid minimum approvals %q: want environment=count", pair)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(rawCount))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid minimum approvals for %s: %q is not a non-negative count", env, rawCount)
+		}
+		floors[strings.ToLower(strings.TrimSpace(env))] = count
+	}
+	return floors, nil
+}
+
+// RequiresApprovalComment reports whether approvals of the request only count when the approver
+// leaves a comment, which is the case for elevated permissions, including custom permissions routed
+// as one
+func RequiresApprovalComment(jitReq *JITAccessRequest) bool {
+	if commentRequiredPermissions[jitReq.Annotations[ApprovalPermissionAnnotation]] {
+		return true
+	}
+	for _, permission := range jitReq.Spec.Permissions {
+		if commentRequiredPermissions[strings.ToLower(permission)] {
+			return true
+		}
+	}
+	return false
+}
+
+// touchesSensitiveNamespace reports whether the mutating webhook marked the request as touching a
+// sensitive namespace
+func touchesSensitiveNamespace(jitReq *JITAccessRequest) bool {
+	return jitReq.Annotations[SensitiveNamespacesAnnotation] != ""
+}
+
+// hasSensitiveNamespaceApproval reports whether one of the sensitive namespace approvers is among
+// those who approved
+func hasSensitiveNamespaceApproval(approvedBy map[string]bool) bool {
+	for _, approver := range SensitiveNamespaceApprovers {
+		if approvedBy[approver] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasApprovalComment reports whether the approval carries a non-blank comment
+func hasApprovalComment(approval Approval) bool {
+	return strings.TrimSpace(approval.Comment) != ""
+}
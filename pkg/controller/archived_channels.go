@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// isChannelArchived reports whether an active request's Slack channel has been archived. Lookup
+// failures are logged and treated as not archived, so a Slack outage never revokes access; the
+// check runs again on the next periodic reconcile.
+func (r *JITAccessRequestReconciler) isChannelArchived(ctx context.Context, jitReq *JITAccessRequest) bool {
+	if r.ArchivedChannels == nil || jitReq.Spec.SlackChannel == "" {
+		return false
+	}
+
+	archived, err := r.ArchivedChannels.IsChannelArchived(ctx, jitReq.Spec.SlackChannel)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to check Slack channel", "channel", jitReq.Spec.SlackChannel)
+		return false
+	}
+	return archived
+}
+
+// revokeForArchivedChannel moves the request to Revoked; the expired-request handling then has
+// its job remove the access entry and secrets
+func (r *JITAccessRequestReconciler) revokeForArchivedChannel(
+	ctx context.Context, jitReq *JITAccessRequest,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	message := fmt.Sprintf("Access revoked because Slack channel %s was archived", jitReq.Spec.SlackChannel)
+	jitReq.Status.Phase = AccessPhaseRevoked
+	jitReq.Status.Message = message
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Revoked",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "SlackChannelArchived",
+		Message:            message,
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	r.publishEvent(ctx, AccessEventRevoked, jitReq)
+	log.Info("Revoked access requested from an archived Slack channel",
+		"request", jitReq.Name, "user", jitReq.Spec.UserID, "channel", jitReq.Spec.SlackChannel)
+	return ctrl.Result{RequeueAfter: time.Second}, nil
+}
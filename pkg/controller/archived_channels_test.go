@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type fakeChannelChecker struct {
+	archived map[string]bool
+	err      error
+	checked  []string
+}
+
+func (f *fakeChannelChecker) IsChannelArchived(_ context.Context, channelID string) (bool, error) {
+	f.checked = append(f.checked, channelID)
+	return f.archived[channelID], f.err
+}
+
+func TestJITAccessRequestReconciler_RevokesRequestFromArchivedChannel(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	request := createTestRequest("archived-channel-request", "default", AccessPhaseActive)
+	request.Spec.SlackChannel = "C0ARCHIVED"
+	job := createTestJob("archived-channel-request", "default")
+	job.Name = JobName(request)
+	job.Status = JITAccessJobStatus{
+		Phase:      JobPhaseActive,
+		ExpiryTime: &metav1.Time{Time: time.Now().Add(time.Hour)},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+
+	checker := &fakeChannelChecker{archived: map[string]bool{"C0ARCHIVED": true}}
+	reconciler := createTestReconciler(fakeClient, scheme, request.Spec.UserID)
+	reconciler.ArchivedChannels = checker
+
+	requestKey := types.NamespacedName{Name: request.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: requestKey})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(ctx, requestKey, &updated))
+	assert.Equal(t, AccessPhaseRevoked, updated.Status.Phase)
+	assert.Contains(t, updated.Status.Message, "C0ARCHIVED")
+	revoked := meta.FindStatusCondition(updated.Status.Conditions, "Revoked")
+	require.NotNil(t, revoked)
+	assert.Equal(t, "SlackChannelArchived", revoked.Reason)
+	assert.Equal(t, []string{"C0ARCHIVED"}, checker.checked)
+
+	// The revoked request then has its job clean up the session
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: requestKey})
+	require.NoError(t, err)
+
+	var updatedJob JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: "default"}, &updatedJob))
+	assert.Equal(t, JobPhaseExpiring, updatedJob.Status.Phase)
+}
+
+func TestJITAccessRequestReconciler_KeepsRequestWhenChannelNotArchived(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		checker *fakeChannelChecker
+	}{
+		{
+			name:    "channel still open",
+			channel: "C0OPEN",
+			checker: &fakeChannelChecker{archived: map[string]bool{"C0ARCHIVED": true}},
+		},
+		{
+			name:    "lookup fails",
+			channel: "C0ARCHIVED",
+			checker: &fakeChannelChecker{
+				archived: map[string]bool{"C0ARCHIVED": true},
+				err:      errors.New("slack rejected lookup of C0ARCHIVED: ratelimited"),
+			},
+		},
+		{
+			name:    "no channel recorded",
+			checker: &fakeChannelChecker{archived: map[string]bool{"": true}},
+		},
+		{
+			name:    "check disabled",
+			channel: "C0ARCHIVED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			request := createTestRequest("open-channel-request", "default", AccessPhaseActive)
+			request.Spec.SlackChannel = tt.channel
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(request).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+
+			reconciler := createTestReconciler(fakeClient, scheme, request.Spec.UserID)
+			if tt.checker != nil {
+				reconciler.ArchivedChannels = tt.checker
+			}
+
+			key := types.NamespacedName{Name: request.Name, Namespace: "default"}
+			_, err := reconciler.Reconcile(t.Context(), reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(t.Context(), key, &updated))
+			assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/cel-go/cel"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AutoApprovalRule auto-approves requests its CEL expression matches. The expression sees userID,
+// role, permissions, duration, environment, cluster and namespaces, for example
+// environment == 'development' && duration <= duration('2h'). environment is the cluster's registry
+// tag, or else production, staging, development or qa as guessed from the cluster name. Requests
+// routed to fallback approvers or touching sensitive namespaces are never auto-approved, whatever
+// the rules say.
+type AutoApprovalRule struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// AutoApprovalRules are compiled auto-approval rules, evaluated in order
+type AutoApprovalRules struct {
+	rules    []AutoApprovalRule
+	programs []cel.Program
+}
+
+// autoApprovalEnv declares the request fields auto-approval expressions can reference
+func autoApprovalEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("userID", cel.StringType),
+		cel.Variable("role", cel.StringType),
+		cel.Variable("permissions", cel.ListType(cel.StringType)),
+		cel.Variable("duration", cel.DurationType),
+		cel.Variable("environment", cel.StringType),
+		cel.Variable("cluster", cel.StringType),
+		cel.Variable("namespaces", cel.ListType(cel.StringType)),
+	)
+}
+
+// CompileAutoApprovalRules type-checks every rule, which must evaluate to a bool
+func CompileAutoApprovalRules(rules []AutoApprovalRule) (*AutoApprovalRules, error) {
+	env, err := autoApprovalEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	compiled := &AutoApprovalRules{rules: rules}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("auto-approval rule %d: a name is required", i)
+		}
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("auto-approval rule %s: %w", rule.Name, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("auto-approval rule %s: expression must evaluate to a bool, not %s",
+				rule.Name, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("auto-approval rule %s: %w", rule.Name, err)
+		}
+		compiled.programs = append(compiled.programs, program)
+	}
+	return compiled, nil
+}
+
+// LoadAutoApprovalRules reads and compiles a JSON list of auto-approval rules, for example
+// [{"name": "short-dev", "expression": "environment == 'development' && duration <= duration('2h')"}]
+func LoadAutoApprovalRules(path string) (*AutoApprovalRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auto-approval rules: %w", err)
+	}
+
+	var rules []AutoApprovalRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse auto-approval rules: %w", err)
+	}
+	return CompileAutoApprovalRules(rules)
+}
+
+// Match returns the name of the first rule that approves the request. A rule that fails to evaluate,
+// for example on a malformed duration, does not approve.
+func (a *AutoApprovalRules) Match(ctx context.Context, input map[string]any) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+
+	for i, program := range a.programs {
+		out, _, err := program.ContextEval(ctx, input)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "auto-approval rule failed to evaluate", "rule", a.rules[i].Name)
+			continue
+		}
+		if approved, ok := out.Value().(bool); ok && approved {
+			return a.rules[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// autoApprovalInput exposes the request fields auto-approval rules are evaluated against. An
+// unparseable duration is passed as a string so rules comparing it fail rather than match.
+func (r *JITAccessRequestReconciler) autoApprovalInput(jitReq *JITAccessRequest) map[string]any {
+	var duration any = jitReq.Spec.Duration
+	if parsed, err := ParseDuration(jitReq.Spec.Duration); err == nil {
+		duration = parsed
+	}
+
+	role := ""
+	if r.RBAC != nil {
+		role = string(r.RBAC.GetUserRole(jitReq.Spec.UserID))
+	}
+
+	return map[string]any{
+		"userID":      jitReq.Spec.UserID,
+		"role":        role,
+		"permissions": stringList(jitReq.Spec.Permissions),
+		"duration":    duration,
+		"environment": r.clusterEnvironment(jitReq),
+		"cluster":     jitReq.Spec.TargetCluster.Name,
+		"namespaces":  stringList(jitReq.Spec.Namespaces),
+	}
+}
+
+// autoApprovalRule returns the configured rule that auto-approves the request, if any
+func (r *JITAccessRequestReconciler) autoApprovalRule(ctx context.Context, jitReq *JITAccessRequest) (string, bool) {
+	if r.AutoApprovalRules == nil {
+		return "", false
+	}
+	return r.AutoApprovalRules.Match(ctx, r.autoApprovalInput(jitReq))
+}
+
+// stringList keeps nil slices as empty CEL lists
+func stringList(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+)
+
+const shortDevExpression = "environment == 'development' && duration <= duration('2h')"
+
+func TestCompileAutoApprovalRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    AutoApprovalRule
+		wantErr string
+	}{
+		{
+			name: "valid rule",
+			rule: AutoApprovalRule{Name: "short-dev", Expression: shortDevExpression},
+		},
+		{
+			name:    "missing name",
+			rule:    AutoApprovalRule{Expression: shortDevExpression},
+			wantErr: "a name is required",
+		},
+		{
+			name:    "syntax error",
+			rule:    AutoApprovalRule{Name: "broken", Expression: "environment =="},
+			wantErr: "auto-approval rule broken",
+		},
+		{
+			name:    "unknown field",
+			rule:    AutoApprovalRule{Name: "unknown", Expression: "team == 'payments'"},
+			wantErr: "undeclared reference",
+		},
+		{
+			name:    "not a bool",
+			rule:    AutoApprovalRule{Name: "string", Expression: "environment"},
+			wantErr: "must evaluate to a bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileAutoApprovalRules([]AutoApprovalRule{tt.rule})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAutoApprovalRulesMatch(t *testing.T) {
+	rules, err := CompileAutoApprovalRules([]AutoApprovalRule{
+		{Name: "short-dev", Expression: shortDevExpression},
+		{Name: "approver-view", Expression: "role == 'approver' && permissions.all(p, p == 'view')"},
+	})
+	require.NoError(t, err)
+
+	rbac := auth.NewRBAC(nil)
+	rbac.SetUserRole("U123456789A", auth.RoleApprover)
+	reconciler := &JITAccessRequestReconciler{RBAC: rbac, AutoApprovalRules: rules}
+	newRequest := func(cluster, duration string, permissions ...string) *JITAccessRequest {
+		jitReq := createTestRequest("rule-request", "default", AccessPhasePending)
+		jitReq.Spec.TargetCluster.Name = cluster
+		jitReq.Spec.Duration = duration
+		jitReq.Spec.Permissions = permissions
+		return jitReq
+	}
+
+	tests := []struct {
+		name     string
+		request  *JITAccessRequest
+		wantRule string
+	}{
+		{name: "short dev request", request: newRequest("dev-east-1", "2h", "edit"), wantRule: "short-dev"},
+		{
+			name:     "approver viewing production",
+			request:  newRequest("prod-east-1", "8h", "view"),
+			wantRule: "approver-view",
+		},
+		{name: "day-suffixed duration", request: newRequest("dev-east-1", "1d", "edit")},
+		{name: "long dev request", request: newRequest("dev-east-1", "3h", "edit")},
+		{name: "production request", request: newRequest("prod-east-1", "1h", "edit")},
+		{name: "unparseable duration fails closed", request: newRequest("dev-east-1", "soon", "edit")},
+		{name: "environment label is ignored", request: labelledRequest("prod-east-1", "development")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := reconciler.autoApprovalRule(t.Context(), tt.request)
+			assert.Equal(t, tt.wantRule != "", ok)
+			assert.Equal(t, tt.wantRule, rule)
+		})
+	}
+}
+
+// labelledRequest is a short edit request whose environment label disagrees with its cluster
+func labelledRequest(cluster, environment string) *JITAccessRequest {
+	jitReq := createTestRequest("rule-request", "default", AccessPhasePending)
+	jitReq.Spec.TargetCluster.Name = cluster
+	jitReq.Labels = map[string]string{"jit.rebelops.io/environment": environment}
+	jitReq.Spec.Duration = "1h"
+	jitReq.Spec.Permissions = []string{"edit"}
+	return jitReq
+}
+
+func TestJITAccessRequestReconciler_AutoApprovesByRule(t *testing.T) {
+	scheme := setupTestScheme(t)
+	rules, err := CompileAutoApprovalRules([]AutoApprovalRule{{Name: "short-dev", Expression: shortDevExpression}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		duration  string
+		wantPhase AccessPhase
+	}{
+		{name: "matching request is approved", duration: "90m", wantPhase: AccessPhaseApproved},
+		{name: "other requests wait for approvers", duration: "4h", wantPhase: AccessPhasePending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			jitReq := createTestRequest("rule-request", "default", AccessPhasePending)
+			jitReq.Spec.Duration = tt.duration
+			jitReq.Spec.Permissions = []string{"edit"}
+			jitReq.Spec.Approvers = []string{"platform-team"}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(jitReq).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+			reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+			reconciler.AutoApprovalRules = rules
+
+			key := types.NamespacedName{Name: jitReq.Name, Namespace: jitReq.Namespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(ctx, key, &updated))
+			assert.Equal(t, tt.wantPhase, updated.Status.Phase)
+			if tt.wantPhase == AccessPhaseApproved {
+				approved := meta.FindStatusCondition(updated.Status.Conditions, "Approved")
+				require.NotNil(t, approved)
+				assert.Equal(t, "AutoApprovalRule", approved.Reason)
+				assert.Contains(t, approved.Message, "short-dev")
+			}
+		})
+	}
+}
+
+func TestAutoApprovalRuleEnvironments(t *testing.T) {
+	reconciler := &JITAccessRequestReconciler{
+		RBAC:                auth.NewRBAC(nil),
+		ClusterEnvironments: map[string]string{"payments-east-1": "Production"},
+	}
+	request := func(cluster string) *JITAccessRequest {
+		jitReq := createTestRequest("rule-request", "default", AccessPhasePending)
+		jitReq.Spec.TargetCluster.Name = cluster
+		jitReq.Spec.Duration = "1h"
+		jitReq.Spec.Permissions = []string{"edit"}
+		return jitReq
+	}
+
+	tests := []struct {
+		cluster     string
+		environment string
+	}{
+		{cluster: "prod-east-1", environment: "production"},
+		{cluster: "staging-east-1", environment: "staging"},
+		{cluster: "dev-east-1", environment: "development"},
+		{cluster: "qa-east-1", environment: "qa"},
+		{cluster: "sandbox-east-1", environment: "production"},
+		{cluster: "payments-east-1", environment: "production"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cluster, func(t *testing.T) {
+			rules, err := CompileAutoApprovalRules([]AutoApprovalRule{
+				{Name: "abbreviated", Expression: "environment == 'dev'"},
+				{Name: tt.environment, Expression: "environment == '" + tt.environment + "'"},
+			})
+			require.NoError(t, err)
+			reconciler.AutoApprovalRules = rules
+
+			rule, ok := reconciler.autoApprovalRule(t.Context(), request(tt.cluster))
+			assert.True(t, ok)
+			assert.Equal(t, tt.environment, rule, "environment is always the full name")
+		})
+	}
+}
+
+func TestShouldAutoApproveKeepsProtectedRequestsFromRules(t *testing.T) {
+	rules, err := CompileAutoApprovalRules([]AutoApprovalRule{{Name: "everything", Expression: "true"}})
+	require.NoError(t, err)
+	reconciler := &JITAccessRequestReconciler{RBAC: auth.NewRBAC(nil), AutoApprovalRules: rules}
+
+	request := func(annotations map[string]string) *JITAccessRequest {
+		jitReq := createTestRequest("rule-request", "default", AccessPhasePending)
+		jitReq.Spec.TargetCluster.Name = "dev-east-1"
+		jitReq.Spec.Duration = "1h"
+		jitReq.Spec.Permissions = []string{"edit"}
+		jitReq.Annotations = annotations
+		return jitReq
+	}
+
+	assert.True(t, reconciler.shouldAutoApprove(t.Context(), request(nil)))
+	assert.False(t, reconciler.shouldAutoApprove(t.Context(),
+		request(map[string]string{FallbackApproversAnnotation: "true"})), "fallback approvers")
+	assert.False(t, reconciler.shouldAutoApprove(t.Context(),
+		request(map[string]string{SensitiveNamespacesAnnotation: "kube-system"})), "sensitive namespaces")
+}
+
+func TestApprovedWithoutApprovalsIgnoresRecordedApprovals(t *testing.T) {
+	reconciler := &JITAccessRequestReconciler{RBAC: auth.NewRBAC(nil)}
+
+	jitReq := createTestRequest("preview-request", "default", AccessPhasePending)
+	jitReq.Spec.Permissions = []string{"edit"}
+	jitReq.Spec.Approvers = []string{"approver1"}
+	jitReq.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+	jitReq.Status.Approvals = []Approval{{Approver: "approver1", ApprovedAt: metav1.Now()}}
+
+	assert.True(t, reconciler.approved(t.Context(), jitReq))
+	assert.False(t, reconciler.ApprovedWithoutApprovals(t.Context(), jitReq))
+	assert.Len(t, jitReq.Status.Approvals, 1, "the request itself is left untouched")
+
+	jitReq.Spec.Approvers = nil
+	jitReq.Annotations = nil
+	assert.True(t, reconciler.ApprovedWithoutApprovals(t.Context(), jitReq))
+}
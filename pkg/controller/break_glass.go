@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// EmergencyAnnotation marks a request as an emergency. View-only emergency requests are
+// granted immediately (break-glass); elevated emergency requests still need approval.
+const EmergencyAnnotation = "jit.rebelops.io/emergency"
+
+// breakGlassViolation is the security violation type recorded for every break-glass grant
+const breakGlassViolation = "break_glass_view"
+
+// isViewOnlyBreakGlass reports whether the request is an emergency asking only for view access
+// from a verified user allowed to create requests. Requests touching a sensitive namespace, or
+// targeting a cluster with an approval floor, always wait for their approvers.
+func (r *JITAccessRequestReconciler) isViewOnlyBreakGlass(jitReq *JITAccessRequest) bool {
+	if jitReq.Annotations[EmergencyAnnotation] != "true" || len(jitReq.Spec.Permissions) == 0 {
+		return false
+	}
+
+	// Requesters write spec.userID themselves, so only a vouched-for identity may skip approval
+	if jitReq.Annotations[RequesterVerifiedAnnotation] != "true" {
+		return false
+	}
+	if touchesSensitiveNamespace(jitReq) || r.minApprovals(jitReq) > 0 {
+		return false
+	}
+
+	for _, permission := range jitReq.Spec.Permissions {
+		if permission != "view" {
+			return false
+		}
+	}
+
+	return r.RBAC.UserHasPermission(jitReq.Spec.UserID, auth.PermissionCreateRequests)
+}
+
+// grantBreakGlass approves a view-only emergency request without waiting for approvers, then
+// records it as a security event and tells the approvers loudly
+func (r *JITAccessRequestReconciler) grantBreakGlass(ctx context.Context, jitReq *JITAccessRequest) error {
+	log := log.FromContext(ctx)
+
+	now := metav1.NewTime(r.now())
+	jitReq.Status.Phase = AccessPhaseApproved
+	jitReq.Status.Message = "Break-glass view access granted without approval"
+	jitReq.Status.Conditions = []metav1.Condition{
+		{
+			Type:               "Submitted",
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: now,
+			Reason:             "RequestSubmitted",
+			Message:            "JIT access request has been submitted",
+		},
+		{
+			Type:               "Approved",
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: now,
+			Reason:             "BreakGlassViewAccess",
+			Message:            "View-only emergency access granted immediately; approvers notified",
+		},
+	}
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		return err
+	}
+
+	metrics.RecordSecurityViolation(breakGlassViolation, jitReq.Spec.UserID, jitReq.Spec.TargetCluster.Name)
+	log.Info("Break-glass view access granted",
+		"user", jitReq.Spec.UserID, "cluster", jitReq.Spec.TargetCluster.Name, "reason", jitReq.Spec.Reason)
+
+	if r.Notifier != nil {
+		if err := r.Notifier.NotifyBreakGlass(ctx, jitReq); err != nil {
+			log.Error(err, "unable to send break-glass notification")
+		}
+	}
+	r.publishEvent(ctx, AccessEventApproved, jitReq)
+
+	return nil
+}
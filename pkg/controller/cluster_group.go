@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ClusterGroups maps a group name to the related clusters a group request is provisioned on,
+// such as the shards of one production service
+type ClusterGroups map[string][]TargetCluster
+
+// LoadClusterGroups reads a JSON object of cluster groups, for example
+// {"prod-shards": [{"name": "prod-shard-1", "awsAccount": "123456789012", "region": "us-east-1"}]}
+func LoadClusterGroups(path string) (ClusterGroups, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster groups: %w", err)
+	}
+
+	var groups ClusterGroups
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster groups: %w", err)
+	}
+
+	for name, members := range groups {
+		if len(members) == 0 {
+			return nil, fmt.Errorf("cluster group %s: at least one member is required", name)
+		}
+		seen := make(map[string]bool, len(members))
+		for i, member := range members {
+			if member.Name == "" || member.AWSAccount == "" || member.Region == "" {
+				return nil, fmt.Errorf("cluster group %s: member %d needs a name, awsAccount and region", name, i)
+			}
+			key := strings.ToLower(member.Name)
+			if seen[key] {
+				return nil, fmt.Errorf("cluster group %s: %s is listed twice", name, member.Name)
+			}
+			seen[key] = true
+		}
+	}
+
+	return groups, nil
+}
+
+// HasMember reports whether the named group contains the cluster
+func (g ClusterGroups) HasMember(group, cluster string) bool {
+	for _, member := range g[group] {
+		if strings.EqualFold(member.Name, cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterJobName returns the name of the job provisioning one member cluster of a group request
+func ClusterJobName(jitReq *JITAccessRequest, cluster string) string {
+	return fmt.Sprintf("%s-%s", JobName(jitReq), strings.ToLower(cluster))
+}
+
+// provisionClusterGroup creates one JITAccessJob per member of the request's cluster group and
+// records them on the request, which then tracks their combined status
+func (r *JITAccessRequestReconciler) provisionClusterGroup(
+	ctx context.Context, jitReq *JITAccessRequest,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	members, ok := r.ClusterGroups[jitReq.Spec.ClusterGroup]
+	if !ok {
+		return r.rejectClusterGroup(ctx, jitReq)
+	}
+
+	clusterJobs := make([]ClusterJobStatus, 0, len(members))
+	for _, member := range members {
+		job := r.createJITAccessJob(jitReq)
+		job.Name = ClusterJobName(jitReq, member.Name)
+		job.Labels["jit.rebelops.io/cluster"] = member.Name
+		job.Labels["jit.rebelops.io/cluster-group"] = jitReq.Spec.ClusterGroup
+		job.Spec.TargetCluster = member
+		job.Spec.JITRoleArn = r.getJITRoleArn(member)
+
+		if err := controllerutil.SetControllerReference(jitReq, job, r.Scheme); err != nil {
+			log.Error(err, "unable to set owner reference")
+			return ctrl.Result{}, err
+		}
+
+		// Jobs created by an earlier, partly failed attempt are kept
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create JITAccessJob", "cluster", member.Name)
+			jitReq.Status.ProvisioningAttempts++
+			if int(jitReq.Status.ProvisioningAttempts) >= r.maxProvisioningAttempts() {
+				return r.deadLetterRequest(ctx, jitReq, err)
+			}
+
+			jitReq.Status.Message = fmt.Sprintf("Failed to create access job for %s: %v", member.Name, err)
+			if updateErr := r.Status().Update(ctx, jitReq); updateErr != nil {
+				log.Error(updateErr, "unable to update JITAccessRequest status after job creation failure")
+			}
+			return ctrl.Result{}, err
+		}
+
+		clusterJobs = append(clusterJobs, ClusterJobStatus{
+			Cluster: member.Name,
+			JobName: job.Name,
+			Phase:   JobPhasePending,
+		})
+	}
+
+	jitReq.Status.ClusterJobs = clusterJobs
+	jitReq.Status.Phase = AccessPhaseActive
+	jitReq.Status.Message = fmt.Sprintf("Access provisioning in progress on %d clusters", len(clusterJobs))
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Provisioning",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "JobCreated",
+		Message: fmt.Sprintf("JIT access jobs have been created for the %d clusters in group %s",
+			len(clusterJobs), jitReq.Spec.ClusterGroup),
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Created JITAccessJobs for cluster group", "group", jitReq.Spec.ClusterGroup, "jobs", len(clusterJobs))
+	return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+}
+
+// rejectClusterGroup fails a request whose cluster group is no longer configured, e.g. because
+// the operator was restarted with a different --cluster-groups file after the request was admitted
+func (r *JITAccessRequestReconciler) rejectClusterGroup(
+	ctx context.Context, jitReq *JITAccessRequest,
+) (ctrl.Result, error) {
+	message := fmt.Sprintf("Cluster group %s is not configured", jitReq.Spec.ClusterGroup)
+
+	jitReq.Status.Phase = AccessPhaseFailed
+	jitReq.Status.Message = message
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Provisioning",
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "UnknownClusterGroup",
+		Message:            message,
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.FromContext(ctx).Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncWithClusterJobs aggregates the status of a group request's jobs. The request is granted
+// once every member has access and fails as soon as any member fails, in which case the other
+// members' access is revoked so the group is never left half-provisioned.
+func (r *JITAccessRequestReconciler) syncWithClusterJobs(
+	ctx context.Context, jitReq *JITAccessRequest,
+) (ctrl.Result, error) {
+	jobs, err := r.requestJobs(ctx, jitReq)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var failed *JITAccessJob
+	var earliest *JITAccessJob
+	provisioned := 0
+	for i := range jobs {
+		job := &jobs[i]
+		r.recordClusterJob(jitReq, job)
+
+		switch {
+		case job.Status.Phase == JobPhaseFailed:
+			if failed == nil {
+				failed = job
+			}
+		case job.Status.AccessEntry != nil && job.Status.StartTime != nil && job.Status.ExpiryTime != nil:
+			provisioned++
+			if earliest == nil || job.Status.ExpiryTime.Before(earliest.Status.ExpiryTime) {
+				earliest = job
+			}
+		}
+	}
+
+	if failed != nil {
+		result, err := r.failRequest(ctx, jitReq, failed)
+		if err != nil {
+			return result, err
+		}
+		for i := range jobs {
+			if _, err := r.expireJob(ctx, jitReq, &jobs[i]); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return result, nil
+	}
+
+	jitReq.Status.Message = fmt.Sprintf("Access provisioned on %d of %d clusters",
+		provisioned, len(jitReq.Status.ClusterJobs))
+
+	// The request expires with its first member, at which point every member is cleaned up
+	granted := provisioned == len(jitReq.Status.ClusterJobs) && jitReq.Status.AccessEntry == nil
+	if granted {
+		jitReq.Status.AccessEntry = &AccessEntryStatus{
+			PrincipalArn: earliest.Status.AccessEntry.PrincipalArn,
+			SessionName:  earliest.Status.AccessEntry.SessionName,
+			CreatedAt:    *earliest.Status.StartTime,
+			ExpiresAt:    *earliest.Status.ExpiryTime,
+		}
+
+		r.setCondition(jitReq, metav1.Condition{
+			Type:               "AccessGranted",
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "AccessProvisioned",
+			Message: fmt.Sprintf("JIT access has been provisioned on all %d clusters in group %s",
+				provisioned, jitReq.Spec.ClusterGroup),
+		})
+	}
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		return ctrl.Result{}, err
+	}
+	if granted {
+		r.publishEvent(ctx, AccessEventGranted, jitReq)
+	}
+
+	// Check again in 2 minutes
+	return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+}
+
+// recordClusterJob copies a member job's phase onto the request's cluster job list
+func (r *JITAccessRequestReconciler) recordClusterJob(jitReq *JITAccessRequest, job *JITAccessJob) {
+	for i := range jitReq.Status.ClusterJobs {
+		if jitReq.Status.ClusterJobs[i].JobName == job.Name {
+			jitReq.Status.ClusterJobs[i].Phase = job.Status.Phase
+		}
+	}
+}
+
+// requestJobs returns the jobs provisioning the request: the single job of a one-cluster request,
+// or every recorded member job of a group request. Jobs that no longer exist are skipped.
+func (r *JITAccessRequestReconciler) requestJobs(
+	ctx context.Context, jitReq *JITAccessRequest,
+) ([]JITAccessJob, error) {
+	names := []string{JobName(jitReq)}
+	if jitReq.Spec.ClusterGroup != "" {
+		names = names[:0]
+		for _, clusterJob := range jitReq.Status.ClusterJobs {
+			names = append(names, clusterJob.JobName)
+		}
+	}
+
+	jobs := make([]JITAccessJob, 0, len(names))
+	for _, name := range names {
+		var job JITAccessJob
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: jitReq.Namespace}, &job); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
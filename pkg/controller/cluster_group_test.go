@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var prodShards = ClusterGroups{
+	"prod-shards": {
+		{Name: "prod-shard-1", AWSAccount: "123456789012", Region: "us-east-1"},
+		{Name: "prod-shard-2", AWSAccount: "123456789012", Region: "us-east-1"},
+		{Name: "prod-shard-3", AWSAccount: "210987654321", Region: "us-west-2"},
+	},
+}
+
+func TestLoadClusterGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid groups",
+			content: `{"prod-shards": [{"name": "prod-shard-1", "awsAccount": "123456789012", "region": "us-east-1"}]}`,
+		},
+		{
+			name:    "empty group",
+			content: `{"prod-shards": []}`,
+			wantErr: "at least one member",
+		},
+		{
+			name:    "member without coordinates",
+			content: `{"prod-shards": [{"name": "prod-shard-1"}]}`,
+			wantErr: "needs a name, awsAccount and region",
+		},
+		{
+			name: "duplicate member",
+			content: `{"prod-shards": [{"name": "prod-shard-1", "awsAccount": "123456789012", "region": "us-east-1"},
+				{"name": "PROD-SHARD-1", "awsAccount": "123456789012", "region": "us-east-1"}]}`,
+			wantErr: "listed twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cluster-groups.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			groups, err := LoadClusterGroups(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, groups.HasMember("prod-shards", "PROD-SHARD-1"))
+			assert.False(t, groups.HasMember("prod-shards", "prod-shard-2"))
+		})
+	}
+}
+
+func createGroupRequest(phase AccessPhase) *JITAccessRequest {
+	jitReq := createTestRequest("group-request", "default", phase)
+	jitReq.Spec.TargetCluster = prodShards["prod-shards"][0]
+	jitReq.Spec.ClusterGroup = "prod-shards"
+	return jitReq
+}
+
+func TestJITAccessRequestReconciler_ClusterGroupSpawnsJobPerMember(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	jitReq := createGroupRequest(AccessPhaseApproved)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(jitReq).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+	reconciler.ClusterGroups = prodShards
+
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+
+	var jobs JITAccessJobList
+	require.NoError(t, fakeClient.List(ctx, &jobs, client.InNamespace("default")))
+	require.Len(t, jobs.Items, 3)
+
+	clusters := make(map[string]JITAccessJob)
+	for _, job := range jobs.Items {
+		clusters[job.Spec.TargetCluster.Name] = job
+	}
+	for _, member := range prodShards["prod-shards"] {
+		job, ok := clusters[member.Name]
+		require.True(t, ok, "expected a job for %s", member.Name)
+		assert.Equal(t, member, job.Spec.TargetCluster)
+		assert.Equal(t, ClusterJobName(jitReq, member.Name), job.Name)
+		assert.Equal(t, jitReq.Name, job.Spec.AccessRequestRef.Name)
+		assert.Equal(t, "arn:aws:iam::"+member.AWSAccount+":role/JITAccessRole", job.Spec.JITRoleArn)
+		assert.Equal(t, "prod-shards", job.Labels["jit.rebelops.io/cluster-group"])
+	}
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	require.Len(t, updated.Status.ClusterJobs, 3)
+	for _, clusterJob := range updated.Status.ClusterJobs {
+		assert.Equal(t, ClusterJobName(jitReq, clusterJob.Cluster), clusterJob.JobName)
+		assert.Equal(t, JobPhasePending, clusterJob.Phase)
+	}
+}
+
+func TestJITAccessRequestReconciler_ClusterGroupAggregatesJobStatus(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	jitReq := createGroupRequest(AccessPhaseActive)
+	var objects []client.Object
+	for i, member := range prodShards["prod-shards"] {
+		job := createTestJob(jitReq.Name, "default")
+		job.Name = ClusterJobName(jitReq, member.Name)
+		job.Spec.TargetCluster = member
+		job.Status.Phase = JobPhaseCreating
+		if i < 2 {
+			expiry := metav1.NewTime(start.Add(time.Duration(2+i) * time.Hour))
+			job.Status.Phase = JobPhaseActive
+			job.Status.StartTime = &start
+			job.Status.ExpiryTime = &expiry
+			job.Status.AccessEntry = &JobAccessEntry{SessionName: "jit-" + member.Name}
+		}
+		objects = append(objects, job)
+		jitReq.Status.ClusterJobs = append(jitReq.Status.ClusterJobs, ClusterJobStatus{
+			Cluster: member.Name,
+			JobName: job.Name,
+			Phase:   JobPhasePending,
+		})
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(objects, jitReq)...).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+	reconciler.ClusterGroups = prodShards
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	reconcileAndGet := func() JITAccessRequest {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		require.NoError(t, err)
+		var updated JITAccessRequest
+		require.NoError(t, fakeClient.Get(ctx, key, &updated))
+		return updated
+	}
+	jobKey := func(cluster string) types.NamespacedName {
+		return types.NamespacedName{Name: ClusterJobName(jitReq, cluster), Namespace: "default"}
+	}
+
+	// Two of three members provisioned: the request is not granted yet
+	updated := reconcileAndGet()
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	assert.Equal(t, "Access provisioned on 2 of 3 clusters", updated.Status.Message)
+	assert.Nil(t, updated.Status.AccessEntry)
+	assert.Equal(t, []JobPhase{JobPhaseActive, JobPhaseActive, JobPhaseCreating}, clusterJobPhases(updated))
+
+	// Once the last member is provisioned the request expires with the earliest member
+	var last JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, jobKey("prod-shard-3"), &last))
+	lastExpiry := metav1.NewTime(start.Add(4 * time.Hour))
+	last.Status.Phase = JobPhaseActive
+	last.Status.StartTime = &start
+	last.Status.ExpiryTime = &lastExpiry
+	last.Status.AccessEntry = &JobAccessEntry{SessionName: "jit-prod-shard-3"}
+	require.NoError(t, fakeClient.Status().Update(ctx, &last))
+
+	updated = reconcileAndGet()
+	assert.Equal(t, "Access provisioned on 3 of 3 clusters", updated.Status.Message)
+	require.NotNil(t, updated.Status.AccessEntry)
+	assert.WithinDuration(t, start.Add(2*time.Hour), updated.Status.AccessEntry.ExpiresAt.Time, time.Second)
+	assert.Equal(t, "jit-prod-shard-1", updated.Status.AccessEntry.SessionName)
+	granted := meta.FindStatusCondition(updated.Status.Conditions, "AccessGranted")
+	require.NotNil(t, granted)
+	assert.Contains(t, granted.Message, "all 3 clusters")
+
+	// A failed member fails the request and revokes the other members
+	var failing JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, jobKey("prod-shard-2"), &failing))
+	failing.Status.Phase = JobPhaseFailed
+	require.NoError(t, fakeClient.Status().Update(ctx, &failing))
+
+	updated = reconcileAndGet()
+	assert.Equal(t, AccessPhaseFailed, updated.Status.Phase)
+	assert.Equal(t, []JobPhase{JobPhaseActive, JobPhaseFailed, JobPhaseActive}, clusterJobPhases(updated))
+	for _, cluster := range []string{"prod-shard-1", "prod-shard-3"} {
+		var job JITAccessJob
+		require.NoError(t, fakeClient.Get(ctx, jobKey(cluster), &job))
+		assert.Equal(t, JobPhaseExpiring, job.Status.Phase, cluster)
+	}
+}
+
+func TestJITAccessRequestReconciler_UnknownClusterGroup(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	jitReq := createGroupRequest(AccessPhaseApproved)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(jitReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseFailed, updated.Status.Phase)
+	assert.Contains(t, updated.Status.Message, "prod-shards is not configured")
+
+	var jobs JITAccessJobList
+	require.NoError(t, fakeClient.List(ctx, &jobs, client.InNamespace("default")))
+	assert.Empty(t, jobs.Items)
+}
+
+func clusterJobPhases(jitReq JITAccessRequest) []JobPhase {
+	phases := make([]JobPhase, 0, len(jitReq.Status.ClusterJobs))
+	for _, clusterJob := range jitReq.Status.ClusterJobs {
+		phases = append(phases, clusterJob.Phase)
+	}
+	return phases
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Week is the length of the "w" duration unit
+const Week = 7 * 24 * time.Hour
+
+var durationPartRegex = regexp.MustCompile(`(\d+)([wdhms])`)
+
+// ParseDuration parses a request duration such as "2h30m", "1d" or "2w3d". Unlike
+// time.ParseDuration it accepts days and weeks; each unit may appear once, largest first.
+func ParseDuration(duration string) (time.Duration, error) {
+	if duration == "" {
+		return 0, fmt.Errorf("duration cannot be empty")
+	}
+
+	// Parse duration components using regexp
+	matches := durationPartRegex.FindAllStringSubmatch(duration, -1)
+
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("invalid duration format: %s", duration)
+	}
+
+	// Units must appear at most once and in descending order (w > d > h > m > s)
+	unitRank := map[string]int{"w": 5, "d": 4, "h": 3, "m": 2, "s": 1}
+	lastRank := 0
+
+	var total time.Duration
+	for _, match := range matches {
+		rank := unitRank[match[2]]
+		if lastRank != 0 {
+			if rank == lastRank {
+				return 0, fmt.Errorf("duplicate duration unit '%s' in %s", match[2], duration)
+			}
+			if rank > lastRank {
+				return 0, fmt.Errorf("duration units must be in descending order (w, d, h, m, s): %s", duration)
+			}
+		}
+		lastRank = rank
+
+		value := 0
+		if _, err := fmt.Sscanf(match[1], "%d", &value); err != nil {
+			return 0, fmt.Errorf("invalid duration value: %s", match[1])
+		}
+		unit := match[2]
+
+		switch unit {
+		case "w":
+			total += time.Duration(value) * Week
+		case "d":
+			total += time.Duration(value) * 24 * time.Hour
+		case "h":
+			total += time.Duration(value) * time.Hour
+		case "m":
+			total += time.Duration(value) * time.Minute
+		case "s":
+			total += time.Duration(value) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration unit: %s", unit)
+		}
+	}
+
+	// Verify that the parsed string matches the original (no invalid parts)
+	rebuiltString := ""
+	for _, match := range matches {
+		rebuiltString += match[0]
+	}
+	if rebuiltString != duration {
+		return 0, fmt.Errorf("invalid duration format: %s", duration)
+	}
+
+	return total, nil
+}
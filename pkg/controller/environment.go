@@ -0,0 +1,41 @@
+package controller
+
+import "strings"
+
+// EnvironmentForClusterName classifies a cluster by its name. Names that reveal no environment
+// count as production, so an unrecognized cluster is never treated as self-service.
+func EnvironmentForClusterName(clusterName string) string {
+	lowerName := strings.ToLower(clusterName)
+
+	if strings.Contains(lowerName, "prod") || strings.Contains(lowerName, "production") {
+		return "production"
+	}
+	if strings.Contains(lowerName, "stag") || strings.Contains(lowerName, "staging") {
+		return "staging"
+	}
+	if strings.Contains(lowerName, "dev") || strings.Contains(lowerName, "development") {
+		return "development"
+	}
+	if strings.Contains(lowerName, "qa") || strings.Contains(lowerName, "test") {
+		return "qa"
+	}
+
+	// Default to production for safety
+	return "production"
+}
+
+// clusterEnvironment returns the environment of the request's target cluster: its registry tag
+// when it has one, otherwise the environment its name suggests. Unlike the environment label, it
+// cannot be set by the requester.
+func (r *JITAccessRequestReconciler) clusterEnvironment(jitReq *JITAccessRequest) string {
+	return registryEnvironment(r.ClusterEnvironments, jitReq.Spec.TargetCluster.Name)
+}
+
+// registryEnvironment looks a cluster up among the registry's environment tags, keyed by lowercase
+// cluster name, and falls back to the environment the name suggests
+func registryEnvironment(environments map[string]string, clusterName string) string {
+	if env := environments[strings.ToLower(clusterName)]; env != "" {
+		return strings.ToLower(env)
+	}
+	return EnvironmentForClusterName(clusterName)
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AccessEventType names a step in a request's lifecycle that is published to EventNotifiers
+type AccessEventType string
+
+const (
+	// AccessEventApproved is published when a request has its required approvals
+	AccessEventApproved AccessEventType = "approved"
+	// AccessEventDenied is published once when a request is denied
+	AccessEventDenied AccessEventType = "denied"
+	// AccessEventGranted is published when access has been provisioned on the cluster
+	AccessEventGranted AccessEventType = "granted"
+	// AccessEventRevoked is published when active access is revoked before its expiry
+	AccessEventRevoked AccessEventType = "revoked"
+	// AccessEventExpired is published when active access reaches its expiry
+	AccessEventExpired AccessEventType = "expired"
+)
+
+// EventNotifier is told about request lifecycle events so teams can trigger their own automation.
+// It is called inline by the reconciler, so implementations must hand the event off rather than
+// deliver it before returning.
+type EventNotifier interface {
+	NotifyEvent(ctx context.Context, event AccessEventType, jitReq *JITAccessRequest) error
+}
+
+// publishEvent hands the event to the configured EventNotifier. Failures are logged and never
+// affect the request.
+func (r *JITAccessRequestReconciler) publishEvent(
+	ctx context.Context, event AccessEventType, jitReq *JITAccessRequest,
+) {
+	if r.EventNotifier == nil {
+		return
+	}
+
+	if err := r.EventNotifier.NotifyEvent(ctx, event, jitReq); err != nil {
+		log.FromContext(ctx).Error(err, "unable to publish access event", "event", event, "request", jitReq.Name)
+	}
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type recordingEventNotifier struct {
+	events []AccessEventType
+}
+
+func (n *recordingEventNotifier) NotifyEvent(_ context.Context, event AccessEventType, _ *JITAccessRequest) error {
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestJITAccessRequestReconciler_PublishesLifecycleEvents(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	jitReq := createTestRequest("event-request", "default", AccessPhasePending)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(jitReq).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+
+	notifier := &recordingEventNotifier{}
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+	reconciler.EventNotifier = notifier
+	clock := &fakeClock{now: time.Now()}
+	reconciler.Clock = clock
+
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	reconcileRequest := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		require.NoError(t, err)
+	}
+
+	// Auto-approved, then provisioned
+	reconcileRequest()
+	reconcileRequest()
+	assert.Equal(t, []AccessEventType{AccessEventApproved}, notifier.events)
+
+	var job JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: JobName(jitReq), Namespace: "default"}, &job))
+	start := metav1.NewTime(clock.now)
+	expiry := metav1.NewTime(start.Add(2 * time.Hour))
+	job.Status.Phase = JobPhaseActive
+	job.Status.StartTime = &start
+	job.Status.ExpiryTime = &expiry
+	job.Status.AccessEntry = &JobAccessEntry{SessionName: "jit-session"}
+	require.NoError(t, fakeClient.Status().Update(ctx, &job))
+
+	// Granted once, however often the active request is reconciled
+	reconcileRequest()
+	reconcileRequest()
+	assert.Equal(t, []AccessEventType{AccessEventApproved, AccessEventGranted}, notifier.events)
+
+	// The grant is past its expiry
+	clock.now = expiry.Add(time.Second)
+	reconcileRequest()
+	assert.Equal(t, []AccessEventType{AccessEventApproved, AccessEventGranted, AccessEventExpired}, notifier.events)
+}
@@ -5,27 +5,106 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/clock"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
 )
 
+// SlackChannelAnnotation and SlackThreadAnnotation record the Slack message that started a
+// request's notification thread; later approval and denial updates are posted as replies to it
+const (
+	SlackChannelAnnotation = "jit.rebelops.io/slack-channel"
+	SlackThreadAnnotation  = "jit.rebelops.io/slack-thread-ts"
+)
+
+// ApprovalNotifier tells approvers that a request is waiting on them, or that break-glass
+// access was granted without them. NotifyPendingRequest may set the Slack thread annotations on
+// the request, which the reconciler then persists; NotifyDecision replies in that thread.
+type ApprovalNotifier interface {
+	NotifyPendingRequest(ctx context.Context, jitReq *JITAccessRequest) error
+	NotifyBreakGlass(ctx context.Context, jitReq *JITAccessRequest) error
+	NotifyDecision(ctx context.Context, jitReq *JITAccessRequest) error
+}
+
+// ChannelArchiveChecker reports whether the Slack channel a request was made from has been archived
+type ChannelArchiveChecker interface {
+	IsChannelArchived(ctx context.Context, channelID string) (bool, error)
+}
+
+// DefaultMaxProvisioningAttempts is how many times job creation is retried before a request fails
+const DefaultMaxProvisioningAttempts = 5
+
+// expiredCleanupRequeue is how often an expired or revoked request checks that its job finished cleanup
+const expiredCleanupRequeue = 30 * time.Second
+
 // JITAccessRequestReconciler reconciles a JITAccessRequest object
 type JITAccessRequestReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	RBAC   *auth.RBAC
+	Scheme   *runtime.Scheme
+	RBAC     *auth.RBAC
+	Notifier ApprovalNotifier
+	// MaxProvisioningAttempts bounds job creation retries; zero uses DefaultMaxProvisioningAttempts
+	MaxProvisioningAttempts int
+	// Clock drives expiry checks and condition timestamps; nil uses the system clock
+	Clock clock.Clock
+	// PropagatedMetadataKeys are the request label and annotation keys copied onto its job
+	PropagatedMetadataKeys []string
+	// TrustedUsers are Slack user IDs whose own requests are approved without approvers, for any
+	// cluster and permission, once a requester service account has vouched for them. Every such
+	// approval is audited.
+	TrustedUsers []string
+	// ArchivedChannels, when set, revokes active requests whose Slack channel has been archived,
+	// as the session is likely abandoned. Nil disables the check.
+	ArchivedChannels ChannelArchiveChecker
+	// ApprovalDelegations let a delegate's approval stand in for an out-of-office approver
+	ApprovalDelegations ApprovalDelegations
+	// ClusterGroups are the groups a request may name to be provisioned on several clusters at once
+	ClusterGroups ClusterGroups
+	// KillSwitch, when engaged, revokes every active request and denies the rest; nil disables it
+	KillSwitch *KillSwitch
+	// EventNotifier, when set, is told when requests are approved, denied, granted, revoked or expired
+	EventNotifier EventNotifier
+	// AutoApprovalRules approve requests matching any of their CEL expressions; nil disables them
+	AutoApprovalRules *AutoApprovalRules
+	// SessionPolicy, when set, re-checks active requests against the current policy on every
+	// periodic reconcile and flags those it would now deny. Nil disables revalidation.
+	SessionPolicy SessionPolicyChecker
+	// RevokePolicyViolations revokes active requests that violate the current policy instead of flagging them
+	RevokePolicyViolations bool
+	// MinApprovals are per-environment approval floors enforced whatever approvers or quorum the
+	// request's spec lists, so a request that bypassed the mutating webhook cannot skip approval.
+	// Requests under a floor are never auto-approved, even for trusted users. Nil disables the floors.
+	MinApprovals map[string]int
+	// ClusterEnvironments are the cluster registry's environment tags keyed by lowercase cluster
+	// name. Floors and auto-approval rules use them, falling back to the cluster name, rather than
+	// the requester-writable environment label.
+	ClusterEnvironments map[string]string
+}
+
+func (r *JITAccessRequestReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
 }
 
 //+kubebuilder:rbac:groups=jit.rebelops.io,resources=jitaccessrequests,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=jit.rebelops.io,resources=jitaccessrequests/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=jit.rebelops.io,resources=jitaccessrequests/finalizers,verbs=update
 //+kubebuilder:rbac:groups=jit.rebelops.io,resources=jitaccessjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile handles JITAccessRequest lifecycle
 func (r *JITAccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -38,18 +117,45 @@ func (r *JITAccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Count phase changes once they are persisted; reconciles that leave the phase alone are not counted
+	from := jitReq.Status.Phase
+	result, err := r.reconcilePhase(ctx, &jitReq)
+	if err == nil && jitReq.Status.Phase != from {
+		metrics.RecordPhaseTransition(string(from), string(jitReq.Status.Phase))
+	}
+	return result, err
+}
+
+// reconcilePhase applies the kill switch and then the handler for the request's phase
+func (r *JITAccessRequestReconciler) reconcilePhase(
+	ctx context.Context, jitReq *JITAccessRequest,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	// During a breach nothing new is provisioned and active access is revoked
+	if stoppedByKillSwitch(jitReq.Status.Phase) {
+		engaged, reason, err := r.KillSwitch.Engaged(ctx, r.Client)
+		if err != nil {
+			log.Error(err, "unable to check kill switch")
+			return ctrl.Result{}, err
+		}
+		if engaged {
+			return r.handleKillSwitch(ctx, jitReq, reason)
+		}
+	}
+
 	// Handle different phases
 	switch jitReq.Status.Phase {
 	case "", AccessPhasePending:
-		return r.handlePendingRequest(ctx, &jitReq)
-	case AccessPhaseApproved:
-		return r.handleApprovedRequest(ctx, &jitReq)
+		return r.handlePendingRequest(ctx, jitReq)
+	case AccessPhaseApproved, AccessPhaseScheduled:
+		return r.handleApprovedRequest(ctx, jitReq)
 	case AccessPhaseDenied:
-		return r.handleDeniedRequest(ctx, &jitReq)
+		return r.handleDeniedRequest(ctx, jitReq)
 	case AccessPhaseActive:
-		return r.handleActiveRequest(ctx, &jitReq)
+		return r.handleActiveRequest(ctx, jitReq)
 	case AccessPhaseExpired, AccessPhaseRevoked:
-		return r.handleExpiredRequest(ctx, &jitReq)
+		return r.handleExpiredRequest(ctx, jitReq)
 	default:
 		log.Info("No action needed for current phase", "phase", jitReq.Status.Phase)
 		return ctrl.Result{}, nil
@@ -61,6 +167,15 @@ func (r *JITAccessRequestReconciler) handlePendingRequest(
 ) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
+	// View-only emergency requests skip approval entirely
+	if jitReq.Status.Phase == "" && r.isViewOnlyBreakGlass(jitReq) {
+		if err := r.grantBreakGlass(ctx, jitReq); err != nil {
+			log.Error(err, "unable to update JITAccessRequest status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
 	// Initialize status if empty
 	if jitReq.Status.Phase == "" {
 		jitReq.Status.Phase = AccessPhasePending
@@ -69,7 +184,7 @@ func (r *JITAccessRequestReconciler) handlePendingRequest(
 			{
 				Type:               "Submitted",
 				Status:             metav1.ConditionTrue,
-				LastTransitionTime: metav1.Now(),
+				LastTransitionTime: metav1.NewTime(r.now()),
 				Reason:             "RequestSubmitted",
 				Message:            "JIT access request has been submitted",
 			},
@@ -79,27 +194,57 @@ func (r *JITAccessRequestReconciler) handlePendingRequest(
 			log.Error(err, "unable to update JITAccessRequest status")
 			return ctrl.Result{}, err
 		}
+
+		r.notifyApprovers(ctx, jitReq)
 		return ctrl.Result{RequeueAfter: time.Second * 30}, nil
 	}
 
 	// Check if auto-approval is possible or if approvals are sufficient
-	if r.shouldAutoApprove(jitReq) || r.hasRequiredApprovals(jitReq) {
+	if r.approved(ctx, jitReq) {
 		jitReq.Status.Phase = AccessPhaseApproved
 		jitReq.Status.Message = "Request approved"
 
 		// Add approval condition
-		r.setCondition(jitReq, metav1.Condition{
+		condition := metav1.Condition{
 			Type:               "Approved",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "RequiredApprovalsReceived",
 			Message:            "JIT access request has been approved",
-		})
+		}
+		trusted := r.isTrustedOperator(jitReq)
+		rule, ruleApproved := r.autoApprovalRule(ctx, jitReq)
+		if trusted {
+			jitReq.Status.Message = "Request auto-approved for trusted operator"
+			condition.Reason = "TrustedOperator"
+			condition.Message = "Requester is a trusted operator; approved without approvals"
+		} else if ruleApproved {
+			jitReq.Status.Message = "Request auto-approved by rule " + rule
+			condition.Reason = "AutoApprovalRule"
+			condition.Message = fmt.Sprintf("Auto-approval rule %s matched the request", rule)
+		}
+		r.setCondition(jitReq, condition)
 
 		if err := r.Status().Update(ctx, jitReq); err != nil {
 			log.Error(err, "unable to update JITAccessRequest status")
 			return ctrl.Result{}, err
 		}
+
+		if trusted {
+			r.auditTrustedOperatorApproval(ctx, jitReq)
+		} else if ruleApproved {
+			log.Info("AUDIT: request auto-approved by rule",
+				"rule", rule,
+				"request", jitReq.Name,
+				"user", jitReq.Spec.UserID,
+				"cluster", jitReq.Spec.TargetCluster.Name,
+				"permissions", jitReq.Spec.Permissions,
+				"duration", jitReq.Spec.Duration)
+		}
+
+		recordApprovalLatencies(jitReq)
+		r.notifyDecision(ctx, jitReq)
+		r.publishEvent(ctx, AccessEventApproved, jitReq)
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
@@ -107,12 +252,54 @@ func (r *JITAccessRequestReconciler) handlePendingRequest(
 	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
 }
 
+// notifyApprovers pings the assigned approvers once, when the request first enters Pending.
+// Notification failures are logged rather than retried so they never block the request.
+func (r *JITAccessRequestReconciler) notifyApprovers(ctx context.Context, jitReq *JITAccessRequest) {
+	if r.Notifier == nil || len(jitReq.Spec.Approvers) == 0 {
+		return
+	}
+
+	threadTS := jitReq.Annotations[SlackThreadAnnotation]
+	if err := r.Notifier.NotifyPendingRequest(ctx, jitReq); err != nil {
+		log.FromContext(ctx).Error(err, "unable to notify approvers", "approvers", jitReq.Spec.Approvers)
+	}
+
+	// Keep the thread so approval and denial updates land in the same conversation
+	if jitReq.Annotations[SlackThreadAnnotation] != threadTS {
+		if err := r.Update(ctx, jitReq); err != nil {
+			log.FromContext(ctx).Error(err, "unable to record slack thread")
+		}
+	}
+}
+
+// notifyDecision posts the request's approval or denial into its Slack notification thread.
+// Failures are logged for the same reason as in notifyApprovers.
+func (r *JITAccessRequestReconciler) notifyDecision(ctx context.Context, jitReq *JITAccessRequest) {
+	if r.Notifier == nil || jitReq.Annotations[SlackThreadAnnotation] == "" {
+		return
+	}
+
+	if err := r.Notifier.NotifyDecision(ctx, jitReq); err != nil {
+		log.FromContext(ctx).Error(err, "unable to post decision to slack thread")
+	}
+}
+
 func (r *JITAccessRequestReconciler) handleApprovedRequest(
 	ctx context.Context,
 	jitReq *JITAccessRequest,
 ) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
+	// Requests that start later wait in Scheduled until their start time
+	if r.isScheduledForLater(jitReq) {
+		return r.holdUntilStart(ctx, jitReq)
+	}
+
+	// Group requests get one job per member cluster
+	if jitReq.Spec.ClusterGroup != "" {
+		return r.provisionClusterGroup(ctx, jitReq)
+	}
+
 	// Create JITAccessJob to handle the actual access provisioning
 	job := r.createJITAccessJob(jitReq)
 
@@ -122,8 +309,14 @@ func (r *JITAccessRequestReconciler) handleApprovedRequest(
 		return ctrl.Result{}, err
 	}
 
-	if err := r.Create(ctx, job); err != nil {
+	// A job left by an earlier reconcile whose status update was lost is not a failed attempt
+	if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
 		log.Error(err, "unable to create JITAccessJob")
+		jitReq.Status.ProvisioningAttempts++
+		if int(jitReq.Status.ProvisioningAttempts) >= r.maxProvisioningAttempts() {
+			return r.deadLetterRequest(ctx, jitReq, err)
+		}
+
 		jitReq.Status.Message = fmt.Sprintf("Failed to create access job: %v", err)
 		if updateErr := r.Status().Update(ctx, jitReq); updateErr != nil {
 			log.Error(updateErr, "unable to update JITAccessRequest status after job creation failure")
@@ -138,7 +331,7 @@ func (r *JITAccessRequestReconciler) handleApprovedRequest(
 	r.setCondition(jitReq, metav1.Condition{
 		Type:               "Provisioning",
 		Status:             metav1.ConditionTrue,
-		LastTransitionTime: metav1.Now(),
+		LastTransitionTime: metav1.NewTime(r.now()),
 		Reason:             "JobCreated",
 		Message:            "JIT access job has been created",
 	})
@@ -158,9 +351,29 @@ func (r *JITAccessRequestReconciler) handleDeniedRequest(
 ) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	// For denied requests, we don't need to do anything special
-	// The status is already set to denied, just log and finish
+	// The Denied condition marks that the denial has been handled, so it is only announced once
+	for _, condition := range jitReq.Status.Conditions {
+		if condition.Type == "Denied" {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Denied",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "RequestDenied",
+		Message:            "JIT access request has been denied",
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
 	log.Info("Request has been denied", "request", jitReq.Name, "reason", jitReq.Status.Message)
+	r.notifyDecision(ctx, jitReq)
+	r.publishEvent(ctx, AccessEventDenied, jitReq)
 
 	// No requeue needed for denied requests
 	return ctrl.Result{}, nil
@@ -180,7 +393,7 @@ func (r *JITAccessRequestReconciler) handleActiveRequest(
 		r.setCondition(jitReq, metav1.Condition{
 			Type:               "Expired",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "AccessExpired",
 			Message:            "JIT access has expired",
 		})
@@ -189,30 +402,124 @@ func (r *JITAccessRequestReconciler) handleActiveRequest(
 			log.Error(err, "unable to update JITAccessRequest status")
 			return ctrl.Result{}, err
 		}
+		r.publishEvent(ctx, AccessEventExpired, jitReq)
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
+	if r.isChannelArchived(ctx, jitReq) {
+		return r.revokeForArchivedChannel(ctx, jitReq)
+	}
+
+	if violation := r.sessionPolicyViolation(ctx, jitReq); violation != nil {
+		return r.handlePolicyViolation(ctx, jitReq, violation)
+	}
+
 	// Check associated JITAccessJob status
 	// This would involve fetching the job and updating accordingly
 	return r.syncWithJob(ctx, jitReq)
 }
 
+// handleExpiredRequest confirms the child jobs have revoked access and removed their secrets. A
+// job that has not started cleanup on its own, such as one for a revoked request whose expiry is
+// still ahead, is moved to Expiring, and the request is requeued until every job reports Completed.
 func (r *JITAccessRequestReconciler) handleExpiredRequest(
 	ctx context.Context,
 	jitReq *JITAccessRequest,
 ) (ctrl.Result, error) {
-	// Ensure cleanup is complete
-	// The JITAccessJob controller will handle the actual cleanup
+	// Without jobs there is nothing left to clean up
+	jobs, err := r.requestJobs(ctx, jitReq)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	cleaning := false
+	for i := range jobs {
+		pending, err := r.expireJob(ctx, jitReq, &jobs[i])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		cleaning = cleaning || pending
+	}
+
+	if cleaning {
+		return ctrl.Result{RequeueAfter: expiredCleanupRequeue}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *JITAccessRequestReconciler) shouldAutoApprove(jitReq *JITAccessRequest) bool {
+// expireJob moves a job that still holds access to Expiring so the job controller revokes it.
+// It reports whether the job has yet to finish cleanup.
+func (r *JITAccessRequestReconciler) expireJob(
+	ctx context.Context, jitReq *JITAccessRequest, job *JITAccessJob,
+) (bool, error) {
+	log := log.FromContext(ctx)
+
+	switch job.Status.Phase {
+	case JobPhaseCompleted, JobPhaseFailed:
+		return false, nil
+	case JobPhaseExpiring:
+		log.Info("Waiting for JITAccessJob cleanup", "job", job.Name)
+		return true, nil
+	}
+
+	job.Status.Phase = JobPhaseExpiring
+	meta.SetStatusCondition(&job.Status.Conditions, metav1.Condition{
+		Type:               "Expiring",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "RequestEnded",
+		Message:            fmt.Sprintf("Access request is %s; cleaning up access", jitReq.Status.Phase),
+	})
+
+	if err := r.Status().Update(ctx, job); err != nil {
+		log.Error(err, "unable to move JITAccessJob to Expiring")
+		return false, err
+	}
+
+	log.Info("Moved JITAccessJob to Expiring for cleanup", "job", job.Name, "phase", jitReq.Status.Phase)
+	return true, nil
+}
+
+// approved reports whether a pending request may move to Approved, by auto-approval or approvals
+func (r *JITAccessRequestReconciler) approved(ctx context.Context, jitReq *JITAccessRequest) bool {
+	return r.shouldAutoApprove(ctx, jitReq) || r.hasRequiredApprovals(jitReq)
+}
+
+// ApprovedWithoutApprovals reports whether the request would be approved as soon as it is
+// submitted, before anyone approves it
+func (r *JITAccessRequestReconciler) ApprovedWithoutApprovals(ctx context.Context, jitReq *JITAccessRequest) bool {
+	submitted := jitReq.DeepCopy()
+	submitted.Status.Approvals = nil
+	return r.approved(ctx, submitted)
+}
+
+func (r *JITAccessRequestReconciler) shouldAutoApprove(ctx context.Context, jitReq *JITAccessRequest) bool {
 	// Implement auto-approval logic based on:
 	// - User role
 	// - Requested permissions
 	// - Target cluster policies
 	// - Time restrictions
 
+	// No policy matched these requests, so a person must look at them
+	if jitReq.Annotations[FallbackApproversAnnotation] == "true" {
+		return false
+	}
+
+	// Sensitive namespaces such as kube-system are never self-service
+	if touchesSensitiveNamespace(jitReq) {
+		return false
+	}
+
+	// Environments with an approval floor always wait for approvers
+	if r.minApprovals(jitReq) > 0 {
+		return false
+	}
+
+	// Trusted operators skip approval for any permission
+	if r.isTrustedOperator(jitReq) {
+		return true
+	}
+
 	// For now, auto-approve "view" permissions for known users
 	if len(jitReq.Spec.Permissions) == 1 && jitReq.Spec.Permissions[0] == "view" {
 		if r.RBAC.UserHasPermission(jitReq.Spec.UserID, auth.PermissionCreateRequests) {
@@ -220,27 +527,91 @@ func (r *JITAccessRequestReconciler) shouldAutoApprove(jitReq *JITAccessRequest)
 		}
 	}
 
-	return false
+	// Policy authors' CEL rules; a rule that fails to evaluate never approves
+	_, approved := r.autoApprovalRule(ctx, jitReq)
+	return approved
 }
 
 func (r *JITAccessRequestReconciler) hasRequiredApprovals(jitReq *JITAccessRequest) bool {
-	if len(jitReq.Spec.Approvers) == 0 {
+	quorum, hasQuorum := requiredApprovals(jitReq)
+	// A zero quorum never waives the approvers the policy listed
+	if hasQuorum && len(jitReq.Spec.Approvers) > 0 {
+		quorum = max(quorum, 1)
+	}
+	floor := r.minApprovals(jitReq)
+	sensitive := touchesSensitiveNamespace(jitReq)
+	if !hasQuorum && len(jitReq.Spec.Approvers) == 0 && floor == 0 && !sensitive {
 		return true // No approvers required
 	}
 
-	// Count valid approvals
-	validApprovals := 0
+	listed := make(map[string]bool, len(jitReq.Spec.Approvers))
+	for _, approver := range jitReq.Spec.Approvers {
+		listed[approver] = true
+	}
+
+	// Count valid approvals, once per approver. Without listed approvers any approver counts.
+	// Approvals of elevated requests only count when they are justified with a comment, and the
+	// requester's or grantee's own approval never counts, even as someone's delegate.
+	needsComment := RequiresApprovalComment(jitReq)
+	approvedBy := make(map[string]bool)
 	for _, approval := range jitReq.Status.Approvals {
-		for _, requiredApprover := range jitReq.Spec.Approvers {
-			if approval.Approver == requiredApprover {
-				validApprovals++
-				break
+		if isSelfApproval(jitReq, approval) {
+			continue
+		}
+		if needsComment && !hasApprovalComment(approval) {
+			continue
+		}
+		if len(listed) == 0 || listed[approval.Approver] {
+			approvedBy[approval.Approver] = true
+			continue
+		}
+		// An out-of-office approver's delegate fills their slot within the delegation window
+		for _, approver := range r.ApprovalDelegations.DelegatedApprovers(approval.Approver, approval.ApprovedAt.Time) {
+			if listed[approver] {
+				approvedBy[approver] = true
 			}
 		}
 	}
 
-	// For now, require all approvers to approve
-	return validApprovals >= len(jitReq.Spec.Approvers)
+	// The environment's floor holds even when the spec asks for fewer approvals
+	if len(approvedBy) < floor {
+		return false
+	}
+
+	// However many others approved, a sensitive namespace needs one of its own approvers
+	if sensitive && !hasSensitiveNamespaceApproval(approvedBy) {
+		return false
+	}
+
+	if hasQuorum {
+		return len(approvedBy) >= quorum
+	}
+
+	// Without a quorum policy, require all approvers to approve
+	return len(approvedBy) >= len(jitReq.Spec.Approvers)
+}
+
+// recordApprovalLatencies observes, once the request is approved, how long after its creation each
+// approver approved it. Auto-approved requests have no approvals and record nothing.
+func recordApprovalLatencies(jitReq *JITAccessRequest) {
+	recorded := make(map[string]bool, len(jitReq.Status.Approvals))
+	for _, approval := range jitReq.Status.Approvals {
+		if recorded[approval.Approver] || isSelfApproval(jitReq, approval) {
+			continue
+		}
+		recorded[approval.Approver] = true
+		metrics.RecordApprovalLatency(approval.Approver, approval.ApprovedAt.Sub(jitReq.CreationTimestamp.Time))
+	}
+}
+
+// isSelfApproval reports whether an approval came from the user who requested the access or the
+// user it is granted to
+func isSelfApproval(jitReq *JITAccessRequest, approval Approval) bool {
+	if approval.Approver == jitReq.Spec.UserID {
+		return true
+	}
+	return jitReq.Spec.OnBehalfOf != nil && jitReq.Spec.OnBehalfOf.UserID != "" &&
+		approval.Approver == jitReq.Spec.OnBehalfOf.UserID
 }
 
 func (r *JITAccessRequestReconciler) isRequestExpired(jitReq *JITAccessRequest) bool {
@@ -248,13 +619,13 @@ func (r *JITAccessRequestReconciler) isRequestExpired(jitReq *JITAccessRequest)
 		return false
 	}
 
-	return time.Now().After(jitReq.Status.AccessEntry.ExpiresAt.Time)
+	return r.now().After(jitReq.Status.AccessEntry.ExpiresAt.Time)
 }
 
 func (r *JITAccessRequestReconciler) createJITAccessJob(jitReq *JITAccessRequest) *JITAccessJob {
-	return &JITAccessJob{
+	job := &JITAccessJob{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("jit-%s-%s", jitReq.Spec.UserID, jitReq.Name),
+			Name:      JobName(jitReq),
 			Namespace: jitReq.Namespace,
 			Labels: map[string]string{
 				"jit.rebelops.io/request": jitReq.Name,
@@ -275,16 +646,27 @@ func (r *JITAccessRequestReconciler) createJITAccessJob(jitReq *JITAccessRequest
 			CleanupPolicy: CleanupPolicyOnExpiry,
 		},
 	}
+
+	propagateMetadata(r.PropagatedMetadataKeys, jitReq, job)
+	return job
 }
 
 func (r *JITAccessRequestReconciler) syncWithJob(ctx context.Context, jitReq *JITAccessRequest) (ctrl.Result, error) {
+	if jitReq.Spec.ClusterGroup != "" {
+		return r.syncWithClusterJobs(ctx, jitReq)
+	}
+
 	// Fetch associated JITAccessJob
-	jobName := fmt.Sprintf("jit-%s-%s", jitReq.Spec.UserID, jitReq.Name)
 	var job JITAccessJob
-	if err := r.Get(ctx, client.ObjectKey{Name: jobName, Namespace: jitReq.Namespace}, &job); err != nil {
+	if err := r.Get(ctx, client.ObjectKey{Name: JobName(jitReq), Namespace: jitReq.Namespace}, &job); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// A failed job never provisions access, so the request must not stay Active
+	if job.Status.Phase == JobPhaseFailed {
+		return r.failRequest(ctx, jitReq, &job)
+	}
+
 	// Update request status based on job status
 	if job.Status.AccessEntry != nil && jitReq.Status.AccessEntry == nil {
 		jitReq.Status.AccessEntry = &AccessEntryStatus{
@@ -297,7 +679,7 @@ func (r *JITAccessRequestReconciler) syncWithJob(ctx context.Context, jitReq *JI
 		r.setCondition(jitReq, metav1.Condition{
 			Type:               "AccessGranted",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "AccessProvisioned",
 			Message:            "JIT access has been successfully provisioned",
 		})
@@ -305,12 +687,85 @@ func (r *JITAccessRequestReconciler) syncWithJob(ctx context.Context, jitReq *JI
 		if err := r.Status().Update(ctx, jitReq); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.publishEvent(ctx, AccessEventGranted, jitReq)
 	}
 
 	// Check again in 2 minutes
 	return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
 }
 
+// failRequest moves the request to Failed, carrying over the reason the job gave up
+func (r *JITAccessRequestReconciler) failRequest(
+	ctx context.Context, jitReq *JITAccessRequest, job *JITAccessJob,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	message := "JIT access job failed"
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == "Failed" && condition.Message != "" {
+			message = condition.Message
+		}
+	}
+
+	jitReq.Status.Phase = AccessPhaseFailed
+	jitReq.Status.Message = message
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Provisioning",
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "JobFailed",
+		Message:            message,
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("JIT access job failed", "request", jitReq.Name, "job", job.Name, "message", message)
+	return ctrl.Result{}, nil
+}
+
+func (r *JITAccessRequestReconciler) maxProvisioningAttempts() int {
+	if r.MaxProvisioningAttempts > 0 {
+		return r.MaxProvisioningAttempts
+	}
+	return DefaultMaxProvisioningAttempts
+}
+
+// deadLetterRequest moves a request whose job could not be created after repeated attempts to
+// the terminal Failed phase so it stops requeueing
+func (r *JITAccessRequestReconciler) deadLetterRequest(
+	ctx context.Context, jitReq *JITAccessRequest, lastErr error,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	message := fmt.Sprintf("Failed to create access job after %d attempts: %v",
+		jitReq.Status.ProvisioningAttempts, lastErr)
+
+	jitReq.Status.Phase = AccessPhaseFailed
+	jitReq.Status.Message = message
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Provisioning",
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "JobCreationFailed",
+		Message:            message,
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	metrics.RecordAccessRequestFailure(jitReq.Spec.TargetCluster.Name, "JobCreationFailed")
+	log.Info("Giving up on JIT access request", "request", jitReq.Name,
+		"attempts", jitReq.Status.ProvisioningAttempts, "error", lastErr.Error())
+	return ctrl.Result{}, nil
+}
+
 func (r *JITAccessRequestReconciler) getJITRoleArn(cluster TargetCluster) string {
 	// This would be configurable per cluster or environment
 	return fmt.Sprintf("arn:aws:iam::%s:role/JITAccessRole", cluster.AWSAccount)
@@ -331,8 +786,14 @@ func (r *JITAccessRequestReconciler) setCondition(jitReq *JITAccessRequest, cond
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *JITAccessRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&JITAccessRequest{}).
-		Owns(&JITAccessJob{}). // Watch owned JITAccessJobs
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&JITAccessRequest{}, builder.WithPredicates(accessRequestPredicate())).
+		Owns(&JITAccessJob{}) // Watch owned JITAccessJobs
+
+	// Engaging the kill switch reconciles every request at once
+	if r.KillSwitch != nil {
+		b = b.Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.killSwitchRequests))
+	}
+
+	return b.Complete(r)
 }
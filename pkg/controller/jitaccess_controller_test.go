@@ -2,17 +2,24 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/rebelopsio/jit-bot/pkg/auth"
@@ -43,7 +50,7 @@ func createTestRequest(name, namespace string, phase AccessPhase) *JITAccessRequ
 	}
 }
 
-func createTestJob(name, namespace string) *JITAccessJob { //nolint:unused // test helper
+func createTestJob(name, namespace string) *JITAccessJob {
 	return &JITAccessJob{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name + "-job",
@@ -381,3 +388,1169 @@ func TestJITAccessRequestReconciler_SetupWithManager(t *testing.T) {
 }
 
 // Removed TestGenerateJobName - generateJobName function doesn't exist
+
+func TestJITAccessRequestReconciler_HasRequiredApprovals(t *testing.T) {
+	approval := func(approver string) Approval {
+		return Approval{Approver: approver, ApprovedAt: metav1.Now()}
+	}
+
+	tests := []struct {
+		name        string
+		quorum      string
+		permissions []string
+		approvers   []string
+		approvals   []Approval
+		want        bool
+	}{
+		{
+			name:   "zero quorum auto-approves",
+			quorum: "0",
+			want:   true,
+		},
+		{
+			name:      "zero quorum does not waive listed approvers",
+			quorum:    "0",
+			approvers: []string{"platform-team", "sre-team"},
+			want:      false,
+		},
+		{
+			name:      "zero quorum with one listed approval",
+			quorum:    "0",
+			approvers: []string{"platform-team", "sre-team"},
+			approvals: []Approval{approval("sre-team")},
+			want:      true,
+		},
+		{
+			name:      "quorum of two with one approval",
+			quorum:    "2",
+			approvals: []Approval{approval("U111111111A")},
+			want:      false,
+		},
+		{
+			name:      "quorum of two with duplicate approvals",
+			quorum:    "2",
+			approvals: []Approval{approval("U111111111A"), approval("U111111111A")},
+			want:      false,
+		},
+		{
+			name:      "quorum of two with two approvals",
+			quorum:    "2",
+			approvals: []Approval{approval("U111111111A"), approval("U222222222B")},
+			want:      true,
+		},
+		{
+			name:      "quorum only counts listed approvers",
+			quorum:    "1",
+			approvers: []string{"platform-team"},
+			approvals: []Approval{approval("U111111111A")},
+			want:      false,
+		},
+		{
+			name:      "no quorum requires all approvers",
+			approvers: []string{"platform-team", "sre-team"},
+			approvals: []Approval{approval("platform-team")},
+			want:      false,
+		},
+		{
+			name: "no quorum and no approvers",
+			want: true,
+		},
+		{
+			name:        "elevated approval without comment is not counted",
+			quorum:      "1",
+			permissions: []string{"admin"},
+			approvals:   []Approval{approval("U111111111A")},
+			want:        false,
+		},
+		{
+			name:        "elevated approval with blank comment is not counted",
+			quorum:      "1",
+			permissions: []string{"cluster-admin"},
+			approvals:   []Approval{{Approver: "U111111111A", ApprovedAt: metav1.Now(), Comment: "  "}},
+			want:        false,
+		},
+		{
+			name:        "elevated approval with comment is counted",
+			quorum:      "1",
+			permissions: []string{"admin"},
+			approvals:   []Approval{{Approver: "U111111111A", ApprovedAt: metav1.Now(), Comment: "INC-42 verified"}},
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &JITAccessRequestReconciler{}
+			req := createTestRequest("test-request", "default", AccessPhasePending)
+			if tt.quorum != "" {
+				req.Annotations = map[string]string{RequiredApprovalsAnnotation: tt.quorum}
+			}
+			if tt.permissions != nil {
+				req.Spec.Permissions = tt.permissions
+			}
+			req.Spec.Approvers = tt.approvers
+			req.Status.Approvals = tt.approvals
+
+			assert.Equal(t, tt.want, r.hasRequiredApprovals(req))
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_MinApprovalsFloor(t *testing.T) {
+	approval := func(approver string) Approval {
+		return Approval{Approver: approver, ApprovedAt: metav1.Now()}
+	}
+
+	tests := []struct {
+		name      string
+		cluster   string
+		label     string
+		quorum    string
+		approvers []string
+		approvals []Approval
+		want      bool
+	}{
+		{
+			name:    "production without approvers or quorum",
+			cluster: "prod-east-1",
+			want:    false,
+		},
+		{
+			name:    "production with a zero quorum",
+			cluster: "prod-east-1",
+			quorum:  "0",
+			want:    false,
+		},
+		{
+			name:      "production with one approval",
+			cluster:   "prod-east-1",
+			quorum:    "0",
+			approvals: []Approval{approval("U111111111A")},
+			want:      true,
+		},
+		{
+			name:      "requester's own approval does not count",
+			cluster:   "prod-east-1",
+			approvals: []Approval{approval("U123456789A")},
+			want:      false,
+		},
+		{
+			name:    "cluster whose name reveals no environment is held to the production floor",
+			cluster: "payments-east-1",
+			want:    false,
+		},
+		{
+			name:    "registry tag overrides the cluster name",
+			cluster: "devices-east-1",
+			want:    false,
+		},
+		{
+			name:    "requester-written environment label is ignored",
+			cluster: "prod-east-1",
+			label:   "development",
+			want:    false,
+		},
+		{
+			name:      "floor does not lower a larger quorum",
+			cluster:   "prod-east-1",
+			quorum:    "2",
+			approvals: []Approval{approval("U111111111A")},
+			want:      false,
+		},
+		{
+			name:    "environment without a floor",
+			cluster: "dev-east-1",
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &JITAccessRequestReconciler{
+				MinApprovals:        map[string]int{"production": 1},
+				ClusterEnvironments: map[string]string{"devices-east-1": "production"},
+			}
+			req := createTestRequest("floor-request", "default", AccessPhasePending)
+			req.Spec.TargetCluster.Name = tt.cluster
+			req.Labels = map[string]string{}
+			if tt.label != "" {
+				req.Labels["jit.rebelops.io/environment"] = tt.label
+			}
+			if tt.quorum != "" {
+				req.Annotations = map[string]string{RequiredApprovalsAnnotation: tt.quorum}
+			}
+			req.Spec.Approvers = tt.approvers
+			req.Status.Approvals = tt.approvals
+
+			assert.Equal(t, tt.want, r.hasRequiredApprovals(req))
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_EmptyApproversCannotSkipProductionFloor(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		trusted     bool
+	}{
+		{name: "edit", permissions: []string{"edit"}},
+		{name: "view-only request is not auto-approved", permissions: []string{"view"}},
+		{name: "trusted operator is not auto-approved", permissions: []string{"edit"}, trusted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			// A request that bypassed the mutating webhook, listing no approvers and a zero quorum
+			jitReq := createTestRequest("empty-approvers", "default", AccessPhasePending)
+			jitReq.Spec.TargetCluster.Name = "prod-east-1"
+			jitReq.Spec.Permissions = tt.permissions
+			jitReq.Spec.Approvers = []string{}
+			jitReq.Labels = map[string]string{"jit.rebelops.io/environment": "development"}
+			jitReq.Annotations = map[string]string{
+				RequiredApprovalsAnnotation: "0",
+				RequesterVerifiedAnnotation: "true",
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(jitReq).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+			reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+			reconciler.MinApprovals = map[string]int{"production": 1}
+			if tt.trusted {
+				reconciler.TrustedUsers = []string{jitReq.Spec.UserID}
+			}
+
+			key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+			_, err := reconciler.Reconcile(t.Context(), reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(t.Context(), key, &updated))
+			assert.Equal(t, AccessPhasePending, updated.Status.Phase)
+
+			var jobs JITAccessJobList
+			require.NoError(t, fakeClient.List(t.Context(), &jobs))
+			assert.Empty(t, jobs.Items)
+		})
+	}
+}
+
+func TestParseMinApprovals(t *testing.T) {
+	floors, err := ParseMinApprovals(" Production=2, staging=1,development=0 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"production": 2, "staging": 1, "development": 0}, floors)
+
+	floors, err = ParseMinApprovals("")
+	require.NoError(t, err)
+	assert.Empty(t, floors)
+
+	for _, value := range []string{"production", "=1", "production=-1", "production=two"} {
+		_, err := ParseMinApprovals(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRequiredApprovalsForDuration(t *testing.T) {
+	assert.Equal(t, 0, RequiredApprovalsForDuration(DefaultApprovalTiers, 20*time.Minute))
+	assert.Equal(t, 1, RequiredApprovalsForDuration(DefaultApprovalTiers, time.Hour))
+	assert.Equal(t, 1, RequiredApprovalsForDuration(DefaultApprovalTiers, 4*time.Hour))
+	assert.Equal(t, 2, RequiredApprovalsForDuration(DefaultApprovalTiers, 48*time.Hour))
+}
+
+type recordingNotifier struct {
+	notified   [][]string
+	breakGlass []string
+	decisions  []string
+	threadTS   string
+}
+
+func (n *recordingNotifier) NotifyPendingRequest(_ context.Context, jitReq *JITAccessRequest) error {
+	n.notified = append(n.notified, jitReq.Spec.Approvers)
+	if n.threadTS != "" {
+		if jitReq.Annotations == nil {
+			jitReq.Annotations = map[string]string{}
+		}
+		jitReq.Annotations[SlackChannelAnnotation] = "C0APPROVALS"
+		jitReq.Annotations[SlackThreadAnnotation] = n.threadTS
+	}
+	return nil
+}
+
+func (n *recordingNotifier) NotifyDecision(_ context.Context, jitReq *JITAccessRequest) error {
+	n.decisions = append(n.decisions, string(jitReq.Status.Phase)+"@"+jitReq.Annotations[SlackThreadAnnotation])
+	return nil
+}
+
+func (n *recordingNotifier) NotifyBreakGlass(_ context.Context, jitReq *JITAccessRequest) error {
+	n.breakGlass = append(n.breakGlass, jitReq.Name)
+	return nil
+}
+
+func TestJITAccessRequestReconciler_NotifiesApproversOnPending(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	prodReq := createTestRequest("prod-request", "default", "")
+	prodReq.Spec.TargetCluster.Name = "prod-east-1"
+	prodReq.Spec.Permissions = []string{"edit"}
+	prodReq.Spec.Approvers = []string{"platform-team", "sre-team", "security-team"}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(prodReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+
+	notifier := &recordingNotifier{}
+	reconciler := createTestReconciler(fakeClient, scheme, prodReq.Spec.UserID)
+	reconciler.Notifier = notifier
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod-request", Namespace: "default"}}
+
+	// Entering Pending notifies the assigned approvers
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, notifier.notified, 1)
+	assert.ElementsMatch(t, []string{"platform-team", "sre-team", "security-team"}, notifier.notified[0])
+
+	// Later reconciles of the still-pending request do not notify again
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, notifier.notified, 1)
+}
+
+func TestJITAccessRequestReconciler_PostsDecisionToSlackThread(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	prodReq := createTestRequest("prod-request", "default", "")
+	prodReq.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+	prodReq.Spec.Permissions = []string{"edit"}
+	prodReq.Spec.Approvers = []string{"sre-team"}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(prodReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+
+	notifier := &recordingNotifier{threadTS: "1700000000.000001"}
+	reconciler := createTestReconciler(fakeClient, scheme, prodReq.Spec.UserID)
+	reconciler.Notifier = notifier
+
+	key := types.NamespacedName{Name: "prod-request", Namespace: "default"}
+	_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	// The thread started by the pending notification is stored on the request
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+	assert.Equal(t, "1700000000.000001", updated.Annotations[SlackThreadAnnotation])
+	assert.Equal(t, "C0APPROVALS", updated.Annotations[SlackChannelAnnotation])
+	assert.Empty(t, notifier.decisions)
+
+	updated.Status.Approvals = []Approval{{Approver: "sre-team", ApprovedAt: metav1.Now()}}
+	require.NoError(t, fakeClient.Status().Update(context.Background(), &updated))
+
+	_, err = reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Approved@1700000000.000001"}, notifier.decisions)
+}
+
+func TestJITAccessRequestReconciler_PostsDenialOnce(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	deniedReq := createDeniedTestRequest()
+	deniedReq.Annotations = map[string]string{
+		SlackChannelAnnotation: "C0APPROVALS",
+		SlackThreadAnnotation:  "1700000000.000001",
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deniedReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+
+	notifier := &recordingNotifier{}
+	reconciler := createTestReconciler(fakeClient, scheme, deniedReq.Spec.UserID)
+	reconciler.Notifier = notifier
+
+	key := types.NamespacedName{Name: deniedReq.Name, Namespace: deniedReq.Namespace}
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"Denied@1700000000.000001"}, notifier.decisions)
+}
+
+func TestJITAccessRequestReconciler_SkipsNotificationWithoutApprovers(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	devReq := createTestRequest("dev-request", "default", "")
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(devReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+
+	notifier := &recordingNotifier{}
+	reconciler := createTestReconciler(fakeClient, scheme, devReq.Spec.UserID)
+	reconciler.Notifier = notifier
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "dev-request", Namespace: "default"}}
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, notifier.notified)
+}
+
+func securityViolationCount(t *testing.T, violationType, userID string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "jit_security_violations_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["violation_type"] == violationType && labels["user"] == userID {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestJITAccessRequestReconciler_BreakGlass(t *testing.T) {
+	tests := []struct {
+		name             string
+		permissions      []string
+		annotations      map[string]string
+		minApprovals     map[string]int
+		expectPhase      AccessPhase
+		expectBreakGlass bool
+	}{
+		{
+			name:             "view-only emergency is granted immediately",
+			permissions:      []string{"view"},
+			expectPhase:      AccessPhaseApproved,
+			expectBreakGlass: true,
+		},
+		{
+			name:        "elevated emergency still waits for approval",
+			permissions: []string{"edit"},
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:        "unverified requester waits for approval",
+			permissions: []string{"view"},
+			annotations: map[string]string{RequesterVerifiedAnnotation: ""},
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:        "sensitive namespace waits for approval",
+			permissions: []string{"view"},
+			annotations: map[string]string{SensitiveNamespacesAnnotation: "kube-system"},
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:         "cluster with an approval floor waits for approval",
+			permissions:  []string{"view"},
+			minApprovals: map[string]int{"production": 1},
+			expectPhase:  AccessPhasePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			emergencyReq := createTestRequest("emergency-request", "default", "")
+			emergencyReq.Annotations = map[string]string{
+				EmergencyAnnotation:         "true",
+				RequesterVerifiedAnnotation: "true",
+			}
+			for key, value := range tt.annotations {
+				emergencyReq.Annotations[key] = value
+			}
+			emergencyReq.Spec.TargetCluster.Name = "prod-east-1"
+			emergencyReq.Spec.Permissions = tt.permissions
+			emergencyReq.Spec.Approvers = []string{"sre-team"}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(emergencyReq).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+
+			notifier := &recordingNotifier{}
+			reconciler := createTestReconciler(fakeClient, scheme, emergencyReq.Spec.UserID)
+			reconciler.Notifier = notifier
+			reconciler.MinApprovals = tt.minApprovals
+
+			before := securityViolationCount(t, breakGlassViolation, emergencyReq.Spec.UserID)
+
+			key := types.NamespacedName{Name: "emergency-request", Namespace: "default"}
+			req := reconcile.Request{NamespacedName: key}
+			_, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+			assert.Equal(t, tt.expectPhase, updated.Status.Phase)
+
+			recorded := securityViolationCount(t, breakGlassViolation, emergencyReq.Spec.UserID) - before
+			if tt.expectBreakGlass {
+				assert.Equal(t, []string{"emergency-request"}, notifier.breakGlass)
+				assert.Empty(t, notifier.notified)
+				assert.Equal(t, float64(1), recorded)
+				assert.Equal(t, "BreakGlassViewAccess", updated.Status.Conditions[1].Reason)
+			} else {
+				assert.Empty(t, notifier.breakGlass)
+				assert.Len(t, notifier.notified, 1)
+				assert.Zero(t, recorded)
+			}
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_JobFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		conditions    []metav1.Condition
+		expectMessage string
+	}{
+		{
+			name: "failure message is copied from the job",
+			conditions: []metav1.Condition{
+				{
+					Type:               "Failed",
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+					Reason:             "AccessGrantFailed",
+					Message:            "Failed to grant access: AccessDenied",
+				},
+			},
+			expectMessage: "Failed to grant access: AccessDenied",
+		},
+		{
+			name:          "job without a failure condition",
+			expectMessage: "JIT access job failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			activeReq := createTestRequest("failed-request", "default", AccessPhaseActive)
+			job := createTestJob("failed-request", "default")
+			job.Name = JobName(activeReq)
+			job.Status.Phase = JobPhaseFailed
+			job.Status.Conditions = tt.conditions
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(activeReq, job).
+				WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+				Build()
+
+			reconciler := createTestReconciler(fakeClient, scheme, activeReq.Spec.UserID)
+
+			key := types.NamespacedName{Name: "failed-request", Namespace: "default"}
+			result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+			assert.Zero(t, result.RequeueAfter)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+			assert.Equal(t, AccessPhaseFailed, updated.Status.Phase)
+			assert.Equal(t, tt.expectMessage, updated.Status.Message)
+			assert.Nil(t, updated.Status.AccessEntry)
+
+			var provisioning *metav1.Condition
+			for i := range updated.Status.Conditions {
+				if updated.Status.Conditions[i].Type == "Provisioning" {
+					provisioning = &updated.Status.Conditions[i]
+				}
+			}
+			require.NotNil(t, provisioning)
+			assert.Equal(t, metav1.ConditionFalse, provisioning.Status)
+			assert.Equal(t, "JobFailed", provisioning.Reason)
+		})
+	}
+}
+
+func failedRequestCount(t *testing.T, cluster string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "jit_access_requests_failed_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "cluster" && label.GetValue() == cluster {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestJITAccessRequestReconciler_DeadLettersRepeatedJobCreationFailures(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	approvedReq := createTestRequest("quota-request", "default", AccessPhaseApproved)
+	approvedReq.Spec.TargetCluster.Name = "quota-cluster"
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(approvedReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(
+				ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption,
+			) error {
+				if _, ok := obj.(*JITAccessJob); ok {
+					return errors.New("exceeded quota: jitaccessjobs")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, scheme, approvedReq.Spec.UserID)
+	reconciler.MaxProvisioningAttempts = 3
+
+	key := types.NamespacedName{Name: "quota-request", Namespace: "default"}
+	before := failedRequestCount(t, "quota-cluster")
+
+	// Attempts before the limit return the error so the request is retried with backoff
+	for attempt := 1; attempt < 3; attempt++ {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		require.Error(t, err)
+
+		var updated JITAccessRequest
+		require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+		assert.Equal(t, AccessPhaseApproved, updated.Status.Phase)
+		assert.Equal(t, int32(attempt), updated.Status.ProvisioningAttempts)
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+	assert.Equal(t, AccessPhaseFailed, updated.Status.Phase)
+	assert.Equal(t, int32(3), updated.Status.ProvisioningAttempts)
+	assert.Contains(t, updated.Status.Message, "after 3 attempts: exceeded quota")
+	assert.Equal(t, float64(1), failedRequestCount(t, "quota-cluster")-before)
+
+	// The failed request is terminal and no longer retries job creation
+	result, err = reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+	assert.Equal(t, int32(3), updated.Status.ProvisioningAttempts)
+}
+
+func TestJITAccessRequestReconciler_ExistingJobIsNotAFailedAttempt(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	approvedReq := createTestRequest("retried-request", "default", AccessPhaseApproved)
+	approvedReq.Spec.TargetCluster.Name = "retried-cluster"
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(approvedReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(
+				ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption,
+			) error {
+				if job, ok := obj.(*JITAccessJob); ok {
+					return apierrors.NewAlreadyExists(schema.GroupResource{Resource: "jitaccessjobs"}, job.Name)
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, scheme, approvedReq.Spec.UserID)
+	reconciler.MaxProvisioningAttempts = 1
+
+	key := types.NamespacedName{Name: "retried-request", Namespace: "default"}
+	before := failedRequestCount(t, "retried-cluster")
+
+	// The job from an earlier reconcile already exists, so the request moves on instead of dead-lettering
+	_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	assert.Zero(t, updated.Status.ProvisioningAttempts)
+	assert.Zero(t, failedRequestCount(t, "retried-cluster")-before)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestJITAccessRequestReconciler_ExpiresOnClock(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	start := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	activeReq := createTestRequest("clocked-request", "default", AccessPhaseActive)
+	activeReq.Status.AccessEntry = &AccessEntryStatus{ExpiresAt: metav1.NewTime(start.Add(time.Hour))}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(activeReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+
+	clock := &fakeClock{now: start.Add(59 * time.Minute)}
+	reconciler := createTestReconciler(fakeClient, scheme, activeReq.Spec.UserID)
+	reconciler.Clock = clock
+
+	key := types.NamespacedName{Name: "clocked-request", Namespace: "default"}
+	_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase, "access is still valid a minute before expiry")
+
+	clock.now = start.Add(time.Hour + time.Second)
+	_, err = reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+	assert.Equal(t, AccessPhaseExpired, updated.Status.Phase)
+
+	var expired *metav1.Condition
+	for i := range updated.Status.Conditions {
+		if updated.Status.Conditions[i].Type == "Expired" {
+			expired = &updated.Status.Conditions[i]
+		}
+	}
+	require.NotNil(t, expired)
+	assert.True(t, expired.LastTransitionTime.Time.Equal(clock.now))
+}
+
+func TestJITAccessRequestReconciler_TrustedOperator(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		onBehalfOf    *Delegate
+		unverified    bool
+		expectPhase   AccessPhase
+		expectTrusted bool
+	}{
+		{
+			name:          "trusted operator's prod edit request is approved",
+			userID:        "U0TRUSTED01",
+			expectPhase:   AccessPhaseApproved,
+			expectTrusted: true,
+		},
+		{
+			name:        "other users still wait for approval",
+			userID:      "U0ORDINARY1",
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:        "trusted operator filing for someone else waits for approval",
+			userID:      "U0TRUSTED01",
+			onBehalfOf:  &Delegate{Email: "contractor@vendor.com"},
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:        "unverified request naming a trusted operator waits for approval",
+			userID:      "U0TRUSTED01",
+			unverified:  true,
+			expectPhase: AccessPhasePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			prodReq := createTestRequest("prod-edit-request", "default", AccessPhasePending)
+			prodReq.Spec.UserID = tt.userID
+			prodReq.Spec.OnBehalfOf = tt.onBehalfOf
+			prodReq.Spec.TargetCluster.Name = "prod-east-1"
+			prodReq.Spec.Permissions = []string{"edit"}
+			prodReq.Spec.Approvers = []string{"platform-team", "sre-team"}
+			if !tt.unverified {
+				prodReq.Annotations = map[string]string{RequesterVerifiedAnnotation: "true"}
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(prodReq).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+
+			reconciler := createTestReconciler(fakeClient, scheme, tt.userID)
+			reconciler.TrustedUsers = []string{"U0TRUSTED01"}
+
+			before := securityViolationCount(t, trustedOperatorApproval, tt.userID)
+
+			key := types.NamespacedName{Name: "prod-edit-request", Namespace: "default"}
+			_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(context.Background(), key, &updated))
+			assert.Equal(t, tt.expectPhase, updated.Status.Phase)
+
+			recorded := securityViolationCount(t, trustedOperatorApproval, tt.userID) - before
+			if tt.expectTrusted {
+				approved := meta.FindStatusCondition(updated.Status.Conditions, "Approved")
+				require.NotNil(t, approved)
+				assert.Equal(t, "TrustedOperator", approved.Reason)
+				assert.Equal(t, float64(1), recorded, "trusted approvals must be audited")
+			} else {
+				assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, "Approved"))
+				assert.Zero(t, recorded)
+			}
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_ExpiredRequestDrivesStuckJobToCompletion(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	// A revoked request whose job is still Active because its own expiry is an hour away
+	revokedReq := createTestRequest("revoked-request", "default", AccessPhaseRevoked)
+	job := createTestJob("revoked-request", "default")
+	job.Name = JobName(revokedReq)
+	job.Status = JITAccessJobStatus{
+		Phase:      JobPhaseActive,
+		ExpiryTime: &metav1.Time{Time: time.Now().Add(time.Hour)},
+		AccessEntry: &JobAccessEntry{
+			CredentialsSecretRef: &ObjectReference{Name: "revoked-request-credentials", Namespace: "default"},
+		},
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "revoked-request-credentials", Namespace: "default"}}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(revokedReq, job, secret).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, scheme, revokedReq.Spec.UserID)
+	provisioner := &fakeAccessProvisioner{}
+	jobReconciler := &JITAccessJobReconciler{Client: fakeClient, Scheme: scheme, AccessManager: provisioner}
+
+	requestKey := types.NamespacedName{Name: revokedReq.Name, Namespace: "default"}
+	jobKey := types.NamespacedName{Name: job.Name, Namespace: "default"}
+
+	// The job controller alone leaves the job Active
+	_, err := jobReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: jobKey})
+	require.NoError(t, err)
+	var updatedJob JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, jobKey, &updatedJob))
+	require.Equal(t, JobPhaseActive, updatedJob.Status.Phase)
+
+	// The request moves the stuck job to Expiring and keeps checking
+	result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: requestKey})
+	require.NoError(t, err)
+	assert.Equal(t, expiredCleanupRequeue, result.RequeueAfter)
+	require.NoError(t, fakeClient.Get(ctx, jobKey, &updatedJob))
+	assert.Equal(t, JobPhaseExpiring, updatedJob.Status.Phase)
+	expiring := meta.FindStatusCondition(updatedJob.Status.Conditions, "Expiring")
+	require.NotNil(t, expiring)
+	assert.Equal(t, "RequestEnded", expiring.Reason)
+
+	// Cleanup is still pending, so the request keeps requeueing
+	result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: requestKey})
+	require.NoError(t, err)
+	assert.Equal(t, expiredCleanupRequeue, result.RequeueAfter)
+
+	// The job controller revokes access and removes the secret
+	_, err = jobReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: jobKey})
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, jobKey, &updatedJob))
+	assert.Equal(t, JobPhaseCompleted, updatedJob.Status.Phase)
+	assert.Equal(t, 1, provisioner.revokeCalls)
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: "default"}, &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Once the job has completed, the request stops requeueing
+	result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: requestKey})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+}
+
+func TestJITAccessRequestReconciler_ExpiredRequestWithoutJob(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	expiredReq := createTestRequest("jobless-request", "default", AccessPhaseExpired)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(expiredReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, expiredReq.Spec.UserID)
+
+	key := types.NamespacedName{Name: "jobless-request", Namespace: "default"}
+	result, err := reconciler.Reconcile(t.Context(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+}
+
+func TestJITAccessRequestReconciler_MixedPermissionsNeedElevatedApproval(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		fallback    bool
+		sensitive   bool
+		expectPhase AccessPhase
+	}{
+		{name: "view alone is auto-approved", permissions: []string{"view"}, expectPhase: AccessPhaseApproved},
+		{
+			name:        "view alongside exec waits for approvers",
+			permissions: []string{"view", "exec"},
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:        "view with fallback approvers waits for approvers",
+			permissions: []string{"view"},
+			fallback:    true,
+			expectPhase: AccessPhasePending,
+		},
+		{
+			name:        "view of a sensitive namespace waits for approvers",
+			permissions: []string{"view"},
+			sensitive:   true,
+			expectPhase: AccessPhasePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			jitReq := createTestRequest("mixed-request", "default", AccessPhasePending)
+			jitReq.Spec.TargetCluster.Name = "prod-east-1"
+			jitReq.Spec.Permissions = tt.permissions
+			jitReq.Spec.Approvers = []string{"platform-team", "sre-team", "security-team"}
+			jitReq.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+			if tt.fallback {
+				jitReq.Annotations[FallbackApproversAnnotation] = "true"
+			}
+			if tt.sensitive {
+				jitReq.Annotations[SensitiveNamespacesAnnotation] = "kube-system"
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(jitReq).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+			reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+
+			key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+			_, err := reconciler.Reconcile(t.Context(), reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(t.Context(), key, &updated))
+			assert.Equal(t, tt.expectPhase, updated.Status.Phase)
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_SensitiveNamespaceNeedsSensitiveApprover(t *testing.T) {
+	tests := []struct {
+		name      string
+		approvers []string
+		approvals []string
+		expected  bool
+	}{
+		{
+			name:      "owner approval alone is not enough",
+			approvers: []string{"payments-team", "platform-team", "security-team"},
+			approvals: []string{"payments-team"},
+			expected:  false,
+		},
+		{
+			name:      "a sensitive namespace approver completes the quorum",
+			approvers: []string{"payments-team", "platform-team", "security-team"},
+			approvals: []string{"payments-team", "security-team"},
+			expected:  true,
+		},
+		{
+			name:     "no listed approvers still need a sensitive namespace approver",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &JITAccessRequestReconciler{}
+			req := createTestRequest("sensitive-request", "default", AccessPhasePending)
+			req.Annotations = map[string]string{SensitiveNamespacesAnnotation: "kube-system"}
+			if tt.approvers != nil {
+				req.Annotations[RequiredApprovalsAnnotation] = "1"
+			}
+			req.Spec.Approvers = tt.approvers
+			for _, approver := range tt.approvals {
+				req.Status.Approvals = append(req.Status.Approvals, Approval{Approver: approver})
+			}
+
+			assert.Equal(t, tt.expected, r.hasRequiredApprovals(req))
+		})
+	}
+}
+
+func approvalLatencySamples(t *testing.T, approver string) (uint64, float64) {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "jit_approval_latency_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "approver" && label.GetValue() == approver {
+					return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestJITAccessRequestReconciler_RecordsApprovalLatencyOnApproval(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+	jitReq := createTestRequest("latency-request", "default", AccessPhasePending)
+	jitReq.CreationTimestamp = metav1.NewTime(created)
+	jitReq.Spec.TargetCluster.Name = "staging-east-1"
+	jitReq.Spec.Permissions = []string{"edit"}
+	jitReq.Spec.Approvers = []string{"U0LATENCY01"}
+	jitReq.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+	jitReq.Status.Approvals = []Approval{
+		{Approver: "U0LATENCY01", ApprovedAt: metav1.NewTime(created.Add(30 * time.Minute))},
+		{Approver: jitReq.Spec.UserID, ApprovedAt: metav1.NewTime(created.Add(time.Minute))},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(jitReq).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(t.Context(), reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(t.Context(), key, &updated))
+	require.Equal(t, AccessPhaseApproved, updated.Status.Phase)
+
+	count, sum := approvalLatencySamples(t, "U0LATENCY01")
+	assert.Equal(t, uint64(1), count)
+	assert.InDelta(t, (30 * time.Minute).Seconds(), sum, 1)
+
+	// The requester's own approval is not an approver's latency
+	count, _ = approvalLatencySamples(t, jitReq.Spec.UserID)
+	assert.Zero(t, count)
+}
+
+func phaseTransitionValue(t *testing.T, from, to AccessPhase) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "jit_request_phase_transitions_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["from"] == string(from) && labels["to"] == string(to) {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestJITAccessRequestReconciler_RecordsPhaseTransitions(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	jitReq := createTestRequest("transition-request", "default", AccessPhasePending)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(jitReq).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	reconcileRequest := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		require.NoError(t, err)
+	}
+	approved := phaseTransitionValue(t, AccessPhasePending, AccessPhaseApproved)
+	activated := phaseTransitionValue(t, AccessPhaseApproved, AccessPhaseActive)
+
+	reconcileRequest()
+	assert.Equal(t, approved+1, phaseTransitionValue(t, AccessPhasePending, AccessPhaseApproved))
+	assert.Equal(t, activated, phaseTransitionValue(t, AccessPhaseApproved, AccessPhaseActive))
+
+	// Creating the job activates the request
+	reconcileRequest()
+	assert.Equal(t, activated+1, phaseTransitionValue(t, AccessPhaseApproved, AccessPhaseActive))
+
+	var job JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: JobName(jitReq), Namespace: "default"}, &job))
+	start := metav1.Now()
+	expiry := metav1.NewTime(start.Add(2 * time.Hour))
+	job.Status.Phase = JobPhaseActive
+	job.Status.StartTime = &start
+	job.Status.ExpiryTime = &expiry
+	job.Status.AccessEntry = &JobAccessEntry{SessionName: "jit-session"}
+	require.NoError(t, fakeClient.Status().Update(ctx, &job))
+
+	// Syncing the granted job and later no-op reconciles keep the phase, so nothing more is counted
+	reconcileRequest()
+	reconcileRequest()
+	assert.Equal(t, approved+1, phaseTransitionValue(t, AccessPhasePending, AccessPhaseApproved))
+	assert.Equal(t, activated+1, phaseTransitionValue(t, AccessPhaseApproved, AccessPhaseActive))
+}
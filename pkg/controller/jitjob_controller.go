@@ -9,18 +9,64 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/clock"
 	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
 	"github.com/rebelopsio/jit-bot/pkg/models"
 )
 
+const (
+	// credentialRefreshWindow is how long before STS expiry the credentials are re-issued
+	credentialRefreshWindow = 10 * time.Minute
+
+	// STS session duration bounds for AssumeRole
+	minSTSSessionDuration = aws.MinSessionDuration
+	maxSTSSessionDuration = aws.MaxSessionDuration
+
+	// Secret types, recorded in the jit.rebelops.io/type label and secret metrics
+	credentialsSecretType = "credentials"
+	kubeConfigSecretType  = "kubeconfig"
+)
+
+// AccessProvisioner grants, refreshes and revokes cluster access for the job controller
+type AccessProvisioner interface {
+	GrantAccess(ctx context.Context, req kubernetes.GrantAccessRequest) (*kubernetes.AccessCredentials, error)
+	RefreshCredentials(
+		ctx context.Context, req kubernetes.RefreshCredentialsRequest,
+	) (*kubernetes.AccessCredentials, error)
+	RevokeAccess(
+		ctx context.Context, clusterAccess *models.ClusterAccess, cluster *models.Cluster, jitRoleArn string,
+	) error
+}
+
 // JITAccessJobReconciler reconciles a JITAccessJob object
 type JITAccessJobReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
-	AccessManager *kubernetes.AccessManager
+	AccessManager AccessProvisioner
+	// Clock drives expiry and credential refresh; nil uses the system clock
+	Clock clock.Clock
+	// PropagatedMetadataKeys are the job label and annotation keys copied onto its secrets
+	PropagatedMetadataKeys []string
+	// RecordGrantSummary writes the access policies, scope and groups that were granted to the job status
+	RecordGrantSummary bool
+	// StepDowns narrow broad permissions partway through a session; nil never steps down
+	StepDowns StepDownSchedule
+	// ClusterEnvironments are the cluster registry's environment tags keyed by lowercase cluster
+	// name, used to label provisioning metrics instead of the requester-writable environment label
+	ClusterEnvironments map[string]string
+}
+
+func (r *JITAccessJobReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
 }
 
 //+kubebuilder:rbac:groups=jit.rebelops.io,resources=jitaccessjobs,verbs=get;list;watch;create;update;patch;delete
@@ -62,17 +108,17 @@ func (r *JITAccessJobReconciler) handlePendingJob(ctx context.Context, job *JITA
 
 	// Initialize status
 	job.Status.Phase = JobPhaseCreating
-	now := metav1.Now()
+	now := metav1.NewTime(r.now())
 	job.Status.StartTime = &now
 
 	// Parse duration and set expiry time
-	duration, err := time.ParseDuration(job.Spec.Duration)
+	duration, err := ParseDuration(job.Spec.Duration)
 	if err != nil {
 		job.Status.Phase = JobPhaseFailed
 		r.setJobCondition(job, metav1.Condition{
 			Type:               "Failed",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "InvalidDuration",
 			Message:            fmt.Sprintf("Failed to parse duration: %v", err),
 		})
@@ -85,7 +131,7 @@ func (r *JITAccessJobReconciler) handlePendingJob(ctx context.Context, job *JITA
 	r.setJobCondition(job, metav1.Condition{
 		Type:               "Started",
 		Status:             metav1.ConditionTrue,
-		LastTransitionTime: metav1.Now(),
+		LastTransitionTime: metav1.NewTime(r.now()),
 		Reason:             "JobStarted",
 		Message:            "JIT access job has started",
 	})
@@ -112,7 +158,7 @@ func (r *JITAccessJobReconciler) handleCreatingJob(ctx context.Context, job *JIT
 		r.setJobCondition(job, metav1.Condition{
 			Type:               "Failed",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "AccessRequestNotFound",
 			Message:            fmt.Sprintf("Failed to fetch access request: %v", err),
 		})
@@ -123,13 +169,23 @@ func (r *JITAccessJobReconciler) handleCreatingJob(ctx context.Context, job *JIT
 	}
 
 	// Create AWS access
+	clusterAccess := r.convertToClusterAccess(&accessReq)
+	log.Info("granting access",
+		"request", accessReq.Name,
+		"cluster", job.Spec.TargetCluster.Name,
+		"requestedBy", clusterAccess.RequestedBy,
+		"grantee", clusterAccess.UserID,
+		"granteeEmail", clusterAccess.UserEmail)
+
 	grantReq := kubernetes.GrantAccessRequest{
-		ClusterAccess: r.convertToClusterAccess(&accessReq),
-		Cluster:       r.convertToCluster(&accessReq.Spec.TargetCluster),
-		UserEmail:     accessReq.Spec.UserEmail,
-		Permissions:   job.Spec.Permissions,
-		Namespaces:    job.Spec.Namespaces,
-		JITRoleArn:    job.Spec.JITRoleArn,
+		ClusterAccess:   clusterAccess,
+		Cluster:         r.convertToCluster(&job.Spec.TargetCluster),
+		UserEmail:       clusterAccess.UserEmail,
+		Permissions:     job.Spec.Permissions,
+		PermissionSpecs: convertPermissionSpecs(accessReq.Spec.PermissionSpecs),
+		Namespaces:      job.Spec.Namespaces,
+		JITRoleArn:      job.Spec.JITRoleArn,
+		ResourceScope:   convertResourceScope(accessReq.Spec.ResourceScope),
 	}
 
 	credentials, err := r.AccessManager.GrantAccess(ctx, grantReq)
@@ -139,7 +195,7 @@ func (r *JITAccessJobReconciler) handleCreatingJob(ctx context.Context, job *JIT
 		r.setJobCondition(job, metav1.Condition{
 			Type:               "Failed",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "AccessGrantFailed",
 			Message:            fmt.Sprintf("Failed to grant access: %v", err),
 		})
@@ -166,22 +222,47 @@ func (r *JITAccessJobReconciler) handleCreatingJob(ctx context.Context, job *JIT
 	job.Status.Phase = JobPhaseActive
 	job.Status.AccessEntry = &JobAccessEntry{
 		PrincipalArn: "arn:aws:sts::" + job.Spec.TargetCluster.AWSAccount + ":assumed-role/JITAccessRole/" +
-			fmt.Sprintf("jit-%s-%s-%d", accessReq.Spec.UserID, job.Spec.TargetCluster.Name, time.Now().Unix()),
+			fmt.Sprintf("jit-%s-%s-%d", accessReq.Spec.UserID, job.Spec.TargetCluster.Name, r.now().Unix()),
 		SessionName: fmt.Sprintf("jit-%s-%s", accessReq.Spec.UserID, job.Spec.TargetCluster.Name),
 		CredentialsSecretRef: &ObjectReference{
 			Name:      credentialsSecret.Name,
 			Namespace: credentialsSecret.Namespace,
 		},
 	}
+	if credentials.SessionName != "" {
+		job.Status.AccessEntry.SessionName = credentials.SessionName
+	}
+	if credentials.PrincipalArn != "" {
+		job.Status.AccessEntry.PrincipalArn = credentials.PrincipalArn
+	}
 	job.Status.KubeConfigSecretRef = &ObjectReference{
 		Name:      kubeConfigSecret.Name,
 		Namespace: kubeConfigSecret.Namespace,
 	}
+	if credentials.TemporaryCredentials != nil {
+		credentialsExpiry := metav1.NewTime(credentials.TemporaryCredentials.Expiration)
+		job.Status.CredentialsExpiryTime = &credentialsExpiry
+	}
+	if credentials.DurationClamped {
+		// Warn rather than fail: the credentials are refreshed until the session ends
+		r.setJobCondition(job, metav1.Condition{
+			Type:               "DurationClamped",
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "CredentialLifetimeCapped",
+			Message: fmt.Sprintf("AWS credentials expire at %s, before the requested %s duration; "+
+				"they are refreshed until the session ends",
+				credentials.ExpiresAt.UTC().Format(time.RFC3339), job.Spec.Duration),
+		})
+	}
+	if r.RecordGrantSummary {
+		job.Status.GrantSummary = grantSummary(credentials.AccessEntry)
+	}
 
 	r.setJobCondition(job, metav1.Condition{
 		Type:               "AccessGranted",
 		Status:             metav1.ConditionTrue,
-		LastTransitionTime: metav1.Now(),
+		LastTransitionTime: metav1.NewTime(r.now()),
 		Reason:             "AccessCreated",
 		Message:            "JIT access has been successfully created",
 	})
@@ -191,7 +272,21 @@ func (r *JITAccessJobReconciler) handleCreatingJob(ctx context.Context, job *JIT
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Successfully granted JIT access", "user", accessReq.Spec.UserID, "cluster", job.Spec.TargetCluster.Name)
+	if job.Status.StartTime != nil {
+		metrics.RecordProvisionDuration(
+			job.Spec.TargetCluster.Name,
+			registryEnvironment(r.ClusterEnvironments, job.Spec.TargetCluster.Name),
+			r.now().Sub(job.Status.StartTime.Time),
+		)
+	}
+
+	// Only the non-secret parts of the credentials are logged
+	log.Info("Successfully granted JIT access",
+		"user", accessReq.Spec.UserID,
+		"cluster", job.Spec.TargetCluster.Name,
+		"sessionName", credentials.SessionName,
+		"principalArn", credentials.PrincipalArn,
+		"expiresAt", credentials.ExpiresAt)
 
 	// Check expiry periodically
 	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
@@ -199,12 +294,12 @@ func (r *JITAccessJobReconciler) handleCreatingJob(ctx context.Context, job *JIT
 
 func (r *JITAccessJobReconciler) handleActiveJob(ctx context.Context, job *JITAccessJob) (ctrl.Result, error) {
 	// Check if job has expired
-	if job.Status.ExpiryTime != nil && time.Now().After(job.Status.ExpiryTime.Time) {
+	if job.Status.ExpiryTime != nil && r.now().After(job.Status.ExpiryTime.Time) {
 		job.Status.Phase = JobPhaseExpiring
 		r.setJobCondition(job, metav1.Condition{
 			Type:               "Expiring",
 			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
+			LastTransitionTime: metav1.NewTime(r.now()),
 			Reason:             "AccessExpired",
 			Message:            "JIT access has expired and is being cleaned up",
 		})
@@ -215,10 +310,136 @@ func (r *JITAccessJobReconciler) handleActiveJob(ctx context.Context, job *JITAc
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
-	// Continue monitoring
+	// Narrow broad permissions once their initial window has passed
+	if r.stepDownDue(job) {
+		return r.stepDownAccess(ctx, job)
+	}
+
+	// Re-issue STS credentials before they lapse mid-session
+	if r.credentialsNeedRefresh(job) {
+		return r.refreshCredentials(ctx, job)
+	}
+
+	// Continue monitoring, waking up at the step-down boundary if it comes first
+	requeueAfter := time.Minute * 2
+	if wait, ok := r.untilStepDown(job); ok && wait < requeueAfter {
+		requeueAfter = wait
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *JITAccessJobReconciler) credentialsNeedRefresh(job *JITAccessJob) bool {
+	if job.Status.CredentialsExpiryTime == nil || job.Status.AccessEntry == nil {
+		return false
+	}
+
+	// No point refreshing if the session itself ends before the credentials do
+	if job.Status.ExpiryTime != nil && !job.Status.CredentialsExpiryTime.Before(job.Status.ExpiryTime) {
+		return false
+	}
+
+	return job.Status.CredentialsExpiryTime.Sub(r.now()) < credentialRefreshWindow
+}
+
+// refreshCredentials re-assumes the JIT role and rewrites the credential secrets in place,
+// leaving the EKS access entry untouched.
+func (r *JITAccessJobReconciler) refreshCredentials(ctx context.Context, job *JITAccessJob) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var accessReq JITAccessRequest
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      job.Spec.AccessRequestRef.Name,
+		Namespace: job.Spec.AccessRequestRef.Namespace,
+	}, &accessReq); err != nil {
+		log.Error(err, "unable to fetch JITAccessRequest for credential refresh")
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	duration := maxSTSSessionDuration
+	if job.Status.ExpiryTime != nil {
+		duration = job.Status.ExpiryTime.Sub(r.now())
+	}
+	duration = min(max(duration, minSTSSessionDuration), maxSTSSessionDuration)
+
+	// A stepped-down session keeps its narrowed permissions
+	permissions := grantedPermissions(job)
+	clusterAccess := r.convertToClusterAccess(&accessReq)
+	clusterAccess.Permissions = permissions
+
+	credentials, err := r.AccessManager.RefreshCredentials(ctx, kubernetes.RefreshCredentialsRequest{
+		ClusterAccess: clusterAccess,
+		Cluster:       r.convertToCluster(&job.Spec.TargetCluster),
+		Permissions:   permissions,
+		JITRoleArn:    job.Spec.JITRoleArn,
+		SessionName:   job.Status.AccessEntry.SessionName,
+		Duration:      duration,
+	})
+	if err != nil {
+		log.Error(err, "failed to refresh credentials")
+		r.setJobCondition(job, metav1.Condition{
+			Type:               "CredentialsRefreshed",
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "RefreshFailed",
+			Message:            fmt.Sprintf("Failed to refresh credentials: %v", err),
+		})
+		if updateErr := r.Status().Update(ctx, job); updateErr != nil {
+			log.Error(updateErr, "unable to update JITAccessJob status")
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	if ref := job.Status.AccessEntry.CredentialsSecretRef; ref != nil {
+		if err = r.updateSecretData(ctx, ref, credentialsSecretData(credentials)); err != nil {
+			log.Error(err, "failed to update credentials secret")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if ref := job.Status.KubeConfigSecretRef; ref != nil {
+		if err = r.updateSecretData(ctx, ref, map[string][]byte{
+			"kubeconfig": []byte(credentials.KubeConfig),
+		}); err != nil {
+			log.Error(err, "failed to update kubeconfig secret")
+			return ctrl.Result{}, err
+		}
+	}
+
+	credentialsExpiry := metav1.NewTime(credentials.ExpiresAt)
+	job.Status.CredentialsExpiryTime = &credentialsExpiry
+
+	r.setJobCondition(job, metav1.Condition{
+		Type:               "CredentialsRefreshed",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "CredentialsRotated",
+		Message:            "Temporary credentials have been refreshed",
+	})
+
+	if err = r.Status().Update(ctx, job); err != nil {
+		log.Error(err, "unable to update JITAccessJob status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Refreshed JIT access credentials", "job", job.Name,
+		"sessionName", credentials.SessionName,
+		"principalArn", credentials.PrincipalArn,
+		"expiresAt", credentials.ExpiresAt)
 	return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
 }
 
+func (r *JITAccessJobReconciler) updateSecretData(
+	ctx context.Context, ref *ObjectReference, data map[string][]byte,
+) error {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, &secret); err != nil {
+		return err
+	}
+
+	secret.Data = data
+	return r.Update(ctx, &secret)
+}
+
 func (r *JITAccessJobReconciler) handleExpiringJob(ctx context.Context, job *JITAccessJob) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
@@ -231,9 +452,12 @@ func (r *JITAccessJobReconciler) handleExpiringJob(ctx context.Context, job *JIT
 		log.Error(err, "unable to fetch JITAccessRequest for cleanup")
 		// Continue with cleanup anyway
 	} else {
-		// Revoke access
+		// Revoke access, targeting the session that was actually granted
 		clusterAccess := r.convertToClusterAccess(&accessReq)
-		cluster := r.convertToCluster(&accessReq.Spec.TargetCluster)
+		if job.Status.AccessEntry != nil {
+			clusterAccess.SessionName = job.Status.AccessEntry.SessionName
+		}
+		cluster := r.convertToCluster(&job.Spec.TargetCluster)
 
 		if err = r.AccessManager.RevokeAccess(ctx, clusterAccess, cluster, job.Spec.JITRoleArn); err != nil {
 			log.Error(err, "failed to revoke access")
@@ -251,6 +475,8 @@ func (r *JITAccessJobReconciler) handleExpiringJob(ctx context.Context, job *JIT
 		}
 		if err := r.Delete(ctx, secret); err != nil {
 			log.Error(err, "failed to delete credentials secret")
+		} else {
+			metrics.RecordSecretDeleted(credentialsSecretType)
 		}
 	}
 
@@ -263,18 +489,20 @@ func (r *JITAccessJobReconciler) handleExpiringJob(ctx context.Context, job *JIT
 		}
 		if err := r.Delete(ctx, secret); err != nil {
 			log.Error(err, "failed to delete kubeconfig secret")
+		} else {
+			metrics.RecordSecretDeleted(kubeConfigSecretType)
 		}
 	}
 
 	// Mark as completed
 	job.Status.Phase = JobPhaseCompleted
-	now := metav1.Now()
+	now := metav1.NewTime(r.now())
 	job.Status.CompletionTime = &now
 
 	r.setJobCondition(job, metav1.Condition{
 		Type:               "Completed",
 		Status:             metav1.ConditionTrue,
-		LastTransitionTime: metav1.Now(),
+		LastTransitionTime: metav1.NewTime(r.now()),
 		Reason:             "AccessRevoked",
 		Message:            "JIT access has been successfully revoked and cleaned up",
 	})
@@ -302,19 +530,28 @@ func (r *JITAccessJobReconciler) createCredentialsSecret(
 			Namespace: job.Namespace,
 			Labels: map[string]string{
 				"jit.rebelops.io/job":  job.Name,
-				"jit.rebelops.io/type": "credentials",
+				"jit.rebelops.io/type": credentialsSecretType,
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"aws-access-key-id":     []byte(creds.TemporaryCredentials.AccessKeyID),
-			"aws-secret-access-key": []byte(creds.TemporaryCredentials.SecretAccessKey),
-			"aws-session-token":     []byte(creds.TemporaryCredentials.SessionToken),
-			"expires-at":            []byte(creds.ExpiresAt.Format(time.RFC3339)),
-		},
+		Data: credentialsSecretData(creds),
 	}
 
-	return secret, r.Create(context.TODO(), secret)
+	propagateMetadata(r.PropagatedMetadataKeys, job, secret)
+	if err := r.Create(context.TODO(), secret); err != nil {
+		return secret, err
+	}
+	metrics.RecordSecretCreated(credentialsSecretType)
+	return secret, nil
+}
+
+func credentialsSecretData(creds *kubernetes.AccessCredentials) map[string][]byte {
+	return map[string][]byte{
+		"aws-access-key-id":     []byte(creds.TemporaryCredentials.AccessKeyID),
+		"aws-secret-access-key": []byte(creds.TemporaryCredentials.SecretAccessKey),
+		"aws-session-token":     []byte(creds.TemporaryCredentials.SessionToken),
+		"expires-at":            []byte(creds.ExpiresAt.Format(time.RFC3339)),
+	}
 }
 
 func (r *JITAccessJobReconciler) createKubeConfigSecret(job *JITAccessJob, kubeConfig string) (*corev1.Secret, error) {
@@ -324,7 +561,7 @@ func (r *JITAccessJobReconciler) createKubeConfigSecret(job *JITAccessJob, kubeC
 			Namespace: job.Namespace,
 			Labels: map[string]string{
 				"jit.rebelops.io/job":  job.Name,
-				"jit.rebelops.io/type": "kubeconfig",
+				"jit.rebelops.io/type": kubeConfigSecretType,
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -333,7 +570,12 @@ func (r *JITAccessJobReconciler) createKubeConfigSecret(job *JITAccessJob, kubeC
 		},
 	}
 
-	return secret, r.Create(context.TODO(), secret)
+	propagateMetadata(r.PropagatedMetadataKeys, job, secret)
+	if err := r.Create(context.TODO(), secret); err != nil {
+		return secret, err
+	}
+	metrics.RecordSecretCreated(kubeConfigSecretType)
+	return secret, nil
 }
 
 func (r *JITAccessJobReconciler) setJobCondition(job *JITAccessJob, condition metav1.Condition) {
@@ -351,17 +593,33 @@ func (r *JITAccessJobReconciler) setJobCondition(job *JITAccessJob, condition me
 
 // Helper functions to convert between types
 func (r *JITAccessJobReconciler) convertToClusterAccess(req *JITAccessRequest) *models.ClusterAccess {
-	duration, _ := time.ParseDuration(req.Spec.Duration)
-	return &models.ClusterAccess{
+	duration, _ := ParseDuration(req.Spec.Duration)
+	access := &models.ClusterAccess{
 		ID:          req.Name,
+		RequestName: req.Name,
 		ClusterID:   req.Spec.TargetCluster.Name,
 		UserID:      req.Spec.UserID,
 		UserEmail:   req.Spec.UserEmail,
+		RequestedBy: req.Spec.UserID,
 		Reason:      req.Spec.Reason,
+		Permissions: req.Spec.Permissions,
 		Duration:    duration,
 		Status:      models.AccessStatusActive,
 		RequestedAt: req.Spec.RequestedAt.Time,
+		CostCenter:  req.Spec.CostCenter,
+		Team:        req.Spec.Team,
 	}
+
+	// Delegated requests grant access to the delegate, not the requester
+	if delegate := req.Spec.OnBehalfOf; delegate != nil {
+		access.UserID = delegate.UserID
+		if access.UserID == "" {
+			access.UserID = delegate.Email
+		}
+		access.UserEmail = delegate.Email
+	}
+
+	return access
 }
 
 func (r *JITAccessJobReconciler) convertToCluster(target *TargetCluster) *models.Cluster {
@@ -377,10 +635,63 @@ func (r *JITAccessJobReconciler) convertToCluster(target *TargetCluster) *models
 	}
 }
 
+// convertResourceScope maps the CRD resource scope onto the AWS inline policy scope
+func convertResourceScope(scope *ResourceScope) *aws.ResourceScope {
+	if scope == nil {
+		return nil
+	}
+	return &aws.ResourceScope{
+		APIGroups:  scope.APIGroups,
+		Resources:  scope.Resources,
+		Verbs:      scope.Verbs,
+		Namespaces: scope.Namespaces,
+	}
+}
+
+// convertPermissionSpecs maps the CRD permission specs onto the AWS access entry specs
+func convertPermissionSpecs(specs []PermissionSpec) []aws.PermissionSpec {
+	if len(specs) == 0 {
+		return nil
+	}
+	converted := make([]aws.PermissionSpec, 0, len(specs))
+	for _, spec := range specs {
+		converted = append(converted, aws.PermissionSpec{
+			Level:      spec.Level,
+			Namespaces: spec.Namespaces,
+			Resources:  spec.Resources,
+		})
+	}
+	return converted
+}
+
+// grantSummary lists what the access entry granted, so the job status shows exactly what AWS
+// was told to grant
+func grantSummary(entry *aws.AccessEntry) *GrantSummary {
+	if entry == nil {
+		return nil
+	}
+
+	summary := &GrantSummary{KubernetesGroups: entry.Groups}
+	if entry.InlinePolicy != nil {
+		summary.InlinePolicy = &GrantedInlinePolicy{
+			Name:       entry.InlinePolicy.Name,
+			Namespaces: entry.InlinePolicy.Namespaces,
+		}
+	}
+	for _, policy := range entry.AccessPolicies {
+		summary.AccessPolicies = append(summary.AccessPolicies, GrantedAccessPolicy{
+			PolicyArn:  policy.PolicyArn,
+			ScopeType:  policy.AccessScope.Type,
+			Namespaces: policy.AccessScope.Namespaces,
+		})
+	}
+	return summary
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *JITAccessJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&JITAccessJob{}).
+		For(&JITAccessJob{}, builder.WithPredicates(accessJobPredicate())).
 		Owns(&corev1.Secret{}). // Watch owned secrets
 		Complete(r)
 }
@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,11 +18,55 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/rebelopsio/jit-bot/pkg/aws"
 	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+	"github.com/rebelopsio/jit-bot/pkg/models"
 )
 
-// Note: The actual AccessManager is a struct, not an interface.
-// For test purposes, we'll use the real AccessManager but won't call AWS APIs in tests.
+// fakeAccessProvisioner stands in for the AWS-backed AccessManager and records calls
+type fakeAccessProvisioner struct {
+	grantCredentials   *kubernetes.AccessCredentials
+	grantRequests      []kubernetes.GrantAccessRequest
+	refreshCredentials *kubernetes.AccessCredentials
+	refreshRequests    []kubernetes.RefreshCredentialsRequest
+	revokeCalls        int
+}
+
+func (f *fakeAccessProvisioner) GrantAccess(
+	_ context.Context, req kubernetes.GrantAccessRequest,
+) (*kubernetes.AccessCredentials, error) {
+	f.grantRequests = append(f.grantRequests, req)
+	return f.grantCredentials, nil
+}
+
+func (f *fakeAccessProvisioner) RefreshCredentials(
+	_ context.Context, req kubernetes.RefreshCredentialsRequest,
+) (*kubernetes.AccessCredentials, error) {
+	f.refreshRequests = append(f.refreshRequests, req)
+	return f.refreshCredentials, nil
+}
+
+func (f *fakeAccessProvisioner) RevokeAccess(
+	_ context.Context, _ *models.ClusterAccess, _ *models.Cluster, _ string,
+) error {
+	f.revokeCalls++
+	return nil
+}
+
+func newFakeCredentials(accessKeyID string, expiresAt time.Time) *kubernetes.AccessCredentials {
+	return &kubernetes.AccessCredentials{
+		TemporaryCredentials: &aws.Credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: "secret-" + accessKeyID,
+			SessionToken:    "token-" + accessKeyID,
+			Expiration:      expiresAt,
+		},
+		KubeConfig:   "kubeconfig-" + accessKeyID,
+		ExpiresAt:    expiresAt,
+		SessionName:  "jit-U123456789A-dev-east-1-20240101-000000",
+		PrincipalArn: "arn:aws:sts::123456789012:assumed-role/JITAccess/jit-U123456789A-dev-east-1-20240101-000000",
+	}
+}
 
 func TestJITAccessJobReconciler_Reconcile(t *testing.T) {
 	scheme := setupJobTestScheme(t)
@@ -289,6 +335,466 @@ func createCompletedTestJob() *JITAccessJob {
 	}
 }
 
+func TestJITAccessJobReconciler_RefreshCredentials(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	request := createTestRequest("test-request", "jit-system", AccessPhaseActive)
+	oldCreds := newFakeCredentials("OLDKEY", time.Now().Add(5*time.Minute))
+	accessEntry := &JobAccessEntry{
+		PrincipalArn: oldCreds.PrincipalArn,
+		SessionName:  oldCreds.SessionName,
+		CredentialsSecretRef: &ObjectReference{
+			Name:      "jit-credentials-test-job",
+			Namespace: "jit-system",
+		},
+	}
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: request.Name, Namespace: request.Namespace},
+			TargetCluster:    request.Spec.TargetCluster,
+			Duration:         "4h",
+			JITRoleArn:       "arn:aws:iam::123456789012:role/JITAccess",
+			Permissions:      []string{"view"},
+		},
+		Status: JITAccessJobStatus{
+			Phase:                 JobPhaseActive,
+			StartTime:             &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			ExpiryTime:            &metav1.Time{Time: time.Now().Add(3 * time.Hour)},
+			CredentialsExpiryTime: &metav1.Time{Time: oldCreds.ExpiresAt},
+			AccessEntry:           accessEntry,
+			KubeConfigSecretRef:   &ObjectReference{Name: "jit-kubeconfig-test-job", Namespace: "jit-system"},
+		},
+	}
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-credentials-test-job", Namespace: "jit-system"},
+		Data:       credentialsSecretData(oldCreds),
+	}
+	kubeConfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-kubeconfig-test-job", Namespace: "jit-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte(oldCreds.KubeConfig)},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job, credentialsSecret, kubeConfigSecret).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	newExpiry := time.Now().Add(3 * time.Hour).Truncate(time.Second)
+	provisioner := &fakeAccessProvisioner{refreshCredentials: newFakeCredentials("NEWKEY", newExpiry)}
+	reconciler := &JITAccessJobReconciler{Client: fakeClient, Scheme: scheme, AccessManager: provisioner}
+
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: job.Name, Namespace: job.Namespace},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, provisioner.refreshRequests, 1)
+	assert.Equal(t, oldCreds.SessionName, provisioner.refreshRequests[0].SessionName)
+	assert.Zero(t, provisioner.revokeCalls, "refresh must not revoke the access entry")
+
+	updatedSecret := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(credentialsSecret), updatedSecret))
+	assert.Equal(t, "NEWKEY", string(updatedSecret.Data["aws-access-key-id"]))
+
+	updatedKubeConfig := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(kubeConfigSecret), updatedKubeConfig))
+	assert.Equal(t, "kubeconfig-NEWKEY", string(updatedKubeConfig.Data["kubeconfig"]))
+
+	updatedJob := &JITAccessJob{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(job), updatedJob))
+	assert.Equal(t, JobPhaseActive, updatedJob.Status.Phase)
+	assert.Equal(t, accessEntry.PrincipalArn, updatedJob.Status.AccessEntry.PrincipalArn)
+	assert.True(t, updatedJob.Status.CredentialsExpiryTime.Time.Equal(newExpiry))
+}
+
+func TestJITAccessJobReconciler_CredentialsNeedRefresh(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	reconciler := &JITAccessJobReconciler{Clock: &fakeClock{now: now}}
+
+	tests := []struct {
+		name              string
+		credentialsExpiry *metav1.Time
+		expiryTime        *metav1.Time
+		want              bool
+	}{
+		{
+			name:              "credentials expiring soon with session remaining",
+			credentialsExpiry: &metav1.Time{Time: now.Add(5 * time.Minute)},
+			expiryTime:        &metav1.Time{Time: now.Add(time.Hour)},
+			want:              true,
+		},
+		{
+			name:              "credentials far from expiry",
+			credentialsExpiry: &metav1.Time{Time: now.Add(time.Hour)},
+			expiryTime:        &metav1.Time{Time: now.Add(2 * time.Hour)},
+			want:              false,
+		},
+		{
+			name:              "session ends before credentials",
+			credentialsExpiry: &metav1.Time{Time: now.Add(5 * time.Minute)},
+			expiryTime:        &metav1.Time{Time: now.Add(4 * time.Minute)},
+			want:              false,
+		},
+		{
+			name: "no credential expiry recorded",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &JITAccessJob{
+				Status: JITAccessJobStatus{
+					CredentialsExpiryTime: tt.credentialsExpiry,
+					ExpiryTime:            tt.expiryTime,
+					AccessEntry:           &JobAccessEntry{SessionName: "jit-session"},
+				},
+			}
+			assert.Equal(t, tt.want, reconciler.credentialsNeedRefresh(job))
+		})
+	}
+}
+
 // Removed TestJITAccessJobReconciler_DetermineNextAction - determineNextAction method doesn't exist
 
 // Removed TestGenerateSecretName - generateSecretName function doesn't exist
+
+func TestJITAccessJobReconciler_ConvertToClusterAccessDelegation(t *testing.T) {
+	r := &JITAccessJobReconciler{}
+
+	req := createTestRequest("test-request", "default", AccessPhaseApproved)
+	access := r.convertToClusterAccess(req)
+	assert.Equal(t, req.Spec.UserID, access.UserID)
+	assert.Equal(t, req.Spec.UserEmail, access.UserEmail)
+	assert.Equal(t, req.Spec.UserID, access.RequestedBy)
+
+	req.Spec.OnBehalfOf = &Delegate{Email: "contractor@vendor.com"}
+	access = r.convertToClusterAccess(req)
+	assert.Equal(t, "contractor@vendor.com", access.UserID)
+	assert.Equal(t, "contractor@vendor.com", access.UserEmail)
+	assert.Equal(t, req.Spec.UserID, access.RequestedBy)
+
+	req.Spec.OnBehalfOf = &Delegate{UserID: "U333333333D", Email: "engineer@company.com"}
+	access = r.convertToClusterAccess(req)
+	assert.Equal(t, "U333333333D", access.UserID)
+	assert.Equal(t, "engineer@company.com", access.UserEmail)
+}
+
+func TestJITAccessJobReconciler_HandleActiveJobExpiresOnClock(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	start := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	job := createExpiredTestJob()
+	job.Status.StartTime = &metav1.Time{Time: start}
+	job.Status.ExpiryTime = &metav1.Time{Time: start.Add(time.Hour)}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	clock := &fakeClock{now: start.Add(30 * time.Minute)}
+	reconciler := &JITAccessJobReconciler{Client: fakeClient, Scheme: scheme, Clock: clock}
+
+	result, err := reconciler.handleActiveJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, result.RequeueAfter)
+	assert.Equal(t, JobPhaseActive, job.Status.Phase)
+
+	clock.now = start.Add(time.Hour + time.Second)
+	result, err = reconciler.handleActiveJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+	assert.Equal(t, JobPhaseExpiring, job.Status.Phase)
+}
+
+func TestJITAccessJobReconciler_PendingJobAcceptsWeeks(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+
+	start := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	job := createNewTestJob()
+	job.Spec.Duration = "2w3d"
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+	reconciler := &JITAccessJobReconciler{Client: fakeClient, Scheme: scheme, Clock: &fakeClock{now: start}}
+
+	_, err := reconciler.handlePendingJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, JobPhaseCreating, job.Status.Phase)
+	require.NotNil(t, job.Status.ExpiryTime)
+	assert.Equal(t, start.Add(17*24*time.Hour), job.Status.ExpiryTime.UTC())
+}
+
+func secretCounterValue(t *testing.T, name, secretType string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "type" && label.GetValue() == secretType {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestJITAccessJobReconciler_RecordsSecretMetrics(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: "missing-request", Namespace: "jit-system"},
+		},
+		Status: JITAccessJobStatus{Phase: JobPhaseExpiring},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+	reconciler := &JITAccessJobReconciler{Client: fakeClient, Scheme: scheme}
+
+	createdCredentials := secretCounterValue(t, "jit_secrets_created_total", credentialsSecretType)
+	createdKubeConfig := secretCounterValue(t, "jit_secrets_created_total", kubeConfigSecretType)
+	deletedCredentials := secretCounterValue(t, "jit_secrets_deleted_total", credentialsSecretType)
+	deletedKubeConfig := secretCounterValue(t, "jit_secrets_deleted_total", kubeConfigSecretType)
+
+	creds := newFakeCredentials("METRICSKEY", time.Now().Add(time.Hour))
+	credentialsSecret, err := reconciler.createCredentialsSecret(job, creds)
+	require.NoError(t, err)
+	kubeConfigSecret, err := reconciler.createKubeConfigSecret(job, creds.KubeConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, createdCredentials+1, secretCounterValue(t, "jit_secrets_created_total", credentialsSecretType))
+	assert.Equal(t, createdKubeConfig+1, secretCounterValue(t, "jit_secrets_created_total", kubeConfigSecretType))
+
+	// A failed create is not counted
+	_, err = reconciler.createKubeConfigSecret(job, creds.KubeConfig)
+	require.Error(t, err)
+	assert.Equal(t, createdKubeConfig+1, secretCounterValue(t, "jit_secrets_created_total", kubeConfigSecretType))
+
+	job.Status.AccessEntry = &JobAccessEntry{
+		CredentialsSecretRef: &ObjectReference{Name: credentialsSecret.Name, Namespace: credentialsSecret.Namespace},
+	}
+	job.Status.KubeConfigSecretRef = &ObjectReference{
+		Name: kubeConfigSecret.Name, Namespace: kubeConfigSecret.Namespace,
+	}
+
+	_, err = reconciler.handleExpiringJob(ctx, job)
+	require.NoError(t, err)
+
+	assert.Equal(t, deletedCredentials+1, secretCounterValue(t, "jit_secrets_deleted_total", credentialsSecretType))
+	assert.Equal(t, deletedKubeConfig+1, secretCounterValue(t, "jit_secrets_deleted_total", kubeConfigSecretType))
+}
+
+func TestJITAccessJobReconciler_RecordsGrantSummary(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	request := createTestRequest("summary-request", "jit-system", AccessPhaseActive)
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "summary-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: request.Name, Namespace: request.Namespace},
+			TargetCluster:    request.Spec.TargetCluster,
+			Duration:         "2h",
+			JITRoleArn:       "arn:aws:iam::123456789012:role/JITAccess",
+			Permissions:      []string{"edit", "cluster-admin"},
+			Namespaces:       []string{"payments", "checkout"},
+		},
+		Status: JITAccessJobStatus{Phase: JobPhaseCreating},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	creds := newFakeCredentials("SUMMARYKEY", time.Now().Add(time.Hour))
+	creds.AccessEntry = &aws.AccessEntry{
+		Groups: []string{"jit-editors"},
+		AccessPolicies: []aws.AccessPolicy{
+			{
+				PolicyArn: aws.EKSEditorPolicy,
+				AccessScope: aws.AccessScope{
+					Type: aws.AccessScopeNamespace, Namespaces: []string{"payments", "checkout"},
+				},
+			},
+			{PolicyArn: aws.EKSAdminPolicy, AccessScope: aws.AccessScope{Type: aws.AccessScopeCluster}},
+		},
+	}
+	reconciler := &JITAccessJobReconciler{
+		Client:             fakeClient,
+		Scheme:             scheme,
+		AccessManager:      &fakeAccessProvisioner{grantCredentials: creds},
+		RecordGrantSummary: true,
+	}
+
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)})
+	require.NoError(t, err)
+
+	var updated JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(job), &updated))
+	assert.Equal(t, JobPhaseActive, updated.Status.Phase)
+	assert.Equal(t, &GrantSummary{
+		AccessPolicies: []GrantedAccessPolicy{
+			{PolicyArn: aws.EKSEditorPolicy, ScopeType: "namespace", Namespaces: []string{"payments", "checkout"}},
+			{PolicyArn: aws.EKSAdminPolicy, ScopeType: "cluster"},
+		},
+		KubernetesGroups: []string{"jit-editors"},
+	}, updated.Status.GrantSummary)
+}
+
+func TestJITAccessJobReconciler_WarnsWhenDurationClamped(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	request := createTestRequest("clamped-request", "jit-system", AccessPhaseActive)
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "clamped-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: request.Name, Namespace: request.Namespace},
+			TargetCluster:    request.Spec.TargetCluster,
+			Duration:         "3d",
+			JITRoleArn:       "arn:aws:iam::123456789012:role/JITAccess",
+			Permissions:      []string{"view"},
+		},
+		Status: JITAccessJobStatus{Phase: JobPhaseCreating},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	// STS sessions are capped at 12h although three days were requested
+	expiresAt := time.Now().Add(12 * time.Hour)
+	creds := newFakeCredentials("CLAMPEDKEY", expiresAt)
+	creds.DurationClamped = true
+	reconciler := &JITAccessJobReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		AccessManager: &fakeAccessProvisioner{grantCredentials: creds},
+	}
+
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)})
+	require.NoError(t, err)
+
+	var updated JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(job), &updated))
+	assert.Equal(t, JobPhaseActive, updated.Status.Phase, "a clamped grant still becomes active")
+
+	clamped := meta.FindStatusCondition(updated.Status.Conditions, "DurationClamped")
+	require.NotNil(t, clamped)
+	assert.Equal(t, "CredentialLifetimeCapped", clamped.Reason)
+	assert.Contains(t, clamped.Message, expiresAt.UTC().Format(time.RFC3339))
+	assert.Contains(t, clamped.Message, "3d")
+	assert.Contains(t, clamped.Message, "refreshed until the session ends")
+}
+
+func TestGrantSummaryForResourceScope(t *testing.T) {
+	entry := &aws.AccessEntry{
+		Groups:       []string{"jit-scoped-u1"},
+		InlinePolicy: &aws.InlinePolicy{Name: "jit-scoped-u1", Namespaces: []string{"batch"}},
+	}
+
+	summary := grantSummary(entry)
+	assert.Equal(t, &GrantedInlinePolicy{Name: "jit-scoped-u1", Namespaces: []string{"batch"}}, summary.InlinePolicy)
+	assert.Empty(t, summary.AccessPolicies)
+	assert.Equal(t, []string{"jit-scoped-u1"}, summary.KubernetesGroups)
+
+	assert.Nil(t, grantSummary(nil))
+}
+
+// provisionHistogram returns the sample count and sum of jit_provision_duration_seconds for a cluster
+func provisionHistogram(t *testing.T, cluster, environment string) (uint64, float64) {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "jit_provision_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["cluster"] == cluster && labels["environment"] == environment {
+				return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestJITAccessJobReconciler_RecordsProvisionDuration(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	request := createTestRequest("provision-request", "jit-system", AccessPhaseApproved)
+	// Requesters can write the environment label; the metric follows the cluster registry
+	request.Labels = map[string]string{"jit.rebelops.io/environment": "staging"}
+	job := createTestJob(request.Name, request.Namespace)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	start := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	creds := newFakeCredentials("PROVISIONKEY", start.Add(time.Hour))
+	reconciler := &JITAccessJobReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Clock:         clock,
+		AccessManager: &fakeAccessProvisioner{grantCredentials: creds},
+
+		ClusterEnvironments: map[string]string{"dev-east-1": "Production"},
+	}
+	countBefore, sumBefore := provisionHistogram(t, "dev-east-1", "production")
+
+	key := client.ObjectKeyFromObject(job)
+	var updated JITAccessJob
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	require.Equal(t, JobPhaseCreating, updated.Status.Phase)
+
+	// Nothing is observed until the access is active
+	count, _ := provisionHistogram(t, "dev-east-1", "production")
+	assert.Equal(t, countBefore, count)
+
+	clock.now = start.Add(45 * time.Second)
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	require.Equal(t, JobPhaseActive, updated.Status.Phase)
+
+	count, sum := provisionHistogram(t, "dev-east-1", "production")
+	spoofed, _ := provisionHistogram(t, "dev-east-1", "staging")
+	assert.Zero(t, spoofed)
+	assert.Equal(t, countBefore+1, count)
+	assert.InDelta(t, 45.0, sum-sumBefore, 0.001)
+}
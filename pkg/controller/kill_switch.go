@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// DefaultKillSwitchName is the ConfigMap that engages the kill switch
+const DefaultKillSwitchName = "jit-kill-switch"
+
+// KillSwitchEngagedKey and KillSwitchReasonKey are the ConfigMap keys that engage the kill switch
+// and record why, e.g. kubectl create configmap jit-kill-switch --from-literal=engaged=true
+const (
+	KillSwitchEngagedKey = "engaged"
+	KillSwitchReasonKey  = "reason"
+)
+
+// killSwitchViolation is the security violation type recorded for every request the kill switch
+// revokes or denies
+const killSwitchViolation = "kill_switch"
+
+// KillSwitch names the ConfigMap security engages during a breach. While its engaged key is "true"
+// every active session is revoked and every new request is denied; deleting the ConfigMap or
+// setting the key to anything else clears it.
+type KillSwitch struct {
+	Namespace string
+	// Name is the ConfigMap name; empty uses DefaultKillSwitchName
+	Name string
+}
+
+func (k *KillSwitch) name() string {
+	if k.Name == "" {
+		return DefaultKillSwitchName
+	}
+	return k.Name
+}
+
+// Key returns the ConfigMap's namespaced name
+func (k *KillSwitch) Key() client.ObjectKey {
+	return client.ObjectKey{Namespace: k.Namespace, Name: k.name()}
+}
+
+// CacheByObject limits the manager's ConfigMap cache to the kill switch ConfigMap, so watching it
+// neither caches every ConfigMap in the cluster nor needs cluster-wide access to them
+func (k *KillSwitch) CacheByObject() map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.ConfigMap{}: {
+			Namespaces: map[string]cache.Config{k.Namespace: {}},
+			Field:      fields.OneTermEqualSelector("metadata.name", k.name()),
+		},
+	}
+}
+
+// Engaged reports whether the kill switch is engaged and the reason recorded with it. A nil kill
+// switch or a missing ConfigMap is disengaged; other read errors are returned so callers fail closed.
+func (k *KillSwitch) Engaged(ctx context.Context, reader client.Reader) (bool, string, error) {
+	if k == nil {
+		return false, "", nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := reader.Get(ctx, k.Key(), &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.SetKillSwitchEngaged(false)
+			return false, "", nil
+		}
+		return false, "", fmt.Errorf("failed to read kill switch %s: %w", k.Key(), err)
+	}
+
+	engaged := strings.EqualFold(strings.TrimSpace(configMap.Data[KillSwitchEngagedKey]), "true")
+	metrics.SetKillSwitchEngaged(engaged)
+	if !engaged {
+		return false, "", nil
+	}
+	return true, configMap.Data[KillSwitchReasonKey], nil
+}
+
+// stoppedByKillSwitch reports whether the kill switch ends requests in the phase. Requests that
+// already ended are left to their usual handling so cleanup of their access still completes.
+func stoppedByKillSwitch(phase AccessPhase) bool {
+	switch phase {
+	case "", AccessPhasePending, AccessPhaseApproved, AccessPhaseScheduled, AccessPhaseActive:
+		return true
+	}
+	return false
+}
+
+// handleKillSwitch revokes an active request, whose job then removes the access entry through the
+// expired-request handling, and denies any request that has not been provisioned yet
+func (r *JITAccessRequestReconciler) handleKillSwitch(
+	ctx context.Context, jitReq *JITAccessRequest, reason string,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	message := "JIT access kill switch is engaged"
+	if reason != "" {
+		message = fmt.Sprintf("%s: %s", message, reason)
+	}
+
+	if jitReq.Status.Phase == AccessPhaseActive {
+		jitReq.Status.Phase = AccessPhaseRevoked
+		jitReq.Status.Message = "Access revoked because the " + message
+		r.setCondition(jitReq, metav1.Condition{
+			Type:               "Revoked",
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "KillSwitchEngaged",
+			Message:            message,
+		})
+	} else {
+		jitReq.Status.Phase = AccessPhaseDenied
+		jitReq.Status.Message = "Request denied because the " + message
+	}
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	if jitReq.Status.Phase == AccessPhaseRevoked {
+		r.publishEvent(ctx, AccessEventRevoked, jitReq)
+	}
+	metrics.RecordKillSwitchAction(string(jitReq.Status.Phase))
+	metrics.RecordSecurityViolation(killSwitchViolation, jitReq.Spec.UserID, jitReq.Spec.TargetCluster.Name)
+	log.Info("AUDIT: kill switch ended JIT access request",
+		"request", jitReq.Name,
+		"namespace", jitReq.Namespace,
+		"user", jitReq.Spec.UserID,
+		"cluster", jitReq.Spec.TargetCluster.Name,
+		"phase", jitReq.Status.Phase,
+		"reason", reason)
+	return ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// killSwitchRequests enqueues every request when the kill switch ConfigMap changes, so engaging
+// it takes effect immediately rather than on each request's next periodic reconcile
+func (r *JITAccessRequestReconciler) killSwitchRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	if client.ObjectKeyFromObject(obj) != r.KillSwitch.Key() {
+		return nil
+	}
+
+	var requests JITAccessRequestList
+	if err := r.List(ctx, &requests); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list JITAccessRequests for the kill switch")
+		return nil
+	}
+
+	log.FromContext(ctx).Info("AUDIT: kill switch changed, reconciling all JIT access requests",
+		"configMap", r.KillSwitch.Key().String(), "requests", len(requests.Items))
+
+	reconcileRequests := make([]reconcile.Request, 0, len(requests.Items))
+	for _, jitReq := range requests.Items {
+		reconcileRequests = append(reconcileRequests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&jitReq),
+		})
+	}
+	return reconcileRequests
+}
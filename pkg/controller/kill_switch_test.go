@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func engagedKillSwitch() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultKillSwitchName, Namespace: "jit-system"},
+		Data: map[string]string{
+			KillSwitchEngagedKey: "true",
+			KillSwitchReasonKey:  "INC-4242 credential leak",
+		},
+	}
+}
+
+func TestKillSwitchCacheByObject(t *testing.T) {
+	byObject := (&KillSwitch{Namespace: "jit-system"}).CacheByObject()
+	require.Len(t, byObject, 1)
+
+	for obj, config := range byObject {
+		assert.IsType(t, &corev1.ConfigMap{}, obj)
+		assert.Equal(t, map[string]cache.Config{"jit-system": {}}, config.Namespaces)
+		assert.Equal(t, "metadata.name="+DefaultKillSwitchName, config.Field.String())
+	}
+}
+
+func TestKillSwitchEngaged(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+	killSwitch := &KillSwitch{Namespace: "jit-system"}
+
+	cleared := engagedKillSwitch()
+	cleared.Data[KillSwitchEngagedKey] = "false"
+
+	tests := []struct {
+		name        string
+		killSwitch  *KillSwitch
+		objects     []client.Object
+		wantEngaged bool
+		wantReason  string
+	}{
+		{name: "disabled", objects: []client.Object{engagedKillSwitch()}},
+		{name: "configmap missing", killSwitch: killSwitch},
+		{name: "cleared", killSwitch: killSwitch, objects: []client.Object{cleared}},
+		{
+			name:        "engaged",
+			killSwitch:  killSwitch,
+			objects:     []client.Object{engagedKillSwitch()},
+			wantEngaged: true,
+			wantReason:  "INC-4242 credential leak",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			engaged, reason, err := tt.killSwitch.Engaged(ctx, reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEngaged, engaged)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_KillSwitchDeniesNewRequests(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	for _, phase := range []AccessPhase{"", AccessPhasePending, AccessPhaseApproved, AccessPhaseScheduled} {
+		t.Run(string(phase), func(t *testing.T) {
+			jitReq := createTestRequest("new-request", "default", phase)
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(jitReq, engagedKillSwitch()).
+				WithStatusSubresource(&JITAccessRequest{}).
+				Build()
+			reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+			reconciler.KillSwitch = &KillSwitch{Namespace: "jit-system"}
+
+			key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			var updated JITAccessRequest
+			require.NoError(t, fakeClient.Get(ctx, key, &updated))
+			assert.Equal(t, AccessPhaseDenied, updated.Status.Phase)
+			assert.Contains(t, updated.Status.Message, "INC-4242 credential leak")
+
+			var jobs JITAccessJobList
+			require.NoError(t, fakeClient.List(ctx, &jobs, client.InNamespace("default")))
+			assert.Empty(t, jobs.Items)
+		})
+	}
+}
+
+func TestJITAccessRequestReconciler_KillSwitchRevokesActiveAccess(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	expiry := metav1.NewTime(start.Add(2 * time.Hour))
+	jitReq := createTestRequest("active-request", "default", AccessPhaseActive)
+	job := createTestJob(jitReq.Name, "default")
+	job.Name = JobName(jitReq)
+	job.Status.Phase = JobPhaseActive
+	job.Status.StartTime = &start
+	job.Status.ExpiryTime = &expiry
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(jitReq, job, engagedKillSwitch()).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, jitReq.Spec.UserID)
+	reconciler.KillSwitch = &KillSwitch{Namespace: "jit-system"}
+
+	// Engaging the switch enqueues every request
+	requests := reconciler.killSwitchRequests(ctx, engagedKillSwitch())
+	key := types.NamespacedName{Name: jitReq.Name, Namespace: "default"}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: key}}, requests)
+
+	result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseRevoked, updated.Status.Phase)
+	revoked := meta.FindStatusCondition(updated.Status.Conditions, "Revoked")
+	require.NotNil(t, revoked)
+	assert.Equal(t, "KillSwitchEngaged", revoked.Reason)
+
+	// The revoked request then drives its job to clean up the access entry
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updatedJob JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(job), &updatedJob))
+	assert.Equal(t, JobPhaseExpiring, updatedJob.Status.Phase)
+}
+
+func TestJITAccessRequestReconciler_KillSwitchIgnoresOtherConfigMaps(t *testing.T) {
+	scheme := setupTestScheme(t)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(createTestRequest("active-request", "default", AccessPhaseActive)).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, "U123456789A")
+	reconciler.KillSwitch = &KillSwitch{Namespace: "jit-system"}
+
+	other := engagedKillSwitch()
+	other.Namespace = "default"
+	assert.Empty(t, reconciler.killSwitchRequests(t.Context(), other))
+}
@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestName generates a JITAccessRequest name. A random suffix keeps names unique when the
+// same user files several requests within one second.
+func RequestName(userID string, now time.Time) string {
+	return fmt.Sprintf("jit-%s-%d-%s", userID, now.Unix(), uuid.New().String()[:8])
+}
+
+// JobName returns the name of the JITAccessJob provisioning the request. It is derived from the
+// (unique) request name so the job can be found again on later reconciles.
+func JobName(jitReq *JITAccessRequest) string {
+	return fmt.Sprintf("jit-%s-%s", jitReq.Spec.UserID, jitReq.Name)
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestNameUniqueWithinSameSecond(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+
+	first := RequestName("U123456789A", now)
+	second := RequestName("U123456789A", now)
+
+	assert.NotEqual(t, first, second)
+	assert.Regexp(t, `^jit-U123456789A-1718020800-[0-9a-f]{8}$`, first)
+}
+
+func TestJobNameIsDerivedFromRequest(t *testing.T) {
+	first := createTestRequest(RequestName("U123456789A", time.Now()), "default", AccessPhaseApproved)
+	second := createTestRequest(RequestName("U123456789A", time.Now()), "default", AccessPhaseApproved)
+
+	// The job is looked up by name on every reconcile, so it must be stable per request
+	assert.Equal(t, JobName(first), JobName(first))
+	assert.NotEqual(t, JobName(first), JobName(second))
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// approvalsChangedPredicate passes updates that add or change approvals. Approvals are
+// written to status by approvers, so they must trigger a reconcile even though they
+// don't bump the generation.
+func approvalsChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldReq, ok := e.ObjectOld.(*JITAccessRequest)
+			if !ok {
+				return false
+			}
+			newReq, ok := e.ObjectNew.(*JITAccessRequest)
+			if !ok {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldReq.Status.Approvals, newReq.Status.Approvals)
+		},
+	}
+}
+
+// deletionPredicate passes updates that mark an object for deletion
+func deletionPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	}
+}
+
+// accessRequestPredicate skips status-only updates, such as the reconciler's own phase
+// and condition writes, while still reacting to spec, annotation and approval changes.
+// The reconciler requeues explicitly after each of its own status transitions.
+func accessRequestPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+		approvalsChangedPredicate(),
+		deletionPredicate(),
+	)
+}
+
+// expiringPredicate passes updates that move a job to Expiring. The request reconciler does this
+// to a job left behind by an expired or revoked request, and the job must clean up right away.
+func expiringPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldJob, ok := e.ObjectOld.(*JITAccessJob)
+			if !ok {
+				return false
+			}
+			newJob, ok := e.ObjectNew.(*JITAccessJob)
+			if !ok {
+				return false
+			}
+			return oldJob.Status.Phase != JobPhaseExpiring && newJob.Status.Phase == JobPhaseExpiring
+		},
+	}
+}
+
+// accessJobPredicate skips status-only updates, which are the job reconciler's own writes, except
+// for the move to Expiring that starts cleanup
+func accessJobPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		expiringPredicate(),
+		deletionPredicate(),
+	)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestAccessRequestPredicate(t *testing.T) {
+	base := createTestRequest("test-request", "default", AccessPhasePending)
+	base.Generation = 1
+
+	tests := []struct {
+		name   string
+		mutate func(req *JITAccessRequest)
+		want   bool
+	}{
+		{
+			name: "status noise only",
+			mutate: func(req *JITAccessRequest) {
+				req.Status.Message = "Request pending approval"
+				req.Status.Conditions = []metav1.Condition{{Type: "Submitted", Status: metav1.ConditionTrue}}
+				req.ResourceVersion = "2"
+			},
+			want: false,
+		},
+		{
+			name: "own phase transition",
+			mutate: func(req *JITAccessRequest) {
+				req.Status.Phase = AccessPhaseApproved
+			},
+			want: false,
+		},
+		{
+			name: "approval added",
+			mutate: func(req *JITAccessRequest) {
+				req.Status.Approvals = append(req.Status.Approvals, Approval{Approver: "U111111111A"})
+			},
+			want: true,
+		},
+		{
+			name: "spec changed",
+			mutate: func(req *JITAccessRequest) {
+				req.Generation = 2
+			},
+			want: true,
+		},
+		{
+			name: "annotation changed",
+			mutate: func(req *JITAccessRequest) {
+				req.Annotations = map[string]string{RequiredApprovalsAnnotation: "1"}
+			},
+			want: true,
+		},
+		{
+			name: "marked for deletion",
+			mutate: func(req *JITAccessRequest) {
+				now := metav1.Now()
+				req.DeletionTimestamp = &now
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := base.DeepCopy()
+			tt.mutate(updated)
+
+			got := accessRequestPredicate().Update(event.UpdateEvent{ObjectOld: base, ObjectNew: updated})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAccessJobPredicate(t *testing.T) {
+	base := createTestJob("test-request", "default")
+	base.Generation = 1
+
+	statusOnly := base.DeepCopy()
+	statusOnly.Status.Phase = JobPhaseActive
+	statusOnly.Status.Conditions = []metav1.Condition{{Type: "Active", Status: metav1.ConditionTrue}}
+	assert.False(t, accessJobPredicate().Update(event.UpdateEvent{ObjectOld: base, ObjectNew: statusOnly}))
+
+	// Moving a job to Expiring is a status write that must start cleanup
+	expiring := statusOnly.DeepCopy()
+	expiring.Status.Phase = JobPhaseExpiring
+	assert.True(t, accessJobPredicate().Update(event.UpdateEvent{ObjectOld: statusOnly, ObjectNew: expiring}))
+	assert.False(t, accessJobPredicate().Update(event.UpdateEvent{ObjectOld: expiring, ObjectNew: expiring.DeepCopy()}))
+
+	specChange := base.DeepCopy()
+	specChange.Generation = 2
+	assert.True(t, accessJobPredicate().Update(event.UpdateEvent{ObjectOld: base, ObjectNew: specChange}))
+
+	assert.True(t, accessJobPredicate().Create(event.CreateEvent{Object: base}))
+}
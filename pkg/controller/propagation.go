@@ -0,0 +1,30 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// propagateMetadata copies the labels and annotations named in keys from src onto dst, so teams'
+// own metadata (e.g. team, cost-center) follows a request onto its job and secrets. Keys dst
+// already sets are left alone, which keeps the controller's own labels authoritative.
+func propagateMetadata(keys []string, src, dst metav1.Object) {
+	dst.SetLabels(copyKeys(keys, src.GetLabels(), dst.GetLabels()))
+	dst.SetAnnotations(copyKeys(keys, src.GetAnnotations(), dst.GetAnnotations()))
+}
+
+func copyKeys(keys []string, from, to map[string]string) map[string]string {
+	for _, key := range keys {
+		value, ok := from[key]
+		if !ok {
+			continue
+		}
+		if _, exists := to[key]; exists {
+			continue
+		}
+		if to == nil {
+			to = make(map[string]string)
+		}
+		to[key] = value
+	}
+	return to
+}
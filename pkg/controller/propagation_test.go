@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPropagatedMetadataFlowsToJobAndSecrets(t *testing.T) {
+	keys := []string{"team", "cost-center", "example.com/owner"}
+
+	request := createTestRequest("tagged-request", "jit-system", AccessPhaseApproved)
+	request.Labels = map[string]string{
+		"team":                    "payments",
+		"cost-center":             "cc-1234",
+		"unlisted":                "ignored",
+		"jit.rebelops.io/request": "spoofed",
+	}
+	request.Annotations = map[string]string{
+		"example.com/owner": "payments-oncall",
+		"unlisted-note":     "ignored",
+	}
+
+	reconciler := &JITAccessRequestReconciler{PropagatedMetadataKeys: append(keys, "jit.rebelops.io/request")}
+	job := reconciler.createJITAccessJob(request)
+
+	assert.Equal(t, "payments", job.Labels["team"])
+	assert.Equal(t, "cc-1234", job.Labels["cost-center"])
+	assert.NotContains(t, job.Labels, "unlisted")
+	// The controller's own labels win over propagated values
+	assert.Equal(t, request.Name, job.Labels["jit.rebelops.io/request"])
+	assert.Equal(t, map[string]string{"example.com/owner": "payments-oncall"}, job.Annotations)
+
+	scheme := setupJobTestScheme(t)
+	jobReconciler := &JITAccessJobReconciler{
+		Client:                 fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Scheme:                 scheme,
+		PropagatedMetadataKeys: keys,
+	}
+	creds := newFakeCredentials("TAGGEDKEY", time.Now().Add(time.Hour))
+
+	credentialsSecret, err := jobReconciler.createCredentialsSecret(job, creds)
+	require.NoError(t, err)
+	kubeConfigSecret, err := jobReconciler.createKubeConfigSecret(job, creds.KubeConfig)
+	require.NoError(t, err)
+
+	for _, secret := range []map[string]string{credentialsSecret.Labels, kubeConfigSecret.Labels} {
+		assert.Equal(t, "payments", secret["team"])
+		assert.Equal(t, "cc-1234", secret["cost-center"])
+		assert.NotContains(t, secret, "jit.rebelops.io/request")
+	}
+	assert.Equal(t, credentialsSecretType, credentialsSecret.Labels["jit.rebelops.io/type"])
+	assert.Equal(t, "payments-oncall", kubeConfigSecret.Annotations["example.com/owner"])
+}
+
+func TestPropagatedMetadataDisabledByDefault(t *testing.T) {
+	request := createTestRequest("tagged-request", "jit-system", AccessPhaseApproved)
+	request.Labels = map[string]string{"team": "payments"}
+	request.Annotations = map[string]string{"example.com/owner": "payments-oncall"}
+
+	job := (&JITAccessRequestReconciler{}).createJITAccessJob(request)
+
+	assert.NotContains(t, job.Labels, "team")
+	assert.Empty(t, job.Annotations)
+}
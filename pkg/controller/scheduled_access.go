@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// isScheduledForLater reports whether an approved request's NotBefore is still ahead
+func (r *JITAccessRequestReconciler) isScheduledForLater(jitReq *JITAccessRequest) bool {
+	return jitReq.Spec.NotBefore != nil && r.now().Before(jitReq.Spec.NotBefore.Time)
+}
+
+// holdUntilStart moves an approved request to Scheduled and requeues it for its start time, when
+// the access job is created. The access duration is counted from then, not from approval.
+func (r *JITAccessRequestReconciler) holdUntilStart(
+	ctx context.Context, jitReq *JITAccessRequest,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	startsIn := jitReq.Spec.NotBefore.Sub(r.now())
+	if jitReq.Status.Phase == AccessPhaseScheduled {
+		return ctrl.Result{RequeueAfter: startsIn}, nil
+	}
+
+	startsAt := jitReq.Spec.NotBefore.UTC().Format(time.RFC3339)
+	jitReq.Status.Phase = AccessPhaseScheduled
+	jitReq.Status.Message = fmt.Sprintf("Access scheduled to start at %s", startsAt)
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               "Scheduled",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "NotBeforeInFuture",
+		Message:            fmt.Sprintf("Access will be provisioned at %s", startsAt),
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Holding approved request until its start time", "request", jitReq.Name, "notBefore", startsAt)
+	return ctrl.Result{RequeueAfter: startsIn}, nil
+}
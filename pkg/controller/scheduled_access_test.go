@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestJITAccessRequestReconciler_HoldsScheduledRequestUntilStart(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	start := time.Date(2024, time.June, 11, 2, 0, 0, 0, time.UTC)
+	request := createTestRequest("maintenance-request", "default", AccessPhaseApproved)
+	request.Spec.NotBefore = &metav1.Time{Time: start}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+
+	clock := &fakeClock{now: start.Add(-3 * time.Hour)}
+	reconciler := createTestReconciler(fakeClient, scheme, request.Spec.UserID)
+	reconciler.Clock = clock
+
+	key := types.NamespacedName{Name: request.Name, Namespace: "default"}
+	result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Hour, result.RequeueAfter, "requeued for the start time")
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseScheduled, updated.Status.Phase)
+	scheduled := meta.FindStatusCondition(updated.Status.Conditions, "Scheduled")
+	require.NotNil(t, scheduled)
+	assert.Equal(t, "NotBeforeInFuture", scheduled.Reason)
+
+	jobKey := types.NamespacedName{Name: JobName(request), Namespace: "default"}
+	err = fakeClient.Get(ctx, jobKey, &JITAccessJob{})
+	assert.True(t, apierrors.IsNotFound(err), "no job is created before the start time")
+
+	// Still held an hour later
+	clock.now = start.Add(-time.Hour)
+	result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, result.RequeueAfter)
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseScheduled, updated.Status.Phase)
+
+	// Provisioned once the start time arrives
+	clock.now = start
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	require.NoError(t, fakeClient.Get(ctx, jobKey, &JITAccessJob{}))
+}
+
+func TestJITAccessRequestReconciler_PastStartTimeProvisionsImmediately(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := t.Context()
+
+	request := createTestRequest("late-request", "default", AccessPhaseApproved)
+	request.Spec.NotBefore = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request).
+		WithStatusSubresource(&JITAccessRequest{}).
+		Build()
+	reconciler := createTestReconciler(fakeClient, scheme, request.Spec.UserID)
+
+	key := types.NamespacedName{Name: request.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, fakeClient.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, "Scheduled"))
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: JobName(request), Namespace: "default"},
+		&JITAccessJob{}))
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// SessionPolicyChecker re-runs the permission and cluster policy checks of admission against an
+// active request, returning why the current policy would deny it
+type SessionPolicyChecker interface {
+	CheckActiveSession(ctx context.Context, jitReq *JITAccessRequest) error
+}
+
+// PolicyViolationCondition flags an active request the current policy would no longer admit
+const PolicyViolationCondition = "PolicyViolation"
+
+// sessionPolicyViolation is the security violation type recorded when an active session is found
+// to violate the current policy
+const sessionPolicyViolation = "active_session_policy_violation"
+
+// sessionPolicyViolation re-checks an active request against the current policy, returning nil
+// when revalidation is disabled or the session still complies
+func (r *JITAccessRequestReconciler) sessionPolicyViolation(ctx context.Context, jitReq *JITAccessRequest) error {
+	if r.SessionPolicy == nil {
+		return nil
+	}
+	return r.SessionPolicy.CheckActiveSession(ctx, jitReq)
+}
+
+// handlePolicyViolation revokes an active request the current policy would deny, or, unless
+// RevokePolicyViolations is set, flags it for revocation with a PolicyViolation condition and
+// leaves the session running. Each session is counted as a security violation once.
+func (r *JITAccessRequestReconciler) handlePolicyViolation(
+	ctx context.Context, jitReq *JITAccessRequest, violation error,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	if r.RevokePolicyViolations {
+		message := fmt.Sprintf("Access revoked because it violates current policy: %v", violation)
+		jitReq.Status.Phase = AccessPhaseRevoked
+		jitReq.Status.Message = message
+
+		r.setCondition(jitReq, metav1.Condition{
+			Type:               "Revoked",
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "PolicyViolation",
+			Message:            message,
+		})
+
+		if err := r.Status().Update(ctx, jitReq); err != nil {
+			log.Error(err, "unable to update JITAccessRequest status")
+			return ctrl.Result{}, err
+		}
+
+		metrics.RecordSecurityViolation(sessionPolicyViolation, jitReq.Spec.UserID, jitReq.Spec.TargetCluster.Name)
+		r.publishEvent(ctx, AccessEventRevoked, jitReq)
+		log.Info("AUDIT: revoked active session that violates current policy",
+			"request", jitReq.Name, "user", jitReq.Spec.UserID, "violation", violation.Error())
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// Already flagged sessions are left to expire or be revoked by hand
+	if meta.IsStatusConditionTrue(jitReq.Status.Conditions, PolicyViolationCondition) {
+		return r.syncWithJob(ctx, jitReq)
+	}
+
+	r.setCondition(jitReq, metav1.Condition{
+		Type:               PolicyViolationCondition,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "FlaggedForRevocation",
+		Message:            fmt.Sprintf("Active session violates current policy: %v", violation),
+	})
+
+	if err := r.Status().Update(ctx, jitReq); err != nil {
+		log.Error(err, "unable to update JITAccessRequest status")
+		return ctrl.Result{}, err
+	}
+
+	metrics.RecordSecurityViolation(sessionPolicyViolation, jitReq.Spec.UserID, jitReq.Spec.TargetCluster.Name)
+	log.Info("AUDIT: flagged active session that violates current policy for revocation",
+		"request", jitReq.Name, "user", jitReq.Spec.UserID, "violation", violation.Error())
+	return r.syncWithJob(ctx, jitReq)
+}
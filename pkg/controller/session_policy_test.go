@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeSessionPolicy forbids the listed permissions, as a cluster downgraded after approval would
+type fakeSessionPolicy struct {
+	forbidden map[string]bool
+	checked   []string
+}
+
+func (f *fakeSessionPolicy) CheckActiveSession(_ context.Context, jitReq *JITAccessRequest) error {
+	f.checked = append(f.checked, jitReq.Name)
+	for _, permission := range jitReq.Spec.Permissions {
+		if f.forbidden[permission] {
+			return errors.New(permission + " is not permitted on cluster " + jitReq.Spec.TargetCluster.Name)
+		}
+	}
+	return nil
+}
+
+// sessionPolicyViolations sums the security violations recorded for active sessions
+func sessionPolicyViolations(t *testing.T) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	total := 0.0
+	for _, family := range families {
+		if family.GetName() != "jit_security_violations_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "violation_type" && label.GetValue() == sessionPolicyViolation {
+					total += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return total
+}
+
+func setupSessionPolicyTest(t *testing.T, permissions []string) (*JITAccessRequestReconciler, *JITAccessRequest) {
+	t.Helper()
+	scheme := setupJobTestScheme(t)
+
+	request := createTestRequest("downgraded-cluster-request", "default", AccessPhaseActive)
+	request.Spec.Permissions = permissions
+	job := createTestJob("downgraded-cluster-request", "default")
+	job.Name = JobName(request)
+	job.Status = JITAccessJobStatus{
+		Phase:      JobPhaseActive,
+		ExpiryTime: &metav1.Time{Time: time.Now().Add(time.Hour)},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessRequest{}, &JITAccessJob{}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, scheme, request.Spec.UserID)
+	reconciler.SessionPolicy = &fakeSessionPolicy{forbidden: map[string]bool{"cluster-admin": true}}
+	return reconciler, request
+}
+
+func TestJITAccessRequestReconciler_FlagsSessionViolatingCurrentPolicy(t *testing.T) {
+	ctx := t.Context()
+	reconciler, request := setupSessionPolicyTest(t, []string{"cluster-admin"})
+	before := sessionPolicyViolations(t)
+
+	key := types.NamespacedName{Name: request.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, reconciler.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	flagged := meta.FindStatusCondition(updated.Status.Conditions, PolicyViolationCondition)
+	require.NotNil(t, flagged)
+	assert.Equal(t, metav1.ConditionTrue, flagged.Status)
+	assert.Equal(t, "FlaggedForRevocation", flagged.Reason)
+	assert.Contains(t, flagged.Message, "cluster-admin is not permitted")
+	assert.Equal(t, 1.0, sessionPolicyViolations(t)-before)
+
+	// A flagged session is counted once, however often it is revalidated
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, sessionPolicyViolations(t)-before)
+	assert.Equal(t, []string{request.Name, request.Name},
+		reconciler.SessionPolicy.(*fakeSessionPolicy).checked)
+}
+
+func TestJITAccessRequestReconciler_RevokesSessionViolatingCurrentPolicy(t *testing.T) {
+	ctx := t.Context()
+	reconciler, request := setupSessionPolicyTest(t, []string{"cluster-admin"})
+	reconciler.RevokePolicyViolations = true
+	before := sessionPolicyViolations(t)
+
+	key := types.NamespacedName{Name: request.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, reconciler.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseRevoked, updated.Status.Phase)
+	revoked := meta.FindStatusCondition(updated.Status.Conditions, "Revoked")
+	require.NotNil(t, revoked)
+	assert.Equal(t, "PolicyViolation", revoked.Reason)
+	assert.Equal(t, 1.0, sessionPolicyViolations(t)-before)
+
+	// The revoked request then has its job clean up the session
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var job JITAccessJob
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: JobName(request), Namespace: "default"}, &job))
+	assert.Equal(t, JobPhaseExpiring, job.Status.Phase)
+}
+
+func TestJITAccessRequestReconciler_KeepsSessionCompliantWithCurrentPolicy(t *testing.T) {
+	ctx := t.Context()
+	reconciler, request := setupSessionPolicyTest(t, []string{"view"})
+	reconciler.RevokePolicyViolations = true
+
+	key := types.NamespacedName{Name: request.Name, Namespace: "default"}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var updated JITAccessRequest
+	require.NoError(t, reconciler.Get(ctx, key, &updated))
+	assert.Equal(t, AccessPhaseActive, updated.Status.Phase)
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, PolicyViolationCondition))
+}
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rebelopsio/jit-bot/pkg/kubernetes"
+)
+
+// StepDown narrows a broad permission once the first part of a session has passed, e.g.
+// cluster-admin for 30 minutes and view for the rest
+type StepDown struct {
+	// After is how long after the job starts the permission is narrowed, e.g. "30m"
+	After string `json:"after"`
+	// Permissions replace the stepped-down permission for the rest of the session
+	Permissions []string `json:"permissions"`
+}
+
+// StepDownSchedule maps a permission to the step-down applied to grants that include it
+type StepDownSchedule map[string]StepDown
+
+// LoadStepDownSchedule reads a JSON step-down schedule, for example
+// {"cluster-admin": {"after": "30m", "permissions": ["view"]}}
+func LoadStepDownSchedule(path string) (StepDownSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read step-down schedule: %w", err)
+	}
+
+	var schedule StepDownSchedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to parse step-down schedule: %w", err)
+	}
+
+	for permission, stepDown := range schedule {
+		after, err := ParseDuration(stepDown.After)
+		if err != nil {
+			return nil, fmt.Errorf("step-down for %s: invalid after: %w", permission, err)
+		}
+		if after <= 0 {
+			return nil, fmt.Errorf("step-down for %s: after must be positive", permission)
+		}
+		if len(stepDown.Permissions) == 0 {
+			return nil, fmt.Errorf("step-down for %s: at least one permission is required", permission)
+		}
+		if slices.Contains(stepDown.Permissions, permission) {
+			return nil, fmt.Errorf("step-down for %s: cannot step down to itself", permission)
+		}
+	}
+
+	return schedule, nil
+}
+
+// plan returns when the permissions step down and what they are narrowed to. When several
+// permissions have a step-down, all of them are narrowed at the earliest boundary.
+func (s StepDownSchedule) plan(permissions []string) (time.Duration, []string, bool) {
+	var after time.Duration
+	var narrowed []string
+	found := false
+
+	for _, permission := range permissions {
+		stepDown, ok := s[permission]
+		if !ok {
+			narrowed = appendUnique(narrowed, permission)
+			continue
+		}
+		// Validated by LoadStepDownSchedule
+		stepAfter, _ := ParseDuration(stepDown.After)
+		if !found || stepAfter < after {
+			after = stepAfter
+		}
+		found = true
+		for _, reduced := range stepDown.Permissions {
+			narrowed = appendUnique(narrowed, reduced)
+		}
+	}
+
+	return after, narrowed, found
+}
+
+// planSpecs narrows permission specs the way plan narrows permissions: a stepped-down spec's
+// namespaces and resources carry over to each level it steps down to. A level the request already
+// holds keeps its own terms, so stepping down never changes what an unaffected level covers.
+func (s StepDownSchedule) planSpecs(permissions []string, specs []PermissionSpec) []PermissionSpec {
+	held := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		if _, ok := s[permission]; !ok {
+			held[permission] = true
+		}
+	}
+
+	var narrowed []PermissionSpec
+	for _, spec := range specs {
+		stepDown, ok := s[spec.Level]
+		if !ok {
+			narrowed = append(narrowed, spec)
+			continue
+		}
+		for _, reduced := range stepDown.Permissions {
+			if held[reduced] {
+				continue
+			}
+			held[reduced] = true
+			narrowed = append(narrowed, PermissionSpec{
+				Level:      reduced,
+				Namespaces: spec.Namespaces,
+				Resources:  spec.Resources,
+			})
+		}
+	}
+	return narrowed
+}
+
+func appendUnique(list []string, value string) []string {
+	if slices.Contains(list, value) {
+		return list
+	}
+	return append(list, value)
+}
+
+// stepDownDue reports whether an active job has reached its step-down boundary and still holds
+// its original permissions
+func (r *JITAccessJobReconciler) stepDownDue(job *JITAccessJob) bool {
+	if job.Status.StepDown != nil || job.Status.StartTime == nil || job.Status.AccessEntry == nil {
+		return false
+	}
+
+	after, _, ok := r.StepDowns.plan(job.Spec.Permissions)
+	if !ok {
+		return false
+	}
+
+	boundary := job.Status.StartTime.Add(after)
+	// Nothing to narrow if the session ends first
+	if job.Status.ExpiryTime != nil && !boundary.Before(job.Status.ExpiryTime.Time) {
+		return false
+	}
+	return !r.now().Before(boundary)
+}
+
+// stepDownAccess deletes the job's access entry and recreates it with the narrowed permissions for
+// the rest of the session, rewriting the credential secrets in place. If the narrowed grant fails
+// the job expires early rather than leave the session without the access entry it reports.
+func (r *JITAccessJobReconciler) stepDownAccess(ctx context.Context, job *JITAccessJob) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var accessReq JITAccessRequest
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      job.Spec.AccessRequestRef.Name,
+		Namespace: job.Spec.AccessRequestRef.Namespace,
+	}, &accessReq); err != nil {
+		log.Error(err, "unable to fetch JITAccessRequest for step-down")
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	_, permissions, _ := r.StepDowns.plan(job.Spec.Permissions)
+	clusterAccess := r.convertToClusterAccess(&accessReq)
+	clusterAccess.SessionName = job.Status.AccessEntry.SessionName
+	cluster := r.convertToCluster(&job.Spec.TargetCluster)
+
+	if err := r.AccessManager.RevokeAccess(ctx, clusterAccess, cluster, job.Spec.JITRoleArn); err != nil {
+		log.Error(err, "failed to delete access entry for step-down")
+		r.setJobCondition(job, metav1.Condition{
+			Type:               "SteppedDown",
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "RevokeFailed",
+			Message:            fmt.Sprintf("Failed to remove the access entry: %v", err),
+		})
+		if updateErr := r.Status().Update(ctx, job); updateErr != nil {
+			log.Error(updateErr, "unable to update JITAccessJob status")
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	remaining := maxSTSSessionDuration
+	if job.Status.ExpiryTime != nil {
+		remaining = job.Status.ExpiryTime.Sub(r.now())
+	}
+	clusterAccess.Permissions = permissions
+	clusterAccess.Duration = min(max(remaining, minSTSSessionDuration), maxSTSSessionDuration)
+
+	credentials, err := r.AccessManager.GrantAccess(ctx, kubernetes.GrantAccessRequest{
+		ClusterAccess: clusterAccess,
+		Cluster:       cluster,
+		UserEmail:     clusterAccess.UserEmail,
+		Permissions:   permissions,
+		PermissionSpecs: convertPermissionSpecs(
+			r.StepDowns.planSpecs(job.Spec.Permissions, accessReq.Spec.PermissionSpecs)),
+		Namespaces:    job.Spec.Namespaces,
+		JITRoleArn:    job.Spec.JITRoleArn,
+		ResourceScope: convertResourceScope(accessReq.Spec.ResourceScope),
+	})
+	if err != nil {
+		log.Error(err, "failed to recreate access entry for step-down")
+		job.Status.Phase = JobPhaseExpiring
+		r.setJobCondition(job, metav1.Condition{
+			Type:               "SteppedDown",
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(r.now()),
+			Reason:             "RegrantFailed",
+			Message:            fmt.Sprintf("Access ended early because the narrowed grant failed: %v", err),
+		})
+		if updateErr := r.Status().Update(ctx, job); updateErr != nil {
+			log.Error(updateErr, "unable to update JITAccessJob status")
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	if ref := job.Status.AccessEntry.CredentialsSecretRef; ref != nil {
+		if err = r.updateSecretData(ctx, ref, credentialsSecretData(credentials)); err != nil {
+			log.Error(err, "failed to update credentials secret")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if ref := job.Status.KubeConfigSecretRef; ref != nil {
+		if err = r.updateSecretData(ctx, ref, map[string][]byte{
+			"kubeconfig": []byte(credentials.KubeConfig),
+		}); err != nil {
+			log.Error(err, "failed to update kubeconfig secret")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if credentials.SessionName != "" {
+		job.Status.AccessEntry.SessionName = credentials.SessionName
+	}
+	if credentials.PrincipalArn != "" {
+		job.Status.AccessEntry.PrincipalArn = credentials.PrincipalArn
+	}
+	credentialsExpiry := metav1.NewTime(credentials.ExpiresAt)
+	job.Status.CredentialsExpiryTime = &credentialsExpiry
+	if r.RecordGrantSummary {
+		job.Status.GrantSummary = grantSummary(credentials.AccessEntry)
+	}
+	job.Status.StepDown = &JobStepDown{
+		SteppedDownAt: metav1.NewTime(r.now()),
+		Permissions:   permissions,
+	}
+
+	r.setJobCondition(job, metav1.Condition{
+		Type:               "SteppedDown",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(r.now()),
+		Reason:             "PermissionsNarrowed",
+		Message: fmt.Sprintf("Access narrowed from %s to %s for the rest of the session",
+			strings.Join(job.Spec.Permissions, ","), strings.Join(permissions, ",")),
+	})
+
+	if err = r.Status().Update(ctx, job); err != nil {
+		log.Error(err, "unable to update JITAccessJob status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("AUDIT: stepped down JIT access",
+		"job", job.Name,
+		"user", accessReq.Spec.UserID,
+		"cluster", job.Spec.TargetCluster.Name,
+		"from", job.Spec.Permissions,
+		"to", permissions)
+	return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+}
+
+// grantedPermissions returns the permissions the job's access entry currently grants: the narrowed
+// ones once the job has stepped down
+func grantedPermissions(job *JITAccessJob) []string {
+	if job.Status.StepDown != nil {
+		return job.Status.StepDown.Permissions
+	}
+	return job.Spec.Permissions
+}
+
+// untilStepDown returns how long until an active job's step-down is due, so the job is
+// reconciled at the boundary rather than on its next periodic check
+func (r *JITAccessJobReconciler) untilStepDown(job *JITAccessJob) (time.Duration, bool) {
+	if job.Status.StepDown != nil || job.Status.StartTime == nil {
+		return 0, false
+	}
+	after, _, ok := r.StepDowns.plan(job.Spec.Permissions)
+	if !ok {
+		return 0, false
+	}
+	wait := job.Status.StartTime.Add(after).Sub(r.now())
+	return wait, wait > 0
+}
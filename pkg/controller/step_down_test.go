@@ -0,0 +1,302 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+)
+
+func TestLoadStepDownSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid schedule",
+			content: `{"cluster-admin": {"after": "30m", "permissions": ["view"]}}`,
+		},
+		{
+			name:    "invalid after",
+			content: `{"cluster-admin": {"after": "soon", "permissions": ["view"]}}`,
+			wantErr: "invalid after",
+		},
+		{
+			name:    "no permissions",
+			content: `{"cluster-admin": {"after": "30m"}}`,
+			wantErr: "at least one permission",
+		},
+		{
+			name:    "steps down to itself",
+			content: `{"edit": {"after": "30m", "permissions": ["edit"]}}`,
+			wantErr: "cannot step down to itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "step-down.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			schedule, err := LoadStepDownSchedule(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"view"}, schedule["cluster-admin"].Permissions)
+		})
+	}
+}
+
+func TestStepDownSchedulePlan(t *testing.T) {
+	schedule := StepDownSchedule{
+		"cluster-admin": {After: "30m", Permissions: []string{"view"}},
+		"admin":         {After: "1h", Permissions: []string{"edit", "view"}},
+	}
+
+	after, permissions, ok := schedule.plan([]string{"view", "cluster-admin", "admin"})
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Minute, after)
+	assert.Equal(t, []string{"view", "edit"}, permissions)
+
+	_, _, ok = schedule.plan([]string{"edit"})
+	assert.False(t, ok)
+}
+
+func TestStepDownSchedulePlanSpecs(t *testing.T) {
+	schedule := StepDownSchedule{
+		"admin": {After: "30m", Permissions: []string{"edit", "view"}},
+	}
+
+	specs := schedule.planSpecs([]string{"view", "admin", "logs"}, []PermissionSpec{
+		{Level: "admin", Namespaces: []string{"payments"}, Resources: []string{"pods"}},
+		{Level: "logs", Namespaces: []string{"batch"}},
+	})
+
+	// view is already held on the request's own terms, so only edit inherits the admin spec
+	assert.Equal(t, []PermissionSpec{
+		{Level: "edit", Namespaces: []string{"payments"}, Resources: []string{"pods"}},
+		{Level: "logs", Namespaces: []string{"batch"}},
+	}, specs)
+}
+
+func TestJITAccessJobReconciler_StepDownKeepsPermissionSpecs(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	start := time.Now().Truncate(time.Second)
+	request := createTestRequest("step-down-spec-request", "jit-system", AccessPhaseActive)
+	request.Spec.Permissions = []string{"admin"}
+	request.Spec.PermissionSpecs = []PermissionSpec{{Level: "admin", Namespaces: []string{"payments"}}}
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "step-down-spec-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: request.Name, Namespace: request.Namespace},
+			TargetCluster:    request.Spec.TargetCluster,
+			Duration:         "2h",
+			JITRoleArn:       "arn:aws:iam::123456789012:role/JITAccess",
+			Permissions:      []string{"admin"},
+		},
+		Status: JITAccessJobStatus{
+			Phase:      JobPhaseActive,
+			StartTime:  &metav1.Time{Time: start},
+			ExpiryTime: &metav1.Time{Time: start.Add(2 * time.Hour)},
+			AccessEntry: &JobAccessEntry{
+				SessionName: "jit-session",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	provisioner := &fakeAccessProvisioner{grantCredentials: newFakeCredentials("EDITKEY", start.Add(2*time.Hour))}
+	reconciler := &JITAccessJobReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		AccessManager: provisioner,
+		Clock:         &fakeClock{now: start.Add(time.Hour)},
+		StepDowns: StepDownSchedule{
+			"admin": {After: "30m", Permissions: []string{"edit"}},
+		},
+	}
+
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)})
+	require.NoError(t, err)
+
+	require.Len(t, provisioner.grantRequests, 1)
+	assert.Equal(t, []string{"edit"}, provisioner.grantRequests[0].Permissions)
+	assert.Equal(t, []aws.PermissionSpec{{Level: "edit", Namespaces: []string{"payments"}}},
+		provisioner.grantRequests[0].PermissionSpecs)
+}
+
+func TestJITAccessJobReconciler_StepsDownAtBoundary(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	start := time.Now().Truncate(time.Second)
+	request := createTestRequest("step-down-request", "jit-system", AccessPhaseActive)
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "step-down-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: request.Name, Namespace: request.Namespace},
+			TargetCluster:    request.Spec.TargetCluster,
+			Duration:         "2h",
+			JITRoleArn:       "arn:aws:iam::123456789012:role/JITAccess",
+			Permissions:      []string{"cluster-admin"},
+		},
+		Status: JITAccessJobStatus{
+			Phase:      JobPhaseCreating,
+			StartTime:  &metav1.Time{Time: start},
+			ExpiryTime: &metav1.Time{Time: start.Add(2 * time.Hour)},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	adminCreds := newFakeCredentials("ADMINKEY", start.Add(time.Hour))
+	adminCreds.AccessEntry = &aws.AccessEntry{AccessPolicies: []aws.AccessPolicy{
+		{PolicyArn: aws.EKSAdminPolicy, AccessScope: aws.AccessScope{Type: aws.AccessScopeCluster}},
+	}}
+	provisioner := &fakeAccessProvisioner{grantCredentials: adminCreds}
+	clock := &fakeClock{now: start}
+	reconciler := &JITAccessJobReconciler{
+		Client:             fakeClient,
+		Scheme:             scheme,
+		AccessManager:      provisioner,
+		Clock:              clock,
+		RecordGrantSummary: true,
+		StepDowns: StepDownSchedule{
+			"cluster-admin": {After: "30m", Permissions: []string{"view"}},
+		},
+	}
+	key := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)}
+	policies := func() []string {
+		var updated JITAccessJob
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(job), &updated))
+		var arns []string
+		for _, policy := range updated.Status.GrantSummary.AccessPolicies {
+			arns = append(arns, policy.PolicyArn)
+		}
+		return arns
+	}
+
+	_, err := reconciler.Reconcile(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{aws.EKSAdminPolicy}, policies())
+
+	// Just before the boundary the job wakes up for it without stepping down
+	clock.now = start.Add(29 * time.Minute)
+	result, err := reconciler.Reconcile(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	assert.Zero(t, provisioner.revokeCalls)
+	assert.Equal(t, []string{aws.EKSAdminPolicy}, policies())
+
+	viewCreds := newFakeCredentials("VIEWKEY", start.Add(2*time.Hour))
+	viewCreds.AccessEntry = &aws.AccessEntry{AccessPolicies: []aws.AccessPolicy{
+		{PolicyArn: aws.EKSViewerPolicy, AccessScope: aws.AccessScope{Type: aws.AccessScopeCluster}},
+	}}
+	provisioner.grantCredentials = viewCreds
+	clock.now = start.Add(30 * time.Minute)
+	_, err = reconciler.Reconcile(ctx, key)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, provisioner.revokeCalls, "the admin access entry is deleted")
+	require.Len(t, provisioner.grantRequests, 2)
+	assert.Equal(t, []string{"view"}, provisioner.grantRequests[1].Permissions)
+	assert.Equal(t, adminCreds.SessionName, provisioner.grantRequests[1].ClusterAccess.SessionName)
+	assert.Equal(t, []string{aws.EKSViewerPolicy}, policies())
+
+	var updated JITAccessJob
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(job), &updated))
+	assert.Equal(t, JobPhaseActive, updated.Status.Phase)
+	require.NotNil(t, updated.Status.StepDown)
+	assert.Equal(t, []string{"view"}, updated.Status.StepDown.Permissions)
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, "SteppedDown"))
+
+	var credentialsSecret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{
+		Name: "jit-credentials-step-down-job", Namespace: "jit-system",
+	}, &credentialsSecret))
+	assert.Equal(t, "VIEWKEY", string(credentialsSecret.Data["aws-access-key-id"]))
+
+	// Stepping down happens once
+	_, err = reconciler.Reconcile(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, provisioner.revokeCalls)
+}
+
+func TestJITAccessJobReconciler_RefreshKeepsSteppedDownPermissions(t *testing.T) {
+	scheme := setupJobTestScheme(t)
+	ctx := t.Context()
+
+	now := time.Now().Truncate(time.Second)
+	request := createTestRequest("step-down-request", "jit-system", AccessPhaseActive)
+	oldCreds := newFakeCredentials("VIEWKEY", now.Add(5*time.Minute))
+	accessEntry := &JobAccessEntry{PrincipalArn: oldCreds.PrincipalArn, SessionName: oldCreds.SessionName}
+	job := &JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "step-down-job", Namespace: "jit-system"},
+		Spec: JITAccessJobSpec{
+			AccessRequestRef: ObjectReference{Name: request.Name, Namespace: request.Namespace},
+			TargetCluster:    request.Spec.TargetCluster,
+			Duration:         "4h",
+			JITRoleArn:       "arn:aws:iam::123456789012:role/JITAccess",
+			Permissions:      []string{"cluster-admin"},
+		},
+		Status: JITAccessJobStatus{
+			Phase:                 JobPhaseActive,
+			StartTime:             &metav1.Time{Time: now.Add(-time.Hour)},
+			ExpiryTime:            &metav1.Time{Time: now.Add(3 * time.Hour)},
+			CredentialsExpiryTime: &metav1.Time{Time: oldCreds.ExpiresAt},
+			AccessEntry:           accessEntry,
+			StepDown: &JobStepDown{
+				SteppedDownAt: metav1.Time{Time: now.Add(-30 * time.Minute)},
+				Permissions:   []string{"view"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job).
+		WithStatusSubresource(&JITAccessJob{}).
+		Build()
+
+	provisioner := &fakeAccessProvisioner{refreshCredentials: newFakeCredentials("NEWKEY", now.Add(3*time.Hour))}
+	reconciler := &JITAccessJobReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		AccessManager: provisioner,
+		Clock:         &fakeClock{now: now},
+		StepDowns: StepDownSchedule{
+			"cluster-admin": {After: "30m", Permissions: []string{"view"}},
+		},
+	}
+
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)})
+	require.NoError(t, err)
+
+	require.Len(t, provisioner.refreshRequests, 1)
+	assert.Equal(t, []string{"view"}, provisioner.refreshRequests[0].Permissions)
+	assert.Equal(t, []string{"view"}, provisioner.refreshRequests[0].ClusterAccess.Permissions)
+	assert.Zero(t, provisioner.revokeCalls, "a stepped-down job is not stepped down again")
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// trustedOperatorApproval is the security violation type recorded for every request
+// auto-approved because its requester is a trusted operator
+const trustedOperatorApproval = "trusted_operator_approval"
+
+// RequesterVerifiedAnnotation is set by the admission webhooks when a requester service account,
+// such as the Slack bot's, filed the request and so vouches for its spec.userID. Requesters write
+// spec.userID themselves, so it identifies a trusted operator only on verified requests.
+const RequesterVerifiedAnnotation = "jit.rebelops.io/requester-verified"
+
+// isTrustedOperator reports whether the request was filed by a configured trusted operator for
+// themselves. Unlike break-glass, this applies to any permission and needs no emergency annotation;
+// delegated requests are never trusted so operators cannot approve access for others, and neither
+// are requests whose spec.userID no requester service account vouched for.
+func (r *JITAccessRequestReconciler) isTrustedOperator(jitReq *JITAccessRequest) bool {
+	if jitReq.Spec.OnBehalfOf != nil || jitReq.Annotations[RequesterVerifiedAnnotation] != "true" {
+		return false
+	}
+	for _, user := range r.TrustedUsers {
+		if user == jitReq.Spec.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// auditTrustedOperatorApproval records a trusted operator's auto-approval as a security event
+func (r *JITAccessRequestReconciler) auditTrustedOperatorApproval(ctx context.Context, jitReq *JITAccessRequest) {
+	metrics.RecordSecurityViolation(trustedOperatorApproval, jitReq.Spec.UserID, jitReq.Spec.TargetCluster.Name)
+	log.FromContext(ctx).Info("AUDIT: request auto-approved for trusted operator without approvals",
+		"user", jitReq.Spec.UserID,
+		"cluster", jitReq.Spec.TargetCluster.Name,
+		"permissions", jitReq.Spec.Permissions,
+		"duration", jitReq.Spec.Duration,
+		"reason", jitReq.Spec.Reason,
+		"approvers", jitReq.Spec.Approvers)
+}
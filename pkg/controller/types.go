@@ -32,32 +32,64 @@ type JITAccessRequestSpec struct {
 	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	UserEmail string `json:"userEmail"`
 
+	// UserEmailVerified is set by the identity flow that filed the request once it has verified
+	// UserEmail, e.g. from the identity provider's email_verified claim. The mutating webhook clears
+	// it unless a requester service account set it.
+	// +kubebuilder:validation:Optional
+	UserEmailVerified bool `json:"userEmailVerified,omitempty"`
+
+	// OnBehalfOf is the effective grantee when the requester files on behalf of someone else
+	// +kubebuilder:validation:Optional
+	OnBehalfOf *Delegate `json:"onBehalfOf,omitempty"`
+
 	// TargetCluster specifies the EKS cluster to access
 	// +kubebuilder:validation:Required
 	TargetCluster TargetCluster `json:"targetCluster"`
 
-	// Reason is the business justification for access
+	// ClusterGroup names a configured group of related clusters to access together. A job is
+	// created for every member; TargetCluster must be a member and carries the approval policy.
+	// +kubebuilder:validation:Optional
+	ClusterGroup string `json:"clusterGroup,omitempty"`
+
+	// Reason is the business justification for access. The webhook applies the per-environment
+	// length limit; the schema only bounds the longest limit that may be configured.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=10
-	// +kubebuilder:validation:MaxLength=500
+	// +kubebuilder:validation:MaxLength=4000
 	Reason string `json:"reason"`
 
 	// Duration is the requested access duration
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Pattern=`^(\d+[dhms])+$`
+	// +kubebuilder:validation:Pattern=`^(\d+[wdhms])+$`
 	Duration string `json:"duration"`
 
-	// Permissions are the requested permission levels
+	// Permissions are the requested permission levels. The valid set is configurable at runtime
+	// and enforced by the validating webhook.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
-	// +kubebuilder:validation:Enum=view;edit;admin;cluster-admin;debug;logs;exec;port-forward
 	Permissions []string `json:"permissions"`
 
+	// PermissionSpecs scope individual permission levels to their own namespaces or resources. Every
+	// level is also listed in Permissions, which the mutating webhook completes; levels without a
+	// spec keep the request-wide Namespaces.
+	// +kubebuilder:validation:Optional
+	PermissionSpecs []PermissionSpec `json:"permissionSpecs,omitempty"`
+
 	// Namespaces are the target namespaces (empty = cluster-wide)
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
 	Namespaces []string `json:"namespaces,omitempty"`
 
+	// NamespacePrefix requests every namespace whose name starts with the prefix (e.g. "team-a-*").
+	// The mutating webhook expands it into Namespaces; the team must be allowed the prefix.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][-a-z0-9]*\*?$`
+	NamespacePrefix string `json:"namespacePrefix,omitempty"`
+
+	// ResourceScope restricts access to specific API groups, resources and verbs
+	// +kubebuilder:validation:Optional
+	ResourceScope *ResourceScope `json:"resourceScope,omitempty"`
+
 	// Approvers are the required approvers for this request
 	// +kubebuilder:validation:Optional
 	Approvers []string `json:"approvers,omitempty"`
@@ -67,11 +99,77 @@ type JITAccessRequestSpec struct {
 	// +kubebuilder:validation:Pattern=`^C[A-Z0-9]{10}$`
 	SlackChannel string `json:"slackChannel,omitempty"`
 
+	// CostCenter is applied as a cost-allocation tag to the granted AWS session
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=256
+	CostCenter string `json:"costCenter,omitempty"`
+
+	// Team is applied as a cost-allocation tag to the granted AWS session
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=256
+	Team string `json:"team,omitempty"`
+
+	// NotBefore delays provisioning until this time, e.g. the start of a maintenance window.
+	// An approved request is held in the Scheduled phase until then.
+	// +kubebuilder:validation:Optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
 	// RequestedAt is when the request was created
 	// +kubebuilder:validation:Required
 	RequestedAt metav1.Time `json:"requestedAt"`
 }
 
+type Delegate struct {
+	// UserID is the grantee's Slack user ID, if they have one
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^U[A-Z0-9]{10}$`
+	UserID string `json:"userID,omitempty"`
+
+	// Email is the grantee's email address
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
+	Email string `json:"email"`
+}
+
+// PermissionSpec is a structured permission: a level from the permission set, optionally limited
+// to its own namespaces and to specific Kubernetes resources
+type PermissionSpec struct {
+	// Level is the permission level, e.g. edit
+	// +kubebuilder:validation:Required
+	Level string `json:"level"`
+
+	// Namespaces limit the permission to specific namespaces (empty = request namespaces)
+	// +kubebuilder:validation:Optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Resources limit the permission to specific core API group resources, granted with the level's
+	// verbs through an inline policy instead of the level's managed access policy. Use
+	// ResourceScope for other API groups.
+	// +kubebuilder:validation:Optional
+	Resources []string `json:"resources,omitempty"`
+}
+
+type ResourceScope struct {
+	// APIGroups are the Kubernetes API groups to grant access to (empty = core group)
+	// +kubebuilder:validation:Optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Resources are the Kubernetes resources to grant access to
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Resources []string `json:"resources"`
+
+	// Verbs are the allowed operations on the resources
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Enum=get;list;watch;create;update;patch;delete;deletecollection
+	Verbs []string `json:"verbs"`
+
+	// Namespaces limit the scope to specific namespaces (empty = request namespaces)
+	// +kubebuilder:validation:Optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
 type TargetCluster struct {
 	// Name is the EKS cluster name
 	// +kubebuilder:validation:Required
@@ -110,17 +208,37 @@ type JITAccessRequestStatus struct {
 
 	// Message is a human readable message about current status
 	Message string `json:"message,omitempty"`
+
+	// ProvisioningAttempts counts failed attempts to create the access job
+	ProvisioningAttempts int32 `json:"provisioningAttempts,omitempty"`
+
+	// ClusterJobs tracks the job provisioning each member of the request's cluster group
+	ClusterJobs []ClusterJobStatus `json:"clusterJobs,omitempty"`
+}
+
+// ClusterJobStatus is the last observed state of one member cluster's job in a group request
+type ClusterJobStatus struct {
+	// Cluster is the member cluster name
+	Cluster string `json:"cluster"`
+
+	// JobName is the JITAccessJob provisioning the cluster
+	JobName string `json:"jobName"`
+
+	// Phase is the job's phase when the request last synced
+	Phase JobPhase `json:"phase,omitempty"`
 }
 
 type AccessPhase string
 
 const (
-	AccessPhasePending  AccessPhase = "Pending"
-	AccessPhaseApproved AccessPhase = "Approved"
-	AccessPhaseDenied   AccessPhase = "Denied"
-	AccessPhaseActive   AccessPhase = "Active"
-	AccessPhaseExpired  AccessPhase = "Expired"
-	AccessPhaseRevoked  AccessPhase = "Revoked"
+	AccessPhasePending   AccessPhase = "Pending"
+	AccessPhaseApproved  AccessPhase = "Approved"
+	AccessPhaseScheduled AccessPhase = "Scheduled"
+	AccessPhaseDenied    AccessPhase = "Denied"
+	AccessPhaseActive    AccessPhase = "Active"
+	AccessPhaseExpired   AccessPhase = "Expired"
+	AccessPhaseRevoked   AccessPhase = "Revoked"
+	AccessPhaseFailed    AccessPhase = "Failed"
 )
 
 type Approval struct {
@@ -130,7 +248,7 @@ type Approval struct {
 	// ApprovedAt is when the approval was given
 	ApprovedAt metav1.Time `json:"approvedAt"`
 
-	// Comment is an optional approval comment
+	// Comment justifies the approval; required for approvals of admin and cluster-admin requests
 	Comment string `json:"comment,omitempty"`
 }
 
@@ -219,6 +337,15 @@ type JITAccessJobStatus struct {
 	// KubeConfigSecretRef references the generated kubeconfig secret
 	KubeConfigSecretRef *ObjectReference `json:"kubeConfigSecretRef,omitempty"`
 
+	// CredentialsExpiryTime is when the current STS credentials expire
+	CredentialsExpiryTime *metav1.Time `json:"credentialsExpiryTime,omitempty"`
+
+	// GrantSummary records what AWS was told to grant when access was provisioned
+	GrantSummary *GrantSummary `json:"grantSummary,omitempty"`
+
+	// StepDown records when the access entry was recreated with narrowed permissions
+	StepDown *JobStepDown `json:"stepDown,omitempty"`
+
 	// Conditions represent the current condition of the job
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -234,6 +361,48 @@ const (
 	JobPhaseFailed    JobPhase = "Failed"
 )
 
+// JobStepDown is the narrowing applied partway through a session by the step-down schedule
+type JobStepDown struct {
+	// SteppedDownAt is when the access entry was recreated
+	SteppedDownAt metav1.Time `json:"steppedDownAt"`
+
+	// Permissions are the narrowed permissions granted for the rest of the session
+	Permissions []string `json:"permissions"`
+}
+
+// GrantSummary is a machine-readable record of the EKS access entry created for a job
+type GrantSummary struct {
+	// AccessPolicies are the AWS-managed access policies associated with the access entry
+	AccessPolicies []GrantedAccessPolicy `json:"accessPolicies,omitempty"`
+
+	// InlinePolicy is the policy generated from a resource scope, used instead of access policies
+	InlinePolicy *GrantedInlinePolicy `json:"inlinePolicy,omitempty"`
+
+	// KubernetesGroups are the Kubernetes groups set on the access entry
+	KubernetesGroups []string `json:"kubernetesGroups,omitempty"`
+}
+
+// GrantedInlinePolicy is the custom policy bound to the access entry through its Kubernetes group
+type GrantedInlinePolicy struct {
+	// Name is the generated policy name, which is also its Kubernetes group
+	Name string `json:"name"`
+
+	// Namespaces the policy is limited to; empty means cluster-wide
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// GrantedAccessPolicy is one access policy association and the scope it was granted with
+type GrantedAccessPolicy struct {
+	// PolicyArn is the EKS cluster access policy
+	PolicyArn string `json:"policyArn"`
+
+	// ScopeType is cluster or namespace
+	ScopeType string `json:"scopeType"`
+
+	// Namespaces the policy is limited to when ScopeType is namespace
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
 type JobAccessEntry struct {
 	// PrincipalArn is the ARN of the principal
 	PrincipalArn string `json:"principalArn"`
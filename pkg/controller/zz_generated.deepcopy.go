@@ -58,6 +58,93 @@ func (in *Approval) DeepCopy() *Approval {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Delegate) DeepCopyInto(out *Delegate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Delegate.
+func (in *Delegate) DeepCopy() *Delegate {
+	if in == nil {
+		return nil
+	}
+	out := new(Delegate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantSummary) DeepCopyInto(out *GrantSummary) {
+	*out = *in
+	if in.AccessPolicies != nil {
+		in, out := &in.AccessPolicies, &out.AccessPolicies
+		*out = make([]GrantedAccessPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InlinePolicy != nil {
+		in, out := &in.InlinePolicy, &out.InlinePolicy
+		*out = new(GrantedInlinePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubernetesGroups != nil {
+		in, out := &in.KubernetesGroups, &out.KubernetesGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantSummary.
+func (in *GrantSummary) DeepCopy() *GrantSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantedAccessPolicy) DeepCopyInto(out *GrantedAccessPolicy) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantedAccessPolicy.
+func (in *GrantedAccessPolicy) DeepCopy() *GrantedAccessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantedAccessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantedInlinePolicy) DeepCopyInto(out *GrantedInlinePolicy) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantedInlinePolicy.
+func (in *GrantedInlinePolicy) DeepCopy() *GrantedInlinePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantedInlinePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JITAccessJob) DeepCopyInto(out *JITAccessJob) {
 	*out = *in
@@ -153,6 +240,20 @@ func (in *JITAccessJobStatus) DeepCopyInto(out *JITAccessJobStatus) {
 		*out = new(ObjectReference)
 		**out = **in
 	}
+	if in.CredentialsExpiryTime != nil {
+		in, out := &in.CredentialsExpiryTime, &out.CredentialsExpiryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.GrantSummary != nil {
+		in, out := &in.GrantSummary, &out.GrantSummary
+		*out = new(GrantSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StepDown != nil {
+		in, out := &in.StepDown, &out.StepDown
+		*out = new(JobStepDown)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -218,22 +319,43 @@ func (in *JITAccessRequestList) DeepCopy() *JITAccessRequestList {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JITAccessRequestSpec) DeepCopyInto(out *JITAccessRequestSpec) {
 	*out = *in
+	if in.OnBehalfOf != nil {
+		in, out := &in.OnBehalfOf, &out.OnBehalfOf
+		*out = new(Delegate)
+		**out = **in
+	}
 	out.TargetCluster = in.TargetCluster
 	if in.Permissions != nil {
 		in, out := &in.Permissions, &out.Permissions
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PermissionSpecs != nil {
+		in, out := &in.PermissionSpecs, &out.PermissionSpecs
+		*out = make([]PermissionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Namespaces != nil {
 		in, out := &in.Namespaces, &out.Namespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResourceScope != nil {
+		in, out := &in.ResourceScope, &out.ResourceScope
+		*out = new(ResourceScope)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Approvers != nil {
 		in, out := &in.Approvers, &out.Approvers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
 	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
 }
 
@@ -269,6 +391,11 @@ func (in *JITAccessRequestStatus) DeepCopyInto(out *JITAccessRequestStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ClusterJobs != nil {
+		in, out := &in.ClusterJobs, &out.ClusterJobs
+		*out = make([]ClusterJobStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JITAccessRequestStatus.
@@ -301,6 +428,27 @@ func (in *JobAccessEntry) DeepCopy() *JobAccessEntry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobStepDown) DeepCopyInto(out *JobStepDown) {
+	*out = *in
+	in.SteppedDownAt.DeepCopyInto(&out.SteppedDownAt)
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStepDown.
+func (in *JobStepDown) DeepCopy() *JobStepDown {
+	if in == nil {
+		return nil
+	}
+	out := new(JobStepDown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -316,6 +464,66 @@ func (in *ObjectReference) DeepCopy() *ObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PermissionSpec) DeepCopyInto(out *PermissionSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PermissionSpec.
+func (in *PermissionSpec) DeepCopy() *PermissionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PermissionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceScope) DeepCopyInto(out *ResourceScope) {
+	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceScope.
+func (in *ResourceScope) DeepCopy() *ResourceScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetCluster) DeepCopyInto(out *TargetCluster) {
 	*out = *in
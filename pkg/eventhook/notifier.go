@@ -0,0 +1,212 @@
+// Package eventhook posts JIT access lifecycle events to a generic outbound webhook so teams can
+// trigger their own automation
+package eventhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body, as "sha256=<hex>", keyed with the
+// shared secret. EventHeader carries the event type.
+const (
+	SignatureHeader = "X-JIT-Signature"
+	EventHeader     = "X-JIT-Event"
+)
+
+// SecretEnv is the environment variable the operator reads the signing secret from
+const SecretEnv = "JIT_EVENT_WEBHOOK_SECRET"
+
+// Delivery defaults used when Config leaves them unset
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+	DefaultQueueSize   = 256
+)
+
+// ErrQueueFull is returned instead of blocking when events arrive faster than they are delivered
+var ErrQueueFull = errors.New("event webhook queue is full; event dropped")
+
+// Config configures the outbound webhook
+type Config struct {
+	// URL receives a POST with the JSON Event for every lifecycle event
+	URL string
+	// Secret signs every payload; receivers verify SignatureHeader with it
+	Secret string
+	// MaxAttempts bounds delivery attempts per event; zero uses DefaultMaxAttempts
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each further retry; zero uses DefaultBackoff
+	Backoff time.Duration
+	// QueueSize is how many events may wait for delivery; zero uses DefaultQueueSize
+	QueueSize int
+}
+
+// Event is the JSON payload posted for a lifecycle event
+type Event struct {
+	Type        controller.AccessEventType `json:"type"`
+	Time        time.Time                  `json:"time"`
+	Request     string                     `json:"request"`
+	Namespace   string                     `json:"namespace"`
+	UserID      string                     `json:"userId"`
+	Cluster     string                     `json:"cluster"`
+	Permissions []string                   `json:"permissions"`
+	Namespaces  []string                   `json:"namespaces,omitempty"`
+	Duration    string                     `json:"duration"`
+	Phase       controller.AccessPhase     `json:"phase"`
+	Message     string                     `json:"message,omitempty"`
+	ExpiresAt   *time.Time                 `json:"expiresAt,omitempty"`
+}
+
+// Notifier implements controller.EventNotifier by queueing events and posting them from a
+// background worker, retrying failed deliveries with exponential backoff. It is a manager
+// Runnable: nothing is delivered until Start is running.
+type Notifier struct {
+	config     Config
+	httpClient *http.Client
+	queue      chan Event
+	now        func() time.Time
+}
+
+// NewNotifier validates the config and creates a notifier
+func NewNotifier(config Config) (*Notifier, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid event webhook URL %q: must be an http or https URL", config.URL)
+	}
+	if config.Secret == "" {
+		return nil, fmt.Errorf("event webhook secret is required to sign payloads (set %s)", SecretEnv)
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultBackoff
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+
+	return &Notifier{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan Event, config.QueueSize),
+		now:        time.Now,
+	}, nil
+}
+
+// NotifyEvent queues the event for delivery without waiting for it
+func (n *Notifier) NotifyEvent(
+	_ context.Context, eventType controller.AccessEventType, jitReq *controller.JITAccessRequest,
+) error {
+	event := Event{
+		Type:        eventType,
+		Time:        n.now().UTC(),
+		Request:     jitReq.Name,
+		Namespace:   jitReq.Namespace,
+		UserID:      jitReq.Spec.UserID,
+		Cluster:     jitReq.Spec.TargetCluster.Name,
+		Permissions: jitReq.Spec.Permissions,
+		Namespaces:  jitReq.Spec.Namespaces,
+		Duration:    jitReq.Spec.Duration,
+		Phase:       jitReq.Status.Phase,
+		Message:     jitReq.Status.Message,
+	}
+	if jitReq.Status.AccessEntry != nil {
+		expiresAt := jitReq.Status.AccessEntry.ExpiresAt.UTC()
+		event.ExpiresAt = &expiresAt
+	}
+
+	select {
+	case n.queue <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Start delivers queued events until ctx is cancelled
+func (n *Notifier) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-n.queue:
+			if err := n.deliver(ctx, event); err != nil {
+				log.FromContext(ctx).Error(err, "unable to deliver access event",
+					"event", event.Type, "request", event.Request)
+			}
+		}
+	}
+}
+
+// deliver posts the event, retrying network errors, rate limiting and server errors with
+// exponential backoff until MaxAttempts is reached
+func (n *Notifier) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	backoff := n.config.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, event.Type, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.config.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one delivery attempt and reports whether a failure is worth retrying
+func (n *Notifier) post(ctx context.Context, eventType controller.AccessEventType, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(eventType))
+	req.Header.Set(SignatureHeader, Sign([]byte(n.config.Secret), body))
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("event webhook returned %s", resp.Status)
+}
+
+// Sign returns the SignatureHeader value for body
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
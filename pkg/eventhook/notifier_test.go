@@ -0,0 +1,156 @@
+package eventhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+type delivery struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+func grantedRequest() *controller.JITAccessRequest {
+	return &controller.JITAccessRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-U123456789A-1718020800", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:        "U123456789A",
+			TargetCluster: controller.TargetCluster{Name: "prod-east-1"},
+			Permissions:   []string{"edit"},
+			Namespaces:    []string{"checkout"},
+			Duration:      "1h",
+		},
+		Status: controller.JITAccessRequestStatus{
+			Phase: controller.AccessPhaseActive,
+			AccessEntry: &controller.AccessEntryStatus{
+				ExpiresAt: metav1.NewTime(time.Date(2024, time.June, 10, 13, 0, 0, 0, time.UTC)),
+			},
+		},
+	}
+}
+
+func startNotifier(t *testing.T, config Config) *Notifier {
+	t.Helper()
+
+	notifier, err := NewNotifier(config)
+	require.NoError(t, err)
+	notifier.now = func() time.Time { return time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC) }
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = notifier.Start(t.Context())
+	}()
+	t.Cleanup(func() { <-done })
+	return notifier
+}
+
+func TestNotifierPostsSignedGrant(t *testing.T) {
+	deliveries := make(chan delivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{event: r.Header.Get(EventHeader), signature: r.Header.Get(SignatureHeader), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := startNotifier(t, Config{URL: server.URL, Secret: "s3cret"})
+	require.NoError(t, notifier.NotifyEvent(t.Context(), controller.AccessEventGranted, grantedRequest()))
+
+	var got delivery
+	select {
+	case got = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+
+	assert.Equal(t, "granted", got.event)
+	assert.Equal(t, Sign([]byte("s3cret"), got.body), got.signature)
+	assert.NotEqual(t, Sign([]byte("other"), got.body), got.signature)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(got.body, &event))
+	expiresAt := time.Date(2024, time.June, 10, 13, 0, 0, 0, time.UTC)
+	assert.Equal(t, Event{
+		Type:        controller.AccessEventGranted,
+		Time:        time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC),
+		Request:     "jit-U123456789A-1718020800",
+		Namespace:   "jit-system",
+		UserID:      "U123456789A",
+		Cluster:     "prod-east-1",
+		Permissions: []string{"edit"},
+		Namespaces:  []string{"checkout"},
+		Duration:    "1h",
+		Phase:       controller.AccessPhaseActive,
+		ExpiresAt:   &expiresAt,
+	}, event)
+}
+
+func TestNotifierRetriesWithBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	delivered := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		close(delivered)
+	}))
+	defer server.Close()
+
+	notifier := startNotifier(t, Config{URL: server.URL, Secret: "s3cret", Backoff: time.Millisecond})
+	require.NoError(t, notifier.NotifyEvent(t.Context(), controller.AccessEventRevoked, grantedRequest()))
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not redelivered")
+	}
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestNotifierDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(Config{URL: server.URL, Secret: "s3cret", Backoff: time.Millisecond})
+	require.NoError(t, err)
+
+	err = notifier.deliver(t.Context(), Event{Type: controller.AccessEventDenied})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestNotifyEventDoesNotBlock(t *testing.T) {
+	// Nothing drains the queue because the notifier was never started
+	notifier, err := NewNotifier(Config{URL: "https://hooks.example.com/jit", Secret: "s3cret", QueueSize: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.NotifyEvent(t.Context(), controller.AccessEventApproved, grantedRequest()))
+	assert.ErrorIs(t, notifier.NotifyEvent(t.Context(), controller.AccessEventGranted, grantedRequest()), ErrQueueFull)
+}
+
+func TestNewNotifierValidatesConfig(t *testing.T) {
+	_, err := NewNotifier(Config{URL: "hooks.example.com/jit", Secret: "s3cret"})
+	assert.Error(t, err)
+
+	_, err = NewNotifier(Config{URL: "https://hooks.example.com/jit"})
+	assert.ErrorContains(t, err, SecretEnv)
+}
@@ -2,6 +2,8 @@ package kubernetes
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -12,6 +14,8 @@ import (
 	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
 
 	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/clock"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
 	"github.com/rebelopsio/jit-bot/pkg/models"
 )
 
@@ -19,16 +23,38 @@ type AccessManager struct {
 	stsService *aws.STSService
 	eksService *aws.EKSService
 	region     string
+
+	// allowedRegions refuses clusters outside the operator's regions; nil allows every region
+	allowedRegions aws.AllowedRegions
+
+	preGrantHooks  []PreGrantHook
+	postGrantHooks []PostGrantHook
+
+	// clusterClient connects to target clusters to apply inline policies; nil uses the operator's
+	// own AWS identity
+	clusterClient clusterClientFunc
+
+	// Clock names sessions for grants without a request time; nil uses the system clock
+	Clock clock.Clock
+}
+
+func (am *AccessManager) now() time.Time {
+	if am.Clock == nil {
+		return time.Now()
+	}
+	return am.Clock.Now()
 }
 
 type GrantAccessRequest struct {
-	ClusterAccess *models.ClusterAccess
-	Cluster       *models.Cluster
-	UserEmail     string
-	Permissions   []string
-	Namespaces    []string
-	JITRoleArn    string
-	AssumeRoleArn string
+	ClusterAccess   *models.ClusterAccess
+	Cluster         *models.Cluster
+	UserEmail       string
+	Permissions     []string
+	PermissionSpecs []aws.PermissionSpec
+	Namespaces      []string
+	JITRoleArn      string
+	AssumeRoleArn   string
+	ResourceScope   *aws.ResourceScope
 }
 
 type AccessCredentials struct {
@@ -36,6 +62,30 @@ type AccessCredentials struct {
 	KubeConfig           string
 	ClusterEndpoint      string
 	ExpiresAt            time.Time
+	SessionName          string
+	PrincipalArn         string
+	// AccessEntry is the EKS access entry created for a grant; nil for refreshed credentials
+	AccessEntry *aws.AccessEntry
+	// DurationClamped is set when the requested duration exceeds the 12h STS session limit, so the
+	// credentials expire before the session ends and must be refreshed
+	DurationClamped bool
+}
+
+// DurationClamped reports whether the requested duration is longer than the STS session limit,
+// so the session is issued capped credentials instead. STS rejects longer sessions outright rather
+// than shortening them.
+func DurationClamped(requested time.Duration) bool {
+	return requested > aws.MaxSessionDuration
+}
+
+// RefreshCredentialsRequest describes an active session whose STS credentials should be re-issued
+type RefreshCredentialsRequest struct {
+	ClusterAccess *models.ClusterAccess
+	Cluster       *models.Cluster
+	Permissions   []string
+	JITRoleArn    string
+	SessionName   string
+	Duration      time.Duration
 }
 
 func NewAccessManager(region string) (*AccessManager, error) {
@@ -56,23 +106,47 @@ func NewAccessManager(region string) (*AccessManager, error) {
 	}, nil
 }
 
+// SetAllowedRegions refuses grants on clusters outside regions. It fails if the access manager's
+// own region is outside them.
+func (am *AccessManager) SetAllowedRegions(regions aws.AllowedRegions) error {
+	if err := regions.Check(am.region); err != nil {
+		return err
+	}
+	am.allowedRegions = regions
+	return nil
+}
+
+// SetPermissionPolicies sets the permissions grants map onto access policies; nil uses
+// aws.DefaultPermissionPolicies
+func (am *AccessManager) SetPermissionPolicies(policies aws.PermissionPolicies) {
+	am.eksService.SetPermissionPolicies(policies)
+}
+
 func (am *AccessManager) GrantAccess(ctx context.Context, req GrantAccessRequest) (*AccessCredentials, error) {
+	// A misconfigured cluster must not point the operator at an unexpected region
+	if err := am.allowedRegions.Check(req.Cluster.Region); err != nil {
+		return nil, fmt.Errorf("access denied for cluster %s: %w", req.Cluster.Name, err)
+	}
+
+	if err := am.runPreGrantHooks(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Step 1: Create temporary IAM role session
-	sessionName := aws.GenerateJITSessionName(req.ClusterAccess.UserID, req.Cluster.ID)
-	policy := aws.CreateJITPolicy(req.Cluster.Name, "", req.Permissions)
+	sessionName := sessionNameFor(req.ClusterAccess, req.Cluster, am.now())
+	policy, err := aws.CreateJITPolicy(req.Cluster.Name, "", req.Permissions)
+	if err != nil {
+		return nil, err
+	}
 
-	// Assume the JIT role with limited permissions
+	// Assume the JIT role with limited permissions. STS rejects sessions over 12h, so longer grants
+	// start with 12h credentials that the job controller refreshes until the session ends.
 	creds, err := am.stsService.AssumeRole(ctx, aws.AssumeRoleInput{
 		RoleArn:         req.JITRoleArn,
 		SessionName:     sessionName,
-		DurationSeconds: int32(req.ClusterAccess.Duration.Seconds()),
+		DurationSeconds: stsDurationSeconds(req.ClusterAccess.Duration),
 		Policy:          policy,
-		Tags: []ststypes.Tag{
-			{Key: awssdk.String("Purpose"), Value: awssdk.String("JITAccess")},
-			{Key: awssdk.String("UserID"), Value: awssdk.String(req.ClusterAccess.UserID)},
-			{Key: awssdk.String("ClusterID"), Value: awssdk.String(req.Cluster.ID)},
-			{Key: awssdk.String("RequestID"), Value: awssdk.String(req.ClusterAccess.ID)},
-		},
+		Tags:            sessionTags(req.ClusterAccess, req.Cluster),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to assume JIT role: %w", err)
@@ -84,14 +158,20 @@ func (am *AccessManager) GrantAccess(ctx context.Context, req GrantAccessRequest
 		extractRoleName(req.JITRoleArn),
 		sessionName)
 
-	username := fmt.Sprintf("jit:%s", req.ClusterAccess.UserID)
+	username, err := RenderUsername(req.Cluster.UsernameTemplate, req.ClusterAccess.UserID, req.ClusterAccess.UserEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes username: %w", err)
+	}
 
-	err = am.eksService.CreateJITAccessEntry(ctx,
+	accessEntry, err := am.eksService.CreateJITAccessEntry(ctx,
 		req.Cluster.Name,
 		principalArn,
 		username,
 		req.Permissions,
-		req.Namespaces)
+		req.PermissionSpecs,
+		req.Namespaces,
+		req.ResourceScope,
+		aws.CostAllocationTags(req.ClusterAccess.CostCenter, req.ClusterAccess.Team))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create EKS access entry: %w", err)
 	}
@@ -102,27 +182,181 @@ func (am *AccessManager) GrantAccess(ctx context.Context, req GrantAccessRequest
 		return nil, fmt.Errorf("failed to describe cluster: %w", err)
 	}
 
+	// Scoped grants only take effect once their group is bound to the inline policy's rules
+	if accessEntry.InlinePolicy != nil {
+		if err := am.grantInlinePolicy(ctx, cluster, accessEntry.InlinePolicy); err != nil {
+			if deleteErr := am.eksService.DeleteAccessEntry(ctx, req.Cluster.Name, principalArn); deleteErr != nil {
+				slog.Error("Failed to remove access entry after inline policy failure",
+					"cluster", req.Cluster.Name, "principalArn", principalArn, "error", deleteErr)
+			}
+			return nil, err
+		}
+	}
+
 	// Step 4: Generate kubeconfig
 	kubeConfig := am.generateKubeConfig(cluster, creds, req.Cluster.Region)
 
-	return &AccessCredentials{
+	accessCreds := &AccessCredentials{
 		TemporaryCredentials: creds,
 		KubeConfig:           kubeConfig,
 		ClusterEndpoint:      awssdk.ToString(cluster.Endpoint),
 		ExpiresAt:            creds.Expiration,
-	}, nil
+		SessionName:          sessionName,
+		PrincipalArn:         principalArn,
+		AccessEntry:          accessEntry,
+	}
+
+	if DurationClamped(req.ClusterAccess.Duration) {
+		accessCreds.DurationClamped = true
+		metrics.RecordDurationClamped(req.Cluster.Name)
+		slog.Warn("AWS credentials expire before the requested duration",
+			"user", req.ClusterAccess.UserID, "cluster", req.Cluster.Name,
+			"requested", req.ClusterAccess.Duration, "expiresAt", creds.Expiration)
+	}
+
+	auditGrant(slog.Default(), req, accessCreds)
+	am.runPostGrantHooks(ctx, req, accessCreds)
+
+	return accessCreds, nil
+}
+
+// KubeConfigFingerprint identifies a generated kubeconfig by its SHA-256 digest, so audit logs can
+// tie a session to the exact config handed out without recording the credentials it embeds
+func KubeConfigFingerprint(kubeConfig string) string {
+	sum := sha256.Sum256([]byte(kubeConfig))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// auditGrant records who was granted access to which cluster and endpoint, with a fingerprint of
+// the kubeconfig in place of its contents
+func auditGrant(logger *slog.Logger, req GrantAccessRequest, creds *AccessCredentials) {
+	logger.Info("AUDIT: granted JIT access",
+		"user", req.ClusterAccess.UserID,
+		"cluster", req.Cluster.Name,
+		"endpoint", creds.ClusterEndpoint,
+		"sessionName", creds.SessionName,
+		"principalArn", creds.PrincipalArn,
+		"expiresAt", creds.ExpiresAt,
+		"kubeConfigFingerprint", KubeConfigFingerprint(creds.KubeConfig))
+}
+
+// RefreshCredentials re-assumes the JIT role using the original session name so the
+// assumed-role principal, and therefore the EKS access entry, stays the same.
+func (am *AccessManager) RefreshCredentials(
+	ctx context.Context, req RefreshCredentialsRequest,
+) (*AccessCredentials, error) {
+	if req.SessionName == "" {
+		return nil, fmt.Errorf("session name is required to refresh credentials")
+	}
+
+	policy, err := aws.CreateJITPolicy(req.Cluster.Name, "", req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := am.stsService.AssumeRole(ctx, aws.AssumeRoleInput{
+		RoleArn:         req.JITRoleArn,
+		SessionName:     req.SessionName,
+		DurationSeconds: stsDurationSeconds(req.Duration),
+		Policy:          policy,
+		Tags:            sessionTags(req.ClusterAccess, req.Cluster),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-assume JIT role: %w", err)
+	}
+
+	cluster, err := am.eksService.DescribeCluster(ctx, req.Cluster.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+
+	accessCreds := &AccessCredentials{
+		TemporaryCredentials: creds,
+		KubeConfig:           am.generateKubeConfig(cluster, creds, req.Cluster.Region),
+		ClusterEndpoint:      awssdk.ToString(cluster.Endpoint),
+		ExpiresAt:            creds.Expiration,
+		SessionName:          req.SessionName,
+		PrincipalArn: fmt.Sprintf("arn:aws:sts::%s:assumed-role/%s/%s",
+			req.Cluster.AWSAccount,
+			extractRoleName(req.JITRoleArn),
+			req.SessionName),
+	}
+
+	slog.Info("Refreshed JIT access credentials",
+		"user", req.ClusterAccess.UserID,
+		"cluster", req.Cluster.Name,
+		"sessionName", accessCreds.SessionName,
+		"principalArn", accessCreds.PrincipalArn,
+		"expiresAt", accessCreds.ExpiresAt)
+
+	return accessCreds, nil
+}
+
+// stsDurationSeconds bounds a requested session duration to what AssumeRole accepts
+func stsDurationSeconds(requested time.Duration) int32 {
+	return int32(min(max(requested, aws.MinSessionDuration), aws.MaxSessionDuration).Seconds())
+}
+
+// sessionTags tags the assumed-role session with the grantee and, for delegated
+// requests, the user who requested access on their behalf
+func sessionTags(access *models.ClusterAccess, cluster *models.Cluster) []ststypes.Tag {
+	tags := []ststypes.Tag{
+		{Key: awssdk.String("Purpose"), Value: awssdk.String("JITAccess")},
+		{Key: awssdk.String("UserID"), Value: awssdk.String(access.UserID)},
+		{Key: awssdk.String("ClusterID"), Value: awssdk.String(cluster.ID)},
+		{Key: awssdk.String("RequestID"), Value: awssdk.String(access.ID)},
+	}
+
+	if access.RequestedBy != "" && access.RequestedBy != access.UserID {
+		tags = append(tags,
+			ststypes.Tag{Key: awssdk.String("RequestedBy"), Value: awssdk.String(access.RequestedBy)},
+			ststypes.Tag{Key: awssdk.String("OnBehalfOf"), Value: awssdk.String(access.UserEmail)},
+		)
+	}
+
+	if access.CostCenter != "" {
+		tags = append(tags,
+			ststypes.Tag{Key: awssdk.String(aws.TagCostCenter), Value: awssdk.String(access.CostCenter)})
+	}
+	if access.Team != "" {
+		tags = append(tags,
+			ststypes.Tag{Key: awssdk.String(aws.TagTeam), Value: awssdk.String(access.Team)})
+	}
+
+	return tags
+}
+
+// sessionNameFor returns the recorded session name, or derives it from the request time so
+// the name is reproducible at revoke time. Accesses without a request time are named after now.
+func sessionNameFor(access *models.ClusterAccess, cluster *models.Cluster, now time.Time) string {
+	if access.SessionName != "" {
+		return access.SessionName
+	}
+	if access.RequestedAt.IsZero() {
+		return aws.JITSessionName(access.UserID, cluster.ID, now)
+	}
+	return aws.JITSessionName(access.UserID, cluster.ID, access.RequestedAt)
 }
 
 func (am *AccessManager) RevokeAccess(
 	ctx context.Context, clusterAccess *models.ClusterAccess, cluster *models.Cluster, jitRoleArn string,
 ) error {
 	// Calculate the principal ARN that was created during access grant
-	sessionName := aws.GenerateJITSessionName(clusterAccess.UserID, cluster.ID)
+	sessionName := sessionNameFor(clusterAccess, cluster, am.now())
 	principalArn := fmt.Sprintf("arn:aws:sts::%s:assumed-role/%s/%s",
 		cluster.AWSAccount,
 		extractRoleName(jitRoleArn),
 		sessionName)
 
+	// Remove the RBAC objects of a scoped grant before the entry that records its group
+	if entry, err := am.eksService.DescribeAccessEntry(ctx, cluster.Name, principalArn); err == nil {
+		if group := inlinePolicyGroup(entry.Groups); group != "" {
+			if err := am.revokeInlinePolicy(ctx, cluster.Name, group); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Remove EKS access entry
 	err := am.eksService.DeleteAccessEntry(ctx, cluster.Name, principalArn)
 	if err != nil {
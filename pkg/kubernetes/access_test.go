@@ -0,0 +1,148 @@
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+func TestSessionTagsIncludeCostAllocation(t *testing.T) {
+	cluster := &models.Cluster{ID: "prod-east-1"}
+
+	access := &models.ClusterAccess{ID: "req-1", UserID: "U1", CostCenter: "CC-1234", Team: "payments"}
+	tags := make(map[string]string)
+	for _, tag := range sessionTags(access, cluster) {
+		tags[awssdk.ToString(tag.Key)] = awssdk.ToString(tag.Value)
+	}
+
+	if tags[aws.TagCostCenter] != "CC-1234" {
+		t.Errorf("Expected CostCenter tag CC-1234, got %q", tags[aws.TagCostCenter])
+	}
+	if tags[aws.TagTeam] != "payments" {
+		t.Errorf("Expected Team tag payments, got %q", tags[aws.TagTeam])
+	}
+
+	for _, tag := range sessionTags(&models.ClusterAccess{ID: "req-2", UserID: "U1"}, cluster) {
+		if key := awssdk.ToString(tag.Key); key == aws.TagCostCenter || key == aws.TagTeam {
+			t.Errorf("Expected no %s tag when unset", key)
+		}
+	}
+}
+
+func TestAccessManagerEnforcesAllowedRegions(t *testing.T) {
+	allowed := aws.NewAllowedRegions([]string{"us-east-1"})
+
+	outside, err := NewAccessManager("eu-west-1")
+	if err != nil {
+		t.Fatalf("Failed to create access manager: %v", err)
+	}
+	if err := outside.SetAllowedRegions(allowed); err == nil {
+		t.Error("Expected an access manager outside the allowed regions to be refused")
+	}
+
+	am, err := NewAccessManager("us-east-1")
+	if err != nil {
+		t.Fatalf("Failed to create access manager: %v", err)
+	}
+	if err := am.SetAllowedRegions(allowed); err != nil {
+		t.Fatalf("Expected an access manager in an allowed region to be accepted: %v", err)
+	}
+
+	_, err = am.GrantAccess(context.Background(), GrantAccessRequest{
+		ClusterAccess: &models.ClusterAccess{ID: "access-1", UserID: "U0REQUESTER"},
+		Cluster:       &models.Cluster{Name: "rogue", Region: "eu-west-1", AWSAccount: "123456789012"},
+		Permissions:   []string{"view"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Expected grant on a disallowed region to be denied, got %v", err)
+	}
+}
+
+func TestGrantAccessCapsSTSSessionDuration(t *testing.T) {
+	var durations []string
+	am := newFakeAWSAccessManager(t, func(r *http.Request) {
+		if r.Method == http.MethodPost && !strings.HasPrefix(r.URL.Path, "/clusters/") {
+			if err := r.ParseForm(); err == nil && r.PostForm.Get("Action") == "AssumeRole" {
+				durations = append(durations, r.PostForm.Get("DurationSeconds"))
+			}
+		}
+	})
+
+	req := hookTestGrantRequest()
+	req.ClusterAccess.Duration = 3 * 24 * time.Hour
+	creds, err := am.GrantAccess(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected a 3-day grant to succeed, got %v", err)
+	}
+	if !creds.DurationClamped {
+		t.Error("Expected the 3-day grant to be reported as clamped")
+	}
+
+	// STS rejects sessions over 12h; the job controller refreshes the credentials for the rest
+	if len(durations) != 1 || durations[0] != "43200" {
+		t.Errorf("Expected one AssumeRole call for 43200 seconds, got %v", durations)
+	}
+
+	req.ClusterAccess.Duration = 4 * time.Hour
+	if creds, err = am.GrantAccess(context.Background(), req); err != nil {
+		t.Fatalf("Expected a 4-hour grant to succeed, got %v", err)
+	}
+	if creds.DurationClamped {
+		t.Error("Expected a grant within the STS limit not to be clamped")
+	}
+}
+
+func TestGrantAccessNamesSessionWithClock(t *testing.T) {
+	var sessionNames []string
+	am := newFakeAWSAccessManager(t, func(r *http.Request) {
+		if r.Method == http.MethodPost && !strings.HasPrefix(r.URL.Path, "/clusters/") {
+			if err := r.ParseForm(); err == nil && r.PostForm.Get("Action") == "AssumeRole" {
+				sessionNames = append(sessionNames, r.PostForm.Get("RoleSessionName"))
+			}
+		}
+	})
+	grantedAt := time.Date(2024, time.June, 10, 14, 30, 22, 0, time.UTC)
+	am.Clock = &fakeClock{now: grantedAt}
+
+	// A grant without a request time takes its session name from the manager's clock
+	req := hookTestGrantRequest()
+	creds, err := am.GrantAccess(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GrantAccess failed: %v", err)
+	}
+
+	expected := aws.JITSessionName(req.ClusterAccess.UserID, req.Cluster.ID, grantedAt)
+	if creds.SessionName != expected {
+		t.Errorf("Expected session name %s, got %s", expected, creds.SessionName)
+	}
+	if len(sessionNames) != 1 || sessionNames[0] != expected {
+		t.Errorf("Expected one AssumeRole call for session %s, got %v", expected, sessionNames)
+	}
+}
+
+func TestDurationClamped(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested time.Duration
+		want      bool
+	}{
+		{name: "within the STS limit", requested: 4 * time.Hour, want: false},
+		{name: "at the STS limit", requested: aws.MaxSessionDuration, want: false},
+		{name: "multi-day session", requested: 3 * 24 * time.Hour, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DurationClamped(tt.requested); got != tt.want {
+				t.Errorf("DurationClamped(%s) = %v, want %v", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
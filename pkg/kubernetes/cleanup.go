@@ -2,32 +2,61 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/rebelopsio/jit-bot/pkg/clock"
 	"github.com/rebelopsio/jit-bot/pkg/models"
 	"github.com/rebelopsio/jit-bot/pkg/store"
 )
 
+// DefaultCleanupConcurrency is the number of clusters cleaned up in parallel when none is configured
+const DefaultCleanupConcurrency = 4
+
 type CleanupService struct {
 	accessManager *AccessManager
 	store         *store.MemoryStore
 	region        string
+	concurrency   int
+
+	// cleanupCluster processes a single cluster; defaults to cleanupClusterAccess
+	cleanupCluster func(ctx context.Context, cluster *models.Cluster) error
+
+	// listEntries lists a cluster's JIT access entry ARNs; defaults to AccessManager.ListActiveAccess
+	listEntries func(ctx context.Context, clusterName string) ([]string, error)
+
+	// Clock decides which accesses have expired; nil uses the system clock
+	Clock clock.Clock
 }
 
-func NewCleanupService(region string, store *store.MemoryStore) (*CleanupService, error) {
+func (cs *CleanupService) now() time.Time {
+	if cs.Clock == nil {
+		return time.Now()
+	}
+	return cs.Clock.Now()
+}
+
+// NewCleanupService creates a cleanup service. concurrency bounds how many clusters are
+// cleaned up in parallel; zero uses DefaultCleanupConcurrency.
+func NewCleanupService(region string, store *store.MemoryStore, concurrency int) (*CleanupService, error) {
 	accessManager, err := NewAccessManager(region)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create access manager: %w", err)
 	}
 
-	return &CleanupService{
+	cs := &CleanupService{
 		accessManager: accessManager,
 		store:         store,
 		region:        region,
-	}, nil
+		concurrency:   concurrency,
+	}
+	cs.cleanupCluster = cs.cleanupClusterAccess
+	cs.listEntries = accessManager.ListActiveAccess
+	return cs, nil
 }
 
 // StartCleanupWorker starts a background worker that periodically cleans up expired access
@@ -59,11 +88,28 @@ func (cs *CleanupService) performCleanup(ctx context.Context) error {
 		return fmt.Errorf("failed to list clusters: %w", err)
 	}
 
+	concurrency := cs.concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCleanupConcurrency
+	}
+
+	// Clusters are cleaned up by a bounded pool; a failing cluster is logged and does not
+	// hold up the others
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
 	for _, cluster := range clusters {
-		if cleanupErr := cs.cleanupClusterAccess(ctx, cluster); cleanupErr != nil {
-			slog.Error("Failed to cleanup cluster", "cluster", cluster.Name, "error", cleanupErr)
-		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(cluster *models.Cluster) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if cleanupErr := cs.cleanupCluster(ctx, cluster); cleanupErr != nil {
+				slog.Error("Failed to cleanup cluster", "cluster", cluster.Name, "error", cleanupErr)
+			}
+		}(cluster)
 	}
+	wg.Wait()
 
 	return nil
 }
@@ -110,9 +156,9 @@ func (cs *CleanupService) isAccessExpired(access *models.ClusterAccess) bool {
 	if access.ExpiresAt == nil {
 		// If no expiration time set, calculate from requested time + duration
 		expirationTime := access.RequestedAt.Add(access.Duration)
-		return time.Now().After(expirationTime)
+		return cs.now().After(expirationTime)
 	}
-	return time.Now().After(*access.ExpiresAt)
+	return cs.now().After(*access.ExpiresAt)
 }
 
 func (cs *CleanupService) revokeExpiredAccess(
@@ -128,24 +174,20 @@ func (cs *CleanupService) revokeExpiredAccess(
 
 	// Update access status in store
 	access.Status = models.AccessStatusExpired
-	expiredAt := time.Now()
+	expiredAt := cs.now()
 	access.RevokedAt = &expiredAt
 	access.RevokeReason = "Automatic expiration"
 
-	// Note: In a real implementation, you'd want to update the store here
-	// For the memory store, we might need to add an UpdateAccess method
+	if err := cs.store.UpdateClusterAccess(access); err != nil {
+		return fmt.Errorf("failed to update access record: %w", err)
+	}
 
 	slog.Info("Successfully revoked expired access", "user", access.UserID)
 	return nil
 }
 
-func (cs *CleanupService) findAccessBySession(_ *SessionInfo) (*models.ClusterAccess, error) {
-	// This is a simplified implementation
-	// In practice, you'd want to maintain a mapping between session names and access IDs
-	// or store the session name in the access record
-
-	// This would need a more sophisticated lookup in a real implementation
-	return nil, fmt.Errorf("access lookup not fully implemented")
+func (cs *CleanupService) findAccessBySession(sessionInfo *SessionInfo) (*models.ClusterAccess, error) {
+	return cs.store.GetAccessBySession(sessionInfo.SessionName)
 }
 
 type SessionInfo struct {
@@ -205,17 +247,20 @@ func (cs *CleanupService) ForceCleanupCluster(ctx context.Context, clusterName s
 	return nil
 }
 
-// CleanupUserAccess removes all access entries for a specific user
+// CleanupUserAccess removes all access entries for a specific user. Every cluster is tried; the
+// clusters that could not be listed and the entries that could not be deleted are returned together.
 func (cs *CleanupService) CleanupUserAccess(ctx context.Context, userID string) error {
 	clusters, err := cs.store.ListClusters()
 	if err != nil {
 		return fmt.Errorf("failed to list clusters: %w", err)
 	}
 
+	var errs []error
 	for _, cluster := range clusters {
 		entries, listErr := cs.accessManager.ListActiveAccess(ctx, cluster.Name)
 		if listErr != nil {
 			slog.Error("Failed to list access for cluster", "cluster", cluster.Name, "error", listErr)
+			errs = append(errs, fmt.Errorf("failed to list access for cluster %s: %w", cluster.Name, listErr))
 			continue
 		}
 
@@ -224,6 +269,7 @@ func (cs *CleanupService) CleanupUserAccess(ctx context.Context, userID string)
 			if sessionInfo != nil && sessionInfo.UserID == userID {
 				if deleteErr := cs.accessManager.eksService.DeleteAccessEntry(ctx, cluster.Name, entryArn); deleteErr != nil {
 					slog.Error("Failed to delete user access entry", "entry_arn", entryArn, "error", deleteErr)
+					errs = append(errs, fmt.Errorf("failed to delete access entry %s: %w", entryArn, deleteErr))
 				} else {
 					slog.Info("Deleted user access entry", "user", userID, "entry_arn", entryArn)
 				}
@@ -231,5 +277,5 @@ func (cs *CleanupService) CleanupUserAccess(ctx context.Context, userID string)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
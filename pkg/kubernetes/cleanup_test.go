@@ -0,0 +1,156 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+func TestFindAccessBySession(t *testing.T) {
+	memStore := store.NewMemoryStore()
+	cs := &CleanupService{store: memStore}
+
+	cluster := &models.Cluster{ID: "cluster1", AWSAccount: "123456789012"}
+	requestedAt := time.Date(2024, time.June, 10, 14, 30, 22, 0, time.UTC)
+
+	granted := &models.ClusterAccess{ID: "access-1", UserID: "U123", RequestedAt: requestedAt}
+	granted.SessionName = sessionNameFor(granted, cluster, requestedAt)
+	other := &models.ClusterAccess{ID: "access-2", UserID: "U456", RequestedAt: requestedAt}
+	other.SessionName = sessionNameFor(other, cluster, requestedAt)
+
+	for _, access := range []*models.ClusterAccess{granted, other} {
+		if err := memStore.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", access.ID, err)
+		}
+	}
+
+	entryArn := "arn:aws:sts::123456789012:assumed-role/JITAccessRole/" + granted.SessionName
+	sessionInfo := extractSessionInfo(entryArn)
+	if sessionInfo == nil {
+		t.Fatalf("Failed to extract session info from %s", entryArn)
+	}
+
+	access, err := cs.findAccessBySession(sessionInfo)
+	if err != nil {
+		t.Fatalf("findAccessBySession failed: %v", err)
+	}
+	if access.ID != "access-1" {
+		t.Errorf("Expected access-1, got %s", access.ID)
+	}
+
+	orphan := extractSessionInfo("arn:aws:sts::123456789012:assumed-role/JITAccessRole/jit-U789-cluster1-20240610-000000")
+	if _, err := cs.findAccessBySession(orphan); err == nil {
+		t.Error("Expected error for session without an access record")
+	}
+}
+
+func TestSessionNameForIsDeterministic(t *testing.T) {
+	cluster := &models.Cluster{ID: "cluster1"}
+	requestedAt := time.Date(2024, time.June, 10, 14, 30, 22, 0, time.UTC)
+	access := &models.ClusterAccess{UserID: "U123", RequestedAt: requestedAt}
+
+	// Revocation must derive the same principal that was granted
+	expected := aws.JITSessionName("U123", "cluster1", requestedAt)
+	if name := sessionNameFor(access, cluster, time.Now()); name != expected {
+		t.Errorf("Expected %s, got %s", expected, name)
+	}
+
+	access.SessionName = "jit-U123-cluster1-recorded"
+	if name := sessionNameFor(access, cluster, time.Now()); name != "jit-U123-cluster1-recorded" {
+		t.Errorf("Expected recorded session name, got %s", name)
+	}
+}
+
+func TestPerformCleanupProcessesClustersConcurrently(t *testing.T) {
+	memStore := store.NewMemoryStore()
+	const clusterCount = 50
+	for i := 0; i < clusterCount; i++ {
+		cluster := &models.Cluster{ID: fmt.Sprintf("cluster-%d", i), Name: fmt.Sprintf("cluster-%d", i)}
+		if err := memStore.CreateCluster(cluster); err != nil {
+			t.Fatalf("Failed to create cluster: %v", err)
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		processed = map[string]bool{}
+		inFlight  int32
+		maxFlight int32
+	)
+
+	cs := &CleanupService{store: memStore, concurrency: 5}
+	cs.cleanupCluster = func(_ context.Context, cluster *models.Cluster) error {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxFlight, seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		processed[cluster.Name] = true
+		mu.Unlock()
+
+		// One cluster failing must not stop the rest from being cleaned up
+		if cluster.Name == "cluster-7" {
+			return errors.New("eks unavailable")
+		}
+		return nil
+	}
+
+	if err := cs.performCleanup(context.Background()); err != nil {
+		t.Fatalf("performCleanup failed: %v", err)
+	}
+
+	if len(processed) != clusterCount {
+		t.Errorf("Expected all %d clusters processed, got %d", clusterCount, len(processed))
+	}
+	if maxFlight > 5 {
+		t.Errorf("Expected at most 5 clusters in flight, got %d", maxFlight)
+	}
+	if maxFlight < 2 {
+		t.Errorf("Expected clusters to be processed concurrently, max in flight was %d", maxFlight)
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestIsAccessExpiredUsesClock(t *testing.T) {
+	start := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	expiresAt := start.Add(time.Hour)
+	clock := &fakeClock{now: start.Add(59 * time.Minute)}
+	cs := &CleanupService{Clock: clock}
+
+	withExpiry := &models.ClusterAccess{ID: "access-1", RequestedAt: start, ExpiresAt: &expiresAt}
+	// Without ExpiresAt the expiry is derived from the requested time and duration
+	withDuration := &models.ClusterAccess{ID: "access-2", RequestedAt: start, Duration: time.Hour}
+
+	for _, access := range []*models.ClusterAccess{withExpiry, withDuration} {
+		if cs.isAccessExpired(access) {
+			t.Errorf("Expected %s to be valid a minute before expiry", access.ID)
+		}
+	}
+
+	clock.now = start.Add(time.Hour + time.Second)
+	for _, access := range []*models.ClusterAccess{withExpiry, withDuration} {
+		if !cs.isAccessExpired(access) {
+			t.Errorf("Expected %s to be expired a second after expiry", access.ID)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// clusterDescriber looks up a cluster in its own region
+type clusterDescriber func(ctx context.Context, cluster *models.Cluster) (*ekstypes.Cluster, error)
+
+// ClusterHealthComponent is the jit_system_health_status component reporting a cluster's reachability
+func ClusterHealthComponent(clusterName string) string {
+	return "cluster:" + clusterName
+}
+
+// CheckClusters describes each configured cluster so misconfigured ones surface at startup rather
+// than on their first request. Each cluster is marked healthy or unhealthy in the
+// jit_system_health_status metric; problems are logged as warnings and returned keyed by cluster.
+func (am *AccessManager) CheckClusters(ctx context.Context, clusters []*models.Cluster) map[string]error {
+	return checkClusters(ctx, am.regionalDescriber(), clusters)
+}
+
+// regionalDescriber describes clusters through an EKS client for their region, falling back to
+// the access manager's region for clusters that don't name one
+func (am *AccessManager) regionalDescriber() clusterDescriber {
+	services := map[string]*aws.EKSService{am.region: am.eksService}
+	return func(ctx context.Context, cluster *models.Cluster) (*ekstypes.Cluster, error) {
+		region := cluster.Region
+		if region == "" {
+			region = am.region
+		}
+		// Clusters outside the region allowlist would be refused on every request anyway
+		if err := am.allowedRegions.Check(region); err != nil {
+			return nil, err
+		}
+		service, ok := services[region]
+		if !ok {
+			var err error
+			if service, err = aws.NewEKSService(region); err != nil {
+				return nil, err
+			}
+			services[region] = service
+		}
+		return service.DescribeCluster(ctx, cluster.Name)
+	}
+}
+
+func checkClusters(ctx context.Context, describe clusterDescriber, clusters []*models.Cluster) map[string]error {
+	unhealthy := make(map[string]error)
+	for _, cluster := range clusters {
+		err := checkCluster(ctx, describe, cluster)
+		metrics.SetSystemHealthStatus(ClusterHealthComponent(cluster.Name), err == nil)
+		if err != nil {
+			slog.Warn("Configured cluster failed its startup check",
+				"cluster", cluster.Name, "region", cluster.Region, "error", err)
+			unhealthy[cluster.Name] = err
+		}
+	}
+	return unhealthy
+}
+
+func checkCluster(ctx context.Context, describe clusterDescriber, cluster *models.Cluster) error {
+	described, err := describe(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	// A cluster that is still creating, updating or being deleted cannot take access entries
+	if described.Status != ekstypes.ClusterStatusActive {
+		return fmt.Errorf("cluster status is %s", described.Status)
+	}
+	return nil
+}
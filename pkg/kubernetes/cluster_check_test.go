@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+func healthStatus(t *testing.T, component string) (float64, bool) {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "jit_system_health_status" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "component" && label.GetValue() == component {
+					return metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestCheckClustersMarksUnreachableClustersUnhealthy(t *testing.T) {
+	describe := func(_ context.Context, cluster *models.Cluster) (*ekstypes.Cluster, error) {
+		switch cluster.Name {
+		case "check-reachable":
+			return &ekstypes.Cluster{Status: ekstypes.ClusterStatusActive}, nil
+		case "check-creating":
+			return &ekstypes.Cluster{Status: ekstypes.ClusterStatusCreating}, nil
+		default:
+			return nil, errors.New("ResourceNotFoundException: No cluster found")
+		}
+	}
+
+	clusters := []*models.Cluster{
+		{Name: "check-reachable", Region: "us-east-1"},
+		{Name: "check-creating", Region: "us-east-1"},
+		{Name: "check-missing", Region: "us-west-2"},
+	}
+	unhealthy := checkClusters(context.Background(), describe, clusters)
+
+	if len(unhealthy) != 2 {
+		t.Fatalf("Expected 2 unhealthy clusters, got %v", unhealthy)
+	}
+	if _, ok := unhealthy["check-reachable"]; ok {
+		t.Errorf("Reachable cluster reported unhealthy: %v", unhealthy["check-reachable"])
+	}
+	if unhealthy["check-missing"] == nil || unhealthy["check-creating"] == nil {
+		t.Errorf("Expected check-missing and check-creating to be unhealthy, got %v", unhealthy)
+	}
+
+	tests := map[string]float64{"check-reachable": 1, "check-creating": 0, "check-missing": 0}
+	for cluster, want := range tests {
+		got, ok := healthStatus(t, ClusterHealthComponent(cluster))
+		if !ok {
+			t.Errorf("No health status recorded for %s", cluster)
+			continue
+		}
+		if got != want {
+			t.Errorf("Expected health status %v for %s, got %v", want, cluster, got)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// Drift types reported by the drift detector
+const (
+	// DriftOrphaned is an access entry present in EKS without an active access record
+	DriftOrphaned = "orphaned"
+	// DriftMissing is an active access record whose access entry is absent from EKS
+	DriftMissing = "missing"
+)
+
+// DriftReport lists the differences found between one cluster's EKS access entries and the store
+type DriftReport struct {
+	Cluster string
+	// Orphaned holds the principal ARNs of untracked access entries
+	Orphaned []string
+	// Missing holds the IDs of active access records without an access entry
+	Missing []string
+}
+
+// StartDriftWorker starts a background worker that periodically reports access drift
+func (cs *CleanupService) StartDriftWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slog.Info("Starting drift detection worker", "interval", interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Drift detection worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := cs.DetectDrift(ctx); err != nil {
+				slog.Error("Drift detection error", "error", err)
+			}
+		}
+	}
+}
+
+// DetectDrift compares each cluster's JIT access entries with the active access records in
+// the store, records the differences in the jit_access_drift_entries gauge and returns them.
+// Drift is only reported; cleanup of orphaned entries is left to the cleanup worker.
+func (cs *CleanupService) DetectDrift(ctx context.Context) ([]DriftReport, error) {
+	clusters, err := cs.store.ListClusters()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	accesses, err := cs.store.ListClusterAccess()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access records: %w", err)
+	}
+
+	var reports []DriftReport
+	for _, cluster := range clusters {
+		report, driftErr := cs.detectClusterDrift(ctx, cluster, accesses)
+		if driftErr != nil {
+			slog.Error("Failed to detect drift for cluster", "cluster", cluster.Name, "error", driftErr)
+			continue
+		}
+
+		metrics.SetAccessDrift(cluster.Name, DriftOrphaned, len(report.Orphaned))
+		metrics.SetAccessDrift(cluster.Name, DriftMissing, len(report.Missing))
+
+		if len(report.Orphaned) > 0 || len(report.Missing) > 0 {
+			slog.Warn("Access drift detected", "cluster", cluster.Name,
+				"orphaned", report.Orphaned, "missing", report.Missing)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (cs *CleanupService) detectClusterDrift(
+	ctx context.Context, cluster *models.Cluster, accesses []*models.ClusterAccess,
+) (DriftReport, error) {
+	report := DriftReport{Cluster: cluster.Name}
+
+	entries, err := cs.listEntries(ctx, cluster.Name)
+	if err != nil {
+		return report, fmt.Errorf("failed to list access entries: %w", err)
+	}
+
+	// Access entries are keyed by the session name embedded in their principal ARN
+	entrySessions := make(map[string]bool, len(entries))
+	for _, entryArn := range entries {
+		if sessionInfo := extractSessionInfo(entryArn); sessionInfo != nil {
+			entrySessions[sessionInfo.SessionName] = true
+		}
+	}
+
+	trackedSessions := make(map[string]bool)
+	for _, access := range accesses {
+		if access.ClusterID != cluster.ID || access.Status != models.AccessStatusActive {
+			continue
+		}
+
+		sessionName := sessionNameFor(access, cluster, cs.now())
+		trackedSessions[sessionName] = true
+		if !entrySessions[sessionName] {
+			report.Missing = append(report.Missing, access.ID)
+		}
+	}
+
+	for _, entryArn := range entries {
+		sessionInfo := extractSessionInfo(entryArn)
+		if sessionInfo == nil || !trackedSessions[sessionInfo.SessionName] {
+			report.Orphaned = append(report.Orphaned, entryArn)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rebelopsio/jit-bot/pkg/models"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+func driftCount(t *testing.T, cluster, driftType string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "jit_access_drift_entries" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["cluster"] == cluster && labels["type"] == driftType {
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestDetectDriftReportsOrphanedAndMissingEntries(t *testing.T) {
+	memStore := store.NewMemoryStore()
+	cluster := &models.Cluster{ID: "cluster1", Name: "cluster1", AWSAccount: "123456789012"}
+	if err := memStore.CreateCluster(cluster); err != nil {
+		t.Fatalf("Failed to create cluster: %v", err)
+	}
+
+	requestedAt := time.Date(2024, time.June, 10, 14, 30, 22, 0, time.UTC)
+	newAccess := func(id, userID string, status models.AccessStatus) *models.ClusterAccess {
+		access := &models.ClusterAccess{
+			ID: id, UserID: userID, ClusterID: "cluster1", Status: status, RequestedAt: requestedAt,
+		}
+		access.SessionName = sessionNameFor(access, cluster, requestedAt)
+		if err := memStore.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", id, err)
+		}
+		return access
+	}
+
+	inSync := newAccess("in-sync", "U1", models.AccessStatusActive)
+	newAccess("missing-1", "U2", models.AccessStatusActive)
+	newAccess("missing-2", "U3", models.AccessStatusActive)
+	newAccess("expired", "U4", models.AccessStatusExpired)
+
+	principal := "arn:aws:sts::123456789012:assumed-role/JITAccessRole/"
+	orphanArn := principal + "jit-U9-cluster1-20240610-000000"
+	entries := []string{principal + inSync.SessionName, orphanArn}
+
+	cs := &CleanupService{store: memStore}
+	cs.listEntries = func(_ context.Context, clusterName string) ([]string, error) {
+		if clusterName != "cluster1" {
+			t.Errorf("Unexpected cluster %s", clusterName)
+		}
+		return entries, nil
+	}
+
+	reports, err := cs.DetectDrift(context.Background())
+	if err != nil {
+		t.Fatalf("DetectDrift failed: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 cluster report, got %d", len(reports))
+	}
+
+	report := reports[0]
+	if len(report.Orphaned) != 1 || report.Orphaned[0] != orphanArn {
+		t.Errorf("Expected orphaned entry %s, got %v", orphanArn, report.Orphaned)
+	}
+	missing := map[string]bool{}
+	for _, id := range report.Missing {
+		missing[id] = true
+	}
+	if len(report.Missing) != 2 || !missing["missing-1"] || !missing["missing-2"] {
+		t.Errorf("Expected missing-1 and missing-2, got %v", report.Missing)
+	}
+
+	// Every scan sets the gauge to what it found rather than adding to it
+	for scan := 1; scan <= 2; scan++ {
+		if scan == 2 {
+			if _, err := cs.DetectDrift(context.Background()); err != nil {
+				t.Fatalf("DetectDrift failed: %v", err)
+			}
+		}
+		if got := driftCount(t, "cluster1", DriftOrphaned); got != 1 {
+			t.Errorf("Scan %d: expected 1 orphaned entry, got %v", scan, got)
+		}
+		if got := driftCount(t, "cluster1", DriftMissing); got != 2 {
+			t.Errorf("Scan %d: expected 2 missing entries, got %v", scan, got)
+		}
+	}
+
+	// Drift that is fixed drops back to zero
+	entries = []string{principal + inSync.SessionName}
+	if _, err := cs.DetectDrift(context.Background()); err != nil {
+		t.Fatalf("DetectDrift failed: %v", err)
+	}
+	if got := driftCount(t, "cluster1", DriftOrphaned); got != 0 {
+		t.Errorf("Expected no orphaned entries after cleanup, got %v", got)
+	}
+}
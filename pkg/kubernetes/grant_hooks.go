@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// PreGrantHook runs before any AWS call made for a grant, for side effects such as opening a
+// change ticket. Returning an error aborts the grant.
+type PreGrantHook interface {
+	BeforeGrant(ctx context.Context, req GrantAccessRequest) error
+}
+
+// PostGrantHook runs once a grant has succeeded, for side effects such as notifying a SIEM.
+// Its errors are logged; the grant has already been made and is not rolled back.
+type PostGrantHook interface {
+	AfterGrant(ctx context.Context, req GrantAccessRequest, creds *AccessCredentials) error
+}
+
+// PreGrantHookFunc adapts a function to PreGrantHook
+type PreGrantHookFunc func(ctx context.Context, req GrantAccessRequest) error
+
+func (f PreGrantHookFunc) BeforeGrant(ctx context.Context, req GrantAccessRequest) error {
+	return f(ctx, req)
+}
+
+// PostGrantHookFunc adapts a function to PostGrantHook
+type PostGrantHookFunc func(ctx context.Context, req GrantAccessRequest, creds *AccessCredentials) error
+
+func (f PostGrantHookFunc) AfterGrant(ctx context.Context, req GrantAccessRequest, creds *AccessCredentials) error {
+	return f(ctx, req, creds)
+}
+
+// AddPreGrantHook registers a hook to run, in registration order, before each grant
+func (am *AccessManager) AddPreGrantHook(hook PreGrantHook) {
+	am.preGrantHooks = append(am.preGrantHooks, hook)
+}
+
+// AddPostGrantHook registers a hook to run, in registration order, after each successful grant
+func (am *AccessManager) AddPostGrantHook(hook PostGrantHook) {
+	am.postGrantHooks = append(am.postGrantHooks, hook)
+}
+
+// runPreGrantHooks stops at the first failing hook so later hooks see only grants that will proceed
+func (am *AccessManager) runPreGrantHooks(ctx context.Context, req GrantAccessRequest) error {
+	for _, hook := range am.preGrantHooks {
+		if err := hook.BeforeGrant(ctx, req); err != nil {
+			return fmt.Errorf("pre-grant hook failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// runPostGrantHooks runs every hook even if one fails, since the grant itself has succeeded
+func (am *AccessManager) runPostGrantHooks(ctx context.Context, req GrantAccessRequest, creds *AccessCredentials) {
+	for _, hook := range am.postGrantHooks {
+		if err := hook.AfterGrant(ctx, req, creds); err != nil {
+			slog.Error("Post-grant hook failed", "user", req.ClusterAccess.UserID,
+				"cluster", req.Cluster.Name, "sessionName", creds.SessionName, "error", err)
+		}
+	}
+}
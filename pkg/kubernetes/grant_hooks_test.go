@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+const fakeAssumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>AKIDEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+const fakeDescribeClusterResponse = `{"cluster": {"name": "hooks-cluster",
+  "endpoint": "https://hooks-cluster.eks.example.com", "certificateAuthority": {"data": "Y2E="}}}`
+
+// newHookTestAccessManager points an access manager at a fake STS and EKS endpoint and
+// returns it with a count of the AWS requests it has made
+func newHookTestAccessManager(t *testing.T) (*AccessManager, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	am := newFakeAWSAccessManager(t, func(*http.Request) { calls.Add(1) })
+	return am, &calls
+}
+
+// newFakeAWSAccessManager points an access manager at a fake STS and EKS endpoint that passes
+// every request to onRequest before answering it
+func newFakeAWSAccessManager(t *testing.T, onRequest func(r *http.Request)) *AccessManager {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		onRequest(r)
+		switch {
+		case !strings.HasPrefix(r.URL.Path, "/clusters/"):
+			w.Header().Set("Content-Type", "text/xml")
+			_, _ = w.Write([]byte(fakeAssumeRoleResponse))
+		case r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fakeDescribeClusterResponse))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_ENDPOINT_URL", server.URL)
+
+	am, err := NewAccessManager("us-east-1")
+	if err != nil {
+		t.Fatalf("Failed to create access manager: %v", err)
+	}
+	return am
+}
+
+func hookTestGrantRequest() GrantAccessRequest {
+	return GrantAccessRequest{
+		ClusterAccess: &models.ClusterAccess{ID: "access-hooks", UserID: "U0REQUESTER", Duration: time.Hour},
+		Cluster:       &models.Cluster{Name: "hooks-cluster", Region: "us-east-1", AWSAccount: "123456789012"},
+		Permissions:   []string{"view"},
+		JITRoleArn:    "arn:aws:iam::123456789012:role/JITAccessRole",
+	}
+}
+
+func TestPreGrantHookFailureAbortsGrant(t *testing.T) {
+	am, calls := newHookTestAccessManager(t)
+
+	ticketErr := errors.New("change ticket system unavailable")
+	am.AddPreGrantHook(PreGrantHookFunc(func(context.Context, GrantAccessRequest) error { return ticketErr }))
+	postRan := false
+	am.AddPostGrantHook(PostGrantHookFunc(func(context.Context, GrantAccessRequest, *AccessCredentials) error {
+		postRan = true
+		return nil
+	}))
+
+	creds, err := am.GrantAccess(context.Background(), hookTestGrantRequest())
+	if !errors.Is(err, ticketErr) {
+		t.Fatalf("Expected the pre-grant hook error, got %v", err)
+	}
+	if creds != nil {
+		t.Errorf("Expected no credentials when a pre-grant hook fails, got %v", creds)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("Expected no AWS calls after a pre-grant hook failure, got %d", n)
+	}
+	if postRan {
+		t.Error("Expected post-grant hooks to be skipped when the grant is aborted")
+	}
+}
+
+func TestPostGrantHooksRunAfterSuccessfulGrant(t *testing.T) {
+	am, calls := newHookTestAccessManager(t)
+
+	var order []string
+	am.AddPreGrantHook(PreGrantHookFunc(func(context.Context, GrantAccessRequest) error {
+		if n := calls.Load(); n != 0 {
+			t.Errorf("Expected the pre-grant hook to run before any AWS call, %d made", n)
+		}
+		order = append(order, "pre")
+		return nil
+	}))
+	checkCreds := func(_ context.Context, _ GrantAccessRequest, creds *AccessCredentials) error {
+		if creds == nil || creds.AccessEntry == nil {
+			t.Errorf("Expected the post-grant hook to see the granted access entry, got %v", creds)
+		}
+		order = append(order, "failing-post")
+		return errors.New("SIEM unreachable")
+	}
+	am.AddPostGrantHook(PostGrantHookFunc(checkCreds))
+	am.AddPostGrantHook(PostGrantHookFunc(func(context.Context, GrantAccessRequest, *AccessCredentials) error {
+		order = append(order, "post")
+		return nil
+	}))
+
+	creds, err := am.GrantAccess(context.Background(), hookTestGrantRequest())
+	if err != nil {
+		t.Fatalf("Expected a failing post-grant hook not to fail the grant, got %v", err)
+	}
+	if creds.ClusterEndpoint != "https://hooks-cluster.eks.example.com" {
+		t.Errorf("Expected credentials for the fake cluster, got endpoint %q", creds.ClusterEndpoint)
+	}
+	if got := strings.Join(order, ","); got != "pre,failing-post,post" {
+		t.Errorf("Expected hooks to run in order pre,failing-post,post, got %s", got)
+	}
+}
@@ -0,0 +1,196 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+)
+
+// inlinePolicyLabel marks the Roles, ClusterRoles and bindings created for inline policies so
+// revocation finds them in whichever namespaces they were granted
+const inlinePolicyLabel = "jit.rebelops.io/inline-policy"
+
+// inlinePolicyGroupPrefix prefixes the Kubernetes group, and RBAC object names, of every inline policy
+const inlinePolicyGroupPrefix = "jit-scoped-"
+
+// clusterClientFunc returns a client for the target cluster's API server
+type clusterClientFunc func(ctx context.Context, cluster *ekstypes.Cluster) (k8sclient.Interface, error)
+
+// operatorClusterClient connects to the cluster as the operator's own AWS identity, which needs an
+// access entry allowing it to manage RBAC on every cluster it grants scoped access to
+func (am *AccessManager) operatorClusterClient(
+	ctx context.Context, cluster *ekstypes.Cluster,
+) (k8sclient.Interface, error) {
+	token, err := am.stsService.ClusterToken(ctx, awssdk.ToString(cluster.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &rest.Config{Host: awssdk.ToString(cluster.Endpoint), BearerToken: token}
+	if cluster.CertificateAuthority != nil {
+		caData, err := base64.StdEncoding.DecodeString(awssdk.ToString(cluster.CertificateAuthority.Data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate authority for cluster %s: %w",
+				awssdk.ToString(cluster.Name), err)
+		}
+		config.CAData = caData
+	}
+	return k8sclient.NewForConfig(config)
+}
+
+func (am *AccessManager) clusterClientFor(ctx context.Context, cluster *ekstypes.Cluster) (k8sclient.Interface, error) {
+	if am.clusterClient != nil {
+		return am.clusterClient(ctx, cluster)
+	}
+	return am.operatorClusterClient(ctx, cluster)
+}
+
+// grantInlinePolicy binds an inline policy's group on the target cluster, removing whatever it
+// created if any step fails
+func (am *AccessManager) grantInlinePolicy(
+	ctx context.Context, cluster *ekstypes.Cluster, policy *aws.InlinePolicy,
+) error {
+	client, err := am.clusterClientFor(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to connect to cluster %s: %w", awssdk.ToString(cluster.Name), err)
+	}
+	if err := applyInlinePolicy(ctx, client, policy); err != nil {
+		if cleanupErr := deleteInlinePolicy(ctx, client, policy.Name); cleanupErr != nil {
+			slog.Error("Failed to clean up inline policy", "policy", policy.Name, "error", cleanupErr)
+		}
+		return fmt.Errorf("failed to apply inline policy: %w", err)
+	}
+	return nil
+}
+
+// revokeInlinePolicy removes the RBAC objects created for the named inline policy
+func (am *AccessManager) revokeInlinePolicy(ctx context.Context, clusterName, name string) error {
+	cluster, err := am.eksService.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	client, err := am.clusterClientFor(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to connect to cluster %s: %w", clusterName, err)
+	}
+	return deleteInlinePolicy(ctx, client, name)
+}
+
+// applyInlinePolicy creates the RBAC objects that give an inline policy's Kubernetes group its rules:
+// a Role and RoleBinding in each of its namespaces, or a ClusterRole and ClusterRoleBinding when it
+// names none. EKS only puts the group on the session; without these objects it grants nothing.
+func applyInlinePolicy(ctx context.Context, client k8sclient.Interface, policy *aws.InlinePolicy) error {
+	rules := make([]rbacv1.PolicyRule, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: rule.APIGroups,
+			Resources: rule.Resources,
+			Verbs:     rule.Verbs,
+		})
+	}
+	subjects := []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: policy.Name}}
+
+	if len(policy.Namespaces) == 0 {
+		meta := inlinePolicyMeta(policy.Name, "")
+		role := &rbacv1.ClusterRole{ObjectMeta: meta, Rules: rules}
+		if _, err := client.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create cluster role %s: %w", policy.Name, err)
+		}
+		binding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: meta,
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: policy.Name},
+		}
+		if _, err := client.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create cluster role binding %s: %w", policy.Name, err)
+		}
+		return nil
+	}
+
+	for _, namespace := range policy.Namespaces {
+		meta := inlinePolicyMeta(policy.Name, namespace)
+		role := &rbacv1.Role{ObjectMeta: meta, Rules: rules}
+		if _, err := client.RbacV1().Roles(namespace).Create(ctx, role, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create role %s/%s: %w", namespace, policy.Name, err)
+		}
+		binding := &rbacv1.RoleBinding{
+			ObjectMeta: meta,
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: policy.Name},
+		}
+		if _, err := client.RbacV1().RoleBindings(namespace).Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create role binding %s/%s: %w", namespace, policy.Name, err)
+		}
+	}
+	return nil
+}
+
+func inlinePolicyMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    map[string]string{inlinePolicyLabel: "true"},
+	}
+}
+
+// deleteInlinePolicy removes every RBAC object created for the named inline policy. Objects that
+// are already gone are skipped; other failures are returned together.
+func deleteInlinePolicy(ctx context.Context, client k8sclient.Interface, name string) error {
+	var errs []error
+	ignoreNotFound := func(err error) {
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	ignoreNotFound(client.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{}))
+	ignoreNotFound(client.RbacV1().ClusterRoles().Delete(ctx, name, metav1.DeleteOptions{}))
+
+	selector := metav1.ListOptions{LabelSelector: inlinePolicyLabel + "=true"}
+	bindings, err := client.RbacV1().RoleBindings("").List(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list role bindings for %s: %w", name, err)
+	}
+	for _, binding := range bindings.Items {
+		if binding.Name == name {
+			ignoreNotFound(client.RbacV1().RoleBindings(binding.Namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+		}
+	}
+	roles, err := client.RbacV1().Roles("").List(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list roles for %s: %w", name, err)
+	}
+	for _, role := range roles.Items {
+		if role.Name == name {
+			ignoreNotFound(client.RbacV1().Roles(role.Namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to delete inline policy %s: %w", name, err)
+	}
+	return nil
+}
+
+// inlinePolicyGroup returns the inline policy group among an access entry's groups, if any
+func inlinePolicyGroup(groups []string) string {
+	for _, group := range groups {
+		if strings.HasPrefix(group, inlinePolicyGroupPrefix) {
+			return group
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+)
+
+func TestApplyInlinePolicyNamespaced(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset()
+	policy := &aws.InlinePolicy{
+		Name:       "jit-scoped-jit-u1-0a1b2c3d",
+		Namespaces: []string{"payments", "batch"},
+		Rules:      []aws.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+	}
+
+	if err := applyInlinePolicy(ctx, client, policy); err != nil {
+		t.Fatalf("Failed to apply inline policy: %v", err)
+	}
+
+	for _, namespace := range policy.Namespaces {
+		role, err := client.RbacV1().Roles(namespace).Get(ctx, policy.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected a role in %s: %v", namespace, err)
+		}
+		if len(role.Rules) != 1 || role.Rules[0].Resources[0] != "pods" || role.Rules[0].Verbs[0] != "get" {
+			t.Errorf("Expected the role in %s to carry the policy's rules, got %v", namespace, role.Rules)
+		}
+
+		binding, err := client.RbacV1().RoleBindings(namespace).Get(ctx, policy.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected a role binding in %s: %v", namespace, err)
+		}
+		subjects := binding.Subjects
+		if len(subjects) != 1 || subjects[0].Kind != "Group" || subjects[0].Name != policy.Name {
+			t.Errorf("Expected the binding in %s to bind the policy's group, got %v", namespace, binding.Subjects)
+		}
+	}
+
+	clusterRoles, err := client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list cluster roles: %v", err)
+	}
+	if len(clusterRoles.Items) != 0 {
+		t.Errorf("Expected a namespaced policy not to create cluster roles, got %d", len(clusterRoles.Items))
+	}
+
+	if err := deleteInlinePolicy(ctx, client, policy.Name); err != nil {
+		t.Fatalf("Failed to delete inline policy: %v", err)
+	}
+	roles, err := client.RbacV1().Roles("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list roles: %v", err)
+	}
+	bindings, err := client.RbacV1().RoleBindings("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list role bindings: %v", err)
+	}
+	if len(roles.Items) != 0 || len(bindings.Items) != 0 {
+		t.Errorf("Expected every role and binding to be deleted, %d roles and %d bindings remain",
+			len(roles.Items), len(bindings.Items))
+	}
+}
+
+func TestApplyInlinePolicyClusterWide(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset()
+	policy := &aws.InlinePolicy{
+		Name:  "jit-scoped-jit-u1-0a1b2c3d",
+		Rules: []aws.PolicyRule{{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"delete"}}},
+	}
+
+	if err := applyInlinePolicy(ctx, client, policy); err != nil {
+		t.Fatalf("Failed to apply inline policy: %v", err)
+	}
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(ctx, policy.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected a cluster role binding: %v", err)
+	}
+	if binding.RoleRef.Kind != "ClusterRole" || binding.RoleRef.Name != policy.Name {
+		t.Errorf("Expected the binding to reference the policy's cluster role, got %v", binding.RoleRef)
+	}
+
+	if err := deleteInlinePolicy(ctx, client, policy.Name); err != nil {
+		t.Fatalf("Failed to delete inline policy: %v", err)
+	}
+	if _, err := client.RbacV1().ClusterRoles().Get(ctx, policy.Name, metav1.GetOptions{}); err == nil {
+		t.Error("Expected the cluster role to be deleted")
+	}
+}
+
+func TestGrantAccessFailsWhenInlinePolicyCannotBeApplied(t *testing.T) {
+	ctx := context.Background()
+	am, _ := newHookTestAccessManager(t)
+	am.clusterClient = func(context.Context, *ekstypes.Cluster) (k8sclient.Interface, error) {
+		return nil, errors.New("cluster unreachable")
+	}
+
+	req := hookTestGrantRequest()
+	req.ResourceScope = &aws.ResourceScope{Resources: []string{"pods"}, Verbs: []string{"get"}}
+
+	creds, err := am.GrantAccess(ctx, req)
+	if err == nil {
+		t.Fatalf("Expected the grant to fail without its inline policy, got credentials %v", creds)
+	}
+}
+
+func TestGrantAccessAppliesInlinePolicy(t *testing.T) {
+	ctx := context.Background()
+	am, _ := newHookTestAccessManager(t)
+	client := fake.NewClientset()
+	am.clusterClient = func(context.Context, *ekstypes.Cluster) (k8sclient.Interface, error) {
+		return client, nil
+	}
+
+	req := hookTestGrantRequest()
+	req.Namespaces = []string{"payments"}
+	req.ResourceScope = &aws.ResourceScope{Resources: []string{"pods"}, Verbs: []string{"get"}}
+
+	creds, err := am.GrantAccess(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to grant scoped access: %v", err)
+	}
+	name := creds.AccessEntry.InlinePolicy.Name
+	if _, err := client.RbacV1().RoleBindings("payments").Get(ctx, name, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the inline policy to be bound in payments: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package kubernetes
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+)
+
+// String implements fmt.Stringer so access credentials printed with %v or %s never expose
+// the temporary credentials or kubeconfig
+func (c *AccessCredentials) String() string {
+	if c == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("{SessionName:%s PrincipalArn:%s ClusterEndpoint:%s ExpiresAt:%s "+
+		"TemporaryCredentials:%s KubeConfig:%s}",
+		c.SessionName, c.PrincipalArn, c.ClusterEndpoint, c.ExpiresAt.Format(time.RFC3339),
+		c.TemporaryCredentials, aws.RedactSecret(c.KubeConfig))
+}
+
+// LogValue implements slog.LogValuer so access credentials logged with slog are masked
+func (c *AccessCredentials) LogValue() slog.Value {
+	if c == nil {
+		return slog.Value{}
+	}
+	return slog.GroupValue(
+		slog.String("sessionName", c.SessionName),
+		slog.String("principalArn", c.PrincipalArn),
+		slog.String("clusterEndpoint", c.ClusterEndpoint),
+		slog.Time("expiresAt", c.ExpiresAt),
+		slog.Any("temporaryCredentials", c.TemporaryCredentials),
+		slog.String("kubeConfig", aws.RedactSecret(c.KubeConfig)),
+	)
+}
+
+// MarshalLog implements logr.Marshaler so access credentials logged through controller-runtime are masked
+func (c *AccessCredentials) MarshalLog() interface{} {
+	if c == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"sessionName":          c.SessionName,
+		"principalArn":         c.PrincipalArn,
+		"clusterEndpoint":      c.ClusterEndpoint,
+		"expiresAt":            c.ExpiresAt,
+		"temporaryCredentials": c.TemporaryCredentials.MarshalLog(),
+		"kubeConfig":           aws.RedactSecret(c.KubeConfig),
+	}
+}
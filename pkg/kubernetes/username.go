@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultUsernameTemplate is used for clusters that do not configure a username template
+const DefaultUsernameTemplate = "jit:{user}"
+
+// maxUsernameLength is the longest Kubernetes username accepted for an EKS access entry
+const maxUsernameLength = 255
+
+// reservedUsernamePrefixes are rejected by EKS access entries
+var reservedUsernamePrefixes = []string{"system:", "eks:", "aws:", "amazon:", "iam:"}
+
+// RenderUsername expands a cluster's username template for a grantee. {user} is replaced with
+// the user ID and {email} with the email address; an empty template uses DefaultUsernameTemplate.
+func RenderUsername(template, userID, email string) (string, error) {
+	if template == "" {
+		template = DefaultUsernameTemplate
+	}
+	if strings.Contains(template, "{email}") && email == "" {
+		return "", fmt.Errorf("username template %q requires an email address", template)
+	}
+
+	username := strings.NewReplacer("{user}", userID, "{email}", email).Replace(template)
+	if err := validateUsername(username); err != nil {
+		return "", fmt.Errorf("invalid username from template %q: %w", template, err)
+	}
+	return username, nil
+}
+
+// ValidateUsernameTemplate checks that a template only uses known placeholders and renders a
+// valid username
+func ValidateUsernameTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if !strings.Contains(template, "{user}") && !strings.Contains(template, "{email}") {
+		return fmt.Errorf("username template %q must contain {user} or {email}", template)
+	}
+
+	_, err := RenderUsername(template, "U0123456789", "user@example.com")
+	return err
+}
+
+func validateUsername(username string) error {
+	if username == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if len(username) > maxUsernameLength {
+		return fmt.Errorf("username must be at most %d characters", maxUsernameLength)
+	}
+	if strings.ContainsAny(username, "{}") {
+		return fmt.Errorf("username %q contains an unknown placeholder", username)
+	}
+	for _, r := range username {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf("username %q cannot contain whitespace or control characters", username)
+		}
+	}
+	for _, prefix := range reservedUsernamePrefixes {
+		if strings.HasPrefix(username, prefix) {
+			return fmt.Errorf("username %q cannot start with reserved prefix %s", username, prefix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "default template", template: "", expected: "jit:U0123456789"},
+		{name: "user template", template: "jit:{user}", expected: "jit:U0123456789"},
+		{name: "email template", template: "{email}", expected: "jane.doe@example.com"},
+		{name: "combined template", template: "oidc:{email}:{user}", expected: "oidc:jane.doe@example.com:U0123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, err := RenderUsername(tt.template, "U0123456789", "jane.doe@example.com")
+			if err != nil {
+				t.Fatalf("RenderUsername failed: %v", err)
+			}
+			if username != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, username)
+			}
+		})
+	}
+}
+
+func TestRenderUsernameRejectsInvalidUsernames(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		email    string
+		errMsg   string
+	}{
+		{name: "reserved prefix", template: "system:{user}", email: "jane@example.com", errMsg: "reserved prefix"},
+		{name: "missing email", template: "{email}", errMsg: "requires an email"},
+		{
+			name:     "unknown placeholder",
+			template: "{team}:{user}",
+			email:    "jane@example.com",
+			errMsg:   "unknown placeholder",
+		},
+		{name: "whitespace", template: "jit user {user}", email: "jane@example.com", errMsg: "whitespace"},
+		{name: "too long", template: strings.Repeat("a", 250) + "{user}", errMsg: "at most 255"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderUsername(tt.template, "U0123456789", tt.email)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestValidateUsernameTemplate(t *testing.T) {
+	for _, template := range []string{"", "jit:{user}", "{email}"} {
+		if err := ValidateUsernameTemplate(template); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", template, err)
+		}
+	}
+
+	for _, template := range []string{"static-user", "eks:{user}"} {
+		if err := ValidateUsernameTemplate(template); err == nil {
+			t.Errorf("Expected %q to be rejected", template)
+		}
+	}
+}
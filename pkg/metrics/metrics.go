@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -8,6 +11,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// UserLabelMode controls how user identities appear in metric labels.
+// Raw user IDs give per-user series, which explodes cardinality with many users.
+type UserLabelMode string
+
+const (
+	// UserLabelRaw keeps the user ID as the label value
+	UserLabelRaw UserLabelMode = "raw"
+	// UserLabelHashed buckets users into a fixed number of hashed values
+	UserLabelHashed UserLabelMode = "hashed"
+	// UserLabelDropped replaces the user ID with a constant
+	UserLabelDropped UserLabelMode = "dropped"
+
+	droppedUserLabel = "redacted"
+	userLabelBuckets = 32
+)
+
+var userLabelMode = UserLabelRaw
+
+// SetUserLabelMode configures the user label mode. It should be called once at startup,
+// before any metrics are recorded; user identities remain available in traces and audit logs.
+func SetUserLabelMode(mode UserLabelMode) error {
+	switch mode {
+	case UserLabelRaw, UserLabelHashed, UserLabelDropped:
+		userLabelMode = mode
+		return nil
+	case "":
+		userLabelMode = UserLabelRaw
+		return nil
+	default:
+		return fmt.Errorf("invalid user label mode %q: must be one of raw, hashed, dropped", mode)
+	}
+}
+
 var (
 	// Access Request Metrics
 	accessRequestsTotal = promauto.NewCounterVec(
@@ -34,6 +70,30 @@ var (
 		[]string{"cluster", "user", "environment", "reason"},
 	)
 
+	accessRequestsFailed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_access_requests_failed_total",
+			Help: "Total number of JIT access requests moved to the terminal Failed phase",
+		},
+		[]string{"cluster", "reason"},
+	)
+
+	requestPhaseTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_request_phase_transitions_total",
+			Help: "Total number of JIT access request phase changes",
+		},
+		[]string{"from", "to"},
+	)
+
+	accessRequestRiskScores = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_access_request_risk_scores_total",
+			Help: "JIT access requests created, by webhook-assigned risk score",
+		},
+		[]string{"cluster", "environment", "risk_score"},
+	)
+
 	accessRequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "jit_access_request_duration_seconds",
@@ -43,6 +103,17 @@ var (
 		[]string{"cluster", "environment", "status"},
 	)
 
+	approvalLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "jit_approval_latency_seconds",
+			Help: "Time from request creation to each approval, by approver",
+			Buckets: []float64{
+				30, 60, 300, 900, 1800, 3600, 2 * 3600, 4 * 3600, 8 * 3600, 24 * 3600,
+			}, // 30s to 1 day
+		},
+		[]string{"approver"},
+	)
+
 	// Active Access Metrics
 	activeAccessSessions = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -61,6 +132,15 @@ var (
 		[]string{"cluster", "environment", "permissions"},
 	)
 
+	provisionDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "jit_provision_duration_seconds",
+			Help:    "Time from job start to access becoming active",
+			Buckets: prometheus.ExponentialBuckets(0.5, 2, 10), // 0.5s to ~4min
+		},
+		[]string{"cluster", "environment"},
+	)
+
 	// Webhook Metrics
 	webhookRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -113,6 +193,14 @@ var (
 		[]string{"service", "operation", "error_code", "region"},
 	)
 
+	durationClampedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_duration_clamped_total",
+			Help: "Grants whose AWS credentials expire before the requested duration",
+		},
+		[]string{"cluster"},
+	)
+
 	// Slack Integration Metrics
 	slackCommandsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -165,6 +253,22 @@ var (
 		[]string{"controller", "error_type"},
 	)
 
+	secretsCreated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_secrets_created_total",
+			Help: "Total number of credential and kubeconfig secrets created by the job controller",
+		},
+		[]string{"type"},
+	)
+
+	secretsDeleted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_secrets_deleted_total",
+			Help: "Total number of credential and kubeconfig secrets deleted by the job controller",
+		},
+		[]string{"type"},
+	)
+
 	// Security Metrics
 	securityViolationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -182,6 +286,29 @@ var (
 		[]string{"user", "from_permission", "to_permission", "cluster"},
 	)
 
+	accessDrift = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "jit_access_drift_entries",
+			Help: "EKS access entries out of sync with tracked access records at the last drift scan",
+		},
+		[]string{"cluster", "type"},
+	)
+
+	killSwitchEngaged = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "jit_kill_switch_engaged",
+			Help: "Whether the JIT access kill switch is engaged (1=engaged, 0=clear)",
+		},
+	)
+
+	killSwitchActionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jit_kill_switch_actions_total",
+			Help: "Total number of requests revoked or denied by the kill switch",
+		},
+		[]string{"phase"},
+	)
+
 	// System Health Metrics
 	systemHealthStatus = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -197,6 +324,14 @@ var (
 			Help: "Timestamp of last successful backup",
 		},
 	)
+
+	buildInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "jit_build_info",
+			Help: "Build information of the running binary (always 1)",
+		},
+		[]string{"version", "commit", "build_date"},
+	)
 )
 
 func init() {
@@ -205,25 +340,37 @@ func init() {
 		accessRequestsTotal,
 		accessRequestsApproved,
 		accessRequestsDenied,
+		accessRequestsFailed,
+		requestPhaseTransitions,
+		accessRequestRiskScores,
 		accessRequestDuration,
+		approvalLatency,
 		activeAccessSessions,
 		accessSessionDuration,
+		provisionDuration,
 		webhookRequestsTotal,
 		webhookRequestDuration,
 		webhookValidationErrors,
 		awsAPICalls,
 		awsAPIDuration,
 		awsAPIErrors,
+		durationClampedTotal,
 		slackCommandsTotal,
 		slackCommandDuration,
 		slackAPIErrors,
 		controllerReconcileTotal,
 		controllerReconcileDuration,
 		controllerErrors,
+		secretsCreated,
+		secretsDeleted,
 		securityViolationsTotal,
 		privilegeEscalationAttempts,
+		accessDrift,
+		killSwitchEngaged,
+		killSwitchActionsTotal,
 		systemHealthStatus,
 		lastSuccessfulBackup,
+		buildInfo,
 	)
 }
 
@@ -231,19 +378,44 @@ func init() {
 
 func RecordAccessRequest(cluster, user, environment string, permissions []string) {
 	permList := joinPermissions(permissions)
-	accessRequestsTotal.WithLabelValues(cluster, user, environment, permList).Inc()
+	accessRequestsTotal.WithLabelValues(cluster, userLabelValue(user), environment, permList).Inc()
+}
+
+// RecordAccessRequestRiskScore counts a new request under the risk score it was assigned
+func RecordAccessRequestRiskScore(cluster, environment string, score int) {
+	accessRequestRiskScores.WithLabelValues(cluster, environment, strconv.Itoa(score)).Inc()
 }
 
 func RecordAccessRequestApproval(cluster, user, environment, approver string, requestTime time.Time) {
-	accessRequestsApproved.WithLabelValues(cluster, user, environment, approver).Inc()
+	accessRequestsApproved.WithLabelValues(cluster, userLabelValue(user), environment, approver).Inc()
 	accessRequestDuration.WithLabelValues(cluster, environment, "approved").Observe(time.Since(requestTime).Seconds())
 }
 
+// RecordApprovalLatency observes how long an approver took to approve a request since it was created.
+// The approver label follows the configured user label mode.
+func RecordApprovalLatency(approver string, latency time.Duration) {
+	approvalLatency.WithLabelValues(userLabelValue(approver)).Observe(latency.Seconds())
+}
+
 func RecordAccessRequestDenial(cluster, user, environment, reason string, requestTime time.Time) {
-	accessRequestsDenied.WithLabelValues(cluster, user, environment, reason).Inc()
+	accessRequestsDenied.WithLabelValues(cluster, userLabelValue(user), environment, reason).Inc()
 	accessRequestDuration.WithLabelValues(cluster, environment, "denied").Observe(time.Since(requestTime).Seconds())
 }
 
+// RecordAccessRequestFailure counts requests that gave up provisioning; reason is a condition reason
+func RecordAccessRequestFailure(cluster, reason string) {
+	accessRequestsFailed.WithLabelValues(cluster, reason).Inc()
+}
+
+// RecordPhaseTransition counts a request moving between phases; a new request's empty phase is
+// recorded as "New"
+func RecordPhaseTransition(from, to string) {
+	if from == "" {
+		from = "New"
+	}
+	requestPhaseTransitions.WithLabelValues(from, to).Inc()
+}
+
 func SetActiveAccessSessions(cluster, environment, permissionLevel string, count int) {
 	activeAccessSessions.WithLabelValues(cluster, environment, permissionLevel).Set(float64(count))
 }
@@ -253,6 +425,10 @@ func RecordAccessSessionCompletion(cluster, environment string, permissions []st
 	accessSessionDuration.WithLabelValues(cluster, environment, permList).Observe(duration.Seconds())
 }
 
+func RecordProvisionDuration(cluster, environment string, duration time.Duration) {
+	provisionDuration.WithLabelValues(cluster, environment).Observe(duration.Seconds())
+}
+
 // Webhook Metrics Functions
 
 func RecordWebhookRequest(webhookType, operation, status string, duration time.Duration) {
@@ -275,10 +451,16 @@ func RecordAWSAPIError(service, operation, errorCode, region string) {
 	awsAPIErrors.WithLabelValues(service, operation, errorCode, region).Inc()
 }
 
+// RecordDurationClamped counts a grant longer than the STS session limit, whose credentials expire
+// before the requested duration
+func RecordDurationClamped(cluster string) {
+	durationClampedTotal.WithLabelValues(cluster).Inc()
+}
+
 // Slack Metrics Functions
 
 func RecordSlackCommand(command, user, channel, status string, duration time.Duration) {
-	slackCommandsTotal.WithLabelValues(command, user, channel, status).Inc()
+	slackCommandsTotal.WithLabelValues(command, userLabelValue(user), channel, status).Inc()
 	slackCommandDuration.WithLabelValues(command).Observe(duration.Seconds())
 }
 
@@ -297,14 +479,44 @@ func RecordControllerError(controller, errorType string) {
 	controllerErrors.WithLabelValues(controller, errorType).Inc()
 }
 
+// RecordSecretCreated counts secrets created for access jobs; secretType is credentials or kubeconfig
+func RecordSecretCreated(secretType string) {
+	secretsCreated.WithLabelValues(secretType).Inc()
+}
+
+// RecordSecretDeleted counts access job secrets deleted on expiry; secretType is credentials or kubeconfig
+func RecordSecretDeleted(secretType string) {
+	secretsDeleted.WithLabelValues(secretType).Inc()
+}
+
 // Security Metrics Functions
 
 func RecordSecurityViolation(violationType, user, cluster string) {
-	securityViolationsTotal.WithLabelValues(violationType, user, cluster).Inc()
+	securityViolationsTotal.WithLabelValues(violationType, userLabelValue(user), cluster).Inc()
 }
 
 func RecordPrivilegeEscalationAttempt(user, fromPerm, toPerm, cluster string) {
-	privilegeEscalationAttempts.WithLabelValues(user, fromPerm, toPerm, cluster).Inc()
+	privilegeEscalationAttempts.WithLabelValues(userLabelValue(user), fromPerm, toPerm, cluster).Inc()
+}
+
+// SetAccessDrift records how many of the cluster's access entries the last scan found out of sync;
+// driftType is orphaned or missing
+func SetAccessDrift(cluster, driftType string, count int) {
+	accessDrift.WithLabelValues(cluster, driftType).Set(float64(count))
+}
+
+// SetKillSwitchEngaged records whether the kill switch is currently engaged
+func SetKillSwitchEngaged(engaged bool) {
+	value := 0.0
+	if engaged {
+		value = 1.0
+	}
+	killSwitchEngaged.Set(value)
+}
+
+// RecordKillSwitchAction counts a request ended by the kill switch; phase is Revoked or Denied
+func RecordKillSwitchAction(phase string) {
+	killSwitchActionsTotal.WithLabelValues(phase).Inc()
 }
 
 // System Health Functions
@@ -321,8 +533,27 @@ func SetLastSuccessfulBackup(timestamp time.Time) {
 	lastSuccessfulBackup.Set(float64(timestamp.Unix()))
 }
 
+func SetBuildInfo(version, commit, buildDate string) {
+	buildInfo.Reset()
+	buildInfo.WithLabelValues(version, commit, buildDate).Set(1)
+}
+
 // Helper Functions
 
+// userLabelValue returns the label value for user according to the configured mode
+func userLabelValue(user string) string {
+	switch userLabelMode {
+	case UserLabelDropped:
+		return droppedUserLabel
+	case UserLabelHashed:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(user))
+		return fmt.Sprintf("bucket-%02d", h.Sum32()%userLabelBuckets)
+	default:
+		return user
+	}
+}
+
 func joinPermissions(permissions []string) string {
 	if len(permissions) == 0 {
 		return "none"
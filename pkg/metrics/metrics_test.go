@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -99,6 +100,33 @@ func TestRecordAccessRequestApproval(t *testing.T) {
 	}
 }
 
+func TestRecordApprovalLatency(t *testing.T) {
+	resetMetrics()
+	t.Cleanup(func() { userLabelMode = UserLabelRaw })
+
+	RecordApprovalLatency("U0APPROVER1", 10*time.Minute)
+
+	histogram, ok := approvalLatency.WithLabelValues("U0APPROVER1").(prometheus.Histogram)
+	require.True(t, ok)
+	metric := &dto.Metric{}
+	require.NoError(t, histogram.Write(metric))
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+	assert.InDelta(t, 600, metric.GetHistogram().GetSampleSum(), 5)
+
+	// Hashed mode buckets approvers instead of exposing their IDs
+	require.NoError(t, SetUserLabelMode(UserLabelHashed))
+	RecordApprovalLatency("U0APPROVER1", 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(approvalLatency, "jit_approval_latency_seconds"))
+
+	hashed := userLabelValue("U0APPROVER1")
+	assert.True(t, strings.HasPrefix(hashed, "bucket-"))
+	histogram, ok = approvalLatency.WithLabelValues(hashed).(prometheus.Histogram)
+	require.True(t, ok)
+	metric = &dto.Metric{}
+	require.NoError(t, histogram.Write(metric))
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+}
+
 func TestRecordAccessRequestDenial(t *testing.T) {
 	// Reset metrics before test
 	resetMetrics()
@@ -120,6 +148,22 @@ func TestRecordAccessRequestDenial(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRecordAccessRequestRiskScore(t *testing.T) {
+	resetMetrics()
+
+	RecordAccessRequestRiskScore("prod-east-1", "production", 57)
+	RecordAccessRequestRiskScore("prod-east-1", "production", 57)
+
+	metricName := "jit_access_request_risk_scores_total"
+	expected := `
+		# HELP jit_access_request_risk_scores_total JIT access requests created, by webhook-assigned risk score
+		# TYPE jit_access_request_risk_scores_total counter
+		jit_access_request_risk_scores_total{cluster="prod-east-1",environment="production",risk_score="57"} 2
+	`
+	err := testutil.CollectAndCompare(accessRequestRiskScores, strings.NewReader(expected), metricName)
+	assert.NoError(t, err)
+}
+
 func TestSetActiveAccessSessions(t *testing.T) {
 	// Reset metrics before test
 	resetMetrics()
@@ -165,6 +209,24 @@ func TestSetActiveAccessSessions(t *testing.T) {
 	}
 }
 
+func TestRecordProvisionDuration(t *testing.T) {
+	// Reset metrics before test
+	resetMetrics()
+
+	start := time.Now().Add(-3 * time.Second)
+	RecordProvisionDuration("prod-east-1", "production", time.Since(start))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(provisionDuration, "jit_provision_duration_seconds"))
+
+	observer := provisionDuration.WithLabelValues("prod-east-1", "production")
+	metric := &dto.Metric{}
+	err := observer.(prometheus.Histogram).Write(metric)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+	assert.GreaterOrEqual(t, metric.GetHistogram().GetSampleSum(), 3.0)
+}
+
 func TestRecordWebhookRequest(t *testing.T) {
 	// Reset metrics before test
 	resetMetrics()
@@ -247,6 +309,62 @@ func TestRecordAWSAPIError(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRecordDurationClamped(t *testing.T) {
+	resetMetrics()
+
+	RecordDurationClamped("prod-east-1")
+
+	metricName := "jit_duration_clamped_total"
+	expected := `
+		# HELP jit_duration_clamped_total Grants whose AWS credentials expire before the requested duration
+		# TYPE jit_duration_clamped_total counter
+		jit_duration_clamped_total{cluster="prod-east-1"} 1
+	`
+	err := testutil.CollectAndCompare(durationClampedTotal, strings.NewReader(expected), metricName)
+	assert.NoError(t, err)
+}
+
+func TestRecordPhaseTransition(t *testing.T) {
+	resetMetrics()
+
+	RecordPhaseTransition("", "Pending")
+	RecordPhaseTransition("Pending", "Approved")
+	RecordPhaseTransition("Pending", "Approved")
+
+	expected := `
+		# HELP jit_request_phase_transitions_total Total number of JIT access request phase changes
+		# TYPE jit_request_phase_transitions_total counter
+		jit_request_phase_transitions_total{from="New",to="Pending"} 1
+		jit_request_phase_transitions_total{from="Pending",to="Approved"} 2
+	`
+	metricName := "jit_request_phase_transitions_total"
+	err := testutil.CollectAndCompare(requestPhaseTransitions, strings.NewReader(expected), metricName)
+	assert.NoError(t, err)
+}
+
+func TestKillSwitchMetrics(t *testing.T) {
+	resetMetrics()
+
+	SetKillSwitchEngaged(true)
+	RecordKillSwitchAction("Revoked")
+	RecordKillSwitchAction("Denied")
+	RecordKillSwitchAction("Denied")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(killSwitchEngaged))
+	expected := `
+		# HELP jit_kill_switch_actions_total Total number of requests revoked or denied by the kill switch
+		# TYPE jit_kill_switch_actions_total counter
+		jit_kill_switch_actions_total{phase="Denied"} 2
+		jit_kill_switch_actions_total{phase="Revoked"} 1
+	`
+	metricName := "jit_kill_switch_actions_total"
+	err := testutil.CollectAndCompare(killSwitchActionsTotal, strings.NewReader(expected), metricName)
+	assert.NoError(t, err)
+
+	SetKillSwitchEngaged(false)
+	assert.Equal(t, 0.0, testutil.ToFloat64(killSwitchEngaged))
+}
+
 func TestRecordSlackCommand(t *testing.T) {
 	// Reset metrics before test
 	resetMetrics()
@@ -405,6 +523,67 @@ func TestSetSystemHealthStatus(t *testing.T) {
 	}
 }
 
+func TestSetBuildInfo(t *testing.T) {
+	resetMetrics()
+
+	SetBuildInfo("v0.1.0", "abc123", "2024-01-01T00:00:00Z")
+	SetBuildInfo("v0.2.0", "def456", "2024-02-01T00:00:00Z")
+
+	// Only the latest build should be reported
+	assert.Equal(t, 1, testutil.CollectAndCount(buildInfo))
+	assert.Equal(t, 1.0, testutil.ToFloat64(buildInfo.WithLabelValues("v0.2.0", "def456", "2024-02-01T00:00:00Z")))
+}
+
+func TestUserLabelValue(t *testing.T) {
+	t.Cleanup(func() { userLabelMode = UserLabelRaw })
+
+	t.Run("raw keeps the user ID", func(t *testing.T) {
+		require.NoError(t, SetUserLabelMode(UserLabelRaw))
+		assert.Equal(t, "U123456789A", userLabelValue("U123456789A"))
+	})
+
+	t.Run("dropped returns a constant", func(t *testing.T) {
+		require.NoError(t, SetUserLabelMode(UserLabelDropped))
+		assert.Equal(t, droppedUserLabel, userLabelValue("U123456789A"))
+		assert.Equal(t, droppedUserLabel, userLabelValue("U987654321B"))
+	})
+
+	t.Run("hashed buckets users", func(t *testing.T) {
+		require.NoError(t, SetUserLabelMode(UserLabelHashed))
+		value := userLabelValue("U123456789A")
+		assert.Regexp(t, `^bucket-\d{2}$`, value)
+		assert.Equal(t, value, userLabelValue("U123456789A"), "hashing must be stable")
+
+		buckets := make(map[string]bool)
+		for i := 0; i < 1000; i++ {
+			buckets[userLabelValue(fmt.Sprintf("U%010d", i))] = true
+		}
+		assert.LessOrEqual(t, len(buckets), userLabelBuckets)
+	})
+
+	t.Run("empty mode defaults to raw", func(t *testing.T) {
+		require.NoError(t, SetUserLabelMode(""))
+		assert.Equal(t, "U123456789A", userLabelValue("U123456789A"))
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		assert.Error(t, SetUserLabelMode("sometimes"))
+	})
+}
+
+func TestRecordAccessRequestDroppedUserLabel(t *testing.T) {
+	resetMetrics()
+	require.NoError(t, SetUserLabelMode(UserLabelDropped))
+	t.Cleanup(func() { userLabelMode = UserLabelRaw })
+
+	RecordAccessRequest("prod-east-1", "U123456789A", "production", []string{"view"})
+	RecordAccessRequest("prod-east-1", "U987654321B", "production", []string{"view"})
+
+	assert.Equal(t, 1, testutil.CollectAndCount(accessRequestsTotal))
+	assert.Equal(t, 2.0, testutil.ToFloat64(
+		accessRequestsTotal.WithLabelValues("prod-east-1", droppedUserLabel, "production", "view")))
+}
+
 func TestJoinPermissions(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -505,14 +684,21 @@ func resetMetrics() {
 	accessRequestsTotal.Reset()
 	accessRequestsApproved.Reset()
 	accessRequestsDenied.Reset()
+	accessRequestRiskScores.Reset()
+	requestPhaseTransitions.Reset()
 	activeAccessSessions.Reset()
 	accessRequestDuration.Reset()
+	approvalLatency.Reset()
+	provisionDuration.Reset()
+	buildInfo.Reset()
 	webhookRequestsTotal.Reset()
 	webhookRequestDuration.Reset()
 	webhookValidationErrors.Reset()
 	awsAPICalls.Reset()
 	awsAPIDuration.Reset()
 	awsAPIErrors.Reset()
+	durationClampedTotal.Reset()
+	killSwitchActionsTotal.Reset()
 	slackCommandsTotal.Reset()
 	slackCommandDuration.Reset()
 	slackAPIErrors.Reset()
@@ -14,6 +14,9 @@ type Cluster struct {
 	Tags              map[string]string `json:"tags"`
 	MaxDuration       time.Duration     `json:"max_duration"`
 	RequiredApprovers int               `json:"required_approvers"`
+	MaxActiveSessions int               `json:"max_active_sessions,omitempty"` // across all users; 0 = unlimited
+	AccessSchedule    *AccessSchedule   `json:"access_schedule,omitempty"`
+	UsernameTemplate  string            `json:"username_template,omitempty"` // e.g. jit:{user} or {email}
 	Enabled           bool              `json:"enabled"`
 	CreatedAt         time.Time         `json:"created_at"`
 	UpdatedAt         time.Time         `json:"updated_at"`
@@ -25,16 +28,23 @@ type ClusterAccess struct {
 	ClusterID    string        `json:"cluster_id"`
 	UserID       string        `json:"user_id"`
 	UserEmail    string        `json:"user_email"`
+	RequestedBy  string        `json:"requested_by,omitempty"`
+	RequestName  string        `json:"request_name,omitempty"` // JITAccessRequest the access was granted for
 	Reason       string        `json:"reason"`
+	Permissions  []string      `json:"permissions,omitempty"`
+	SessionName  string        `json:"session_name,omitempty"`
 	Duration     time.Duration `json:"duration"`
 	Status       AccessStatus  `json:"status"`
 	ApprovedBy   []string      `json:"approved_by"`
 	RequestedAt  time.Time     `json:"requested_at"`
 	ApprovedAt   *time.Time    `json:"approved_at,omitempty"`
+	GrantedAt    *time.Time    `json:"granted_at,omitempty"`
 	ExpiresAt    *time.Time    `json:"expires_at,omitempty"`
 	RevokedAt    *time.Time    `json:"revoked_at,omitempty"`
 	RevokedBy    string        `json:"revoked_by,omitempty"`
 	RevokeReason string        `json:"revoke_reason,omitempty"`
+	CostCenter   string        `json:"cost_center,omitempty"`
+	Team         string        `json:"team,omitempty"`
 }
 
 type AccessStatus string
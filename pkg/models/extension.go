@@ -0,0 +1,36 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinRemainingAccess is the shortest remaining access an extension may leave. Extensions that
+// would leave less, e.g. because of clock skew, are clamped up to this floor.
+const MinRemainingAccess = 15 * time.Minute
+
+// ExtendedExpiry computes the expiry after extending the access by extension, measured from the
+// current expiry (or now when none is set). Extensions that are not positive or that would
+// still leave the access expired are rejected.
+func (a *ClusterAccess) ExtendedExpiry(extension time.Duration, now time.Time) (time.Time, error) {
+	if extension <= 0 {
+		return time.Time{}, fmt.Errorf("extension must be positive, got %s", extension)
+	}
+
+	base := now
+	if a.ExpiresAt != nil {
+		base = *a.ExpiresAt
+	}
+
+	expiry := base.Add(extension)
+	if !expiry.After(now) {
+		return time.Time{}, fmt.Errorf("extended expiry %s is already in the past",
+			expiry.UTC().Format(time.RFC3339))
+	}
+
+	if expiry.Sub(now) < MinRemainingAccess {
+		return now.Add(MinRemainingAccess), nil
+	}
+
+	return expiry, nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtendedExpiry(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) *time.Time {
+		ts := now.Add(offset)
+		return &ts
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		extension time.Duration
+		want      time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "normal extension of active access",
+			expiresAt: at(30 * time.Minute),
+			extension: time.Hour,
+			want:      now.Add(90 * time.Minute),
+		},
+		{
+			name:      "past-dated extension is rejected",
+			expiresAt: at(-3 * time.Hour),
+			extension: time.Hour,
+			wantErr:   true,
+		},
+		{
+			name:      "extension ending exactly now is rejected",
+			expiresAt: at(-time.Hour),
+			extension: time.Hour,
+			wantErr:   true,
+		},
+		{
+			name:      "tiny extension is clamped to the floor",
+			expiresAt: at(-5 * time.Minute),
+			extension: 10 * time.Minute,
+			want:      now.Add(MinRemainingAccess),
+		},
+		{
+			name:      "access without expiry extends from now",
+			extension: time.Hour,
+			want:      now.Add(time.Hour),
+		},
+		{
+			name:      "negative extension is rejected",
+			expiresAt: at(time.Hour),
+			extension: -30 * time.Minute,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access := &ClusterAccess{ID: "access-1", ExpiresAt: tt.expiresAt}
+
+			got, err := access.ExtendedExpiry(tt.extension, now)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got expiry %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected expiry %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
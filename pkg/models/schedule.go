@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccessSchedule restricts when a cluster may be accessed without an emergency override
+type AccessSchedule struct {
+	// Timezone is an IANA location name, e.g. "America/New_York" (empty = UTC)
+	Timezone string           `json:"timezone"`
+	Windows  []ScheduleWindow `json:"windows"`
+}
+
+// ScheduleWindow is a daily time range, in HH:MM, on the given days.
+// A window whose end is before its start runs past midnight into the next day.
+type ScheduleWindow struct {
+	Days  []time.Weekday `json:"days"`
+	Start string         `json:"start"`
+	End   string         `json:"end"`
+}
+
+// Location resolves the schedule timezone
+func (s *AccessSchedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule timezone %q: %w", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// Allows reports whether t falls inside one of the schedule windows,
+// evaluated in the schedule's timezone rather than the server's
+func (s *AccessSchedule) Allows(t time.Time) (bool, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return false, err
+	}
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, window := range s.Windows {
+		start, err := parseClock(window.Start)
+		if err != nil {
+			return false, err
+		}
+		end, err := parseClock(window.End)
+		if err != nil {
+			return false, err
+		}
+
+		if start <= end {
+			if containsWeekday(window.Days, local.Weekday()) && minute >= start && minute < end {
+				return true, nil
+			}
+			continue
+		}
+
+		// Overnight window: the late part belongs to the listed day, the early part to the day after
+		if containsWeekday(window.Days, local.Weekday()) && minute >= start {
+			return true, nil
+		}
+		previous := (local.Weekday() + 6) % 7
+		if containsWeekday(window.Days, previous) && minute < end {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// String describes the allowed windows, e.g. "Mon,Tue 09:00-17:00 (Europe/London)"
+func (s *AccessSchedule) String() string {
+	windows := make([]string, 0, len(s.Windows))
+	for _, window := range s.Windows {
+		days := make([]string, 0, len(window.Days))
+		for _, day := range window.Days {
+			days = append(days, day.String()[:3])
+		}
+		windows = append(windows, fmt.Sprintf("%s %s-%s", strings.Join(days, ","), window.Start, window.End))
+	}
+
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(windows, "; "), timezone)
+}
+
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule time %q, expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
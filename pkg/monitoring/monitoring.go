@@ -2,7 +2,10 @@ package monitoring
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -22,12 +25,37 @@ const (
 
 var logger = log.Log.WithName("monitoring")
 
+// Build information, injected at build time via
+// -ldflags "-X github.com/rebelopsio/jit-bot/pkg/monitoring.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "none"
+	BuildDate = "unknown"
+)
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+}
+
+// GetBuildInfo returns the build information injected at build time
+func GetBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+	}
+}
+
 // Config holds monitoring configuration
 type Config struct {
 	MetricsEnabled bool                    `yaml:"metrics"     json:"metrics"`
 	MetricsPort    int                     `yaml:"metricsPort" json:"metricsPort"`
 	HealthPort     int                     `yaml:"healthPort"  json:"healthPort"`
 	Tracing        telemetry.TracingConfig `yaml:"tracing"     json:"tracing"`
+	UserLabel      string                  `yaml:"userLabel"   json:"userLabel"` // raw, hashed or dropped
 }
 
 // Monitor manages metrics and tracing
@@ -36,6 +64,9 @@ type Monitor struct {
 	tracerProvider *trace.TracerProvider
 	metricsServer  *http.Server
 	healthServer   *http.Server
+
+	// listeners are closed by Stop even if their server has not started serving on them yet
+	listeners []net.Listener
 }
 
 // NewMonitor creates a new monitoring instance
@@ -45,8 +76,24 @@ func NewMonitor(config Config) *Monitor {
 	}
 }
 
-// Start initializes and starts monitoring services
-func (m *Monitor) Start(ctx context.Context) error {
+// stopTimeout bounds how long the servers drain when the Start context ends
+const stopTimeout = 10 * time.Second
+
+// Start initializes and starts monitoring services. They are stopped when ctx is done, and
+// anything already started is stopped again if Start fails.
+func (m *Monitor) Start(ctx context.Context) (err error) {
+	if err := metrics.SetUserLabelMode(metrics.UserLabelMode(m.config.UserLabel)); err != nil {
+		return fmt.Errorf("failed to configure metrics: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if stopErr := m.Stop(context.Background()); stopErr != nil {
+				logger.Error(stopErr, "Failed to stop monitoring after a failed start")
+			}
+		}
+	}()
+
 	// Initialize tracing
 	if m.config.Tracing.Enabled {
 		tp, err := telemetry.InitTracing(ctx, m.config.Tracing)
@@ -59,17 +106,30 @@ func (m *Monitor) Start(ctx context.Context) error {
 
 	// Start metrics server
 	if m.config.MetricsEnabled {
-		m.startMetricsServer()
+		if err := m.startMetricsServer(); err != nil {
+			return err
+		}
 		logger.Info("Metrics server started", "port", m.config.MetricsPort)
 	}
 
 	// Start health server
-	m.startHealthServer()
+	if err := m.startHealthServer(); err != nil {
+		return err
+	}
 	logger.Info("Health server started", "port", m.config.HealthPort)
 
 	// Initialize system health checks
 	m.initializeHealthChecks()
 
+	go func() {
+		<-ctx.Done()
+		stopCtx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+		defer cancel()
+		if err := m.Stop(stopCtx); err != nil {
+			logger.Error(err, "Failed to stop monitoring")
+		}
+	}()
+
 	return nil
 }
 
@@ -91,6 +151,13 @@ func (m *Monitor) Stop(ctx context.Context) error {
 		}
 	}
 
+	// Shutdown only closes listeners a server is already serving on
+	for _, listener := range m.listeners {
+		if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, fmt.Errorf("listener close failed: %w", err))
+		}
+	}
+
 	// Shutdown tracer provider
 	if m.tracerProvider != nil {
 		if err := m.tracerProvider.Shutdown(ctx); err != nil {
@@ -105,7 +172,9 @@ func (m *Monitor) Stop(ctx context.Context) error {
 	return nil
 }
 
-func (m *Monitor) startMetricsServer() {
+// startMetricsServer binds the metrics port before serving in the background, so a port
+// conflict fails Start instead of silently disabling metrics
+func (m *Monitor) startMetricsServer() error {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -118,14 +187,45 @@ func (m *Monitor) startMetricsServer() {
 		IdleTimeout:       60 * time.Second,
 	}
 
+	listener, err := net.Listen("tcp", m.metricsServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind metrics server: %w", err)
+	}
+	m.listeners = append(m.listeners, listener)
+
 	go func() {
-		if err := m.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := m.metricsServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 			logger.Error(err, "Metrics server failed")
 		}
 	}()
+	return nil
+}
+
+func (m *Monitor) startHealthServer() error {
+	m.healthServer = &http.Server{
+		Addr:              fmt.Sprintf(":%d", m.config.HealthPort),
+		Handler:           m.healthHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", m.healthServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind health server: %w", err)
+	}
+	m.listeners = append(m.listeners, listener)
+
+	go func() {
+		if err := m.healthServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error(err, "Health server failed")
+		}
+	}()
+	return nil
 }
 
-func (m *Monitor) startHealthServer() {
+func (m *Monitor) healthHandler() http.Handler {
 	mux := http.NewServeMux()
 
 	// Add health check endpoints
@@ -159,20 +259,14 @@ func (m *Monitor) startHealthServer() {
 		}
 	})
 
-	m.healthServer = &http.Server{
-		Addr:              fmt.Sprintf(":%d", m.config.HealthPort),
-		Handler:           mux,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       30 * time.Second,
-		WriteTimeout:      30 * time.Second,
-		IdleTimeout:       60 * time.Second,
-	}
-
-	go func() {
-		if err := m.healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error(err, "Health server failed")
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(GetBuildInfo()); err != nil {
+			logger.Error(err, "Failed to write version response")
 		}
-	}()
+	})
+
+	return mux
 }
 
 func (m *Monitor) initializeHealthChecks() {
@@ -181,6 +275,9 @@ func (m *Monitor) initializeHealthChecks() {
 	metrics.SetSystemHealthStatus("webhook", true)
 	metrics.SetSystemHealthStatus("aws", true)
 	metrics.SetSystemHealthStatus("slack", true)
+
+	info := GetBuildInfo()
+	metrics.SetBuildInfo(info.Version, info.GitCommit, info.BuildDate)
 }
 
 func (m *Monitor) isSystemReady() bool {
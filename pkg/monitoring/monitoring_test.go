@@ -0,0 +1,131 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionEndpoint(t *testing.T) {
+	originalVersion, originalCommit, originalDate := Version, GitCommit, BuildDate
+	t.Cleanup(func() {
+		Version, GitCommit, BuildDate = originalVersion, originalCommit, originalDate
+	})
+	Version, GitCommit, BuildDate = "v1.2.3", "abc1234", "2024-01-01T00:00:00Z"
+
+	monitor := NewMonitor(Config{})
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	rec := httptest.NewRecorder()
+
+	monitor.healthHandler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "v1.2.3", body["version"])
+	assert.Equal(t, "abc1234", body["gitCommit"])
+	assert.Equal(t, "2024-01-01T00:00:00Z", body["buildDate"])
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
+
+func TestStartFailsWhenMetricsPortInUse(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = occupied.Close() })
+
+	monitor := NewMonitor(Config{
+		MetricsEnabled: true,
+		MetricsPort:    occupied.Addr().(*net.TCPAddr).Port,
+		HealthPort:     freePort(t),
+	})
+	t.Cleanup(func() { _ = monitor.Stop(context.Background()) })
+
+	err = monitor.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to bind metrics server")
+}
+
+func TestStartServesMetricsAndHealth(t *testing.T) {
+	monitor := NewMonitor(Config{
+		MetricsEnabled: true,
+		MetricsPort:    freePort(t),
+		HealthPort:     freePort(t),
+	})
+	require.NoError(t, monitor.Start(context.Background()))
+	t.Cleanup(func() { _ = monitor.Stop(context.Background()) })
+
+	for port, path := range map[int]string{
+		monitor.config.MetricsPort: "/metrics",
+		monitor.config.HealthPort:  "/healthz",
+	} {
+		url := "http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) + path
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, url)
+	}
+}
+
+func TestStartReleasesMetricsPortWhenHealthPortInUse(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = occupied.Close() })
+
+	metricsPort := freePort(t)
+	monitor := NewMonitor(Config{
+		MetricsEnabled: true,
+		MetricsPort:    metricsPort,
+		HealthPort:     occupied.Addr().(*net.TCPAddr).Port,
+	})
+
+	err = monitor.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to bind health server")
+
+	// The metrics listener opened before the failure is closed again
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(metricsPort)))
+	require.NoError(t, err)
+	_ = listener.Close()
+}
+
+func TestStartStopsWhenContextIsDone(t *testing.T) {
+	metricsPort, healthPort := freePort(t), freePort(t)
+	monitor := NewMonitor(Config{MetricsEnabled: true, MetricsPort: metricsPort, HealthPort: healthPort})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, monitor.Start(ctx))
+	cancel()
+
+	for _, port := range []int{metricsPort, healthPort} {
+		assert.Eventually(t, func() bool {
+			listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+			if err != nil {
+				return false
+			}
+			_ = listener.Close()
+			return true
+		}, 5*time.Second, 10*time.Millisecond, "port %d", port)
+	}
+}
@@ -0,0 +1,108 @@
+package slack
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned instead of calling Slack while the circuit breaker is open
+var ErrCircuitOpen = errors.New("slack API circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker stops calling the Slack API after consecutive failures so a rate-limited or
+// unavailable Slack is not hammered. After the cooldown a single trial call is let through
+// (half-open); its outcome closes or re-opens the breaker.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	cooldown         time.Duration
+	state            breakerState
+	failures         int
+	openedAt         time.Time
+	now              func() time.Time
+}
+
+// NewCircuitBreaker creates a breaker that opens after failureThreshold consecutive failures
+// and half-opens after cooldown. Non-positive values fall back to the defaults.
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = defaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// Call runs fn unless the breaker is open. Rejected calls are recorded as "circuit_open"
+// Slack API errors and return ErrCircuitOpen.
+func (b *CircuitBreaker) Call(operation string, fn func() error) error {
+	if !b.allow() {
+		metrics.RecordSlackAPIError(operation, "circuit_open")
+		return ErrCircuitOpen
+	}
+
+	err := fn()
+	b.record(err)
+	if err != nil {
+		metrics.RecordSlackAPIError(operation, "request_failed")
+	}
+	return err
+}
+
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		// Let a single trial call through
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// A trial call is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
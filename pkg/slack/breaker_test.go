@@ -0,0 +1,149 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func slackAPIErrorCount(t *testing.T, operation, errorType string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "jit_slack_api_errors_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["operation"] == operation && labels["error_type"] == errorType {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)}
+	breaker := NewCircuitBreaker(threshold, cooldown)
+	breaker.now = clock.Now
+	return breaker, clock
+}
+
+func TestCircuitBreakerTripsAndRecovers(t *testing.T) {
+	breaker, clock := newTestBreaker(3, time.Minute)
+	failing := errors.New("ratelimited")
+	calls := 0
+	fail := func() error { calls++; return failing }
+	succeed := func() error { calls++; return nil }
+
+	before := slackAPIErrorCount(t, "test.trip", "circuit_open")
+
+	// Consecutive failures up to the threshold are passed through
+	for i := 0; i < 3; i++ {
+		if err := breaker.Call("test.trip", fail); !errors.Is(err, failing) {
+			t.Fatalf("Call %d: expected Slack error, got %v", i, err)
+		}
+	}
+
+	// The breaker is now open and short-circuits without calling Slack
+	if err := breaker.Call("test.trip", succeed); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected open breaker to skip the call, got %d calls", calls)
+	}
+	if got := slackAPIErrorCount(t, "test.trip", "circuit_open") - before; got != 1 {
+		t.Errorf("Expected 1 circuit_open error recorded, got %v", got)
+	}
+
+	// After the cooldown a trial call is let through and a success closes the breaker
+	clock.now = clock.now.Add(time.Minute)
+	if err := breaker.Call("test.trip", succeed); err != nil {
+		t.Fatalf("Expected half-open trial call to succeed, got %v", err)
+	}
+	if err := breaker.Call("test.trip", succeed); err != nil {
+		t.Fatalf("Expected closed breaker to allow calls, got %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("Expected 5 calls after recovery, got %d", calls)
+	}
+}
+
+func TestCircuitBreakerReopensOnFailedTrial(t *testing.T) {
+	breaker, clock := newTestBreaker(1, time.Minute)
+	failing := errors.New("unavailable")
+
+	_ = breaker.Call("test.reopen", func() error { return failing })
+
+	clock.now = clock.now.Add(time.Minute)
+	if err := breaker.Call("test.reopen", func() error { return failing }); !errors.Is(err, failing) {
+		t.Fatalf("Expected trial call to reach Slack, got %v", err)
+	}
+
+	// The failed trial re-opens the breaker for a full cooldown
+	clock.now = clock.now.Add(30 * time.Second)
+	if err := breaker.Call("test.reopen", func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected breaker to re-open after failed trial, got %v", err)
+	}
+}
+
+func TestCircuitBreakerResetsFailuresOnSuccess(t *testing.T) {
+	breaker, _ := newTestBreaker(2, time.Minute)
+	failing := errors.New("timeout")
+
+	_ = breaker.Call("test.reset", func() error { return failing })
+	_ = breaker.Call("test.reset", func() error { return nil })
+	_ = breaker.Call("test.reset", func() error { return failing })
+
+	// Failures were not consecutive, so the breaker stays closed
+	if err := breaker.Call("test.reset", func() error { return nil }); err != nil {
+		t.Errorf("Expected closed breaker, got %v", err)
+	}
+}
+
+func TestNotifierStopsCallingSlackWhenBreakerOpen(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{FailureThreshold: 2, Cooldown: "1m"})
+	notifier.apiURL = server.URL
+
+	req := newProdRequest()
+	req.Spec.Approvers = []string{"U0APPROVER1"}
+
+	for i := 0; i < 5; i++ {
+		if err := notifier.NotifyPendingRequest(context.Background(), req); err == nil {
+			t.Fatalf("Notification %d: expected error while Slack is rate limiting", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected Slack to be called 2 times before the breaker opened, got %d", got)
+	}
+}
@@ -0,0 +1,77 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ChannelChecker looks up Slack channels with the bot token, so the operator can revoke
+// sessions requested from channels that have since been archived
+type ChannelChecker struct {
+	token      string
+	apiURL     string
+	httpClient *http.Client
+	breaker    *CircuitBreaker
+}
+
+// NewChannelChecker creates a checker that calls conversations.info with the given bot token
+func NewChannelChecker(token string) *ChannelChecker {
+	return &ChannelChecker{
+		token:      token,
+		apiURL:     defaultSlackAPIURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		breaker:    NewCircuitBreaker(0, 0),
+	}
+}
+
+// IsChannelArchived reports whether the channel has been archived. A channel the bot cannot see
+// is an error rather than archived, so a missing invite never revokes access.
+func (c *ChannelChecker) IsChannelArchived(ctx context.Context, channelID string) (bool, error) {
+	var archived bool
+	err := c.breaker.Call("conversations.info", func() error {
+		var lookupErr error
+		archived, lookupErr = c.lookupArchived(ctx, channelID)
+		return lookupErr
+	})
+	return archived, err
+}
+
+func (c *ChannelChecker) lookupArchived(ctx context.Context, channelID string) (bool, error) {
+	endpoint := c.apiURL + "/conversations.info?channel=" + url.QueryEscape(channelID)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build slack request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up slack channel: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return false, fmt.Errorf("slack rate limited lookup of %s (retry after %ss)",
+			channelID, resp.Header.Get("Retry-After"))
+	}
+
+	var result struct {
+		OK      bool   `json:"ok"`
+		Error   string `json:"error"`
+		Channel struct {
+			IsArchived bool `json:"is_archived"`
+		} `json:"channel"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return false, fmt.Errorf("slack rejected lookup of %s: %s", channelID, result.Error)
+	}
+
+	return result.Channel.IsArchived, nil
+}
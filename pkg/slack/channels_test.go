@@ -0,0 +1,49 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChannelCheckerIsChannelArchived(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.info" {
+			t.Errorf("Unexpected Slack API path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("Expected bot token authorization, got %q", r.Header.Get("Authorization"))
+		}
+
+		switch channel := r.URL.Query().Get("channel"); channel {
+		case "C0ARCHIVED", "C0OPEN":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"ok":      true,
+				"channel": map[string]interface{}{"id": channel, "is_archived": channel == "C0ARCHIVED"},
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "channel_not_found"})
+		}
+	}))
+	defer server.Close()
+
+	checker := NewChannelChecker("xoxb-test")
+	checker.apiURL = server.URL
+
+	archived, err := checker.IsChannelArchived(t.Context(), "C0ARCHIVED")
+	if err != nil || !archived {
+		t.Errorf("Expected C0ARCHIVED to be archived, got %v (err %v)", archived, err)
+	}
+
+	archived, err = checker.IsChannelArchived(t.Context(), "C0OPEN")
+	if err != nil || archived {
+		t.Errorf("Expected C0OPEN to be open, got %v (err %v)", archived, err)
+	}
+
+	// A channel the bot cannot see is an error, never archived
+	archived, err = checker.IsChannelArchived(t.Context(), "C0PRIVATE")
+	if err == nil || archived {
+		t.Errorf("Expected an error for an unknown channel, got %v (err %v)", archived, err)
+	}
+}
@@ -3,7 +3,10 @@ package slack
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,11 +19,17 @@ import (
 
 const (
 	cmdAdmin = "admin"
+
+	// defaultHistoryDays and maxHistoryDays bound the window of /jit history
+	defaultHistoryDays = 7
+	maxHistoryDays     = 90
 )
 
 type CommandHandler struct {
-	rbac  *auth.RBAC
-	store *store.MemoryStore
+	rbac          *auth.RBAC
+	store         *store.MemoryStore
+	responseTypes map[string]string
+	creds         *K8sCommandHandler // nil disables /jit creds
 }
 
 func NewCommandHandler(rbac *auth.RBAC, store *store.MemoryStore) *CommandHandler {
@@ -30,6 +39,25 @@ func NewCommandHandler(rbac *auth.RBAC, store *store.MemoryStore) *CommandHandle
 	}
 }
 
+// SetResponseTypes overrides whether a subcommand's successful response is ephemeral or
+// posted in_channel; subcommands not listed keep their default visibility
+func (h *CommandHandler) SetResponseTypes(responseTypes map[string]string) {
+	h.responseTypes = responseTypes
+}
+
+// SetCredentialsHandler enables /jit creds, which re-sends a requester's credentials from the
+// secrets the operator wrote for their JITAccessRequest
+func (h *CommandHandler) SetCredentialsHandler(creds *K8sCommandHandler) {
+	h.creds = creds
+}
+
+func (h *CommandHandler) responseType(subcommand, defaultType string) string {
+	if responseType, ok := h.responseTypes[subcommand]; ok {
+		return responseType
+	}
+	return defaultType
+}
+
 type SlackCommand struct {
 	Token       string `form:"token"`
 	TeamID      string `form:"team_id"`
@@ -71,7 +99,11 @@ func (h *CommandHandler) HandleJITCommand(w http.ResponseWriter, r *http.Request
 	case "list":
 		h.handleListClusters(w, cmd)
 	case "status":
-		h.handleStatus(w, cmd)
+		h.handleStatus(w, cmd, args)
+	case "history":
+		h.handleHistory(w, cmd, args)
+	case "creds":
+		h.handleCreds(w, r, cmd, args)
 	case cmdAdmin:
 		h.handleAdmin(w, cmd, args)
 	case "help":
@@ -121,7 +153,7 @@ func (h *CommandHandler) handleRequestAccess(w http.ResponseWriter, cmd SlackCom
 	}
 
 	response := map[string]interface{}{
-		"response_type": "in_channel",
+		"response_type": h.responseType("request", "in_channel"),
 		"text":          fmt.Sprintf("Access request submitted for cluster `%s`", cluster.DisplayName),
 		"attachments": []map[string]interface{}{
 			{
@@ -172,7 +204,7 @@ func (h *CommandHandler) handleListClusters(w http.ResponseWriter, _ SlackComman
 	}
 
 	response := map[string]interface{}{
-		"response_type": "ephemeral",
+		"response_type": h.responseType("list", "ephemeral"),
 		"text":          "Available clusters:",
 		"attachments": []map[string]interface{}{
 			{
@@ -188,7 +220,12 @@ func (h *CommandHandler) handleListClusters(w http.ResponseWriter, _ SlackComman
 	}
 }
 
-func (h *CommandHandler) handleStatus(w http.ResponseWriter, cmd SlackCommand) {
+func (h *CommandHandler) handleStatus(w http.ResponseWriter, cmd SlackCommand, args []string) {
+	if len(args) > 0 {
+		h.handleStatusDetail(w, cmd, args[0])
+		return
+	}
+
 	accesses, err := h.store.ListUserAccesses(cmd.UserID)
 	if err != nil {
 		h.sendError(w, "Failed to retrieve access status")
@@ -228,7 +265,7 @@ func (h *CommandHandler) handleStatus(w http.ResponseWriter, cmd SlackCommand) {
 	}
 
 	response := map[string]interface{}{
-		"response_type": "ephemeral",
+		"response_type": h.responseType("status", "ephemeral"),
 		"text":          "Your access requests:",
 		"attachments": []map[string]interface{}{
 			{
@@ -244,6 +281,208 @@ func (h *CommandHandler) handleStatus(w http.ResponseWriter, cmd SlackCommand) {
 	}
 }
 
+// handleStatusDetail shows one access, looked up by access ID or by the JITAccessRequest it was
+// granted for, with its approval trail. Only the user, whoever filed it for them, and approvers
+// may view it.
+func (h *CommandHandler) handleStatusDetail(w http.ResponseWriter, cmd SlackCommand, id string) {
+	access, err := h.store.GetAccess(id)
+	if err != nil {
+		access, err = h.store.GetAccessByRequestName(id)
+	}
+	if err != nil {
+		h.sendError(w, fmt.Sprintf("Request not found: %s", id))
+		return
+	}
+
+	if access.UserID != cmd.UserID && access.RequestedBy != cmd.UserID &&
+		!h.rbac.UserHasPermission(cmd.UserID, auth.PermissionApproveRequests) {
+		h.sendError(w, "You don't have permission to view this request")
+		return
+	}
+
+	response := map[string]interface{}{
+		"response_type": h.responseType("status", "ephemeral"),
+		"text":          formatAccessDetail(access, time.Now()),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func formatAccessDetail(access *models.ClusterAccess, now time.Time) string {
+	var detail strings.Builder
+
+	fmt.Fprintf(&detail, "*Request `%s`* - %s\n", access.ID, access.Status)
+	fmt.Fprintf(&detail, "👤 Requester: <@%s>\n", access.UserID)
+	if access.RequestedBy != "" && access.RequestedBy != access.UserID {
+		fmt.Fprintf(&detail, "📨 Filed by: <@%s>\n", access.RequestedBy)
+	}
+	fmt.Fprintf(&detail, "🎯 Cluster: %s\n", access.ClusterID)
+	if len(access.Permissions) > 0 {
+		fmt.Fprintf(&detail, "🔑 Permissions: %s\n", strings.Join(access.Permissions, ", "))
+	}
+	fmt.Fprintf(&detail, "⏱️ Duration: %s\n", access.Duration)
+	fmt.Fprintf(&detail, "📝 Reason: %s\n", access.Reason)
+	fmt.Fprintf(&detail, "🕐 Requested: %s\n", access.RequestedAt.UTC().Format(time.RFC3339))
+
+	if access.ExpiresAt != nil && access.Status == models.AccessStatusActive {
+		if remaining := access.ExpiresAt.Sub(now).Round(time.Minute); remaining > 0 {
+			fmt.Fprintf(&detail, "⌛ Remaining: %s (expires %s)\n",
+				remaining, access.ExpiresAt.UTC().Format(time.RFC3339))
+		} else {
+			fmt.Fprintf(&detail, "⌛ Expired: %s\n", access.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	detail.WriteString("\n*Approvals:*\n")
+	if len(access.ApprovedBy) == 0 {
+		detail.WriteString("• None yet\n")
+	}
+	for _, approver := range access.ApprovedBy {
+		fmt.Fprintf(&detail, "• <@%s>\n", approver)
+	}
+	if access.ApprovedAt != nil {
+		fmt.Fprintf(&detail, "Approved at %s\n", access.ApprovedAt.UTC().Format(time.RFC3339))
+	}
+
+	if access.RevokedAt != nil {
+		fmt.Fprintf(&detail, "\n🚫 Revoked by <@%s> at %s",
+			access.RevokedBy, access.RevokedAt.UTC().Format(time.RFC3339))
+		if access.RevokeReason != "" {
+			fmt.Fprintf(&detail, " - %q", access.RevokeReason)
+		}
+		detail.WriteString("\n")
+	}
+
+	return detail.String()
+}
+
+// handleHistory lists the caller's sessions that ended (expired or were revoked) within the
+// last N days, most recent first
+func (h *CommandHandler) handleHistory(w http.ResponseWriter, cmd SlackCommand, args []string) {
+	days := defaultHistoryDays
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 || parsed > maxHistoryDays {
+			h.sendError(w, fmt.Sprintf("Days must be a number between 1 and %d. Usage: `/jit history [days]`",
+				maxHistoryDays))
+			return
+		}
+		days = parsed
+	}
+
+	accesses, err := h.store.ListUserAccesses(cmd.UserID)
+	if err != nil {
+		h.sendError(w, "Failed to retrieve access history")
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	sessions := make([]*models.ClusterAccess, 0, len(accesses))
+	for _, access := range accesses {
+		if access.Status != models.AccessStatusExpired && access.Status != models.AccessStatusRevoked {
+			continue
+		}
+		if sessionEnd(access).Before(since) {
+			continue
+		}
+		sessions = append(sessions, access)
+	}
+
+	if len(sessions) == 0 {
+		h.sendMessage(w, fmt.Sprintf("You have no completed sessions in the last %d days.", days))
+		return
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessionEnd(sessions[i]).After(sessionEnd(sessions[j]))
+	})
+
+	fields := make([]map[string]interface{}, 0, len(sessions))
+	for _, access := range sessions {
+		cluster, _ := h.store.GetCluster(access.ClusterID)
+		clusterName := access.ClusterID
+		if cluster != nil {
+			clusterName = cluster.DisplayName
+		}
+
+		permissions := "-"
+		if len(access.Permissions) > 0 {
+			permissions = strings.Join(access.Permissions, ", ")
+		}
+
+		fields = append(fields, map[string]interface{}{
+			"title": clusterName,
+			"value": fmt.Sprintf("Permissions: %s\nEnded: %s (%s)\nUsed: %s",
+				permissions,
+				sessionEnd(access).Format("2006-01-02 15:04"), access.Status,
+				sessionDurationUsed(access).String()),
+			"short": true,
+		})
+	}
+
+	response := map[string]interface{}{
+		"response_type": h.responseType("history", "ephemeral"),
+		"text":          fmt.Sprintf("Your sessions from the last %d days:", days),
+		"attachments": []map[string]interface{}{
+			{
+				"color":  "good",
+				"fields": fields,
+			},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// sessionEnd is when a completed session ended: its revocation, else its expiry, else when it
+// was requested
+func sessionEnd(access *models.ClusterAccess) time.Time {
+	switch {
+	case access.Status == models.AccessStatusRevoked && access.RevokedAt != nil:
+		return *access.RevokedAt
+	case access.ExpiresAt != nil:
+		return *access.ExpiresAt
+	default:
+		return access.RequestedAt
+	}
+}
+
+// sessionDurationUsed is how long the access was held; revoked sessions may end early
+func sessionDurationUsed(access *models.ClusterAccess) time.Duration {
+	if access.GrantedAt == nil {
+		return access.Duration
+	}
+	used := sessionEnd(access).Sub(*access.GrantedAt)
+	if used < 0 {
+		return 0
+	}
+	return used.Round(time.Second)
+}
+
+// handleCreds answers /jit creds through the Kubernetes-backed handler. The reply is always
+// ephemeral, whatever the configured response types, since it carries credentials.
+func (h *CommandHandler) handleCreds(w http.ResponseWriter, r *http.Request, cmd SlackCommand, args []string) {
+	if h.creds == nil {
+		h.sendError(w, "Credential re-sends are not enabled on this server.")
+		return
+	}
+
+	response, err := h.creds.HandleCredsCommand(r.Context(), cmd, args)
+	if err != nil {
+		slog.Error("Failed to re-send credentials", "user", cmd.UserID, "error", err)
+	}
+	response.ResponseType = "ephemeral"
+	w.Header().Set("Content-Type", "application/json")
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 func (h *CommandHandler) handleAdmin(w http.ResponseWriter, cmd SlackCommand, args []string) {
 	if err := h.rbac.ValidatePermission(cmd.UserID, auth.PermissionManageClusters); err != nil {
 		h.sendError(w, "You don't have admin permissions.")
@@ -270,7 +509,9 @@ func (h *CommandHandler) sendHelp(w http.ResponseWriter) {
 	help := `*JIT Access Commands:*
 • ` + "`/jit request <cluster> <reason>`" + ` - Request access to a cluster
 • ` + "`/jit list`" + ` - List available clusters
-• ` + "`/jit status`" + ` - View your access requests
+• ` + "`/jit status [request-id]`" + ` - View your access requests, or one request's approval trail
+• ` + "`/jit history [days]`" + ` - View your completed sessions (default 7 days)
+• ` + "`/jit creds <request-id>`" + ` - Re-send the credentials of your active request
 • ` + "`/jit admin`" + ` - Admin commands (admin only)
 • ` + "`/jit help`" + ` - Show this help
 
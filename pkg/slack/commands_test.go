@@ -141,6 +141,58 @@ func TestHandleRequestAccess(t *testing.T) {
 	}
 }
 
+func TestHandleRequestAccessResponseType(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseTypes map[string]string
+		expected      string
+	}{
+		{name: "default is in_channel", expected: "in_channel"},
+		{
+			name:          "configured ephemeral",
+			responseTypes: map[string]string{"request": "ephemeral"},
+			expected:      "ephemeral",
+		},
+		{
+			name:          "other subcommands do not affect request",
+			responseTypes: map[string]string{"list": "in_channel"},
+			expected:      "in_channel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memStore := store.NewMemoryStore()
+			handler := NewCommandHandler(auth.NewRBAC([]string{"admin1"}), memStore)
+			handler.SetResponseTypes(tt.responseTypes)
+
+			cluster := &models.Cluster{
+				ID:          "test-cluster",
+				Name:        "test-cluster",
+				DisplayName: "Test Cluster",
+				MaxDuration: time.Hour,
+				Enabled:     true,
+				CreatedBy:   "admin1",
+			}
+			if err := memStore.CreateCluster(cluster); err != nil {
+				t.Fatalf("Failed to create cluster: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.HandleJITCommand(rr, createTestRequest("request test-cluster debugging issue #1234", "user123"))
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response["response_type"] != tt.expected {
+				t.Errorf("Expected response_type %s, got %v", tt.expected, response["response_type"])
+			}
+		})
+	}
+}
+
 func TestHandleRequestAccessInvalidCluster(t *testing.T) {
 	rbac := auth.NewRBAC([]string{"admin1"})
 	memStore := store.NewMemoryStore()
@@ -354,6 +406,81 @@ func TestHandleStatus(t *testing.T) {
 	}
 }
 
+func TestHandleStatusDetail(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	rbac.SetUserRole("approver1", auth.RoleApprover)
+	memStore := store.NewMemoryStore()
+	handler := NewCommandHandler(rbac, memStore)
+
+	approvedAt := time.Date(2024, 6, 10, 13, 0, 0, 0, time.UTC)
+	access := &models.ClusterAccess{
+		ID:          "access-456",
+		ClusterID:   "cluster-123",
+		UserID:      "user123",
+		RequestName: "jit-user123-1718020800",
+		Reason:      "Investigating checkout errors",
+		Permissions: []string{"edit"},
+		Duration:    time.Hour,
+		Status:      models.AccessStatusApproved,
+		ApprovedBy:  []string{"approver1"},
+		ApprovedAt:  &approvedAt,
+		RequestedAt: approvedAt.Add(-time.Hour),
+	}
+	if err := memStore.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		userID   string
+		contains []string
+	}{
+		{
+			name:     "requester by access ID",
+			text:     "status access-456",
+			userID:   "user123",
+			contains: []string{"access-456", "approved", "edit", "<@approver1>", "2024-06-10T13:00:00Z"},
+		},
+		{
+			name:     "approver by request name",
+			text:     "status jit-user123-1718020800",
+			userID:   "approver1",
+			contains: []string{"Investigating checkout errors", "<@approver1>"},
+		},
+		{
+			name:     "other user is refused",
+			text:     "status access-456",
+			userID:   "user999",
+			contains: []string{"don't have permission"},
+		},
+		{
+			name:     "unknown request",
+			text:     "status access-missing",
+			userID:   "user123",
+			contains: []string{"Request not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.HandleJITCommand(rr, createTestRequest(tt.text, tt.userID))
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			text, _ := response["text"].(string)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected response to contain %q, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
 func TestHandleStatusEmpty(t *testing.T) {
 	rbac := auth.NewRBAC([]string{"admin1"})
 	memStore := store.NewMemoryStore()
@@ -453,3 +580,135 @@ func TestHandleUnknownCommand(t *testing.T) {
 		t.Error("Should return error for unknown command")
 	}
 }
+
+func TestHandleHistory(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	memStore := store.NewMemoryStore()
+	handler := NewCommandHandler(rbac, memStore)
+
+	cluster := &models.Cluster{ID: "cluster-123", Name: "prod-east-1", DisplayName: "Prod East", Enabled: true}
+	if err := memStore.CreateCluster(cluster); err != nil {
+		t.Fatalf("Failed to create cluster: %v", err)
+	}
+
+	daysAgo := func(days int) *time.Time {
+		at := time.Now().AddDate(0, 0, -days)
+		return &at
+	}
+	accesses := []*models.ClusterAccess{
+		{
+			ID: "expired-recent", ClusterID: "cluster-123", UserID: "user123", Status: models.AccessStatusExpired,
+			Permissions: []string{"view", "logs"}, Duration: 2 * time.Hour,
+			GrantedAt: daysAgo(2), ExpiresAt: daysAgo(2),
+		},
+		{
+			ID: "revoked-early", ClusterID: "cluster-123", UserID: "user123", Status: models.AccessStatusRevoked,
+			Permissions: []string{"edit"}, Duration: 4 * time.Hour,
+			GrantedAt: daysAgo(5), RevokedAt: daysAgo(5), ExpiresAt: daysAgo(4),
+		},
+		{
+			ID: "expired-old", ClusterID: "cluster-123", UserID: "user123", Status: models.AccessStatusExpired,
+			Permissions: []string{"admin"}, GrantedAt: daysAgo(20), ExpiresAt: daysAgo(20),
+		},
+		{
+			ID: "still-active", ClusterID: "cluster-123", UserID: "user123", Status: models.AccessStatusActive,
+			Permissions: []string{"exec"}, GrantedAt: daysAgo(1), ExpiresAt: daysAgo(-1),
+		},
+		{
+			ID: "other-user", ClusterID: "cluster-123", UserID: "user456", Status: models.AccessStatusExpired,
+			Permissions: []string{"debug"}, GrantedAt: daysAgo(1), ExpiresAt: daysAgo(1),
+		},
+	}
+	// Grant times an hour before the session ended
+	for _, access := range accesses {
+		granted := access.GrantedAt.Add(-time.Hour)
+		access.GrantedAt = &granted
+		if err := memStore.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create access %s: %v", access.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "default window",
+			text:    "history",
+			want:    []string{"last 7 days", "Prod East", "view, logs", "edit", "(revoked)", "Used: 1h0m0s"},
+			notWant: []string{"admin", "exec", "debug"},
+		},
+		{
+			name:    "short window",
+			text:    "history 3",
+			want:    []string{"last 3 days", "view, logs"},
+			notWant: []string{"edit", "admin"},
+		},
+		{
+			name: "long window",
+			text: "history 30",
+			want: []string{"view, logs", "edit", "admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.HandleJITCommand(rr, createTestRequest(tt.text, "user123"))
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response["response_type"] != "ephemeral" {
+				t.Error("History response should be ephemeral")
+			}
+
+			body, err := json.Marshal(response)
+			if err != nil {
+				t.Fatalf("Failed to encode response: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("Expected history to contain %q, got: %s", want, body)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(body), notWant) {
+					t.Errorf("Expected history not to contain %q, got: %s", notWant, body)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleHistoryEmptyAndInvalidWindow(t *testing.T) {
+	rbac := auth.NewRBAC([]string{"admin1"})
+	memStore := store.NewMemoryStore()
+	handler := NewCommandHandler(rbac, memStore)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "history", want: "no completed sessions in the last 7 days"},
+		{text: "history 0", want: "Days must be a number"},
+		{text: "history week", want: "Days must be a number"},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		handler.HandleJITCommand(rr, createTestRequest(tt.text, "user123"))
+
+		var response map[string]interface{}
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		text, ok := response["text"].(string)
+		if !ok || !strings.Contains(text, tt.want) {
+			t.Errorf("%q: expected %q, got %v", tt.text, tt.want, response["text"])
+		}
+	}
+}
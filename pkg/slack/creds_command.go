@@ -0,0 +1,140 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// HandleCredsCommand processes /jit creds <request-id> commands, re-sending the kubeconfig and
+// temporary credentials of the caller's own active session from the secrets the operator wrote,
+// without provisioning anything. Only the requester may fetch them, and only privately.
+func (h *K8sCommandHandler) HandleCredsCommand(
+	ctx context.Context,
+	cmd SlackCommand,
+	args []string,
+) (*SlackResponse, error) {
+	if len(args) < 1 {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "❌ Usage: /jit creds <request-id>",
+		}, nil
+	}
+
+	requestName := args[0]
+
+	var request controller.JITAccessRequest
+	if err := h.client.Get(ctx, client.ObjectKey{Name: requestName, Namespace: h.namespace}, &request); err != nil {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         fmt.Sprintf("❌ Request not found: %s", requestName),
+		}, err
+	}
+
+	// Admins and approvers can see a request but never its credentials
+	if request.Spec.UserID != cmd.UserID {
+		log.FromContext(ctx).Info("AUDIT: denied credential re-send to non-owner",
+			"request", request.Name, "user", cmd.UserID, "owner", request.Spec.UserID)
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "❌ Only the requester can fetch the credentials for this request",
+		}, nil
+	}
+
+	if request.Status.Phase != controller.AccessPhaseActive {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         fmt.Sprintf("❌ Request %s is not active (%s)", request.Name, request.Status.Phase),
+		}, nil
+	}
+
+	var text strings.Builder
+	for _, jobName := range requestJobNames(&request) {
+		var job controller.JITAccessJob
+		if err := h.client.Get(ctx, client.ObjectKey{Name: jobName, Namespace: request.Namespace}, &job); err != nil {
+			return &SlackResponse{
+				ResponseType: "ephemeral",
+				Text:         fmt.Sprintf("❌ Credentials for %s are not available yet", request.Name),
+			}, fmt.Errorf("failed to get job %s: %w", jobName, err)
+		}
+
+		section, err := h.formatJobCredentials(ctx, &job)
+		if err != nil {
+			return &SlackResponse{
+				ResponseType: "ephemeral",
+				Text:         fmt.Sprintf("❌ Credentials for %s are not available yet", request.Name),
+			}, err
+		}
+		text.WriteString(section)
+	}
+
+	log.FromContext(ctx).Info("AUDIT: re-sent credentials to requester",
+		"request", request.Name, "user", cmd.UserID)
+
+	return &SlackResponse{
+		ResponseType: "ephemeral",
+		Text:         text.String(),
+	}, nil
+}
+
+// requestJobNames returns the jobs holding a request's credentials: one per member cluster for
+// group requests, otherwise the request's single job
+func requestJobNames(request *controller.JITAccessRequest) []string {
+	if len(request.Status.ClusterJobs) == 0 {
+		return []string{controller.JobName(request)}
+	}
+
+	names := make([]string, 0, len(request.Status.ClusterJobs))
+	for _, clusterJob := range request.Status.ClusterJobs {
+		names = append(names, clusterJob.JobName)
+	}
+	return names
+}
+
+// formatJobCredentials reads the job's kubeconfig and credentials secrets into a message section
+func (h *K8sCommandHandler) formatJobCredentials(ctx context.Context, job *controller.JITAccessJob) (string, error) {
+	if job.Status.Phase != controller.JobPhaseActive {
+		return "", fmt.Errorf("job %s is %s, not active", job.Name, job.Status.Phase)
+	}
+
+	var section strings.Builder
+	section.WriteString(fmt.Sprintf("🔐 *Credentials for %s*\n", job.Spec.TargetCluster.Name))
+
+	if ref := job.Status.KubeConfigSecretRef; ref != nil {
+		data, err := h.secretData(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		section.WriteString(fmt.Sprintf("*Kubeconfig*\n```\n%s\n```\n", strings.TrimSpace(string(data["kubeconfig"]))))
+	}
+
+	if entry := job.Status.AccessEntry; entry != nil && entry.CredentialsSecretRef != nil {
+		data, err := h.secretData(ctx, entry.CredentialsSecretRef)
+		if err != nil {
+			return "", err
+		}
+		section.WriteString(fmt.Sprintf("*Temporary AWS credentials* (expire %s)\n```\n", data["expires-at"]))
+		section.WriteString(fmt.Sprintf("export AWS_ACCESS_KEY_ID=%s\n", data["aws-access-key-id"]))
+		section.WriteString(fmt.Sprintf("export AWS_SECRET_ACCESS_KEY=%s\n", data["aws-secret-access-key"]))
+		section.WriteString(fmt.Sprintf("export AWS_SESSION_TOKEN=%s\n```\n", data["aws-session-token"]))
+	}
+
+	return section.String(), nil
+}
+
+func (h *K8sCommandHandler) secretData(
+	ctx context.Context,
+	ref *controller.ObjectReference,
+) (map[string][]byte, error) {
+	var secret corev1.Secret
+	if err := h.client.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+	return secret.Data, nil
+}
@@ -0,0 +1,158 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/store"
+)
+
+func newCredsTestHandler(t *testing.T, phase controller.AccessPhase) *K8sCommandHandler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := controller.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add core scheme: %v", err)
+	}
+
+	request := &controller.JITAccessRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-U0REQUESTER-1718020800", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:        "U0REQUESTER",
+			TargetCluster: controller.TargetCluster{Name: "prod-east-1"},
+		},
+		Status: controller.JITAccessRequestStatus{Phase: phase},
+	}
+	job := &controller.JITAccessJob{
+		ObjectMeta: metav1.ObjectMeta{Name: controller.JobName(request), Namespace: "jit-system"},
+		Spec:       controller.JITAccessJobSpec{TargetCluster: request.Spec.TargetCluster},
+		Status: controller.JITAccessJobStatus{
+			Phase:               controller.JobPhaseActive,
+			KubeConfigSecretRef: &controller.ObjectReference{Name: "jit-kubeconfig", Namespace: "jit-system"},
+			AccessEntry: &controller.JobAccessEntry{
+				CredentialsSecretRef: &controller.ObjectReference{Name: "jit-credentials", Namespace: "jit-system"},
+			},
+		},
+	}
+	kubeConfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-kubeconfig", Namespace: "jit-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte("apiVersion: v1\nkind: Config\n")},
+	}
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-credentials", Namespace: "jit-system"},
+		Data: map[string][]byte{
+			"aws-access-key-id":     []byte("ASIATESTKEY"),
+			"aws-secret-access-key": []byte("secret"),
+			"aws-session-token":     []byte("token"),
+			"expires-at":            []byte("2024-06-10T14:00:00Z"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(request, job, kubeConfigSecret, credentialsSecret).
+		Build()
+
+	return NewK8sCommandHandler(fakeClient, auth.NewRBAC([]string{"U0ADMIN"}), "jit-system")
+}
+
+func TestHandleCredsCommandOwner(t *testing.T) {
+	handler := newCredsTestHandler(t, controller.AccessPhaseActive)
+
+	resp, err := handler.HandleCredsCommand(context.Background(),
+		SlackCommand{UserID: "U0REQUESTER"}, []string{"jit-U0REQUESTER-1718020800"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.ResponseType != "ephemeral" {
+		t.Errorf("Expected ephemeral response, got %s", resp.ResponseType)
+	}
+	for _, want := range []string{
+		"prod-east-1",
+		"kind: Config",
+		"AWS_ACCESS_KEY_ID=ASIATESTKEY",
+		"expire 2024-06-10T14:00:00Z",
+	} {
+		if !strings.Contains(resp.Text, want) {
+			t.Errorf("Expected response to contain %q, got:\n%s", want, resp.Text)
+		}
+	}
+}
+
+func TestHandleCredsCommandNonOwner(t *testing.T) {
+	handler := newCredsTestHandler(t, controller.AccessPhaseActive)
+
+	for _, userID := range []string{"U0OTHER", "U0ADMIN"} {
+		resp, err := handler.HandleCredsCommand(context.Background(),
+			SlackCommand{UserID: userID}, []string{"jit-U0REQUESTER-1718020800"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !strings.Contains(resp.Text, "Only the requester") {
+			t.Errorf("Expected %s to be denied, got: %s", userID, resp.Text)
+		}
+		if strings.Contains(resp.Text, "ASIATESTKEY") || strings.Contains(resp.Text, "kind: Config") {
+			t.Errorf("Expected credentials to be withheld from %s", userID)
+		}
+	}
+}
+
+func TestHandleCredsCommandInactiveRequest(t *testing.T) {
+	handler := newCredsTestHandler(t, controller.AccessPhaseExpired)
+
+	resp, err := handler.HandleCredsCommand(context.Background(),
+		SlackCommand{UserID: "U0REQUESTER"}, []string{"jit-U0REQUESTER-1718020800"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(resp.Text, "is not active") {
+		t.Errorf("Expected inactive request to be refused, got: %s", resp.Text)
+	}
+}
+
+func TestHandleJITCommandRoutesCreds(t *testing.T) {
+	handler := NewCommandHandler(auth.NewRBAC([]string{"U0ADMIN"}), store.NewMemoryStore())
+	handler.SetResponseTypes(map[string]string{"creds": "in_channel"})
+	handler.SetCredentialsHandler(newCredsTestHandler(t, controller.AccessPhaseActive))
+
+	rr := httptest.NewRecorder()
+	handler.HandleJITCommand(rr, createTestRequest("creds jit-U0REQUESTER-1718020800", "U0REQUESTER"))
+
+	var response SlackResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.ResponseType != "ephemeral" {
+		t.Errorf("Expected credentials to be sent privately, got %s", response.ResponseType)
+	}
+	if !strings.Contains(response.Text, "AWS_ACCESS_KEY_ID=ASIATESTKEY") {
+		t.Errorf("Expected the requester's credentials, got:\n%s", response.Text)
+	}
+}
+
+func TestHandleJITCommandCredsDisabled(t *testing.T) {
+	handler := NewCommandHandler(auth.NewRBAC([]string{"U0ADMIN"}), store.NewMemoryStore())
+
+	rr := httptest.NewRecorder()
+	handler.HandleJITCommand(rr, createTestRequest("creds jit-U0REQUESTER-1718020800", "U0REQUESTER"))
+
+	if !strings.Contains(rr.Body.String(), "not enabled") {
+		t.Errorf("Expected /jit creds to be refused without a credentials handler, got: %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,77 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DirectMessenger sends direct messages to Slack users with the bot token, e.g. to deliver
+// credentials to the user they were granted to
+type DirectMessenger struct {
+	token      string
+	apiURL     string
+	httpClient *http.Client
+	breaker    *CircuitBreaker
+}
+
+// NewDirectMessenger creates a messenger that calls chat.postMessage with the given bot token
+func NewDirectMessenger(token string) *DirectMessenger {
+	return &DirectMessenger{
+		token:      token,
+		apiURL:     defaultSlackAPIURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		breaker:    NewCircuitBreaker(0, 0),
+	}
+}
+
+// SendDirectMessage posts text to the user's direct message conversation with the bot
+func (m *DirectMessenger) SendDirectMessage(ctx context.Context, userID, text string) error {
+	return m.breaker.Call("chat.postMessage", func() error {
+		return m.send(ctx, userID, text)
+	})
+}
+
+func (m *DirectMessenger) send(ctx context.Context, userID, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"channel": userID,
+		"text":    text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	url := m.apiURL + "/chat.postMessage"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build slack request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	httpReq.Header.Set("Authorization", "Bearer "+m.token)
+
+	resp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("slack rate limited message to %s (retry after %ss)",
+			userID, resp.Header.Get("Retry-After"))
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack rejected message to %s: %s", userID, result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDirectMessengerSendDirectMessage(t *testing.T) {
+	var posted map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" {
+			t.Errorf("Unexpected Slack API path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("Expected bot token authorization, got %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("Failed to decode message: %v", err)
+		}
+
+		if posted["channel"] == "U0UNKNOWN" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "channel_not_found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
+	}))
+	defer server.Close()
+
+	messenger := NewDirectMessenger("xoxb-test")
+	messenger.apiURL = server.URL
+
+	if err := messenger.SendDirectMessage(t.Context(), "U123456789A", "your credentials"); err != nil {
+		t.Fatalf("Expected the message to be sent, got %v", err)
+	}
+	if posted["channel"] != "U123456789A" || posted["text"] != "your credentials" {
+		t.Errorf("Expected a direct message to U123456789A, got %v", posted)
+	}
+
+	if err := messenger.SendDirectMessage(t.Context(), "U0UNKNOWN", "your credentials"); err == nil {
+		t.Error("Expected an error when Slack rejects the message")
+	}
+}
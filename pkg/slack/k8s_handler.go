@@ -77,7 +77,7 @@ func (h *K8sCommandHandler) HandleRequestCommand(
 	// Create JITAccessRequest
 	request := &controller.JITAccessRequest{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("jit-%s-%d", cmd.UserID, time.Now().Unix()),
+			Name:      controller.RequestName(cmd.UserID, time.Now()),
 			Namespace: h.namespace,
 			Labels: map[string]string{
 				"jit.rebelops.io/user":    cmd.UserID,
@@ -155,6 +155,15 @@ func (h *K8sCommandHandler) HandleApproveCommand(
 		}, err
 	}
 
+	// Elevated requests are only approved with a justification
+	if controller.RequiresApprovalComment(&request) && strings.TrimSpace(comment) == "" {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text: fmt.Sprintf("❌ Request `%s` asks for elevated permissions; approve it with a comment: "+
+				"/jit approve %s <comment>", requestName, requestName),
+		}, nil
+	}
+
 	// Add approval
 	approval := controller.Approval{
 		Approver:   cmd.UserID,
@@ -205,24 +214,8 @@ func (h *K8sCommandHandler) HandleListCommand(
 	response.WriteString("📋 JIT Access Requests:\n")
 
 	for _, req := range requestList.Items {
-		status := "❓"
-		switch req.Status.Phase {
-		case controller.AccessPhasePending:
-			status = "⏳"
-		case controller.AccessPhaseApproved:
-			status = "✅"
-		case controller.AccessPhaseDenied:
-			status = "❌"
-		case controller.AccessPhaseActive:
-			status = "🟢"
-		case controller.AccessPhaseExpired:
-			status = "⏰"
-		case controller.AccessPhaseRevoked:
-			status = "🔴"
-		}
-
 		response.WriteString(fmt.Sprintf("%s `%s` - %s (%s) - %s\n",
-			status, req.Name, req.Spec.TargetCluster.Name, req.Spec.Duration, req.Status.Phase))
+			phaseEmoji(req.Status.Phase), req.Name, req.Spec.TargetCluster.Name, req.Spec.Duration, req.Status.Phase))
 	}
 
 	return &SlackResponse{
@@ -231,6 +224,139 @@ func (h *K8sCommandHandler) HandleListCommand(
 	}, nil
 }
 
+// HandleStatusCommand processes /jit status <request-id> commands, showing the full detail and
+// approval trail of a single request to its requester, its approvers, and admins
+func (h *K8sCommandHandler) HandleStatusCommand(
+	ctx context.Context,
+	cmd SlackCommand,
+	args []string,
+) (*SlackResponse, error) {
+	if len(args) < 1 {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "❌ Usage: /jit status <request-id>",
+		}, nil
+	}
+
+	requestName := args[0]
+
+	var request controller.JITAccessRequest
+	if err := h.client.Get(ctx, client.ObjectKey{Name: requestName, Namespace: h.namespace}, &request); err != nil {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         fmt.Sprintf("❌ Request not found: %s", requestName),
+		}, err
+	}
+
+	if !h.canViewRequest(cmd.UserID, &request) {
+		return &SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "❌ You don't have permission to view this request",
+		}, nil
+	}
+
+	return &SlackResponse{
+		ResponseType: "ephemeral",
+		Text:         formatRequestDetail(&request, time.Now()),
+	}, nil
+}
+
+// canViewRequest reports whether the user is the requester, one of the request's approvers,
+// or holds approver permissions (which admins do)
+func (h *K8sCommandHandler) canViewRequest(userID string, request *controller.JITAccessRequest) bool {
+	if request.Spec.UserID == userID {
+		return true
+	}
+	for _, approver := range request.Spec.Approvers {
+		if approver == userID {
+			return true
+		}
+	}
+	return h.rbac.UserHasPermission(userID, auth.PermissionApproveRequests)
+}
+
+func formatRequestDetail(request *controller.JITAccessRequest, now time.Time) string {
+	var detail strings.Builder
+
+	detail.WriteString(fmt.Sprintf("%s *Request `%s`* - %s\n",
+		phaseEmoji(request.Status.Phase), request.Name, request.Status.Phase))
+	detail.WriteString(fmt.Sprintf("👤 Requester: <@%s>\n", request.Spec.UserID))
+	detail.WriteString(fmt.Sprintf("🎯 Cluster: %s\n", request.Spec.TargetCluster.Name))
+	detail.WriteString(fmt.Sprintf("🔑 Permissions: %s\n", strings.Join(request.Spec.Permissions, ", ")))
+	if len(request.Spec.Namespaces) > 0 {
+		detail.WriteString(fmt.Sprintf("📁 Namespaces: %s\n", strings.Join(request.Spec.Namespaces, ", ")))
+	}
+	detail.WriteString(fmt.Sprintf("⏱️ Duration: %s\n", request.Spec.Duration))
+	detail.WriteString(fmt.Sprintf("📝 Reason: %s\n", request.Spec.Reason))
+
+	if entry := request.Status.AccessEntry; entry != nil && !entry.ExpiresAt.IsZero() {
+		remaining := entry.ExpiresAt.Sub(now).Round(time.Minute)
+		if remaining > 0 {
+			detail.WriteString(fmt.Sprintf("⌛ Remaining: %s (expires %s)\n",
+				remaining, entry.ExpiresAt.UTC().Format(time.RFC3339)))
+		} else {
+			detail.WriteString(fmt.Sprintf("⌛ Expired: %s\n", entry.ExpiresAt.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	if len(request.Spec.Approvers) > 0 {
+		detail.WriteString(fmt.Sprintf("👥 Required approvers: %s\n", strings.Join(request.Spec.Approvers, ", ")))
+	}
+
+	detail.WriteString("\n*Approvals:*\n")
+	if len(request.Status.Approvals) == 0 {
+		detail.WriteString("• None yet\n")
+	}
+	for _, approval := range request.Status.Approvals {
+		detail.WriteString(fmt.Sprintf("• <@%s> at %s",
+			approval.Approver, approval.ApprovedAt.UTC().Format(time.RFC3339)))
+		if approval.Comment != "" {
+			detail.WriteString(fmt.Sprintf(" - %q", approval.Comment))
+		}
+		detail.WriteString("\n")
+	}
+
+	if len(request.Status.Conditions) > 0 {
+		detail.WriteString("\n*Conditions:*\n")
+		for _, condition := range request.Status.Conditions {
+			detail.WriteString(fmt.Sprintf("• %s=%s (%s)", condition.Type, condition.Status, condition.Reason))
+			if condition.Message != "" {
+				detail.WriteString(": " + condition.Message)
+			}
+			detail.WriteString("\n")
+		}
+	}
+
+	if request.Status.Message != "" {
+		detail.WriteString(fmt.Sprintf("\n💬 %s\n", request.Status.Message))
+	}
+
+	return detail.String()
+}
+
+func phaseEmoji(phase controller.AccessPhase) string {
+	switch phase {
+	case controller.AccessPhasePending:
+		return "⏳"
+	case controller.AccessPhaseApproved:
+		return "✅"
+	case controller.AccessPhaseScheduled:
+		return "🗓️"
+	case controller.AccessPhaseDenied:
+		return "❌"
+	case controller.AccessPhaseActive:
+		return "🟢"
+	case controller.AccessPhaseExpired:
+		return "⏰"
+	case controller.AccessPhaseRevoked:
+		return "🔴"
+	case controller.AccessPhaseFailed:
+		return "⚠️"
+	default:
+		return "❓"
+	}
+}
+
 // Helper functions
 func (h *K8sCommandHandler) getClusterAccount(clusterName string) string {
 	// This should be configurable via ConfigMap
@@ -0,0 +1,200 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func newStatusTestHandler(t *testing.T) *K8sCommandHandler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := controller.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add scheme: %v", err)
+	}
+
+	approvedAt := metav1.NewTime(time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC))
+	request := &controller.JITAccessRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "jit-U0REQUESTER-1718020800", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:        "U0REQUESTER",
+			TargetCluster: controller.TargetCluster{Name: "prod-east-1"},
+			Reason:        "INC-123 debugging",
+			Duration:      "2h",
+			Permissions:   []string{"view", "edit"},
+			Approvers:     []string{"U0APPROVER1", "sre-team"},
+		},
+		Status: controller.JITAccessRequestStatus{
+			Phase: controller.AccessPhaseActive,
+			Approvals: []controller.Approval{
+				{Approver: "U0APPROVER1", ApprovedAt: approvedAt, Comment: "looks good"},
+			},
+			AccessEntry: &controller.AccessEntryStatus{
+				ExpiresAt: metav1.NewTime(time.Now().Add(90 * time.Minute)),
+			},
+			Conditions: []metav1.Condition{
+				{
+					Type:               "AccessGranted",
+					Status:             metav1.ConditionTrue,
+					Reason:             "Provisioned",
+					Message:            "EKS access entry created",
+					LastTransitionTime: approvedAt,
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(request).WithStatusSubresource(request).Build()
+
+	rbac := auth.NewRBAC([]string{"U0ADMIN"})
+	rbac.SetUserRole("U0REQUESTER", auth.RoleRequester)
+	rbac.SetUserRole("U0OTHER", auth.RoleRequester)
+
+	return NewK8sCommandHandler(fakeClient, rbac, "jit-system")
+}
+
+func TestHandleStatusCommandAuthorizedViewers(t *testing.T) {
+	handler := newStatusTestHandler(t)
+
+	tests := []struct {
+		name   string
+		userID string
+	}{
+		{name: "requester", userID: "U0REQUESTER"},
+		{name: "listed approver", userID: "U0APPROVER1"},
+		{name: "admin", userID: "U0ADMIN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := handler.HandleStatusCommand(context.Background(),
+				SlackCommand{UserID: tt.userID}, []string{"jit-U0REQUESTER-1718020800"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if resp.ResponseType != "ephemeral" {
+				t.Errorf("Expected ephemeral response, got %s", resp.ResponseType)
+			}
+
+			for _, want := range []string{
+				"Active",
+				"prod-east-1",
+				"view, edit",
+				"<@U0APPROVER1> at 2024-06-10T12:00:00Z",
+				`"looks good"`,
+				"AccessGranted=True (Provisioned): EKS access entry created",
+				"Remaining: 1h30m0s",
+			} {
+				if !strings.Contains(resp.Text, want) {
+					t.Errorf("Expected response to contain %q, got:\n%s", want, resp.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleStatusCommandUnauthorized(t *testing.T) {
+	handler := newStatusTestHandler(t)
+
+	resp, err := handler.HandleStatusCommand(context.Background(),
+		SlackCommand{UserID: "U0OTHER"}, []string{"jit-U0REQUESTER-1718020800"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(resp.Text, "don't have permission") {
+		t.Errorf("Expected permission error, got: %s", resp.Text)
+	}
+	if strings.Contains(resp.Text, "looks good") {
+		t.Error("Expected approval trail to be hidden from unauthorized users")
+	}
+}
+
+func TestHandleStatusCommandNotFound(t *testing.T) {
+	handler := newStatusTestHandler(t)
+
+	resp, err := handler.HandleStatusCommand(context.Background(),
+		SlackCommand{UserID: "U0ADMIN"}, []string{"missing"})
+	if err == nil {
+		t.Fatal("Expected error for missing request")
+	}
+	if !strings.Contains(resp.Text, "Request not found") {
+		t.Errorf("Expected not found message, got: %s", resp.Text)
+	}
+}
+
+func TestHandleRequestCommandSameSecondRequestsDoNotCollide(t *testing.T) {
+	handler := newStatusTestHandler(t)
+
+	cmd := SlackCommand{UserID: "U0REQUESTER", UserName: "requester", ChannelID: "C0JIT"}
+	args := []string{"dev-west-2", "1h", "Debugging", "the", "ingress"}
+
+	// Both requests are filed well within the same second
+	for i := 0; i < 2; i++ {
+		if _, err := handler.HandleRequestCommand(context.Background(), cmd, args); err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+	}
+
+	var requests controller.JITAccessRequestList
+	if err := handler.client.List(context.Background(), &requests,
+		client.MatchingLabels{"jit.rebelops.io/user": "U0REQUESTER"}); err != nil {
+		t.Fatalf("Failed to list requests: %v", err)
+	}
+	if len(requests.Items) != 2 {
+		t.Errorf("Expected 2 distinct requests, got %d", len(requests.Items))
+	}
+}
+
+func TestHandleApproveCommandElevatedRequiresComment(t *testing.T) {
+	handler := newStatusTestHandler(t)
+	handler.rbac.SetUserRole("U0APPROVER1", auth.RoleApprover)
+
+	ctx := context.Background()
+	key := client.ObjectKey{Name: "jit-U0REQUESTER-1718020800", Namespace: "jit-system"}
+
+	var request controller.JITAccessRequest
+	if err := handler.client.Get(ctx, key, &request); err != nil {
+		t.Fatalf("Failed to get request: %v", err)
+	}
+	request.Spec.Permissions = []string{"admin"}
+	if err := handler.client.Update(ctx, &request); err != nil {
+		t.Fatalf("Failed to update request: %v", err)
+	}
+
+	resp, err := handler.HandleApproveCommand(ctx, SlackCommand{UserID: "U0APPROVER1"}, []string{key.Name})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(resp.Text, "approve it with a comment") {
+		t.Errorf("Expected comment-less approval to be rejected, got: %s", resp.Text)
+	}
+
+	resp, err = handler.HandleApproveCommand(ctx, SlackCommand{UserID: "U0APPROVER1"},
+		[]string{key.Name, "INC-42", "verified"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(resp.Text, "Approved request") {
+		t.Errorf("Expected commented approval to be accepted, got: %s", resp.Text)
+	}
+
+	if err := handler.client.Get(ctx, key, &request); err != nil {
+		t.Fatalf("Failed to get request: %v", err)
+	}
+	// The fixture already carries one approval; only the commented one is added
+	if len(request.Status.Approvals) != 2 || request.Status.Approvals[1].Comment != "INC-42 verified" {
+		t.Errorf("Expected only the commented approval to be recorded, got %+v", request.Status.Approvals)
+	}
+}
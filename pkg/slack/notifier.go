@@ -0,0 +1,313 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+const (
+	defaultSlackAPIURL = "https://slack.com/api"
+)
+
+// NotifierConfig configures approver notifications for pending requests
+type NotifierConfig struct {
+	// Channel receives pings for approver groups
+	Channel string `json:"channel"`
+	// ApproverGroups maps approver team names (e.g. "sre-team") to Slack user group IDs
+	ApproverGroups map[string]string `json:"approverGroups"`
+	// FailureThreshold opens the Slack circuit breaker after this many consecutive failures (default 5)
+	FailureThreshold int `json:"failureThreshold"`
+	// Cooldown is how long the breaker stays open before a trial call, e.g. "1m" (default 30s)
+	Cooldown string `json:"cooldown"`
+}
+
+// LoadNotifierConfig reads approver notification settings from a JSON file
+func LoadNotifierConfig(path string) (*NotifierConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifier config: %w", err)
+	}
+
+	var cfg NotifierConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse notifier config: %w", err)
+	}
+
+	if cfg.Cooldown != "" {
+		if _, err := time.ParseDuration(cfg.Cooldown); err != nil {
+			return nil, fmt.Errorf("invalid circuit breaker cooldown: %w", err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// ApprovalNotifier posts pending requests to their approvers. Approver groups are pinged in the
+// configured channel; approvers given as Slack user IDs are messaged directly.
+type ApprovalNotifier struct {
+	token      string
+	config     NotifierConfig
+	apiURL     string
+	httpClient *http.Client
+	breaker    *CircuitBreaker
+	// delegations route notifications for out-of-office approvers to their delegates
+	delegations controller.ApprovalDelegations
+	now         func() time.Time
+}
+
+// NewApprovalNotifier creates a notifier that posts with the given bot token
+func NewApprovalNotifier(token string, config NotifierConfig) *ApprovalNotifier {
+	cooldown, _ := time.ParseDuration(config.Cooldown) // validated by LoadNotifierConfig; zero uses the default
+
+	return &ApprovalNotifier{
+		token:      token,
+		config:     config,
+		apiURL:     defaultSlackAPIURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		breaker:    NewCircuitBreaker(config.FailureThreshold, cooldown),
+		now:        time.Now,
+	}
+}
+
+// EnableDelegations also notifies the delegates of approvers who are out of office
+func (n *ApprovalNotifier) EnableDelegations(delegations controller.ApprovalDelegations) {
+	n.delegations = delegations
+}
+
+// notificationTargets holds the resolved recipients for a pending request
+type notificationTargets struct {
+	Mentions []string
+	Users    []string
+}
+
+// NotifyPendingRequest sends the request details to the request's approvers.
+// The first message posted starts the request's thread, which is recorded in its annotations.
+func (n *ApprovalNotifier) NotifyPendingRequest(ctx context.Context, req *controller.JITAccessRequest) error {
+	approvers := append([]string{}, req.Spec.Approvers...)
+	approvers = append(approvers, n.delegations.ActiveDelegates(req.Spec.Approvers, n.now())...)
+	targets := n.resolveTargets(approvers)
+
+	if len(targets.Mentions) > 0 {
+		if n.config.Channel == "" {
+			return fmt.Errorf("no notification channel configured for approver groups")
+		}
+		text := fmt.Sprintf("%s: access request awaiting approval", strings.Join(targets.Mentions, " "))
+		posted, err := n.postMessage(ctx, n.config.Channel, "", text, requestBlocks(text, req))
+		if err != nil {
+			return err
+		}
+		recordThread(req, posted)
+	}
+
+	for _, user := range targets.Users {
+		text := "Access request awaiting your approval"
+		posted, err := n.postMessage(ctx, user, "", text, requestBlocks(text, req))
+		if err != nil {
+			return err
+		}
+		recordThread(req, posted)
+	}
+
+	return nil
+}
+
+// NotifyDecision replies in the request's notification thread once it is approved or denied
+func (n *ApprovalNotifier) NotifyDecision(ctx context.Context, req *controller.JITAccessRequest) error {
+	channel := req.Annotations[controller.SlackChannelAnnotation]
+	threadTS := req.Annotations[controller.SlackThreadAnnotation]
+	if channel == "" || threadTS == "" {
+		return fmt.Errorf("request %s has no slack thread", req.Name)
+	}
+
+	var text string
+	switch req.Status.Phase {
+	case controller.AccessPhaseApproved:
+		text = fmt.Sprintf("✅ Request `%s` approved", req.Name)
+	case controller.AccessPhaseDenied:
+		text = fmt.Sprintf("❌ Request `%s` denied", req.Name)
+		if req.Status.Message != "" {
+			text += ": " + req.Status.Message
+		}
+	default:
+		return fmt.Errorf("request %s has no decision in phase %s", req.Name, req.Status.Phase)
+	}
+
+	_, err := n.postMessage(ctx, channel, threadTS, text, []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		},
+	})
+	return err
+}
+
+// slackMessageRef identifies a message Slack accepted, for threading replies under it
+type slackMessageRef struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// recordThread keeps the first posted message as the request's thread
+func recordThread(req *controller.JITAccessRequest, posted slackMessageRef) {
+	if posted.TS == "" || req.Annotations[controller.SlackThreadAnnotation] != "" {
+		return
+	}
+	if req.Annotations == nil {
+		req.Annotations = map[string]string{}
+	}
+	req.Annotations[controller.SlackChannelAnnotation] = posted.Channel
+	req.Annotations[controller.SlackThreadAnnotation] = posted.TS
+}
+
+// NotifyBreakGlass alerts the notification channel (with @here) and the request's approvers that
+// view-only emergency access was granted without approval
+func (n *ApprovalNotifier) NotifyBreakGlass(ctx context.Context, req *controller.JITAccessRequest) error {
+	targets := n.resolveTargets(req.Spec.Approvers)
+
+	if n.config.Channel == "" {
+		if len(targets.Mentions) > 0 || len(targets.Users) == 0 {
+			return fmt.Errorf("no notification channel configured for break-glass alerts")
+		}
+	} else {
+		mentions := append([]string{"<!here>"}, targets.Mentions...)
+		text := fmt.Sprintf("%s: :rotating_light: break-glass view access granted without approval",
+			strings.Join(mentions, " "))
+		if _, err := n.postMessage(ctx, n.config.Channel, "", text, detailBlocks(text, req)); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range targets.Users {
+		text := ":rotating_light: Break-glass view access was granted without your approval"
+		if _, err := n.postMessage(ctx, user, "", text, detailBlocks(text, req)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *ApprovalNotifier) resolveTargets(approvers []string) notificationTargets {
+	var targets notificationTargets
+	for _, approver := range approvers {
+		switch {
+		case n.config.ApproverGroups[approver] != "":
+			targets.Mentions = append(targets.Mentions,
+				fmt.Sprintf("<!subteam^%s>", n.config.ApproverGroups[approver]))
+		case isSlackUserID(approver):
+			targets.Users = append(targets.Users, approver)
+		default:
+			// Unmapped team names still show up in the channel ping, just without a group mention
+			targets.Mentions = append(targets.Mentions, "@"+approver)
+		}
+	}
+	return targets
+}
+
+func isSlackUserID(id string) bool {
+	return len(id) > 1 && (id[0] == 'U' || id[0] == 'W') && strings.ToUpper(id) == id
+}
+
+// postMessage posts to channel, as a reply in the thread threadTS when it is set
+func (n *ApprovalNotifier) postMessage(
+	ctx context.Context, channel, threadTS, text string, blocks []map[string]interface{},
+) (slackMessageRef, error) {
+	var posted slackMessageRef
+	err := n.breaker.Call("chat.postMessage", func() error {
+		var sendErr error
+		posted, sendErr = n.sendMessage(ctx, channel, threadTS, text, blocks)
+		return sendErr
+	})
+	return posted, err
+}
+
+func (n *ApprovalNotifier) sendMessage(
+	ctx context.Context, channel, threadTS, text string, blocks []map[string]interface{},
+) (slackMessageRef, error) {
+	payload := map[string]interface{}{
+		"channel": channel,
+		"text":    text,
+		"blocks":  blocks,
+	}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return slackMessageRef{}, fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, n.apiURL+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return slackMessageRef{}, fmt.Errorf("failed to build slack request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	httpReq.Header.Set("Authorization", "Bearer "+n.token)
+
+	resp, err := n.httpClient.Do(httpReq)
+	if err != nil {
+		return slackMessageRef{}, fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return slackMessageRef{}, fmt.Errorf("slack rate limited message to %s (retry after %ss)",
+			channel, resp.Header.Get("Retry-After"))
+	}
+
+	var result struct {
+		slackMessageRef
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return slackMessageRef{}, fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return slackMessageRef{}, fmt.Errorf("slack rejected message to %s: %s", channel, result.Error)
+	}
+
+	return result.slackMessageRef, nil
+}
+
+// requestBlocks renders the request details and the request's namespace and name, which approvers
+// use to approve or deny it. There are no buttons: nothing serves Slack interactivity callbacks.
+func requestBlocks(text string, req *controller.JITAccessRequest) []map[string]interface{} {
+	return append(detailBlocks(text, req), map[string]interface{}{
+		"type": "context",
+		"elements": []map[string]string{
+			{"type": "mrkdwn", "text": fmt.Sprintf("*Request:* `%s/%s`", req.Namespace, req.Name)},
+		},
+	})
+}
+
+// detailBlocks renders the message text and request details without any actions
+func detailBlocks(text string, req *controller.JITAccessRequest) []map[string]interface{} {
+	details := fmt.Sprintf("*Requester:* <@%s>\n*Cluster:* %s\n*Permissions:* %s\n*Duration:* %s\n*Reason:* %s",
+		req.Spec.UserID,
+		req.Spec.TargetCluster.Name,
+		strings.Join(req.Spec.Permissions, ", "),
+		req.Spec.Duration,
+		req.Spec.Reason,
+	)
+
+	return []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		},
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": details},
+		},
+	}
+}
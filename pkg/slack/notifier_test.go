@@ -0,0 +1,303 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func newProdRequest() *controller.JITAccessRequest {
+	return &controller.JITAccessRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-request", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:      "U123456789A",
+			UserEmail:   "engineer@company.com",
+			Permissions: []string{"edit"},
+			Duration:    "2h",
+			Reason:      "Investigating production incident",
+			Approvers:   []string{"platform-team", "sre-team", "security-team"},
+			TargetCluster: controller.TargetCluster{
+				Name:       "prod-east-1",
+				AWSAccount: "123456789012",
+				Region:     "us-east-1",
+			},
+		},
+	}
+}
+
+type postedMessage struct {
+	Channel  string                   `json:"channel"`
+	ThreadTS string                   `json:"thread_ts"`
+	Text     string                   `json:"text"`
+	Blocks   []map[string]interface{} `json:"blocks"`
+}
+
+func newTestSlackAPI(t *testing.T) (*httptest.Server, *[]postedMessage) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var messages []postedMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" {
+			t.Errorf("Unexpected Slack API path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("Expected bot token authorization, got %q", r.Header.Get("Authorization"))
+		}
+
+		var msg postedMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Failed to decode message: %v", err)
+		}
+		mu.Lock()
+		messages = append(messages, msg)
+		ts := fmt.Sprintf("1700000000.%06d", len(messages))
+		mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": msg.Channel, "ts": ts})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &messages
+}
+
+func TestNotifyPendingRequestProdApprovers(t *testing.T) {
+	server, messages := newTestSlackAPI(t)
+
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{
+		Channel: "C0APPROVALS",
+		ApproverGroups: map[string]string{
+			"platform-team": "S0PLATFORM",
+			"sre-team":      "S0SRE",
+			"security-team": "S0SECURITY",
+			"data-team":     "S0DATA",
+		},
+	})
+	notifier.apiURL = server.URL
+
+	if err := notifier.NotifyPendingRequest(context.Background(), newProdRequest()); err != nil {
+		t.Fatalf("NotifyPendingRequest failed: %v", err)
+	}
+
+	if len(*messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(*messages))
+	}
+
+	msg := (*messages)[0]
+	if msg.Channel != "C0APPROVALS" {
+		t.Errorf("Expected message in C0APPROVALS, got %s", msg.Channel)
+	}
+
+	for _, group := range []string{"<!subteam^S0PLATFORM>", "<!subteam^S0SRE>", "<!subteam^S0SECURITY>"} {
+		if !strings.Contains(msg.Text, group) {
+			t.Errorf("Expected message to mention %s, got %q", group, msg.Text)
+		}
+	}
+	if strings.Contains(msg.Text, "S0DATA") {
+		t.Errorf("Message should not mention unassigned approver groups, got %q", msg.Text)
+	}
+
+	body, _ := json.Marshal(msg.Blocks)
+	for _, want := range []string{"jit-system/prod-request", "prod-east-1"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected blocks to contain %q", want)
+		}
+	}
+	// Nothing handles Slack interactivity callbacks, so the message offers no buttons
+	if strings.Contains(string(body), `"button"`) {
+		t.Errorf("Expected no buttons in the approver notification, got %s", body)
+	}
+}
+
+func TestNotifyPendingRequestDirectMessage(t *testing.T) {
+	server, messages := newTestSlackAPI(t)
+
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{})
+	notifier.apiURL = server.URL
+
+	req := newProdRequest()
+	req.Spec.Approvers = []string{"U0APPROVER1"}
+
+	if err := notifier.NotifyPendingRequest(context.Background(), req); err != nil {
+		t.Fatalf("NotifyPendingRequest failed: %v", err)
+	}
+
+	if len(*messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(*messages))
+	}
+	if (*messages)[0].Channel != "U0APPROVER1" {
+		t.Errorf("Expected direct message to U0APPROVER1, got %s", (*messages)[0].Channel)
+	}
+}
+
+func TestNotifyPendingRequestDelegates(t *testing.T) {
+	server, messages := newTestSlackAPI(t)
+
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{})
+	notifier.apiURL = server.URL
+	notifier.now = func() time.Time { return now }
+	notifier.EnableDelegations(controller.ApprovalDelegations{
+		{
+			Approver: "U0APPROVER1", Delegate: "U0DELEGATE1",
+			From: now.Add(-24 * time.Hour), Until: now.Add(24 * time.Hour),
+		},
+		{
+			Approver: "U0APPROVER1", Delegate: "U0DELEGATE2",
+			From: now.Add(24 * time.Hour), Until: now.Add(48 * time.Hour),
+		},
+	})
+
+	req := newProdRequest()
+	req.Spec.Approvers = []string{"U0APPROVER1"}
+
+	if err := notifier.NotifyPendingRequest(context.Background(), req); err != nil {
+		t.Fatalf("NotifyPendingRequest failed: %v", err)
+	}
+
+	var channels []string
+	for _, msg := range *messages {
+		channels = append(channels, msg.Channel)
+	}
+	if strings.Join(channels, ",") != "U0APPROVER1,U0DELEGATE1" {
+		t.Errorf("Expected the approver and their current delegate to be messaged, got %v", channels)
+	}
+	if len(req.Spec.Approvers) != 1 {
+		t.Errorf("Expected the request's approvers to be left unchanged, got %v", req.Spec.Approvers)
+	}
+}
+
+func TestNotifyPendingRequestWithoutChannel(t *testing.T) {
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{
+		ApproverGroups: map[string]string{"sre-team": "S0SRE"},
+	})
+
+	if err := notifier.NotifyPendingRequest(context.Background(), newProdRequest()); err == nil {
+		t.Error("Expected error when approver groups have no channel")
+	}
+}
+
+func TestLoadNotifierConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifier.json")
+	data := `{"channel":"C0APPROVALS","approverGroups":{"sre-team":"S0SRE"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadNotifierConfig(path)
+	if err != nil {
+		t.Fatalf("LoadNotifierConfig failed: %v", err)
+	}
+	if cfg.Channel != "C0APPROVALS" || cfg.ApproverGroups["sre-team"] != "S0SRE" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	if _, err := LoadNotifierConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing config file")
+	}
+}
+
+func TestNotifyBreakGlassAlertsChannel(t *testing.T) {
+	server, messages := newTestSlackAPI(t)
+
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{
+		Channel:        "C0APPROVALS",
+		ApproverGroups: map[string]string{"sre-team": "S0SRE"},
+	})
+	notifier.apiURL = server.URL
+
+	req := newProdRequest()
+	req.Spec.Permissions = []string{"view"}
+	req.Spec.Approvers = []string{"sre-team"}
+
+	if err := notifier.NotifyBreakGlass(context.Background(), req); err != nil {
+		t.Fatalf("NotifyBreakGlass failed: %v", err)
+	}
+
+	if len(*messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(*messages))
+	}
+
+	msg := (*messages)[0]
+	for _, want := range []string{"<!here>", "<!subteam^S0SRE>", "break-glass"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("Expected alert to contain %q, got %q", want, msg.Text)
+		}
+	}
+
+	// Access is already granted, so there is nothing to approve
+	body, _ := json.Marshal(msg.Blocks)
+	if strings.Contains(string(body), "jit-system/") {
+		t.Error("Expected break-glass alert without the request reference approvers act on")
+	}
+}
+
+func TestNotifyDecisionRepliesInRequestThread(t *testing.T) {
+	server, messages := newTestSlackAPI(t)
+
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{
+		Channel:        "C0APPROVALS",
+		ApproverGroups: map[string]string{"sre-team": "S0SRE"},
+	})
+	notifier.apiURL = server.URL
+
+	req := newProdRequest()
+	req.Spec.Approvers = []string{"sre-team", "U0APPROVER1"}
+
+	if err := notifier.NotifyPendingRequest(context.Background(), req); err != nil {
+		t.Fatalf("NotifyPendingRequest failed: %v", err)
+	}
+
+	// The channel message starts the thread; the direct message does not replace it
+	if got := req.Annotations[controller.SlackChannelAnnotation]; got != "C0APPROVALS" {
+		t.Errorf("Expected thread channel C0APPROVALS, got %q", got)
+	}
+	threadTS := req.Annotations[controller.SlackThreadAnnotation]
+	if threadTS != "1700000000.000001" {
+		t.Fatalf("Expected thread ts of the first message, got %q", threadTS)
+	}
+
+	req.Status.Phase = controller.AccessPhaseApproved
+	if err := notifier.NotifyDecision(context.Background(), req); err != nil {
+		t.Fatalf("NotifyDecision failed: %v", err)
+	}
+
+	if len(*messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(*messages))
+	}
+	update := (*messages)[2]
+	if update.Channel != "C0APPROVALS" || update.ThreadTS != threadTS {
+		t.Errorf("Expected reply in thread %s of C0APPROVALS, got thread %q in %s",
+			threadTS, update.ThreadTS, update.Channel)
+	}
+	if !strings.Contains(update.Text, "approved") {
+		t.Errorf("Expected approval update, got %q", update.Text)
+	}
+	for _, msg := range (*messages)[:2] {
+		if msg.ThreadTS != "" {
+			t.Errorf("Expected the initial notifications to start threads, got reply to %s", msg.ThreadTS)
+		}
+	}
+}
+
+func TestNotifyDecisionWithoutThread(t *testing.T) {
+	notifier := NewApprovalNotifier("xoxb-test", NotifierConfig{Channel: "C0APPROVALS"})
+
+	req := newProdRequest()
+	req.Status.Phase = controller.AccessPhaseDenied
+	if err := notifier.NotifyDecision(context.Background(), req); err == nil {
+		t.Error("Expected error for a request without a slack thread")
+	}
+}
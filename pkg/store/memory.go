@@ -12,12 +12,18 @@ type MemoryStore struct {
 	mu       sync.RWMutex
 	clusters map[string]*models.Cluster
 	accesses map[string]*models.ClusterAccess
+	sessions map[string]string              // session name -> access ID
+	requests map[string]string              // JITAccessRequest name -> access ID
+	active   map[string]map[string]struct{} // user and cluster -> active access IDs
 }
 
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
 		clusters: make(map[string]*models.Cluster),
 		accesses: make(map[string]*models.ClusterAccess),
+		sessions: make(map[string]string),
+		requests: make(map[string]string),
+		active:   make(map[string]map[string]struct{}),
 	}
 }
 
@@ -91,9 +97,118 @@ func (s *MemoryStore) CreateAccess(access *models.ClusterAccess) error {
 	}
 
 	s.accesses[access.ID] = access
+	s.indexSession(access)
+	s.indexRequest(access)
+	s.indexActive(access)
 	return nil
 }
 
+// GetAccessBySession resolves an STS session name back to the access record it was granted for
+func (s *MemoryStore) GetAccessBySession(sessionName string) (*models.ClusterAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accessID, exists := s.sessions[sessionName]
+	if !exists {
+		return nil, fmt.Errorf("no access found for session %s", sessionName)
+	}
+
+	access, exists := s.accesses[accessID]
+	if !exists {
+		return nil, fmt.Errorf("access %s not found", accessID)
+	}
+	return access, nil
+}
+
+// indexSession records the session name -> access ID mapping; callers must hold the write lock
+func (s *MemoryStore) indexSession(access *models.ClusterAccess) {
+	if access.SessionName != "" {
+		s.sessions[access.SessionName] = access.ID
+	}
+}
+
+// GetAccessByRequestName resolves a JITAccessRequest name to the access record granted for it
+func (s *MemoryStore) GetAccessByRequestName(name string) (*models.ClusterAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accessID, exists := s.requests[name]
+	if !exists {
+		return nil, fmt.Errorf("no access found for request %s", name)
+	}
+
+	access, exists := s.accesses[accessID]
+	if !exists {
+		return nil, fmt.Errorf("access %s not found", accessID)
+	}
+	return access, nil
+}
+
+// indexRequest records the request name -> access ID mapping; callers must hold the write lock
+func (s *MemoryStore) indexRequest(access *models.ClusterAccess) {
+	if access.RequestName != "" {
+		s.requests[access.RequestName] = access.ID
+	}
+}
+
+// GetActiveAccessForUserCluster returns the user's active, unexpired access to the cluster, the most
+// recently requested one if the user holds several, or nil when there is none
+func (s *MemoryStore) GetActiveAccessForUserCluster(userID, clusterID string) (*models.ClusterAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.activeAccess(userID, clusterID, time.Now()), nil
+}
+
+// activeAccess resolves the active index, skipping records that have since been revoked, expired
+// or moved to another user or cluster in place; callers must hold the lock
+func (s *MemoryStore) activeAccess(userID, clusterID string, now time.Time) *models.ClusterAccess {
+	var latest *models.ClusterAccess
+	for id := range s.active[activeKey(userID, clusterID)] {
+		access, exists := s.accesses[id]
+		if !exists || access.UserID != userID || access.ClusterID != clusterID || !isActive(access, now) {
+			continue
+		}
+		if latest == nil || access.RequestedAt.After(latest.RequestedAt) {
+			latest = access
+		}
+	}
+	return latest
+}
+
+// indexActive adds the access to, or removes it from, its user and cluster's active set; callers
+// must hold the write lock
+func (s *MemoryStore) indexActive(access *models.ClusterAccess) {
+	if !isActive(access, time.Now()) {
+		s.unindexActive(access)
+		return
+	}
+	key := activeKey(access.UserID, access.ClusterID)
+	if s.active[key] == nil {
+		s.active[key] = make(map[string]struct{})
+	}
+	s.active[key][access.ID] = struct{}{}
+}
+
+// unindexActive removes the access from its user and cluster's active set; callers must hold the
+// write lock
+func (s *MemoryStore) unindexActive(access *models.ClusterAccess) {
+	key := activeKey(access.UserID, access.ClusterID)
+	delete(s.active[key], access.ID)
+	if len(s.active[key]) == 0 {
+		delete(s.active, key)
+	}
+}
+
+func activeKey(userID, clusterID string) string {
+	return userID + "/" + clusterID
+}
+
+// isActive reports whether the access is active and unexpired
+func isActive(access *models.ClusterAccess, now time.Time) bool {
+	return access.Status == models.AccessStatusActive && (access.ExpiresAt == nil || !access.ExpiresAt.Before(now))
+}
+
 func (s *MemoryStore) GetAccess(id string) (*models.ClusterAccess, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -118,6 +233,30 @@ func (s *MemoryStore) ListUserAccesses(userID string) ([]*models.ClusterAccess,
 	return accesses, nil
 }
 
+// CountActiveAccesses counts the user's active, unexpired accesses
+func (s *MemoryStore) CountActiveAccesses(userID string) (int, error) {
+	return s.countActive(func(access *models.ClusterAccess) bool { return access.UserID == userID }), nil
+}
+
+// CountActiveClusterAccesses counts the active, unexpired accesses to a cluster across all users
+func (s *MemoryStore) CountActiveClusterAccesses(clusterID string) (int, error) {
+	return s.countActive(func(access *models.ClusterAccess) bool { return access.ClusterID == clusterID }), nil
+}
+
+func (s *MemoryStore) countActive(match func(access *models.ClusterAccess) bool) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	count := 0
+	for _, access := range s.accesses {
+		if isActive(access, now) && match(access) {
+			count++
+		}
+	}
+	return count
+}
+
 // CreateClusterAccess creates a new cluster access record (alias for CreateAccess)
 func (s *MemoryStore) CreateClusterAccess(access *models.ClusterAccess) error {
 	return s.CreateAccess(access)
@@ -136,6 +275,30 @@ func (s *MemoryStore) UpdateClusterAccess(access *models.ClusterAccess) error {
 	}
 
 	s.accesses[access.ID] = access
+	s.indexSession(access)
+	s.indexRequest(access)
+	s.indexActive(access)
+	return nil
+}
+
+// DeleteAccess removes an access record along with its session, request and active index entries
+func (s *MemoryStore) DeleteAccess(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	access, exists := s.accesses[id]
+	if !exists {
+		return fmt.Errorf("access %s not found", id)
+	}
+
+	delete(s.accesses, id)
+	if s.sessions[access.SessionName] == id {
+		delete(s.sessions, access.SessionName)
+	}
+	if s.requests[access.RequestName] == id {
+		delete(s.requests, access.RequestName)
+	}
+	s.unindexActive(access)
 	return nil
 }
 
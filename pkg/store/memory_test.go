@@ -310,3 +310,260 @@ func TestListUserAccesses(t *testing.T) {
 		t.Errorf("Expected 0 accesses for user-789, got %d", len(accesses))
 	}
 }
+
+func TestCountActiveAccesses(t *testing.T) {
+	store := NewMemoryStore()
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	accesses := []*models.ClusterAccess{
+		{ID: "access-1", UserID: "user-123", Status: models.AccessStatusActive, ExpiresAt: &future},
+		{ID: "access-2", UserID: "user-123", Status: models.AccessStatusActive},
+		{ID: "access-3", UserID: "user-123", Status: models.AccessStatusActive, ExpiresAt: &past},
+		{ID: "access-4", UserID: "user-123", Status: models.AccessStatusPending},
+		{ID: "access-5", UserID: "user-456", Status: models.AccessStatusActive, ExpiresAt: &future},
+	}
+	for _, access := range accesses {
+		if err := store.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", access.ID, err)
+		}
+	}
+
+	count, err := store.CountActiveAccesses("user-123")
+	if err != nil {
+		t.Fatalf("CountActiveAccesses failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 active accesses for user-123, got %d", count)
+	}
+
+	count, err = store.CountActiveAccesses("user-789")
+	if err != nil {
+		t.Fatalf("CountActiveAccesses failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 active accesses for user-789, got %d", count)
+	}
+}
+
+func TestCountActiveClusterAccesses(t *testing.T) {
+	store := NewMemoryStore()
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	accesses := []*models.ClusterAccess{
+		{ID: "access-1", UserID: "user-123", ClusterID: "cluster-1", Status: models.AccessStatusActive},
+		{ID: "access-2", UserID: "user-456", ClusterID: "cluster-1",
+			Status: models.AccessStatusActive, ExpiresAt: &future},
+		{ID: "access-3", UserID: "user-789", ClusterID: "cluster-1",
+			Status: models.AccessStatusActive, ExpiresAt: &past},
+		{ID: "access-4", UserID: "user-123", ClusterID: "cluster-1", Status: models.AccessStatusRevoked},
+		{ID: "access-5", UserID: "user-123", ClusterID: "cluster-2", Status: models.AccessStatusActive},
+	}
+	for _, access := range accesses {
+		if err := store.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", access.ID, err)
+		}
+	}
+
+	count, err := store.CountActiveClusterAccesses("cluster-1")
+	if err != nil {
+		t.Fatalf("CountActiveClusterAccesses failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 active accesses to cluster-1, got %d", count)
+	}
+}
+
+func TestGetAccessBySession(t *testing.T) {
+	store := NewMemoryStore()
+
+	access := &models.ClusterAccess{
+		ID:          "access-1",
+		ClusterID:   "cluster-1",
+		UserID:      "user-123",
+		Status:      models.AccessStatusActive,
+		SessionName: "jit-user-123-cluster-1-20240610-143022",
+	}
+	if err := store.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+
+	found, err := store.GetAccessBySession("jit-user-123-cluster-1-20240610-143022")
+	if err != nil {
+		t.Fatalf("GetAccessBySession failed: %v", err)
+	}
+	if found.ID != "access-1" {
+		t.Errorf("Expected access-1, got %s", found.ID)
+	}
+
+	// Sessions recorded after creation are indexed on update
+	unindexed := &models.ClusterAccess{ID: "access-2", UserID: "user-456", Status: models.AccessStatusActive}
+	if err := store.CreateAccess(unindexed); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+	unindexed.SessionName = "jit-user-456-cluster-1-20240610-150000"
+	if err := store.UpdateClusterAccess(unindexed); err != nil {
+		t.Fatalf("Failed to update access: %v", err)
+	}
+
+	found, err = store.GetAccessBySession("jit-user-456-cluster-1-20240610-150000")
+	if err != nil {
+		t.Fatalf("GetAccessBySession failed after update: %v", err)
+	}
+	if found.ID != "access-2" {
+		t.Errorf("Expected access-2, got %s", found.ID)
+	}
+
+	if _, err := store.GetAccessBySession("jit-unknown"); err == nil {
+		t.Error("Resolving an unknown session should return error")
+	}
+}
+
+func TestGetAccessByRequestName(t *testing.T) {
+	store := NewMemoryStore()
+
+	access := &models.ClusterAccess{
+		ID:          "access-1",
+		RequestName: "jit-user-123-1718020800",
+		UserID:      "user-123",
+		Status:      models.AccessStatusActive,
+	}
+	if err := store.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+
+	found, err := store.GetAccessByRequestName("jit-user-123-1718020800")
+	if err != nil {
+		t.Fatalf("GetAccessByRequestName failed: %v", err)
+	}
+	if found.ID != "access-1" {
+		t.Errorf("Expected access-1, got %s", found.ID)
+	}
+
+	if _, err := store.GetAccessByRequestName("jit-unknown"); err == nil {
+		t.Error("Resolving an unknown request should return error")
+	}
+}
+
+func TestGetActiveAccessForUserCluster(t *testing.T) {
+	store := NewMemoryStore()
+
+	found, err := store.GetActiveAccessForUserCluster("user-123", "cluster-1")
+	if err != nil {
+		t.Fatalf("GetActiveAccessForUserCluster failed: %v", err)
+	}
+	if found != nil {
+		t.Fatalf("Expected no active access, got %s", found.ID)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	accesses := []*models.ClusterAccess{
+		{ID: "active", UserID: "user-123", ClusterID: "cluster-1", Status: models.AccessStatusActive},
+		{ID: "other-cluster", UserID: "user-123", ClusterID: "cluster-2", Status: models.AccessStatusActive},
+		{
+			ID: "expired", UserID: "user-456", ClusterID: "cluster-1",
+			Status: models.AccessStatusActive, ExpiresAt: &expired,
+		},
+		{ID: "revoked", UserID: "user-789", ClusterID: "cluster-1", Status: models.AccessStatusRevoked},
+	}
+	for _, access := range accesses {
+		if err := store.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", access.ID, err)
+		}
+	}
+
+	found, err = store.GetActiveAccessForUserCluster("user-123", "cluster-1")
+	if err != nil {
+		t.Fatalf("GetActiveAccessForUserCluster failed: %v", err)
+	}
+	if found == nil || found.ID != "active" {
+		t.Fatalf("Expected the active access, got %v", found)
+	}
+
+	for _, user := range []string{"user-456", "user-789"} {
+		found, err = store.GetActiveAccessForUserCluster(user, "cluster-1")
+		if err != nil {
+			t.Fatalf("GetActiveAccessForUserCluster failed: %v", err)
+		}
+		if found != nil {
+			t.Errorf("Expected no active access for %s, got %s", user, found.ID)
+		}
+	}
+
+	// Revoking the access removes it from the index
+	accesses[0].Status = models.AccessStatusRevoked
+	if err := store.UpdateClusterAccess(accesses[0]); err != nil {
+		t.Fatalf("Failed to update access: %v", err)
+	}
+	found, err = store.GetActiveAccessForUserCluster("user-123", "cluster-1")
+	if err != nil {
+		t.Fatalf("GetActiveAccessForUserCluster failed: %v", err)
+	}
+	if found != nil {
+		t.Errorf("Expected no active access after revocation, got %s", found.ID)
+	}
+}
+
+func TestGetActiveAccessForUserClusterReturnsLatest(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+
+	for i, id := range []string{"older", "newer"} {
+		access := &models.ClusterAccess{
+			ID:          id,
+			UserID:      "user-123",
+			ClusterID:   "cluster-1",
+			Status:      models.AccessStatusActive,
+			RequestedAt: now.Add(time.Duration(i) * time.Minute),
+		}
+		if err := store.CreateAccess(access); err != nil {
+			t.Fatalf("Failed to create %s: %v", id, err)
+		}
+	}
+
+	found, err := store.GetActiveAccessForUserCluster("user-123", "cluster-1")
+	if err != nil {
+		t.Fatalf("GetActiveAccessForUserCluster failed: %v", err)
+	}
+	if found == nil || found.ID != "newer" {
+		t.Errorf("Expected the most recent access, got %v", found)
+	}
+}
+
+func TestDeleteAccess(t *testing.T) {
+	store := NewMemoryStore()
+
+	access := &models.ClusterAccess{
+		ID:          "access-1",
+		UserID:      "user-123",
+		ClusterID:   "cluster-1",
+		Status:      models.AccessStatusActive,
+		SessionName: "jit-user-123-cluster-1",
+		RequestName: "request-1",
+	}
+	if err := store.CreateAccess(access); err != nil {
+		t.Fatalf("Failed to create access: %v", err)
+	}
+
+	if err := store.DeleteAccess("access-1"); err != nil {
+		t.Fatalf("DeleteAccess failed: %v", err)
+	}
+
+	found, err := store.GetActiveAccessForUserCluster("user-123", "cluster-1")
+	if err != nil {
+		t.Fatalf("GetActiveAccessForUserCluster failed: %v", err)
+	}
+	if found != nil {
+		t.Errorf("Expected no active access after deletion, got %s", found.ID)
+	}
+	if _, err := store.GetAccessBySession("jit-user-123-cluster-1"); err == nil {
+		t.Error("Expected the session index entry to be removed")
+	}
+	if _, err := store.GetAccessByRequestName("request-1"); err == nil {
+		t.Error("Expected the request index entry to be removed")
+	}
+	if err := store.DeleteAccess("access-1"); err == nil {
+		t.Error("Deleting an unknown access should return error")
+	}
+}
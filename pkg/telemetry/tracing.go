@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -27,6 +30,15 @@ var (
 	tracer oteltrace.Tracer
 )
 
+// DefaultSampleRate is the fraction of traces sampled in environments without a rate of their own
+const DefaultSampleRate = 0.1
+
+// DefaultSampleRates are the per-environment sampling fractions used unless configured otherwise
+var DefaultSampleRates = map[string]float64{
+	"production": 0.01,
+	"staging":    0.05,
+}
+
 // TracingConfig holds configuration for tracing
 type TracingConfig struct {
 	Enabled     bool    `yaml:"enabled"     json:"enabled"`
@@ -34,7 +46,61 @@ type TracingConfig struct {
 	Endpoint    string  `yaml:"endpoint"    json:"endpoint"`
 	ServiceName string  `yaml:"serviceName" json:"serviceName"`
 	Environment string  `yaml:"environment" json:"environment"`
-	SampleRate  float64 `yaml:"sampleRate"  json:"sampleRate"`
+	SampleRate  float64 `yaml:"sampleRate"  json:"sampleRate"` // 0 uses the environment's rate
+
+	// SampleRates maps environments to the fraction of traces sampled there; nil uses DefaultSampleRates
+	SampleRates map[string]float64 `yaml:"sampleRates" json:"sampleRates"`
+}
+
+// EffectiveSampleRate returns the fraction of traces sampled: the environment's configured rate,
+// then SampleRate, then the environment's default rate, then DefaultSampleRate
+func (c TracingConfig) EffectiveSampleRate() float64 {
+	env := getEnvironment(c.Environment)
+	if rate, ok := c.SampleRates[env]; ok {
+		return rate
+	}
+	if c.SampleRate > 0 {
+		return c.SampleRate
+	}
+	if rate, ok := DefaultSampleRates[env]; ok {
+		return rate
+	}
+	return DefaultSampleRate
+}
+
+// ParseSampleRates parses per-environment sample rates such as "production=0.01,staging=0.05"
+func ParseSampleRates(value string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		env, rawRate, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(env) == "" {
+			return nil, fmt.Errorf("invalid sample rate %q: want environment=rate", pair)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid sample rate for %s: %q is not between 0 and 1", env, rawRate)
+		}
+		rates[strings.TrimSpace(env)] = rate
+	}
+	return rates, nil
+}
+
+// FormatSampleRates renders rates in the form ParseSampleRates reads, sorted by environment
+func FormatSampleRates(rates map[string]float64) string {
+	envs := make([]string, 0, len(rates))
+	for env := range rates {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+
+	pairs := make([]string, 0, len(envs))
+	for _, env := range envs {
+		pairs = append(pairs, env+"="+strconv.FormatFloat(rates[env], 'g', -1, 64))
+	}
+	return strings.Join(pairs, ",")
 }
 
 // InitTracing initializes OpenTelemetry tracing
@@ -75,15 +141,10 @@ func InitTracing(ctx context.Context, config TracingConfig) (*trace.TracerProvid
 	}
 
 	// Create tracer provider
-	sampleRate := config.SampleRate
-	if sampleRate <= 0 {
-		sampleRate = 0.1 // Default 10% sampling
-	}
-
 	tp := trace.NewTracerProvider(
 		trace.WithBatcher(exporter),
 		trace.WithResource(res),
-		trace.WithSampler(trace.TraceIDRatioBased(sampleRate)),
+		trace.WithSampler(trace.TraceIDRatioBased(config.EffectiveSampleRate())),
 	)
 
 	// Set global providers
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveSampleRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TracingConfig
+		want   float64
+	}{
+		{name: "production default", config: TracingConfig{Environment: "production"}, want: 0.01},
+		{name: "staging default", config: TracingConfig{Environment: "staging"}, want: 0.05},
+		{name: "development default", config: TracingConfig{Environment: "development"}, want: DefaultSampleRate},
+		{
+			name:   "configured environment rate",
+			config: TracingConfig{Environment: "production", SampleRates: map[string]float64{"production": 0.2}},
+			want:   0.2,
+		},
+		{
+			name:   "single rate overrides defaults",
+			config: TracingConfig{Environment: "production", SampleRate: 0.5},
+			want:   0.5,
+		},
+		{
+			name: "environment rate wins over single rate",
+			config: TracingConfig{
+				Environment: "staging",
+				SampleRate:  0.5,
+				SampleRates: map[string]float64{"staging": 0.25},
+			},
+			want: 0.25,
+		},
+		{
+			name:   "configured zero disables sampling",
+			config: TracingConfig{Environment: "production", SampleRates: map[string]float64{"production": 0}},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.config.EffectiveSampleRate())
+		})
+	}
+}
+
+func TestParseSampleRates(t *testing.T) {
+	rates, err := ParseSampleRates(" production=0.02, staging=0.1,dev=1 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"production": 0.02, "staging": 0.1, "dev": 1}, rates)
+
+	rates, err = ParseSampleRates(FormatSampleRates(DefaultSampleRates))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSampleRates, rates)
+
+	for _, invalid := range []string{"production", "=0.1", "production=high", "production=1.5", "staging=-0.1"} {
+		_, err := ParseSampleRates(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestInitTracingUsesConfiguredSampleRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        float64
+		wantSampled bool
+	}{
+		{name: "always sampled", rate: 1, wantSampled: true},
+		{name: "never sampled", rate: 0, wantSampled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := InitTracing(t.Context(), TracingConfig{
+				Enabled:     true,
+				Exporter:    "otlp",
+				Endpoint:    "127.0.0.1:1",
+				Environment: "production",
+				SampleRates: map[string]float64{"production": tt.rate},
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+				_ = tp.Shutdown(ctx)
+			})
+
+			_, span := tp.Tracer("test").Start(t.Context(), "sampled")
+			span.End()
+			assert.Equal(t, tt.wantSampled, span.SpanContext().IsSampled())
+		})
+	}
+}
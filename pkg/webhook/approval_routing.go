@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"strings"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// ApprovalPermissionAnnotation records the requested permission whose approval requirements the
+// request was routed by
+const ApprovalPermissionAnnotation = controller.ApprovalPermissionAnnotation
+
+// approvalPermission returns the highest-risk permission of the request, which alone decides the
+// approvers it needs: asking for view alongside exec needs the same approvers as exec alone.
+// Elevated permissions always outrank the rest, whatever their risk weights, so lower-risk
+// permissions can never lower the bar.
+func (m *JITAccessRequestMutator) approvalPermission(permissions []string) string {
+	weights := m.riskWeights().Permissions
+
+	highest := ""
+	highestElevated, highestWeight := false, 0
+	for _, perm := range permissions {
+		perm = strings.ToLower(perm)
+		elevated := hasElevatedPermissions([]string{perm})
+		weight := weights[perm]
+
+		if highest == "" || (elevated && !highestElevated) ||
+			(elevated == highestElevated && weight > highestWeight) {
+			highest, highestElevated, highestWeight = perm, elevated, weight
+		}
+	}
+	return highest
+}
+
+// recordApprovalPermission annotates the request with the permission its approvers were chosen for
+func (m *JITAccessRequestMutator) recordApprovalPermission(req *controller.JITAccessRequest, permission string) {
+	if permission == "" {
+		delete(req.Annotations, ApprovalPermissionAnnotation)
+		return
+	}
+	req.Annotations[ApprovalPermissionAnnotation] = permission
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestSetApproversRoutesByHighestRiskPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		cluster     string
+		permissions []string
+		want        []string
+		wantRouted  string
+	}{
+		{
+			name:        "view alone in production",
+			cluster:     "prod-east-1",
+			permissions: []string{"view"},
+			want:        []string{"platform-team", "sre-team"},
+			wantRouted:  "view",
+		},
+		{
+			name:        "view and exec in production need the exec approvers",
+			cluster:     "prod-east-1",
+			permissions: []string{"view", "exec"},
+			want:        []string{"platform-team", "sre-team", "security-team"},
+			wantRouted:  "exec",
+		},
+		{
+			name:        "view alone in staging needs no approvers",
+			cluster:     "staging-east-1",
+			permissions: []string{"view"},
+			want:        []string{},
+			wantRouted:  "view",
+		},
+		{
+			name:        "view and exec in staging need the exec approvers",
+			cluster:     "staging-east-1",
+			permissions: []string{"view", "exec"},
+			want:        []string{"platform-team"},
+			wantRouted:  "exec",
+		},
+		{
+			name:        "riskiest elevated permission wins",
+			cluster:     "prod-east-1",
+			permissions: []string{"exec", "logs", "admin"},
+			want:        []string{"platform-team", "sre-team", "security-team"},
+			wantRouted:  "admin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+					Permissions:   tt.permissions,
+					Duration:      "1h",
+				},
+			}
+
+			m.setApprovers(req)
+
+			assert.ElementsMatch(t, tt.want, req.Spec.Approvers)
+			assert.Equal(t, tt.wantRouted, req.Annotations[ApprovalPermissionAnnotation])
+		})
+	}
+}
+
+func TestApprovalPermissionElevatedOutranksWeights(t *testing.T) {
+	// Misconfigured weights rank exec below logs; exec still decides the approvers
+	weights := DefaultRiskWeights
+	weights.Permissions = maps.Clone(DefaultRiskWeights.Permissions)
+	weights.Permissions["exec"] = 0
+	m := &JITAccessRequestMutator{RiskWeights: &weights}
+
+	assert.Equal(t, "exec", m.approvalPermission([]string{"logs", "exec"}))
+	assert.Equal(t, "logs", m.approvalPermission([]string{"view", "logs"}))
+	assert.Equal(t, "", m.approvalPermission(nil))
+}
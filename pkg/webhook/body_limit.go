@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// DefaultMaxBodyBytes caps admission request bodies when no limit is configured
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// limitBody rejects admission requests whose body exceeds maxBytes before they are decoded,
+// recording a "body_too_large" validation error for the webhook type
+func limitBody(webhookType string, maxBytes int64, next http.Handler) http.Handler {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tooLarge := func() {
+			metrics.RecordWebhookValidationError(webhookType, "body_too_large", "body")
+			http.Error(w, fmt.Sprintf("request body exceeds %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+		}
+
+		if r.ContentLength > maxBytes {
+			tooLarge()
+			return
+		}
+
+		// Content-Length may be absent or wrong, so read at most one byte past the limit
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+		_ = r.Body.Close()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			tooLarge()
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bodyTooLargeCount(t *testing.T, webhookType string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "jit_webhook_validation_errors_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["webhook_type"] == webhookType && labels["error_type"] == "body_too_large" {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		chunked    bool
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "body within limit is passed through",
+			body:       strings.Repeat("a", 64),
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "oversized body is rejected",
+			body:       strings.Repeat("a", 65),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "oversized body without content length is rejected",
+			body:       strings.Repeat("a", 1024),
+			chunked:    true,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rr := httptest.NewRecorder()
+
+			before := bodyTooLargeCount(t, "validating")
+			limitBody("validating", 64, next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantCalled, called)
+
+			recorded := bodyTooLargeCount(t, "validating") - before
+			if tt.wantCalled {
+				assert.Zero(t, recorded)
+			} else {
+				assert.Equal(t, float64(1), recorded)
+			}
+		})
+	}
+}
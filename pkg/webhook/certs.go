@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// CertName and KeyName are the serving certificate files, matching the controller-runtime webhook server defaults
+const (
+	CertName = "tls.crt"
+	KeyName  = "tls.key"
+)
+
+// DefaultCertWait is how long the operator waits at startup for cert-manager to populate the
+// serving certificate before giving up
+const DefaultCertWait = 2 * time.Minute
+
+// certPollInterval is how often WaitForCerts checks the certificate directory
+const certPollInterval = time.Second
+
+// CertDirOrDefault returns certDir, or the directory the webhook server reads when none is set
+func CertDirOrDefault(certDir string) string {
+	if certDir == "" {
+		return filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
+	return certDir
+}
+
+// certsPresent reports an error naming the first serving certificate file missing from certDir
+func certsPresent(certDir string) error {
+	for _, name := range []string{CertName, KeyName} {
+		info, err := os.Stat(filepath.Join(certDir, name))
+		if err != nil {
+			return fmt.Errorf("webhook certificate %s not available: %w", name, err)
+		}
+		if info.Size() == 0 {
+			return fmt.Errorf("webhook certificate %s is empty", name)
+		}
+	}
+	return nil
+}
+
+// WaitForCerts blocks until the serving certificate and key exist in certDir, polling every
+// interval, so webhooks are not registered before cert-manager has mounted their certificate.
+// It gives up with the last error seen once timeout elapses or ctx is cancelled.
+func WaitForCerts(ctx context.Context, certDir string, timeout, interval time.Duration) error {
+	if interval <= 0 {
+		interval = certPollInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := certsPresent(certDir)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(fmt.Errorf("timed out waiting for webhook certificates in %s", certDir), err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// CertsChecker is a readiness check that fails while the serving certificate is missing, for
+// example after cert-manager removed it during a rotation
+func CertsChecker(certDir string) healthz.Checker {
+	return func(_ *http.Request) error {
+		return certsPresent(certDir)
+	}
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCerts(t *testing.T, dir string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CertName), []byte("certificate"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, KeyName), []byte("key"), 0o600))
+}
+
+func TestWaitForCertsAlreadyPresent(t *testing.T) {
+	dir := t.TempDir()
+	writeCerts(t, dir)
+
+	assert.NoError(t, WaitForCerts(t.Context(), dir, time.Second, 10*time.Millisecond))
+}
+
+func TestWaitForCertsAppearAfterDelay(t *testing.T) {
+	dir := t.TempDir()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		// The key lands after the certificate, as with a projected secret being populated
+		_ = os.WriteFile(filepath.Join(dir, CertName), []byte("certificate"), 0o600)
+		time.Sleep(50 * time.Millisecond)
+		_ = os.WriteFile(filepath.Join(dir, KeyName), []byte("key"), 0o600)
+	}()
+
+	start := time.Now()
+	require.NoError(t, WaitForCerts(t.Context(), dir, 5*time.Second, 10*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestWaitForCertsTimesOut(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CertName), []byte("certificate"), 0o600))
+
+	err := WaitForCerts(t.Context(), dir, 100*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out waiting for webhook certificates")
+	assert.Contains(t, err.Error(), KeyName)
+}
+
+func TestWaitForCertsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	assert.Error(t, WaitForCerts(ctx, t.TempDir(), time.Minute, 10*time.Millisecond))
+}
+
+func TestCertsChecker(t *testing.T) {
+	dir := t.TempDir()
+	check := CertsChecker(dir)
+	req, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+	require.NoError(t, err)
+
+	assert.Error(t, check(req))
+
+	writeCerts(t, dir)
+	assert.NoError(t, check(req))
+
+	// An empty certificate, e.g. mid-rotation, is not ready
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CertName), nil, 0o600))
+	assert.ErrorContains(t, check(req), "is empty")
+}
+
+func TestCertDirOrDefault(t *testing.T) {
+	assert.Equal(t, "/certs", CertDirOrDefault("/certs"))
+	assert.Equal(t, filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"), CertDirOrDefault(""))
+}
@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// RegisteredCluster is a clusters.yaml entry: the cluster's coordinates plus its approval policy
+type RegisteredCluster struct {
+	controller.TargetCluster `json:",inline"`
+
+	// RequiredApprovers is the configured cluster's models.Cluster RequiredApprovers, see Config
+	RequiredApprovers int `json:"requiredApprovers,omitempty"`
+
+	// MaxActiveSessions caps simultaneous active sessions on the cluster across all users (0 = unlimited)
+	MaxActiveSessions int `json:"maxActiveSessions,omitempty"`
+
+	// Environment tags the cluster's environment when its name does not say, e.g. production for
+	// "devices-east"; empty derives it from the name
+	Environment string `json:"environment,omitempty"`
+
+	// ForbiddenPermissions are permissions no request may hold on the cluster, e.g. cluster-admin
+	// on a cluster downgraded to read-mostly access
+	ForbiddenPermissions []string `json:"forbiddenPermissions,omitempty"`
+}
+
+// clusterRegistryFile mirrors the clusters.yaml key of the operator ConfigMap
+type clusterRegistryFile struct {
+	Clusters []RegisteredCluster `json:"clusters"`
+}
+
+// LoadClusterRegistry reads the known clusters from a clusters.yaml file (as mounted from the
+// operator ConfigMap) and keys them by lowercase cluster name
+func LoadClusterRegistry(path string) (map[string]RegisteredCluster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster registry: %w", err)
+	}
+
+	var file clusterRegistryFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster registry: %w", err)
+	}
+
+	clusters := make(map[string]RegisteredCluster, len(file.Clusters))
+	for i, cluster := range file.Clusters {
+		if cluster.Name == "" {
+			return nil, fmt.Errorf("cluster %d: name is required", i)
+		}
+		if cluster.RequiredApprovers < 0 {
+			return nil, fmt.Errorf("cluster %s: requiredApprovers cannot be negative", cluster.Name)
+		}
+		if cluster.MaxActiveSessions < 0 {
+			return nil, fmt.Errorf("cluster %s: maxActiveSessions cannot be negative", cluster.Name)
+		}
+		clusters[strings.ToLower(cluster.Name)] = cluster
+	}
+
+	return clusters, nil
+}
+
+// Config returns the entry as a configured cluster
+func (c RegisteredCluster) Config() *models.Cluster {
+	return &models.Cluster{
+		ID:                c.Name,
+		Name:              c.Name,
+		AWSAccount:        c.AWSAccount,
+		Region:            c.Region,
+		Environment:       c.Environment,
+		RequiredApprovers: c.RequiredApprovers,
+		MaxActiveSessions: c.MaxActiveSessions,
+		Enabled:           true,
+	}
+}
+
+// ClusterConfigs returns the registry's entries as configured clusters keyed by lowercase name
+func ClusterConfigs(clusters map[string]RegisteredCluster) map[string]*models.Cluster {
+	configs := make(map[string]*models.Cluster, len(clusters))
+	for name, cluster := range clusters {
+		configs[name] = cluster.Config()
+	}
+	return configs
+}
+
+// ClusterEnvironments returns the registry's environment tags keyed by lowercase cluster name, for
+// the controller to classify clusters the same way the webhooks do
+func ClusterEnvironments(clusters map[string]RegisteredCluster) map[string]string {
+	environments := make(map[string]string)
+	for name, cluster := range clusters {
+		if cluster.Environment != "" {
+			environments[name] = strings.ToLower(cluster.Environment)
+		}
+	}
+	return environments
+}
+
+// resolveCluster fills the AWS account, region and endpoint of a request that only names its
+// cluster, so GitOps-managed requests need not repeat the cluster's coordinates
+func (m *JITAccessRequestMutator) resolveCluster(req *controller.JITAccessRequest) {
+	known, ok := m.Clusters[req.Spec.TargetCluster.Name]
+	if !ok {
+		return
+	}
+
+	target := &req.Spec.TargetCluster
+	if target.AWSAccount == "" {
+		target.AWSAccount = known.AWSAccount
+	}
+	if target.Region == "" {
+		target.Region = strings.ToLower(known.Region)
+	}
+	if target.Endpoint == "" {
+		target.Endpoint = known.Endpoint
+	}
+}
+
+// validateClusterPermissions denies permissions the target cluster's registry entry forbids
+func (v *JITAccessRequestValidator) validateClusterPermissions(req *controller.JITAccessRequest) error {
+	cluster, ok := v.Clusters[strings.ToLower(req.Spec.TargetCluster.Name)]
+	if !ok {
+		return nil
+	}
+
+	for _, permission := range req.Spec.Permissions {
+		if contains(cluster.ForbiddenPermissions, permission) {
+			return fmt.Errorf("%s is not permitted on cluster %s", permission, req.Spec.TargetCluster.Name)
+		}
+	}
+	return nil
+}
+
+// registryEnvironment returns the registry's environment tag for the named cluster, falling back to
+// the environment its name suggests
+func registryEnvironment(clusters map[string]RegisteredCluster, clusterName string) string {
+	if known, ok := clusters[strings.ToLower(clusterName)]; ok && known.Environment != "" {
+		return strings.ToLower(known.Environment)
+	}
+	return determineEnvironment(clusterName)
+}
+
+// clusterEnvironment returns the environment of the request's target cluster
+func (m *JITAccessRequestMutator) clusterEnvironment(req *controller.JITAccessRequest) string {
+	return registryEnvironment(m.Clusters, req.Spec.TargetCluster.Name)
+}
+
+// clusterEnvironment returns the environment of the request's target cluster
+func (v *JITAccessRequestValidator) clusterEnvironment(req *controller.JITAccessRequest) string {
+	return registryEnvironment(v.Clusters, req.Spec.TargetCluster.Name)
+}
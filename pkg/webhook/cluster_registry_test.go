@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+const testClusterRegistry = `clusters:
+  - name: "prod-east-1"
+    awsAccount: "123456789012"
+    region: "us-east-1"
+    endpoint: "https://abcdef123.gr7.us-east-1.eks.amazonaws.com"
+    maxDuration: "4h"
+    requireApproval: true
+  - name: "staging-east-1"
+    awsAccount: "123456789012"
+    region: "us-east-1"
+    requiredApprovers: 2
+  - name: "dev-west-2"
+    awsAccount: "987654321098"
+    region: "us-west-2"
+`
+
+func TestLoadClusterRegistry(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "clusters.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(testClusterRegistry), 0o600))
+
+	clusters, err := LoadClusterRegistry(valid)
+	require.NoError(t, err)
+	require.Len(t, clusters, 3)
+	assert.Equal(t, "123456789012", clusters["prod-east-1"].AWSAccount)
+	assert.Equal(t, "us-west-2", clusters["dev-west-2"].Region)
+	assert.Equal(t, 2, clusters["staging-east-1"].RequiredApprovers)
+	assert.Zero(t, clusters["prod-east-1"].RequiredApprovers)
+
+	// The quorum is read from the entries as configured clusters
+	configs := ClusterConfigs(clusters)
+	assert.Equal(t, 2, configs["staging-east-1"].RequiredApprovers)
+	assert.Equal(t, "staging-east-1", configs["staging-east-1"].Name)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("clusters:\n  - region: us-east-1\n"), 0o600))
+
+	_, err = LoadClusterRegistry(invalid)
+	assert.Error(t, err)
+
+	negative := filepath.Join(dir, "negative.yaml")
+	require.NoError(t, os.WriteFile(negative,
+		[]byte("clusters:\n  - name: dev-west-2\n    requiredApprovers: -1\n"), 0o600))
+
+	_, err = LoadClusterRegistry(negative)
+	assert.Error(t, err)
+}
+
+func TestMutatorCompletesMinimalSpec(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "clusters.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testClusterRegistry), 0o600))
+	clusters, err := LoadClusterRegistry(path)
+	require.NoError(t, err)
+
+	// What a platform team would commit to Git: no approvers, defaults, labels or AWS coordinates
+	minimal := &controller.JITAccessRequest{
+		TypeMeta:   metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall-prod-debug", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:        "U123456789A",
+			UserEmail:     "oncall@company.com",
+			TargetCluster: controller.TargetCluster{Name: "Prod-East-1"},
+			Reason:        "Investigating elevated error rates in checkout service",
+			Duration:      "2h",
+			Permissions:   []string{"edit"},
+		},
+	}
+
+	mutator := &JITAccessRequestMutator{Clusters: clusters}
+	mutator.mutate(minimal)
+
+	assert.Equal(t, controller.TargetCluster{
+		Name:       "prod-east-1",
+		AWSAccount: "123456789012",
+		Region:     "us-east-1",
+		Endpoint:   "https://abcdef123.gr7.us-east-1.eks.amazonaws.com",
+	}, minimal.Spec.TargetCluster)
+	assert.ElementsMatch(t, []string{"platform-team", "sre-team", "security-team"}, minimal.Spec.Approvers)
+	assert.False(t, minimal.Spec.RequestedAt.IsZero())
+	assert.Equal(t, controller.AccessPhasePending, minimal.Status.Phase)
+	assert.Equal(t, map[string]string{
+		"jit.rebelops.io/type":        "access-request",
+		"jit.rebelops.io/phase":       string(controller.AccessPhasePending),
+		"jit.rebelops.io/user":        "U123456789A",
+		"jit.rebelops.io/cluster":     "prod-east-1",
+		"jit.rebelops.io/environment": envProduction,
+	}, minimal.Labels)
+	assert.Equal(t, "3", minimal.Annotations[controller.RequiredApprovalsAnnotation])
+	assert.Equal(t, "2h", minimal.Annotations["jit.rebelops.io/duration"])
+
+	// The completed object passes admission validation
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	validator := &JITAccessRequestValidator{decoder: admission.NewDecoder(scheme)}
+
+	raw, err := json.Marshal(minimal)
+	require.NoError(t, err)
+
+	resp := validator.Handle(t.Context(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	assert.True(t, resp.Allowed, "expected completed request to be valid, got: %v", resp.Result)
+}
+
+func TestMutatorKeepsExplicitClusterDetails(t *testing.T) {
+	mutator := &JITAccessRequestMutator{Clusters: map[string]RegisteredCluster{
+		"dev-west-2": {TargetCluster: controller.TargetCluster{
+			Name: "dev-west-2", AWSAccount: "987654321098", Region: "us-west-2",
+		}},
+	}}
+
+	explicit := controller.TargetCluster{Name: "dev-west-2", AWSAccount: "111111111111", Region: "us-east-2"}
+	req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{TargetCluster: explicit}}
+	mutator.resolveCluster(req)
+
+	assert.Equal(t, "111111111111", req.Spec.TargetCluster.AWSAccount)
+	assert.Equal(t, "us-east-2", req.Spec.TargetCluster.Region)
+}
+
+func TestMutatorUsesClusterRequiredApprovers(t *testing.T) {
+	mutator := &JITAccessRequestMutator{ClusterConfigs: map[string]*models.Cluster{
+		"staging-east-1": {Name: "staging-east-1", RequiredApprovers: 2},
+	}}
+
+	tests := []struct {
+		name      string
+		cluster   string
+		requested string
+		want      string
+	}{
+		{name: "cluster default replaces the duration tier", cluster: "Staging-East-1", want: "2"},
+		{name: "request may raise the quorum", cluster: "staging-east-1", requested: "3", want: "3"},
+		{name: "request cannot lower the quorum", cluster: "staging-east-1", requested: "1", want: "2"},
+		{name: "unregistered cluster uses the duration tier", cluster: "dev-east-1", want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 30 minutes falls in the zero-approval duration tier
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+					Duration:      "30m",
+					Permissions:   []string{"view"},
+				},
+			}
+			if tt.requested != "" {
+				req.Annotations = map[string]string{controller.RequiredApprovalsAnnotation: tt.requested}
+			}
+
+			mutator.setApprovers(req)
+			assert.Equal(t, tt.want, req.Annotations[controller.RequiredApprovalsAnnotation])
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// ruleError tags a validation error with the specific rule that failed, so a check covering
+// several rules, such as the reason check, records which one a request tripped over
+type ruleError struct {
+	rule string
+	err  error
+}
+
+func (e *ruleError) Error() string { return e.err.Error() }
+
+func (e *ruleError) Unwrap() error { return e.err }
+
+// ruleErrorf formats a validation error for the given rule
+func ruleErrorf(rule, format string, args ...any) error {
+	return &ruleError{rule: rule, err: fmt.Errorf(format, args...)}
+}
+
+// deny denies the admission request and counts the denial in jit_webhook_validation_errors_total
+// under the rule that failed: the rule tagged on err, otherwise the check's own rule. The message
+// is prefixed unless prefix is empty.
+func deny(rule, field, prefix string, err error) admission.Response {
+	var tagged *ruleError
+	if errors.As(err, &tagged) {
+		rule = tagged.rule
+	}
+	metrics.RecordWebhookValidationError("validating", rule, field)
+
+	if prefix == "" {
+		return admission.Denied(err.Error())
+	}
+	return admission.Denied(fmt.Sprintf("%s: %v", prefix, err))
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// DenyRule hard-denies requests for a permission whose duration exceeds MaxDuration.
+// Unlike duration caps, matching requests are rejected outright rather than shortened.
+type DenyRule struct {
+	Permission  string `json:"permission"`
+	MaxDuration string `json:"maxDuration"`
+	Policy      string `json:"policy"`
+}
+
+// LoadDenyRules reads deny rules from a JSON file
+func LoadDenyRules(path string) ([]DenyRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deny rules: %w", err)
+	}
+
+	var rules []DenyRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse deny rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		if rule.Permission == "" {
+			return nil, fmt.Errorf("deny rule %d: permission is required", i)
+		}
+		if _, err := parseDuration(rule.MaxDuration); err != nil {
+			return nil, fmt.Errorf("deny rule %d: %w", i, err)
+		}
+	}
+
+	return rules, nil
+}
+
+func (v *JITAccessRequestValidator) validateDenyRules(req *controller.JITAccessRequest) error {
+	duration, err := parseDuration(req.Spec.Duration)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range v.DenyRules {
+		if !contains(req.Spec.Permissions, rule.Permission) {
+			continue
+		}
+
+		maxDuration, err := parseDuration(rule.MaxDuration)
+		if err != nil {
+			return fmt.Errorf("invalid deny rule for %s: %w", rule.Permission, err)
+		}
+		if duration > maxDuration {
+			return fmt.Errorf("%s for %s is not permitted beyond %s (policy: %s)",
+				rule.Permission, req.Spec.Duration, rule.MaxDuration, rule.Policy)
+		}
+	}
+
+	return nil
+}
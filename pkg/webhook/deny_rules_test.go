@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestValidateDenyRules(t *testing.T) {
+	privilegedRules := []DenyRule{
+		{Permission: "cluster-admin", MaxDuration: "24h", Policy: "privileged-access: cluster-admin is limited to 24h"},
+	}
+	customRules := []DenyRule{
+		{Permission: "edit", MaxDuration: "8h", Policy: "SEC-12"},
+	}
+
+	tests := []struct {
+		name        string
+		rules       []DenyRule
+		permissions []string
+		duration    string
+		wantErr     bool
+		errMsg      string
+	}{
+		{
+			name:        "cluster-admin for 7d is denied",
+			rules:       privilegedRules,
+			permissions: []string{"cluster-admin"},
+			duration:    "7d",
+			wantErr:     true,
+			errMsg:      "policy: privileged-access",
+		},
+		{
+			name:        "cluster-admin for 1h is allowed",
+			rules:       privilegedRules,
+			permissions: []string{"cluster-admin"},
+			duration:    "1h",
+			wantErr:     false,
+		},
+		{
+			name:        "cluster-admin at the rule limit is allowed",
+			rules:       privilegedRules,
+			permissions: []string{"cluster-admin"},
+			duration:    "24h",
+			wantErr:     false,
+		},
+		{
+			name:        "view for 7d is unaffected",
+			rules:       privilegedRules,
+			permissions: []string{"view"},
+			duration:    "7d",
+			wantErr:     false,
+		},
+		{
+			name:        "custom rule denies edit beyond limit",
+			rules:       customRules,
+			permissions: []string{"view", "edit"},
+			duration:    "1d",
+			wantErr:     true,
+			errMsg:      "policy: SEC-12",
+		},
+		{
+			name:        "custom rules only match their permission",
+			rules:       customRules,
+			permissions: []string{"cluster-admin"},
+			duration:    "7d",
+			wantErr:     false,
+		},
+		{
+			name:        "no rules by default",
+			permissions: []string{"cluster-admin"},
+			duration:    "7d",
+			wantErr:     false,
+		},
+		{
+			name:        "empty rule set disables deny rules",
+			rules:       []DenyRule{},
+			permissions: []string{"cluster-admin"},
+			duration:    "7d",
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &JITAccessRequestValidator{DenyRules: tt.rules}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					Permissions: tt.permissions,
+					Duration:    tt.duration,
+				},
+			}
+
+			err := v.validateDenyRules(req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadDenyRules(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid,
+		[]byte(`[{"permission":"cluster-admin","maxDuration":"4h","policy":"SEC-7"}]`), 0o600))
+
+	rules, err := LoadDenyRules(valid)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "cluster-admin", rules[0].Permission)
+	assert.Equal(t, "SEC-7", rules[0].Policy)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid,
+		[]byte(`[{"permission":"cluster-admin","maxDuration":"forever"}]`), 0o600))
+
+	_, err = LoadDenyRules(invalid)
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// killSwitchViolation is the security violation type recorded when a request is filed while the
+// kill switch is engaged
+const killSwitchViolation = "kill_switch"
+
+// validateKillSwitch denies every new request, emergency ones included, while the kill switch is
+// engaged. The switch fails closed: if its state cannot be read the request is denied too.
+func (v *JITAccessRequestValidator) validateKillSwitch(ctx context.Context, req *controller.JITAccessRequest) error {
+	if v.KillSwitch == nil {
+		return nil
+	}
+
+	engaged, reason, err := v.KillSwitch.Engaged(ctx, v.Client)
+	if err != nil {
+		return fmt.Errorf("unable to confirm the kill switch is clear: %w", err)
+	}
+	if !engaged {
+		return nil
+	}
+
+	metrics.RecordSecurityViolation(killSwitchViolation, req.Spec.UserID, req.Spec.TargetCluster.Name)
+	if reason == "" {
+		return errors.New("JIT access is suspended")
+	}
+	return fmt.Errorf("JIT access is suspended: %s", reason)
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestValidatorKillSwitch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, controller.AddToScheme(scheme))
+
+	killSwitch := func(engaged string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: controller.DefaultKillSwitchName, Namespace: "jit-system"},
+			Data: map[string]string{
+				controller.KillSwitchEngagedKey: engaged,
+				controller.KillSwitchReasonKey:  "INC-4242 credential leak",
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		objects     []client.Object
+		scheme      *runtime.Scheme
+		operation   admissionv1.Operation
+		emergency   bool
+		wantAllowed bool
+		wantMessage string
+	}{
+		{name: "no kill switch configmap", operation: admissionv1.Create, wantAllowed: true},
+		{
+			name:        "kill switch cleared",
+			objects:     []client.Object{killSwitch("false")},
+			operation:   admissionv1.Create,
+			wantAllowed: true,
+		},
+		{
+			name:        "kill switch engaged",
+			objects:     []client.Object{killSwitch("true")},
+			operation:   admissionv1.Create,
+			wantMessage: "JIT access is suspended: INC-4242 credential leak",
+		},
+		{
+			name:        "emergency request while engaged",
+			objects:     []client.Object{killSwitch("true")},
+			operation:   admissionv1.Create,
+			emergency:   true,
+			wantMessage: "JIT access is suspended",
+		},
+		{
+			name:        "updates to existing requests while engaged",
+			objects:     []client.Object{killSwitch("true")},
+			operation:   admissionv1.Update,
+			wantAllowed: true,
+		},
+		{
+			name:        "kill switch unreadable",
+			scheme:      runtime.NewScheme(),
+			operation:   admissionv1.Create,
+			wantMessage: "unable to confirm the kill switch is clear",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientScheme := scheme
+			if tt.scheme != nil {
+				clientScheme = tt.scheme
+			}
+			validator := &JITAccessRequestValidator{
+				Client:     fake.NewClientBuilder().WithScheme(clientScheme).WithObjects(tt.objects...).Build(),
+				KillSwitch: &controller.KillSwitch{Namespace: "jit-system"},
+				decoder:    admission.NewDecoder(scheme),
+			}
+
+			request := &controller.JITAccessRequest{
+				TypeMeta:   metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+				ObjectMeta: metav1.ObjectMeta{Name: "kill-switch-request", Namespace: "jit-system"},
+				Spec: controller.JITAccessRequestSpec{
+					UserID:    "U123456789A",
+					UserEmail: "engineer@company.com",
+					TargetCluster: controller.TargetCluster{
+						Name: "dev-east-1", AWSAccount: "123456789012", Region: "us-east-1",
+					},
+					Reason:      "Investigating elevated error rates in checkout",
+					Duration:    "1h",
+					Permissions: []string{"view"},
+				},
+			}
+			if tt.emergency {
+				request.Annotations = map[string]string{EmergencyAnnotation: "true"}
+			}
+			raw, err := json.Marshal(request)
+			require.NoError(t, err)
+
+			resp := validator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+
+			if tt.wantAllowed {
+				assert.True(t, resp.Allowed, "expected request to be allowed, got: %v", resp.Result)
+				return
+			}
+			require.False(t, resp.Allowed)
+			assert.Contains(t, resp.Result.Message, "kill switch engaged: ")
+			assert.Contains(t, resp.Result.Message, tt.wantMessage)
+		})
+	}
+}
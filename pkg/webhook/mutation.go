@@ -3,14 +3,19 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/rebelopsio/jit-bot/pkg/aws"
 	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
 )
 
 const (
@@ -18,10 +23,98 @@ const (
 	envStaging    = "staging"
 )
 
+// defaultPermissionAliases maps shorthand permission names to their canonical form
+var defaultPermissionAliases = map[string]string{
+	"ro":    "view",
+	"rw":    "edit",
+	"admin": "admin",
+}
+
+// SkipMutationAnnotation asks the mutator to admit a fully-formed request unchanged. It is only
+// honored for service accounts listed in SkipMutationServiceAccounts.
+const SkipMutationAnnotation = "jit.rebelops.io/skip-mutation"
+
+// DefaultProductionApprovers are the approvers assigned to production requests that no approver
+// policy matched, unless the operator's --default-production-approvers flag says otherwise
+var DefaultProductionApprovers = []string{"platform-team", "sre-team"}
+
+// FallbackApproversAnnotation marks requests whose approvers are the production fallback rather
+// than the result of an approver policy
+const FallbackApproversAnnotation = controller.FallbackApproversAnnotation
+
+// serviceAccountUserPrefix prefixes the admission username of every service account
+const serviceAccountUserPrefix = "system:serviceaccount:"
+
+// fallbackDefaultDuration is used when no requested permission has a configured default
+const fallbackDefaultDuration = time.Hour
+
+// defaultPermissionDurations maps permissions to the duration applied when none is requested.
+// When several permissions are requested the tightest default wins.
+var defaultPermissionDurations = map[string]time.Duration{
+	"view":          4 * time.Hour,
+	"logs":          4 * time.Hour,
+	"edit":          time.Hour,
+	"port-forward":  time.Hour,
+	"debug":         time.Hour,
+	"exec":          30 * time.Minute,
+	"admin":         30 * time.Minute,
+	"cluster-admin": 30 * time.Minute,
+}
+
 // JITAccessRequestMutator mutates JITAccessRequest resources
 type JITAccessRequestMutator struct {
 	Client  client.Client
 	decoder admission.Decoder
+
+	// PermissionAliases overrides the default permission alias table
+	PermissionAliases map[string]string
+
+	// DefaultDurations overrides the default duration per permission
+	DefaultDurations map[string]time.Duration
+
+	// ApprovalTiers scale the approval quorum with the requested duration, e.g.
+	// controller.DefaultApprovalTiers; nil disables them
+	ApprovalTiers []controller.ApprovalTier
+
+	// NamespaceApprovers maps namespaces to the approver teams that own them. Their teams are
+	// required in addition to the cluster-level approvers.
+	NamespaceApprovers map[string][]string
+
+	// Clusters is the registry of known clusters, keyed by lowercase name, used to fill in the
+	// AWS account, region and endpoint of requests that only name their cluster
+	Clusters map[string]RegisteredCluster
+
+	// ClusterConfigs are the configured clusters, keyed by lowercase name. A cluster's
+	// RequiredApprovers sets the default approval quorum of requests targeting it; zero leaves the
+	// quorum to the duration tiers.
+	ClusterConfigs map[string]*models.Cluster
+
+	// RiskWeights overrides the default weights used to score requests
+	RiskWeights *RiskWeights
+
+	// SensitiveNamespaces always need platform and security approval, even in development,
+	// e.g. DefaultSensitiveNamespaces
+	SensitiveNamespaces []string
+
+	// CanonicalDurations rewrites each valid duration to its canonical form, e.g. "90m" and "1h30m"
+	// both become "1h30m", so equal durations compare and label alike
+	CanonicalDurations bool
+
+	// DefaultProductionApprovers are assigned to production requests no approver policy matched;
+	// nil uses DefaultProductionApprovers
+	DefaultProductionApprovers []string
+
+	// SkipMutationServiceAccounts are the service accounts, as system:serviceaccount:<namespace>:<name>,
+	// allowed to skip mutation with the skip-mutation annotation (e.g. during migrations)
+	SkipMutationServiceAccounts []string
+
+	// RequesterServiceAccounts are the service accounts, such as the Slack bot's, trusted to file
+	// requests for the spec.userID they name; their requests are marked requester-verified
+	RequesterServiceAccounts []string
+
+	// PermissionPolicies are the requestable permissions; approval policy judges custom ones by the
+	// built-in permission they grant. nil uses aws.DefaultPermissionPolicies
+	PermissionPolicies aws.PermissionPolicies
 }
 
 // Handle mutates JITAccessRequest resources
@@ -32,11 +125,22 @@ func (m *JITAccessRequestMutator) Handle(ctx context.Context, req admission.Requ
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	// Apply mutations
-	m.setDefaults(accessReq)
-	m.normalizeData(accessReq)
-	m.injectMetadata(accessReq)
-	m.setApprovers(accessReq)
+	// Privileged callers may apply a fully-formed request as is; the validator still checks it
+	if accessReq.Annotations[SkipMutationAnnotation] == "true" {
+		if m.canSkipMutation(req.UserInfo.Username) {
+			return admission.Allowed("mutation skipped by annotation")
+		}
+		delete(accessReq.Annotations, SkipMutationAnnotation)
+	}
+
+	// Expand a namespace prefix before namespace owners are added as approvers
+	if err := m.expandNamespacePrefix(ctx, accessReq); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	m.mutate(accessReq)
+	m.verifyRequester(req, accessReq)
+	m.stripUnverifiedEmail(req, accessReq)
 
 	// Create patch
 	marshaledReq, err := json.Marshal(accessReq)
@@ -47,6 +151,11 @@ func (m *JITAccessRequestMutator) Handle(ctx context.Context, req admission.Requ
 	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledReq)
 }
 
+// canSkipMutation reports whether the admission user is a service account allowed to skip mutation
+func (m *JITAccessRequestMutator) canSkipMutation(username string) bool {
+	return listedServiceAccount(m.SkipMutationServiceAccounts, username)
+}
+
 // InjectDecoder injects the decoder
 func (m *JITAccessRequestMutator) InjectDecoder(d admission.Decoder) error {
 	m.decoder = d
@@ -55,7 +164,21 @@ func (m *JITAccessRequestMutator) InjectDecoder(d admission.Decoder) error {
 
 // Mutation functions
 
+// mutate populates every derivable field, so a minimal spec (user, cluster name, reason,
+// duration and permissions) yields a complete request
+func (m *JITAccessRequestMutator) mutate(req *controller.JITAccessRequest) {
+	m.setDefaults(req)
+	m.normalizeData(req)
+	m.resolveCluster(req)
+	m.injectMetadata(req)
+	m.setApprovers(req)
+	m.setRiskScore(req)
+}
+
 func (m *JITAccessRequestMutator) setDefaults(req *controller.JITAccessRequest) {
+	// Structured permissions count as requested permissions
+	m.mergePermissionSpecs(req)
+
 	// Set default permissions if none specified
 	if len(req.Spec.Permissions) == 0 {
 		req.Spec.Permissions = []string{"view"}
@@ -63,7 +186,8 @@ func (m *JITAccessRequestMutator) setDefaults(req *controller.JITAccessRequest)
 
 	// Set default duration if not specified
 	if req.Spec.Duration == "" {
-		req.Spec.Duration = "1h"
+		levels := permissionLevels(m.PermissionPolicies, req.Spec.Permissions)
+		req.Spec.Duration = formatDuration(m.defaultDuration(levels))
 	}
 
 	// Set RequestedAt if not set
@@ -98,10 +222,15 @@ func (m *JITAccessRequestMutator) normalizeData(req *controller.JITAccessRequest
 	// Normalize region (lowercase)
 	req.Spec.TargetCluster.Region = strings.ToLower(req.Spec.TargetCluster.Region)
 
-	// Normalize permissions (lowercase and deduplicate)
+	// Normalize permissions (lowercase, expand aliases and deduplicate)
+	aliases := m.permissionAliases()
 	normalizedPerms := make(map[string]bool)
 	for _, perm := range req.Spec.Permissions {
-		normalizedPerms[strings.ToLower(perm)] = true
+		perm = strings.ToLower(perm)
+		if canonical, ok := aliases[perm]; ok {
+			perm = canonical
+		}
+		normalizedPerms[perm] = true
 	}
 
 	perms := make([]string, 0, len(normalizedPerms))
@@ -129,6 +258,45 @@ func (m *JITAccessRequestMutator) normalizeData(req *controller.JITAccessRequest
 
 	// Normalize duration format
 	req.Spec.Duration = normalizeDuration(req.Spec.Duration)
+	if m.CanonicalDurations {
+		req.Spec.Duration = canonicalDuration(req.Spec.Duration)
+	}
+}
+
+func (m *JITAccessRequestMutator) permissionAliases() map[string]string {
+	if m.PermissionAliases != nil {
+		return m.PermissionAliases
+	}
+	return defaultPermissionAliases
+}
+
+// defaultDuration returns the tightest configured default across the requested permissions
+func (m *JITAccessRequestMutator) defaultDuration(permissions []string) time.Duration {
+	durations := m.DefaultDurations
+	if durations == nil {
+		durations = defaultPermissionDurations
+	}
+	aliases := m.permissionAliases()
+
+	var tightest time.Duration
+	for _, perm := range permissions {
+		perm = strings.ToLower(perm)
+		if canonical, ok := aliases[perm]; ok {
+			perm = canonical
+		}
+		d, ok := durations[perm]
+		if !ok {
+			continue
+		}
+		if tightest == 0 || d < tightest {
+			tightest = d
+		}
+	}
+
+	if tightest == 0 {
+		return fallbackDefaultDuration
+	}
+	return tightest
 }
 
 func (m *JITAccessRequestMutator) injectMetadata(req *controller.JITAccessRequest) {
@@ -147,31 +315,99 @@ func (m *JITAccessRequestMutator) injectMetadata(req *controller.JITAccessReques
 	req.Annotations["jit.rebelops.io/duration"] = req.Spec.Duration
 	req.Annotations["jit.rebelops.io/cluster"] = req.Spec.TargetCluster.Name
 
+	// Record both identities on delegated requests
+	if req.Spec.OnBehalfOf != nil {
+		req.Annotations["jit.rebelops.io/requested-by"] = req.Spec.UserID
+		req.Annotations["jit.rebelops.io/on-behalf-of"] = req.Spec.OnBehalfOf.Email
+	}
+
 	// Add user metadata to labels
 	req.Labels["jit.rebelops.io/user"] = req.Spec.UserID
 	req.Labels["jit.rebelops.io/cluster"] = req.Spec.TargetCluster.Name
 
-	// Add environment label based on cluster name
-	env := determineEnvironment(req.Spec.TargetCluster.Name)
-	req.Labels["jit.rebelops.io/environment"] = env
+	// Add environment label from the cluster registry or, failing that, the cluster name
+	req.Labels["jit.rebelops.io/environment"] = m.clusterEnvironment(req)
+}
+
+// productionFallbackApprovers returns the approvers assigned when policy leaves a production request
+// without any
+func (m *JITAccessRequestMutator) productionFallbackApprovers() []string {
+	if len(m.DefaultProductionApprovers) > 0 {
+		return m.DefaultProductionApprovers
+	}
+	return DefaultProductionApprovers
 }
 
 func (m *JITAccessRequestMutator) setApprovers(req *controller.JITAccessRequest) {
+	if req.Annotations == nil {
+		req.Annotations = make(map[string]string)
+	}
+
+	// Approvers are routed by the highest-risk permission requested
+	permission := m.approvalPermission(permissionLevels(m.PermissionPolicies, req.Spec.Permissions))
+	m.recordApprovalPermission(req, permission)
+
 	// If approvers are already set, respect them
-	if len(req.Spec.Approvers) > 0 {
+	if len(req.Spec.Approvers) == 0 {
+		m.assignApprovers(req, permission)
+	}
+	m.markSensitiveNamespaces(req)
+
+	m.setRequiredApprovals(req)
+}
+
+// setRequiredApprovals records the approval quorum. By default every listed approver must approve;
+// duration tiers may only raise that, while the cluster's required approver count replaces it. A
+// request may ask for more approvals, never fewer, and elevated or production requests always need
+// at least one.
+func (m *JITAccessRequestMutator) setRequiredApprovals(req *controller.JITAccessRequest) {
+	duration, err := parseDuration(req.Spec.Duration)
+	if err != nil {
 		return
 	}
 
-	// Determine required approvers based on cluster and permissions
-	env := determineEnvironment(req.Spec.TargetCluster.Name)
-	hasElevatedPerms := hasElevatedPermissions(req.Spec.Permissions)
+	required := len(req.Spec.Approvers)
+	if m.ApprovalTiers != nil {
+		required = max(required, controller.RequiredApprovalsForDuration(m.ApprovalTiers, duration))
+	}
+	if config := m.ClusterConfigs[strings.ToLower(req.Spec.TargetCluster.Name)]; config != nil &&
+		config.RequiredApprovers > 0 {
+		required = config.RequiredApprovers
+	}
+
+	if requested, err := strconv.Atoi(req.Annotations[controller.RequiredApprovalsAnnotation]); err == nil &&
+		requested > required {
+		required = requested
+	}
+
+	elevated := hasElevatedPermissions(permissionLevels(m.PermissionPolicies, req.Spec.Permissions))
+	if m.clusterEnvironment(req) == envProduction || elevated {
+		required = max(required, 1)
+	}
+	req.Annotations[controller.RequiredApprovalsAnnotation] = strconv.Itoa(required)
+}
+
+// assignApprovers sets the approvers the policy requires for the cluster, routing permission and
+// namespaces
+func (m *JITAccessRequestMutator) assignApprovers(req *controller.JITAccessRequest, permission string) {
+	// Determine required approvers based on cluster and the routing permission
+	env := m.clusterEnvironment(req)
+	hasElevatedPerms := hasElevatedPermissions([]string{permission})
 
 	approvers := []string{}
+	fallback := false
 
 	// Production clusters always require approval
 	switch env {
 	case envProduction:
-		approvers = append(approvers, "platform-team", "sre-team")
+		// The production approver policy is keyed on the cluster name; clusters only the registry
+		// tags as production get the fallback approvers
+		if determineEnvironment(req.Spec.TargetCluster.Name) == envProduction {
+			approvers = append(approvers, "platform-team", "sre-team")
+		} else {
+			approvers = append(approvers, m.productionFallbackApprovers()...)
+			fallback = true
+		}
 
 		// Additional approval for elevated permissions in prod
 		if hasElevatedPerms {
@@ -185,6 +421,16 @@ func (m *JITAccessRequestMutator) setApprovers(req *controller.JITAccessRequest)
 	}
 	// Development environments don't require approval for basic access
 
+	// Namespace owners must approve access to their namespaces in every environment
+	for _, ns := range req.Spec.Namespaces {
+		approvers = append(approvers, m.NamespaceApprovers[strings.ToLower(ns)]...)
+	}
+
+	// Sensitive namespaces such as kube-system are never self-service
+	if len(m.requestedSensitiveNamespaces(req)) > 0 {
+		approvers = append(approvers, controller.SensitiveNamespaceApprovers...)
+	}
+
 	// Remove duplicates
 	uniqueApprovers := make(map[string]bool)
 	for _, approver := range approvers {
@@ -196,6 +442,11 @@ func (m *JITAccessRequestMutator) setApprovers(req *controller.JITAccessRequest)
 		finalApprovers = append(finalApprovers, approver)
 	}
 
+	// The controller never auto-approves requests routed to the fallback approvers
+	if fallback {
+		req.Annotations[FallbackApproversAnnotation] = "true"
+	}
+
 	req.Spec.Approvers = finalApprovers
 
 	// Update annotations to track auto-assigned approvers
@@ -206,23 +457,22 @@ func (m *JITAccessRequestMutator) setApprovers(req *controller.JITAccessRequest)
 
 // Helper functions
 
+// durationUnitReplacer spells out common duration units in the compact form. Longer words come
+// first so "minutes" is not rewritten as "m" + "utes".
+var durationUnitReplacer = strings.NewReplacer(
+	"minutes", "m",
+	"minute", "m",
+	"mins", "m",
+	"min", "m",
+	"hours", "h",
+	"hour", "h",
+	"days", "d",
+	"day", "d",
+)
+
 func normalizeDuration(duration string) string {
 	// Normalize common duration formats
-	replacements := map[string]string{
-		"min":     "m",
-		"mins":    "m",
-		"minute":  "m",
-		"minutes": "m",
-		"hour":    "h",
-		"hours":   "h",
-		"day":     "d",
-		"days":    "d",
-	}
-
-	normalized := strings.ToLower(duration)
-	for old, new := range replacements {
-		normalized = strings.ReplaceAll(normalized, old, new)
-	}
+	normalized := durationUnitReplacer.Replace(strings.ToLower(duration))
 
 	// Remove spaces
 	normalized = strings.ReplaceAll(normalized, " ", "")
@@ -230,24 +480,40 @@ func normalizeDuration(duration string) string {
 	return normalized
 }
 
-func determineEnvironment(clusterName string) string {
-	lowerName := strings.ToLower(clusterName)
+// canonicalDuration rewrites a duration in hours, minutes and seconds with no zero or overflowing
+// units, e.g. "90m" becomes "1h30m" and "1d" becomes "24h". Invalid durations are returned unchanged
+// for the validating webhook to reject.
+func canonicalDuration(duration string) string {
+	parsed, err := parseDuration(duration)
+	if err != nil || parsed <= 0 {
+		return duration
+	}
+	return formatDuration(parsed)
+}
 
-	if strings.Contains(lowerName, "prod") || strings.Contains(lowerName, "production") {
-		return envProduction
+// formatDuration renders a duration in the compact form accepted by parseDuration (e.g. "2h30m")
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "0s"
 	}
-	if strings.Contains(lowerName, "stag") || strings.Contains(lowerName, "staging") {
-		return envStaging
+
+	var b strings.Builder
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dh", h)
+		d -= h * time.Hour
 	}
-	if strings.Contains(lowerName, "dev") || strings.Contains(lowerName, "development") {
-		return "development"
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dm", m)
+		d -= m * time.Minute
 	}
-	if strings.Contains(lowerName, "qa") || strings.Contains(lowerName, "test") {
-		return "qa"
+	if s := d / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%ds", s)
 	}
+	return b.String()
+}
 
-	// Default to production for safety
-	return envProduction
+func determineEnvironment(clusterName string) string {
+	return controller.EnvironmentForClusterName(clusterName)
 }
 
 func hasElevatedPermissions(permissions []string) bool {
@@ -1,9 +1,23 @@
 package webhook
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
 )
 
 func TestDetermineEnvironment(t *testing.T) {
@@ -112,3 +126,590 @@ func TestHasElevatedPermissions(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeDataPermissionAliases(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		want        []string
+		wantValid   bool
+	}{
+		{
+			name:        "ro expands to view",
+			permissions: []string{"ro"},
+			want:        []string{"view"},
+			wantValid:   true,
+		},
+		{
+			name:        "uppercase RW expands to edit",
+			permissions: []string{"RW"},
+			want:        []string{"edit"},
+			wantValid:   true,
+		},
+		{
+			name:        "alias and canonical name deduplicate",
+			permissions: []string{"ro", "view"},
+			want:        []string{"view"},
+			wantValid:   true,
+		},
+		{
+			name:        "unknown alias is left untouched",
+			permissions: []string{"superuser"},
+			want:        []string{"superuser"},
+			wantValid:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					Permissions: tt.permissions,
+					Duration:    "1h",
+				},
+			}
+
+			m.normalizeData(req)
+			assert.ElementsMatch(t, tt.want, req.Spec.Permissions)
+
+			err := validatePermissions(nil, req.Spec.Permissions)
+			if tt.wantValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeDataCustomPermissionAliases(t *testing.T) {
+	m := &JITAccessRequestMutator{
+		PermissionAliases: map[string]string{"read": "view"},
+	}
+	req := &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{
+			Permissions: []string{"read", "ro"},
+		},
+	}
+
+	m.normalizeData(req)
+	assert.ElementsMatch(t, []string{"view", "ro"}, req.Spec.Permissions)
+}
+
+func TestSetDefaultsDurationByPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		duration    string
+		want        string
+	}{
+		{
+			name:        "view only gets the longer default",
+			permissions: []string{"view"},
+			want:        "4h",
+		},
+		{
+			name:        "admin gets the shorter default",
+			permissions: []string{"admin"},
+			want:        "30m",
+		},
+		{
+			name:        "tightest default wins",
+			permissions: []string{"view", "edit"},
+			want:        "1h",
+		},
+		{
+			name:        "no permissions defaults to view",
+			permissions: nil,
+			want:        "4h",
+		},
+		{
+			name:        "unknown permission falls back",
+			permissions: []string{"unknown"},
+			want:        "1h",
+		},
+		{
+			name:        "explicit duration is untouched",
+			permissions: []string{"admin"},
+			duration:    "2h",
+			want:        "2h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					Permissions: tt.permissions,
+					Duration:    tt.duration,
+				},
+			}
+
+			m.setDefaults(req)
+			assert.Equal(t, tt.want, req.Spec.Duration)
+			assert.NoError(t, validateDuration(req.Spec.Duration, DefaultMaxDuration))
+		})
+	}
+}
+
+func TestSetApproversQuorumByDuration(t *testing.T) {
+	tests := []struct {
+		name        string
+		cluster     string
+		permissions []string
+		duration    string
+		tiers       []controller.ApprovalTier
+		want        string
+	}{
+		{
+			name:     "tiers are off by default",
+			duration: "2d",
+			want:     "0",
+		},
+		{
+			name:     "20 minutes needs no approver",
+			duration: "20m",
+			tiers:    controller.DefaultApprovalTiers,
+			want:     "0",
+		},
+		{
+			name:     "2 hours needs one approver",
+			duration: "2h",
+			tiers:    controller.DefaultApprovalTiers,
+			want:     "1",
+		},
+		{
+			name:     "2 days needs two approvers",
+			duration: "2d",
+			tiers:    controller.DefaultApprovalTiers,
+			want:     "2",
+		},
+		{
+			name:     "custom tiers",
+			duration: "2h",
+			tiers: []controller.ApprovalTier{
+				{MaxDuration: 4 * time.Hour, Approvals: 0},
+				{MaxDuration: 0, Approvals: 3},
+			},
+			want: "0",
+		},
+		{
+			name:        "short tier never waives listed approvers",
+			cluster:     "staging-east-1",
+			permissions: []string{"cluster-admin"},
+			duration:    "20m",
+			tiers:       controller.DefaultApprovalTiers,
+			want:        "1",
+		},
+		{
+			name:        "short production request needs every listed approver",
+			cluster:     "prod-east-1",
+			permissions: []string{"view"},
+			duration:    "20m",
+			tiers:       controller.DefaultApprovalTiers,
+			want:        "2",
+		},
+		{
+			name:        "elevated request needs at least one approval",
+			permissions: []string{"edit"},
+			duration:    "20m",
+			tiers:       controller.DefaultApprovalTiers,
+			want:        "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := tt.cluster
+			if cluster == "" {
+				cluster = "dev-east-1"
+			}
+			m := &JITAccessRequestMutator{ApprovalTiers: tt.tiers}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: cluster},
+					Permissions:   tt.permissions,
+					Duration:      tt.duration,
+				},
+			}
+
+			m.setApprovers(req)
+			assert.Equal(t, tt.want, req.Annotations[controller.RequiredApprovalsAnnotation])
+		})
+	}
+}
+
+func TestSetApproversNamespaceOwners(t *testing.T) {
+	namespaceApprovers := map[string][]string{
+		"payments": {"payments-team"},
+		"ledger":   {"finance-team", "payments-team"},
+		"search":   {"search-team"},
+	}
+
+	tests := []struct {
+		name       string
+		cluster    string
+		namespaces []string
+		want       []string
+	}{
+		{
+			name:       "two namespaces with different owners require both teams",
+			cluster:    "dev-east-1",
+			namespaces: []string{"payments", "search"},
+			want:       []string{"payments-team", "search-team"},
+		},
+		{
+			name:       "namespace owners join cluster-level approvers",
+			cluster:    "prod-east-1",
+			namespaces: []string{"Payments"},
+			want:       []string{"payments-team", "platform-team", "sre-team"},
+		},
+		{
+			name:       "shared owners are deduplicated",
+			cluster:    "dev-east-1",
+			namespaces: []string{"payments", "ledger"},
+			want:       []string{"finance-team", "payments-team"},
+		},
+		{
+			name:       "unowned namespace adds nothing",
+			cluster:    "dev-east-1",
+			namespaces: []string{"default"},
+			want:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{NamespaceApprovers: namespaceApprovers}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+					Permissions:   []string{"view"},
+					Namespaces:    tt.namespaces,
+					Duration:      "1h",
+				},
+			}
+
+			m.setApprovers(req)
+			assert.ElementsMatch(t, tt.want, req.Spec.Approvers)
+		})
+	}
+}
+
+func TestSetApproversSensitiveNamespaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		cluster    string
+		namespaces []string
+		want       []string
+	}{
+		{
+			name:       "dev request touching kube-system needs approval",
+			cluster:    "dev-east-1",
+			namespaces: []string{"default", "kube-system"},
+			want:       []string{"platform-team", "security-team"},
+		},
+		{
+			name:       "sensitive namespaces match case-insensitively",
+			cluster:    "dev-east-1",
+			namespaces: []string{"Istio-System"},
+			want:       []string{"platform-team", "security-team"},
+		},
+		{
+			name:       "prod approvers gain the security team",
+			cluster:    "prod-east-1",
+			namespaces: []string{"kube-system"},
+			want:       []string{"platform-team", "security-team", "sre-team"},
+		},
+		{
+			name:       "dev request for other namespaces stays self-service",
+			cluster:    "dev-east-1",
+			namespaces: []string{"default"},
+			want:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{SensitiveNamespaces: DefaultSensitiveNamespaces}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+					Permissions:   []string{"view"},
+					Namespaces:    tt.namespaces,
+					Duration:      "1h",
+				},
+			}
+
+			m.setApprovers(req)
+			assert.ElementsMatch(t, tt.want, req.Spec.Approvers)
+			if len(tt.want) > 0 {
+				assert.NotEmpty(t, req.Annotations[SensitiveNamespacesAnnotation])
+			} else {
+				assert.NotContains(t, req.Annotations, SensitiveNamespacesAnnotation)
+			}
+		})
+	}
+}
+
+func TestSetApproversSensitiveNamespacesKeepsRequestedApprovers(t *testing.T) {
+	m := &JITAccessRequestMutator{SensitiveNamespaces: DefaultSensitiveNamespaces}
+	req := &controller.JITAccessRequest{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{SensitiveNamespacesAnnotation: ""}},
+		Spec: controller.JITAccessRequestSpec{
+			TargetCluster: controller.TargetCluster{Name: "dev-east-1"},
+			Permissions:   []string{"view"},
+			Namespaces:    []string{"default", "kube-system"},
+			Duration:      "1h",
+			Approvers:     []string{"my-teammate"},
+		},
+	}
+
+	m.setApprovers(req)
+
+	assert.ElementsMatch(t, []string{"my-teammate", "platform-team", "security-team"}, req.Spec.Approvers)
+	assert.Equal(t, "kube-system", req.Annotations[SensitiveNamespacesAnnotation])
+}
+
+func TestSetApproversProductionFallback(t *testing.T) {
+	clusters := map[string]RegisteredCluster{
+		"devices-east-1": {
+			TargetCluster: controller.TargetCluster{Name: "devices-east-1"},
+			Environment:   "Production",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		cluster      string
+		fallback     []string
+		want         []string
+		wantFallback bool
+	}{
+		{
+			name:         "cluster tagged production falls back to default approvers",
+			cluster:      "devices-east-1",
+			want:         []string{"platform-team", "sre-team"},
+			wantFallback: true,
+		},
+		{
+			name:         "configured fallback approvers",
+			cluster:      "devices-east-1",
+			fallback:     []string{"prod-oncall"},
+			want:         []string{"prod-oncall"},
+			wantFallback: true,
+		},
+		{
+			name:    "untagged dev cluster stays self-service",
+			cluster: "dev-east-1",
+			want:    []string{},
+		},
+		{
+			name:    "prod policy match needs no fallback",
+			cluster: "prod-east-1",
+			want:    []string{"platform-team", "sre-team"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{Clusters: clusters, DefaultProductionApprovers: tt.fallback}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+					Permissions:   []string{"view"},
+					Duration:      "1h",
+				},
+			}
+
+			m.setApprovers(req)
+			assert.ElementsMatch(t, tt.want, req.Spec.Approvers)
+			assert.Equal(t, tt.wantFallback, req.Annotations[FallbackApproversAnnotation] == "true")
+		})
+	}
+}
+
+func TestRegistryEnvironmentOverridesClusterName(t *testing.T) {
+	m := &JITAccessRequestMutator{Clusters: map[string]RegisteredCluster{
+		"dev-payments": {TargetCluster: controller.TargetCluster{Name: "dev-payments"}, Environment: "production"},
+	}}
+	req := &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{
+			TargetCluster: controller.TargetCluster{Name: "dev-payments"},
+			Permissions:   []string{"view"},
+			Duration:      "1h",
+		},
+	}
+
+	m.setApprovers(req)
+	assert.NotEmpty(t, req.Spec.Approvers, "a cluster tagged production must not be self-service")
+	assert.Equal(t, "true", req.Annotations[FallbackApproversAnnotation])
+
+	m.setRiskScore(req)
+	assert.Equal(t, strconv.Itoa(riskScore(m.riskWeights(), envProduction, req)), req.Annotations[RiskScoreAnnotation])
+}
+
+func TestCustomAdminPermissionRoutedAsClusterAdmin(t *testing.T) {
+	policies := aws.DefaultPermissionPolicies()
+	policies["superuser"] = aws.PermissionPolicy{PolicyArn: aws.EKSAdminPolicy, ClusterScoped: true}
+	m := &JITAccessRequestMutator{PermissionPolicies: policies}
+	req := &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{
+			TargetCluster: controller.TargetCluster{Name: "staging-east-1"},
+			Permissions:   []string{"superuser"},
+			Duration:      "1h",
+		},
+	}
+
+	m.setApprovers(req)
+	assert.Equal(t, "cluster-admin", req.Annotations[ApprovalPermissionAnnotation])
+	assert.Equal(t, []string{"platform-team"}, req.Spec.Approvers)
+	assert.Equal(t, "1", req.Annotations[controller.RequiredApprovalsAnnotation])
+	assert.True(t, controller.RequiresApprovalComment(req))
+}
+
+func TestInjectMetadataRegistryEnvironment(t *testing.T) {
+	m := &JITAccessRequestMutator{Clusters: map[string]RegisteredCluster{
+		"devices-east-1": {TargetCluster: controller.TargetCluster{Name: "devices-east-1"}, Environment: "production"},
+	}}
+	req := &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{TargetCluster: controller.TargetCluster{Name: "devices-east-1"}},
+	}
+	req.Labels = map[string]string{}
+
+	m.injectMetadata(req)
+	assert.Equal(t, envProduction, req.Labels["jit.rebelops.io/environment"])
+}
+
+func TestLoadNamespaceApprovers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "namespace-approvers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"Payments":["payments-team"],"search":["search-team"]}`), 0o600))
+
+	approvers, err := LoadNamespaceApprovers(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments-team"}, approvers["payments"])
+	assert.Equal(t, []string{"search-team"}, approvers["search"])
+
+	_, err = LoadNamespaceApprovers(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{in: 4 * time.Hour, want: "4h"},
+		{in: 30 * time.Minute, want: "30m"},
+		{in: 2*time.Hour + 30*time.Minute, want: "2h30m"},
+		{in: 90 * time.Second, want: "1m30s"},
+		{in: 0, want: "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatDuration(tt.in))
+		})
+	}
+}
+
+func TestNormalizeDataCanonicalDurations(t *testing.T) {
+	tests := []struct {
+		want   string
+		inputs []string
+	}{
+		{want: "1h", inputs: []string{"1h", "60m", "3600s", "60 minutes", "1 hour"}},
+		{want: "1h30m", inputs: []string{"90m", "1h30m", "5400s", "90 mins", "1h 30min"}},
+		{want: "24h", inputs: []string{"1d", "24h", "1440m", "1 day"}},
+		{want: "36h", inputs: []string{"1d12h", "36h", "2160m"}},
+		{want: "168h", inputs: []string{"1w", "7d", "168h"}},
+		{want: "forever", inputs: []string{"forever"}}, // invalid durations are left for the validator
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			for _, input := range tt.inputs {
+				m := &JITAccessRequestMutator{CanonicalDurations: true}
+				req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{Duration: input}}
+
+				m.normalizeData(req)
+				assert.Equal(t, tt.want, req.Spec.Duration, input)
+			}
+		})
+	}
+
+	// Without the option durations keep the requester's units
+	m := &JITAccessRequestMutator{}
+	req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{Duration: "90 minutes"}}
+	m.normalizeData(req)
+	assert.Equal(t, "90m", req.Spec.Duration)
+}
+
+func TestMutatorSkipMutationAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+
+	const migrator = "system:serviceaccount:jit-system:migrator"
+	mutator := &JITAccessRequestMutator{
+		decoder:                     admission.NewDecoder(scheme),
+		SkipMutationServiceAccounts: []string{migrator},
+	}
+
+	request := &controller.JITAccessRequest{
+		TypeMeta: metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "migrated-request",
+			Namespace:   "jit-system",
+			Annotations: map[string]string{SkipMutationAnnotation: "true"},
+		},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:        "U123456789A",
+			UserEmail:     "oncall@company.com",
+			TargetCluster: controller.TargetCluster{Name: "Prod-East-1"},
+			Reason:        "Migrating an approved request from the old system",
+			Duration:      "2h",
+			Permissions:   []string{"edit"},
+		},
+	}
+	raw, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		username   string
+		expectSkip bool
+	}{
+		{name: "listed service account", username: migrator, expectSkip: true},
+		{name: "unlisted service account", username: "system:serviceaccount:default:builder"},
+		{name: "user named like a listed account", username: "jit-system:migrator"},
+		{name: "human user", username: "alice@company.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := mutator.Handle(t.Context(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: raw},
+					UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+				},
+			})
+			require.True(t, resp.Allowed)
+
+			if tt.expectSkip {
+				assert.Empty(t, resp.Patches, "expected the request to be admitted unchanged")
+				return
+			}
+
+			// Unprivileged callers are mutated as usual and lose the annotation
+			paths := make([]string, 0, len(resp.Patches))
+			for _, patch := range resp.Patches {
+				paths = append(paths, patch.Path)
+			}
+			assert.Contains(t, paths, "/spec/approvers")
+			assert.Contains(t, paths, "/metadata/annotations/jit.rebelops.io~1skip-mutation")
+		})
+	}
+}
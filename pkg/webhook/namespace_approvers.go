@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadNamespaceApprovers reads the approver teams that own each namespace from a JSON file,
+// e.g. {"payments": ["payments-team"]}
+func LoadNamespaceApprovers(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace approvers: %w", err)
+	}
+
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace approvers: %w", err)
+	}
+
+	return normalizeNamespaceApprovers(raw), nil
+}
+
+// normalizeNamespaceApprovers lowercases namespace keys to match normalized request namespaces
+func normalizeNamespaceApprovers(raw map[string][]string) map[string][]string {
+	approvers := make(map[string][]string, len(raw))
+	for namespace, teams := range raw {
+		key := strings.ToLower(namespace)
+		approvers[key] = append(approvers[key], teams...)
+	}
+	return approvers
+}
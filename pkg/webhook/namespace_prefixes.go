@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// LoadNamespacePrefixes reads the namespace prefixes each team may request from a JSON file,
+// e.g. {"team-a": ["team-a-"]}
+func LoadNamespacePrefixes(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace prefixes: %w", err)
+	}
+
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace prefixes: %w", err)
+	}
+
+	prefixes := make(map[string][]string, len(raw))
+	for team, allowed := range raw {
+		key := strings.ToLower(team)
+		for _, prefix := range allowed {
+			prefix = normalizeNamespacePrefix(prefix)
+			if prefix == "" {
+				return nil, fmt.Errorf("team %s: namespace prefix must not be empty", team)
+			}
+			prefixes[key] = append(prefixes[key], prefix)
+		}
+	}
+
+	return prefixes, nil
+}
+
+// normalizeNamespacePrefix lowercases a prefix and drops its trailing wildcard, so "Team-A-*"
+// and "team-a-" name the same namespaces
+func normalizeNamespacePrefix(prefix string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(prefix)), "*")
+}
+
+// expandNamespacePrefix adds every existing namespace matching the request's prefix to its
+// namespaces. Namespaces created after admission are not covered by the request.
+func (m *JITAccessRequestMutator) expandNamespacePrefix(ctx context.Context, req *controller.JITAccessRequest) error {
+	if req.Spec.NamespacePrefix == "" || m.Client == nil {
+		return nil
+	}
+
+	prefix := normalizeNamespacePrefix(req.Spec.NamespacePrefix)
+	req.Spec.NamespacePrefix = prefix + "*"
+
+	var namespaces corev1.NamespaceList
+	if err := m.Client.List(ctx, &namespaces); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	for _, ns := range namespaces.Items {
+		if strings.HasPrefix(ns.Name, prefix) {
+			req.Spec.Namespaces = append(req.Spec.Namespaces, ns.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateNamespacePrefix requires a requested prefix to fall within one of the team's allowed
+// prefixes and to have matched at least one namespace, since no namespaces means cluster-wide
+func (v *JITAccessRequestValidator) validateNamespacePrefix(req *controller.JITAccessRequest) error {
+	if req.Spec.NamespacePrefix == "" {
+		return nil
+	}
+
+	prefix := normalizeNamespacePrefix(req.Spec.NamespacePrefix)
+	if prefix == "" {
+		return fmt.Errorf("namespace prefix must not be empty")
+	}
+	if contains(req.Spec.Permissions, "cluster-admin") {
+		return fmt.Errorf("cluster-admin permission applies cluster-wide, a namespace prefix should not be specified")
+	}
+	if req.Spec.Team == "" {
+		return fmt.Errorf("a team is required to request namespace prefix %s*", prefix)
+	}
+
+	allowed := false
+	for _, teamPrefix := range v.NamespacePrefixes[strings.ToLower(req.Spec.Team)] {
+		if strings.HasPrefix(prefix, teamPrefix) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("namespace prefix %s* is not allowed for team %s", prefix, req.Spec.Team)
+	}
+
+	for _, ns := range req.Spec.Namespaces {
+		if strings.HasPrefix(ns, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("namespace prefix %s* matches no namespaces", prefix)
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestLoadNamespacePrefixes(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "prefixes.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"Team-A": ["team-a-*", "shared-a-"]}`), 0o600))
+
+	prefixes, err := LoadNamespacePrefixes(valid)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"team-a": {"team-a-", "shared-a-"}}, prefixes)
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, []byte(`{"team-a": ["*"]}`), 0o600))
+
+	_, err = LoadNamespacePrefixes(empty)
+	assert.Error(t, err)
+}
+
+func TestMutatorExpandsNamespacePrefix(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	var namespaces []runtime.Object
+	for _, name := range []string{"team-a-api", "team-a-worker", "team-b-api", "kube-system"} {
+		namespaces = append(namespaces, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	mutator := &JITAccessRequestMutator{
+		Client:             fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(namespaces...).Build(),
+		NamespaceApprovers: map[string][]string{"team-a-worker": {"team-a-leads"}},
+	}
+
+	req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{
+		TargetCluster:   controller.TargetCluster{Name: "dev-east-1"},
+		Duration:        "1h",
+		Permissions:     []string{"edit"},
+		Namespaces:      []string{"team-a-api"},
+		NamespacePrefix: "Team-A-*",
+	}}
+	require.NoError(t, mutator.expandNamespacePrefix(t.Context(), req))
+	mutator.mutate(req)
+
+	assert.Equal(t, "team-a-*", req.Spec.NamespacePrefix)
+	assert.ElementsMatch(t, []string{"team-a-api", "team-a-worker"}, req.Spec.Namespaces)
+	// Owners of the expanded namespaces are required approvers
+	assert.Contains(t, req.Spec.Approvers, "team-a-leads")
+}
+
+func TestValidateNamespacePrefix(t *testing.T) {
+	validator := &JITAccessRequestValidator{
+		NamespacePrefixes: map[string][]string{"team-a": {"team-a-"}},
+	}
+
+	tests := []struct {
+		name        string
+		team        string
+		prefix      string
+		namespaces  []string
+		permissions []string
+		wantErr     string
+	}{
+		{name: "no prefix", team: "team-b"},
+		{
+			name: "allowed prefix", team: "Team-A", prefix: "team-a-*",
+			namespaces: []string{"team-a-api", "team-a-worker"},
+		},
+		{
+			name: "narrower prefix within the allowlist", team: "team-a", prefix: "team-a-api*",
+			namespaces: []string{"team-a-api"},
+		},
+		{
+			name: "prefix outside the team's allowlist", team: "team-a", prefix: "team-*",
+			namespaces: []string{"team-a-api", "team-b-api"}, wantErr: "not allowed for team team-a",
+		},
+		{
+			name: "other team's prefix", team: "team-b", prefix: "team-a-*",
+			namespaces: []string{"team-a-api"}, wantErr: "not allowed for team team-b",
+		},
+		{
+			name: "prefix without a team", prefix: "team-a-*",
+			namespaces: []string{"team-a-api"}, wantErr: "team is required",
+		},
+		{name: "prefix matching nothing", team: "team-a", prefix: "team-a-*", wantErr: "matches no namespaces"},
+		{
+			name: "cluster-admin", team: "team-a", prefix: "team-a-*", namespaces: []string{"team-a-api"},
+			permissions: []string{"cluster-admin"}, wantErr: "cluster-wide",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions := tt.permissions
+			if permissions == nil {
+				permissions = []string{"edit"}
+			}
+			req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{
+				Team:            tt.team,
+				Permissions:     permissions,
+				Namespaces:      tt.namespaces,
+				NamespacePrefix: tt.prefix,
+			}}
+
+			err := validator.validateNamespacePrefix(req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// validateNamespaceScoping denies elevated requests that would apply cluster-wide in environments
+// configured to require explicit namespaces. cluster-admin is cluster-wide by definition and is
+// governed by approvals instead; view-only access may stay cluster-wide.
+func (v *JITAccessRequestValidator) validateNamespaceScoping(req *controller.JITAccessRequest) error {
+	if len(v.RequireNamespacesEnvironments) == 0 {
+		return nil
+	}
+	if len(req.Spec.Namespaces) > 0 || req.Spec.NamespacePrefix != "" {
+		return nil
+	}
+	if contains(req.Spec.Permissions, "cluster-admin") || !hasElevatedPermissions(req.Spec.Permissions) {
+		return nil
+	}
+
+	env := v.clusterEnvironment(req)
+	for _, required := range v.RequireNamespacesEnvironments {
+		if strings.EqualFold(strings.TrimSpace(required), env) {
+			return fmt.Errorf("%s requests for %s must list the namespaces they need",
+				env, strings.Join(req.Spec.Permissions, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestValidatorRequiresNamespacesInConfiguredEnvironments(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+
+	tests := []struct {
+		name         string
+		environments []string
+		cluster      string
+		permissions  []string
+		namespaces   []string
+		wantAllowed  bool
+	}{
+		{
+			name:         "prod edit without namespaces",
+			environments: []string{"production"},
+			cluster:      "prod-east-1",
+			permissions:  []string{"edit"},
+		},
+		{
+			name:         "prod admin without namespaces",
+			environments: []string{"Production"},
+			cluster:      "prod-east-1",
+			permissions:  []string{"admin"},
+		},
+		{
+			name:         "dev edit without namespaces",
+			environments: []string{"production"},
+			cluster:      "dev-east-1",
+			permissions:  []string{"edit"},
+			wantAllowed:  true,
+		},
+		{
+			name:         "prod edit with namespaces",
+			environments: []string{"production"},
+			cluster:      "prod-east-1",
+			permissions:  []string{"edit"},
+			namespaces:   []string{"checkout"},
+			wantAllowed:  true,
+		},
+		{
+			name:         "prod view without namespaces",
+			environments: []string{"production"},
+			cluster:      "prod-east-1",
+			permissions:  []string{"view"},
+			wantAllowed:  true,
+		},
+		{
+			name:         "prod cluster-admin",
+			environments: []string{"production"},
+			cluster:      "prod-east-1",
+			permissions:  []string{"cluster-admin"},
+			wantAllowed:  true,
+		},
+		{
+			name:        "policy not configured",
+			cluster:     "prod-east-1",
+			permissions: []string{"edit"},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &JITAccessRequestValidator{
+				decoder:                       admission.NewDecoder(scheme),
+				RequireNamespacesEnvironments: tt.environments,
+			}
+
+			request := &controller.JITAccessRequest{
+				TypeMeta:   metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+				ObjectMeta: metav1.ObjectMeta{Name: "scoped-request", Namespace: "jit-system"},
+				Spec: controller.JITAccessRequestSpec{
+					UserID:    "U123456789A",
+					UserEmail: "engineer@company.com",
+					TargetCluster: controller.TargetCluster{
+						Name: tt.cluster, AWSAccount: "123456789012", Region: "us-east-1",
+					},
+					Reason: "Rolling back the checkout deployment after the failed release INC-4242, " +
+						"which is returning errors to customers",
+					Duration:    "1h",
+					Permissions: tt.permissions,
+					Namespaces:  tt.namespaces,
+				},
+			}
+			raw, err := json.Marshal(request)
+			require.NoError(t, err)
+
+			resp := validator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+
+			if tt.wantAllowed {
+				assert.True(t, resp.Allowed, "expected request to be allowed, got: %v", resp.Result)
+				return
+			}
+			require.False(t, resp.Allowed)
+			assert.Contains(t, resp.Result.Message, "namespaces required: production requests")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rebelopsio/jit-bot/pkg/aws"
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+var specResourceRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(/[a-z][a-z0-9]*)?$`)
+
+// mergePermissionSpecs normalizes the levels of structured permissions and lists them in
+// Permissions, and their namespaces in Namespaces, so approval policy, namespace owners, sensitive
+// namespaces, namespace scoping, deny rules and risk scoring see everything requested. Levels that
+// used the request's namespaces are pinned to them first, so merging never widens a level.
+func (m *JITAccessRequestMutator) mergePermissionSpecs(req *controller.JITAccessRequest) {
+	if len(req.Spec.PermissionSpecs) == 0 {
+		return
+	}
+
+	aliases := m.permissionAliases()
+	specced := make(map[string]bool, len(req.Spec.PermissionSpecs))
+	for i := range req.Spec.PermissionSpecs {
+		spec := &req.Spec.PermissionSpecs[i]
+		spec.Level = strings.ToLower(spec.Level)
+		if canonical, ok := aliases[spec.Level]; ok {
+			spec.Level = canonical
+		}
+		specced[spec.Level] = true
+	}
+
+	if requestNamespaces := slices.Clone(req.Spec.Namespaces); len(requestNamespaces) > 0 {
+		for i := range req.Spec.PermissionSpecs {
+			spec := &req.Spec.PermissionSpecs[i]
+			if len(spec.Namespaces) == 0 && m.PermissionPolicies.Level(spec.Level) != "cluster-admin" {
+				spec.Namespaces = requestNamespaces
+			}
+		}
+		for _, permission := range req.Spec.Permissions {
+			if !specced[permission] && m.PermissionPolicies.Level(permission) != "cluster-admin" {
+				req.Spec.PermissionSpecs = append(req.Spec.PermissionSpecs,
+					controller.PermissionSpec{Level: permission, Namespaces: requestNamespaces})
+				specced[permission] = true
+			}
+		}
+	}
+
+	for _, spec := range req.Spec.PermissionSpecs {
+		if !contains(req.Spec.Permissions, spec.Level) {
+			req.Spec.Permissions = append(req.Spec.Permissions, spec.Level)
+		}
+		for _, ns := range spec.Namespaces {
+			if !contains(req.Spec.Namespaces, ns) {
+				req.Spec.Namespaces = append(req.Spec.Namespaces, ns)
+			}
+		}
+	}
+}
+
+// validatePermissionSpecs checks structured permissions the way the string form is checked: each
+// level must be a valid permission listed in Permissions, at most once, with valid namespaces and
+// resources. Resource-limited specs share one inline policy, so they must name the same namespaces.
+func validatePermissionSpecs(policies aws.PermissionPolicies, req *controller.JITAccessRequest) error {
+	specs := req.Spec.PermissionSpecs
+	if len(specs) == 0 {
+		return nil
+	}
+
+	if req.Spec.ResourceScope != nil {
+		return fmt.Errorf("permission specs cannot be combined with a resource scope")
+	}
+
+	seen := make(map[string]bool, len(specs))
+	var resourceNamespaces []string
+	resourceSpecs := 0
+	for _, spec := range specs {
+		if _, ok := policies.Lookup(spec.Level); !ok {
+			return fmt.Errorf("invalid permission '%s'. Valid permissions are: %v", spec.Level, policies.Names())
+		}
+		if !contains(req.Spec.Permissions, spec.Level) {
+			return fmt.Errorf("%s is not listed in permissions", spec.Level)
+		}
+		if seen[spec.Level] {
+			return fmt.Errorf("duplicate permission spec for %s", spec.Level)
+		}
+		seen[spec.Level] = true
+
+		if err := validateNamespaces(spec.Namespaces, []string{spec.Level}); err != nil {
+			return fmt.Errorf("%s: %w", spec.Level, err)
+		}
+
+		if len(spec.Resources) == 0 {
+			continue
+		}
+		if policies.Level(spec.Level) == "cluster-admin" {
+			return fmt.Errorf("cluster-admin cannot be limited to resources")
+		}
+		for _, resource := range spec.Resources {
+			if !specResourceRegex.MatchString(resource) {
+				return fmt.Errorf("%s: invalid resource: %s", spec.Level, resource)
+			}
+		}
+
+		namespaces := spec.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = req.Spec.Namespaces
+		}
+		if resourceSpecs > 0 && !slices.Equal(namespaces, resourceNamespaces) {
+			return fmt.Errorf("permission specs limited to resources must name the same namespaces")
+		}
+		resourceNamespaces = namespaces
+		resourceSpecs++
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestMutatorMergesPermissionSpecs(t *testing.T) {
+	req := &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{
+			TargetCluster: controller.TargetCluster{Name: "dev-east-1"},
+			PermissionSpecs: []controller.PermissionSpec{
+				{Level: "Edit", Namespaces: []string{"payments"}, Resources: []string{"pods"}},
+			},
+		},
+	}
+
+	m := &JITAccessRequestMutator{}
+	m.setDefaults(req)
+	m.normalizeData(req)
+
+	// A structured level stands in for the string form rather than defaulting to view
+	assert.Equal(t, []string{"edit"}, req.Spec.Permissions)
+	assert.Equal(t, "edit", req.Spec.PermissionSpecs[0].Level)
+}
+
+func TestMutatorMergesPermissionSpecNamespaces(t *testing.T) {
+	req := &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{
+			TargetCluster: controller.TargetCluster{Name: "dev-east-1"},
+			Permissions:   []string{"exec"},
+			Namespaces:    []string{"default"},
+			PermissionSpecs: []controller.PermissionSpec{
+				{Level: "view", Namespaces: []string{"payments"}},
+				{Level: "logs"},
+			},
+		},
+	}
+
+	m := &JITAccessRequestMutator{NamespaceApprovers: map[string][]string{"payments": {"payments-team"}}}
+	m.setDefaults(req)
+	m.setApprovers(req)
+
+	assert.ElementsMatch(t, []string{"default", "payments"}, req.Spec.Namespaces)
+	assert.ElementsMatch(t, []controller.PermissionSpec{
+		{Level: "view", Namespaces: []string{"payments"}},
+		{Level: "logs", Namespaces: []string{"default"}},
+		{Level: "exec", Namespaces: []string{"default"}},
+	}, req.Spec.PermissionSpecs, "levels that used the request's namespaces keep them")
+	assert.Contains(t, req.Spec.Approvers, "payments-team")
+}
+
+func TestValidatePermissionSpecs(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		namespaces  []string
+		specs       []controller.PermissionSpec
+		scope       *controller.ResourceScope
+		wantErr     string
+	}{
+		{
+			name:        "legacy string form",
+			permissions: []string{"edit"},
+		},
+		{
+			name:        "level scoped to namespaces",
+			permissions: []string{"view", "edit"},
+			specs:       []controller.PermissionSpec{{Level: "edit", Namespaces: []string{"payments"}}},
+		},
+		{
+			name:        "level limited to resources",
+			permissions: []string{"edit"},
+			specs: []controller.PermissionSpec{
+				{Level: "edit", Namespaces: []string{"payments"}, Resources: []string{"pods", "pods/log"}},
+			},
+		},
+		{
+			name:        "unknown level",
+			permissions: []string{"superuser"},
+			specs:       []controller.PermissionSpec{{Level: "superuser"}},
+			wantErr:     "invalid permission 'superuser'",
+		},
+		{
+			name:        "level not listed in permissions",
+			permissions: []string{"view"},
+			specs:       []controller.PermissionSpec{{Level: "edit"}},
+			wantErr:     "edit is not listed in permissions",
+		},
+		{
+			name:        "duplicate level",
+			permissions: []string{"edit"},
+			specs:       []controller.PermissionSpec{{Level: "edit"}, {Level: "edit", Namespaces: []string{"web"}}},
+			wantErr:     "duplicate permission spec for edit",
+		},
+		{
+			name:        "cluster-admin with namespaces",
+			permissions: []string{"cluster-admin"},
+			specs:       []controller.PermissionSpec{{Level: "cluster-admin", Namespaces: []string{"payments"}}},
+			wantErr:     "cluster-admin permission applies cluster-wide",
+		},
+		{
+			name:        "cluster-admin limited to resources",
+			permissions: []string{"cluster-admin"},
+			specs:       []controller.PermissionSpec{{Level: "cluster-admin", Resources: []string{"pods"}}},
+			wantErr:     "cluster-admin cannot be limited to resources",
+		},
+		{
+			name:        "wildcard resource",
+			permissions: []string{"edit"},
+			specs:       []controller.PermissionSpec{{Level: "edit", Resources: []string{"*"}}},
+			wantErr:     "invalid resource: *",
+		},
+		{
+			name:        "resource specs in different namespaces",
+			permissions: []string{"view", "edit"},
+			namespaces:  []string{"default"},
+			specs: []controller.PermissionSpec{
+				{Level: "view", Resources: []string{"configmaps"}},
+				{Level: "edit", Namespaces: []string{"payments"}, Resources: []string{"pods"}},
+			},
+			wantErr: "must name the same namespaces",
+		},
+		{
+			name:        "combined with resource scope",
+			permissions: []string{"edit"},
+			specs:       []controller.PermissionSpec{{Level: "edit", Namespaces: []string{"payments"}}},
+			scope:       &controller.ResourceScope{Resources: []string{"pods"}, Verbs: []string{"get"}},
+			wantErr:     "cannot be combined with a resource scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{
+				Permissions:     tt.permissions,
+				Namespaces:      tt.namespaces,
+				PermissionSpecs: tt.specs,
+				ResourceScope:   tt.scope,
+			}}
+
+			err := validatePermissionSpecs(nil, req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// ApproverPreview is the approval policy outcome for a hypothetical request
+type ApproverPreview struct {
+	Cluster           string   `json:"cluster"`
+	Environment       string   `json:"environment"`
+	Permissions       []string `json:"permissions"`
+	Duration          string   `json:"duration"`
+	Approvers         []string `json:"approvers"`
+	RequiredApprovals int      `json:"required_approvals"`
+}
+
+// PreviewApprovers applies the same defaulting, normalization and approval policy as Handle to a
+// hypothetical request and reports the approvers it would be assigned. Nothing is persisted.
+func (m *JITAccessRequestMutator) PreviewApprovers(spec controller.JITAccessRequestSpec) (*ApproverPreview, error) {
+	req, err := m.PreviewRequest(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := strconv.Atoi(req.Annotations[controller.RequiredApprovalsAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine required approvals: %w", err)
+	}
+
+	approvers := append([]string{}, req.Spec.Approvers...)
+	sort.Strings(approvers)
+	permissions := append([]string{}, req.Spec.Permissions...)
+	sort.Strings(permissions)
+
+	return &ApproverPreview{
+		Cluster:           req.Spec.TargetCluster.Name,
+		Environment:       m.clusterEnvironment(req),
+		Permissions:       permissions,
+		Duration:          req.Spec.Duration,
+		Approvers:         approvers,
+		RequiredApprovals: required,
+	}, nil
+}
+
+// PreviewRequest returns a hypothetical request as the approval policy of Handle would admit it,
+// so callers can ask the operator's approval policy about it. Nothing is persisted.
+func (m *JITAccessRequestMutator) PreviewRequest(
+	spec controller.JITAccessRequestSpec,
+) (*controller.JITAccessRequest, error) {
+	req := &controller.JITAccessRequest{Spec: *spec.DeepCopy()}
+
+	m.setDefaults(req)
+	m.normalizeData(req)
+	if _, err := parseDuration(req.Spec.Duration); err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	m.setApprovers(req)
+	return req, nil
+}
+
+// PreviewEnvironment returns the environment the approval policy routes a previewed request by
+func (m *JITAccessRequestMutator) PreviewEnvironment(req *controller.JITAccessRequest) string {
+	return m.clusterEnvironment(req)
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestPreviewApprovers(t *testing.T) {
+	tests := []struct {
+		name              string
+		spec              controller.JITAccessRequestSpec
+		expectedApprovers []string
+		expectedRequired  int
+	}{
+		{
+			name: "prod with elevated permissions",
+			spec: controller.JITAccessRequestSpec{
+				TargetCluster: controller.TargetCluster{Name: "Prod-East-1"},
+				Permissions:   []string{"edit", "ro"},
+				Duration:      "2h",
+			},
+			expectedApprovers: []string{"platform-team", "security-team", "sre-team"},
+			expectedRequired:  3,
+		},
+		{
+			name: "prod view only",
+			spec: controller.JITAccessRequestSpec{
+				TargetCluster: controller.TargetCluster{Name: "prod-east-1"},
+				Permissions:   []string{"view"},
+				Duration:      "8h",
+			},
+			expectedApprovers: []string{"platform-team", "sre-team"},
+			expectedRequired:  2,
+		},
+		{
+			name: "staging elevated with defaulted duration",
+			spec: controller.JITAccessRequestSpec{
+				TargetCluster: controller.TargetCluster{Name: "staging-west-2"},
+				Permissions:   []string{"exec"},
+			},
+			expectedApprovers: []string{"platform-team"},
+			expectedRequired:  1,
+		},
+		{
+			name: "dev view",
+			spec: controller.JITAccessRequestSpec{
+				TargetCluster: controller.TargetCluster{Name: "dev-east-1"},
+				Duration:      "1h",
+			},
+			expectedApprovers: []string{},
+			expectedRequired:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &JITAccessRequestMutator{}
+
+			preview, err := m.PreviewApprovers(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedApprovers, preview.Approvers)
+			assert.Equal(t, tt.expectedRequired, preview.RequiredApprovals)
+
+			// The preview must match what the mutator assigns to a real request
+			req := &controller.JITAccessRequest{Spec: *tt.spec.DeepCopy()}
+			m.setDefaults(req)
+			m.normalizeData(req)
+			m.setApprovers(req)
+
+			assigned := append([]string{}, req.Spec.Approvers...)
+			sort.Strings(assigned)
+			assert.Equal(t, assigned, preview.Approvers)
+			assert.Equal(t, req.Annotations[controller.RequiredApprovalsAnnotation],
+				strconv.Itoa(preview.RequiredApprovals))
+		})
+	}
+}
+
+func TestPreviewApproversInvalidDuration(t *testing.T) {
+	m := &JITAccessRequestMutator{}
+
+	_, err := m.PreviewApprovers(controller.JITAccessRequestSpec{
+		TargetCluster: controller.TargetCluster{Name: "prod-east-1"},
+		Duration:      "forever",
+	})
+	assert.Error(t, err)
+}
+
+func TestPreviewApproversDoesNotModifySpec(t *testing.T) {
+	m := &JITAccessRequestMutator{}
+	spec := controller.JITAccessRequestSpec{
+		TargetCluster: controller.TargetCluster{Name: "Prod-East-1"},
+		Permissions:   []string{"rw"},
+	}
+
+	_, err := m.PreviewApprovers(spec)
+	require.NoError(t, err)
+	assert.Equal(t, "Prod-East-1", spec.TargetCluster.Name)
+	assert.Equal(t, []string{"rw"}, spec.Permissions)
+	assert.Empty(t, spec.Approvers)
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"unicode"
+)
+
+// errReasonContainsSecret marks reasons rejected because they look like they contain a credential
+var errReasonContainsSecret = errors.New("reason appears to contain a secret")
+
+// minSecretEntropy is the Shannon entropy, in bits per character, above which a long mixed-case
+// alphanumeric token is treated as a secret. Random base64 runs well above it; words and
+// identifiers stay below it.
+const minSecretEntropy = 4.0
+
+var (
+	awsAccessKeyPattern = regexp.MustCompile(`\b(AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)
+	jwtPattern          = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]+`)
+	slackTokenPattern   = regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)
+	secretTokenPattern  = regexp.MustCompile(`[A-Za-z0-9+/=_-]{32,}`)
+)
+
+// detectSecret names the kind of credential the reason appears to contain, so it is refused
+// before being stored in the request and copied into logs and notifications
+func detectSecret(reason string) (string, bool) {
+	switch {
+	case awsAccessKeyPattern.MatchString(reason):
+		return "an AWS access key", true
+	case jwtPattern.MatchString(reason):
+		return "a JWT", true
+	case slackTokenPattern.MatchString(reason):
+		return "a Slack token", true
+	}
+
+	for _, token := range secretTokenPattern.FindAllString(reason, -1) {
+		if mixedAlphanumeric(token) && shannonEntropy(token) >= minSecretEntropy {
+			return "a high-entropy token", true
+		}
+	}
+	return "", false
+}
+
+// validateReasonSecrets rejects reasons that look like they contain a credential without
+// repeating it in the error
+func validateReasonSecrets(reason string) error {
+	if kind, found := detectSecret(reason); found {
+		return fmt.Errorf("%w (%s); remove it and rotate the credential", errReasonContainsSecret, kind)
+	}
+	return nil
+}
+
+// mixedAlphanumeric reports whether the token mixes upper case, lower case and digits, which
+// excludes hex digests such as commit SHAs and image digests
+func mixedAlphanumeric(token string) bool {
+	var upper, lower, digit bool
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// shannonEntropy returns the entropy of the token in bits per character
+func shannonEntropy(token string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range token {
+		counts[r]++
+	}
+
+	length := float64(len([]rune(token)))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxReasonLength is the longest reason admitted in environments without their own limit
+const DefaultMaxReasonLength = 500
+
+// minReasonLength is the shortest reason validateReason admits
+const minReasonLength = 10
+
+// maxReasonLengthCeiling is the CRD's MaxLength for reasons; no environment may allow more
+const maxReasonLengthCeiling = 4000
+
+// ReasonValidator decides whether a request's business justification is good enough to admit it.
+// Organizations with their own rules, such as required keywords, regexes or an external classifier,
+// can replace the built-in heuristic with their own implementation. The context carries the target
+// cluster's environment, see ReasonEnvironment.
+type ReasonValidator interface {
+	// ValidateReason returns an error explaining why the reason is insufficient for the permissions
+	ValidateReason(ctx context.Context, reason string, permissions []string) error
+}
+
+// DefaultReasonValidator is the built-in heuristic: reasons must be at least 10 characters and at
+// most DefaultMaxReasonLength, or the environment's limit in MaxLengths, must not be a
+// placeholder such as "test", must avoid generic phrases such as "need access", and cluster-admin
+// requests need at least 50 characters of justification. Reasons that look like they contain a
+// credential, such as an AWS access key or JWT, are refused.
+type DefaultReasonValidator struct {
+	// AllowSecretLikeReasons admits reasons that look like they contain a credential
+	AllowSecretLikeReasons bool
+
+	// MaxLengths caps reason length by environment, e.g. longer incident write-ups in production;
+	// environments not listed use DefaultMaxReasonLength
+	MaxLengths map[string]int
+}
+
+// ValidateReason implements ReasonValidator
+func (d DefaultReasonValidator) ValidateReason(ctx context.Context, reason string, permissions []string) error {
+	err := validateReason(reason, d.maxLength(ReasonEnvironment(ctx)))
+	if d.AllowSecretLikeReasons && errors.Is(err, errReasonContainsSecret) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return validateReasonForPermissions(reason, permissions)
+}
+
+func (v *JITAccessRequestValidator) reasonValidator() ReasonValidator {
+	if v.ReasonValidator == nil {
+		return DefaultReasonValidator{}
+	}
+	return v.ReasonValidator
+}
+
+func (d DefaultReasonValidator) maxLength(env string) int {
+	if maxLength, ok := d.MaxLengths[env]; ok {
+		return maxLength
+	}
+	return DefaultMaxReasonLength
+}
+
+type reasonEnvironmentKey struct{}
+
+// WithReasonEnvironment records the environment of the request whose reason is being validated
+func WithReasonEnvironment(ctx context.Context, env string) context.Context {
+	return context.WithValue(ctx, reasonEnvironmentKey{}, env)
+}
+
+// ReasonEnvironment returns the environment recorded by WithReasonEnvironment, or "" if none was
+func ReasonEnvironment(ctx context.Context) string {
+	env, _ := ctx.Value(reasonEnvironmentKey{}).(string)
+	return env
+}
+
+// ParseReasonLengths reads comma-separated environment=length pairs, e.g. production=2000,development=300
+func ParseReasonLengths(value string) (map[string]int, error) {
+	lengths := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		env, rawLength, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(env) == "" {
+			return nil, fmt.Errorf("invalid reason length %q: want environment=length", pair)
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(rawLength))
+		if err != nil || length < minReasonLength || length > maxReasonLengthCeiling {
+			return nil, fmt.Errorf("invalid reason length for %s: %q is not between %d and %d",
+				env, rawLength, minReasonLength, maxReasonLengthCeiling)
+		}
+		lengths[strings.TrimSpace(env)] = length
+	}
+	return lengths, nil
+}
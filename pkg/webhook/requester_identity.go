@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// RequesterVerifiedAnnotation marks requests whose spec.userID a requester service account vouched for
+const RequesterVerifiedAnnotation = controller.RequesterVerifiedAnnotation
+
+var errUnverifiedRequester = errors.New("only a requester service account may mark the requester verified")
+
+// listedServiceAccount reports whether username is a service account named in accounts
+func listedServiceAccount(accounts []string, username string) bool {
+	if !strings.HasPrefix(username, serviceAccountUserPrefix) {
+		return false
+	}
+	for _, account := range accounts {
+		if account == username {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyRequester marks new requests filed by a requester service account as verified. Marks set
+// by anyone else are left for the validator to reject.
+func (m *JITAccessRequestMutator) verifyRequester(req admission.Request, accessReq *controller.JITAccessRequest) {
+	if req.Operation != admissionv1.Create || !listedServiceAccount(m.RequesterServiceAccounts, req.UserInfo.Username) {
+		return
+	}
+	if accessReq.Annotations == nil {
+		accessReq.Annotations = make(map[string]string)
+	}
+	accessReq.Annotations[RequesterVerifiedAnnotation] = "true"
+}
+
+// stripUnverifiedEmail clears spec.userEmailVerified unless a requester service account set it, so
+// requesters cannot vouch for their own email. Updates keep the flag while the email is unchanged
+// from the already-verified request.
+func (m *JITAccessRequestMutator) stripUnverifiedEmail(req admission.Request, accessReq *controller.JITAccessRequest) {
+	if !accessReq.Spec.UserEmailVerified || listedServiceAccount(m.RequesterServiceAccounts, req.UserInfo.Username) {
+		return
+	}
+
+	if req.Operation == admissionv1.Update {
+		old := &controller.JITAccessRequest{}
+		if err := m.decoder.DecodeRaw(req.OldObject, old); err == nil &&
+			old.Spec.UserEmailVerified && old.Spec.UserEmail == accessReq.Spec.UserEmail {
+			return
+		}
+	}
+	accessReq.Spec.UserEmailVerified = false
+}
+
+// validateRequesterVerified checks the verified mark against the admission user: only a requester
+// service account may set it, and it survives updates only while spec.userID is unchanged
+func (v *JITAccessRequestValidator) validateRequesterVerified(
+	req admission.Request, accessReq *controller.JITAccessRequest,
+) error {
+	if accessReq.Annotations[RequesterVerifiedAnnotation] != "true" ||
+		listedServiceAccount(v.RequesterServiceAccounts, req.UserInfo.Username) {
+		return nil
+	}
+
+	if req.Operation == admissionv1.Update {
+		old := &controller.JITAccessRequest{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err == nil &&
+			old.Annotations[RequesterVerifiedAnnotation] == "true" && old.Spec.UserID == accessReq.Spec.UserID {
+			return nil
+		}
+	}
+	return errUnverifiedRequester
+}
@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+const slackBotAccount = "system:serviceaccount:jit-system:jit-bot"
+
+func requesterTestRequest(t *testing.T, userID string, verified bool) []byte {
+	t.Helper()
+
+	request := &controller.JITAccessRequest{
+		TypeMeta:   metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+		ObjectMeta: metav1.ObjectMeta{Name: "requester-request", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:    userID,
+			UserEmail: "engineer@company.com",
+			TargetCluster: controller.TargetCluster{
+				Name: "dev-east-1", AWSAccount: "123456789012", Region: "us-east-1",
+			},
+			Reason:      "Investigating elevated error rates in checkout",
+			Duration:    "1h",
+			Permissions: []string{"view"},
+		},
+	}
+	if verified {
+		request.Annotations = map[string]string{RequesterVerifiedAnnotation: "true"}
+	}
+	raw, err := json.Marshal(request)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestMutatorMarksRequesterServiceAccountRequestsVerified(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	mutator := &JITAccessRequestMutator{
+		decoder:                  admission.NewDecoder(scheme),
+		RequesterServiceAccounts: []string{slackBotAccount},
+	}
+
+	tests := []struct {
+		name         string
+		username     string
+		wantVerified bool
+	}{
+		{name: "requester service account", username: slackBotAccount, wantVerified: true},
+		{name: "other service account", username: "system:serviceaccount:default:builder"},
+		{name: "human user", username: "alice@company.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := mutator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: requesterTestRequest(t, "U0TRUSTED01", false)},
+				UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+			}})
+			require.True(t, resp.Allowed)
+
+			verified := false
+			for _, patch := range resp.Patches {
+				if patch.Path == "/metadata/annotations" {
+					annotations, _ := patch.Value.(map[string]any)
+					verified = annotations[RequesterVerifiedAnnotation] == "true"
+				}
+			}
+			assert.Equal(t, tt.wantVerified, verified)
+		})
+	}
+}
+
+func emailVerifiedTestRequest(t *testing.T, email string) []byte {
+	t.Helper()
+
+	var request controller.JITAccessRequest
+	require.NoError(t, json.Unmarshal(requesterTestRequest(t, "U0TRUSTED01", false), &request))
+	request.Spec.UserEmail = email
+	request.Spec.UserEmailVerified = true
+	raw, err := json.Marshal(&request)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestMutatorStripsSelfAttestedEmailVerification(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	mutator := &JITAccessRequestMutator{
+		decoder:                  admission.NewDecoder(scheme),
+		RequesterServiceAccounts: []string{slackBotAccount},
+	}
+
+	tests := []struct {
+		name         string
+		operation    admissionv1.Operation
+		username     string
+		old          []byte
+		wantVerified bool
+	}{
+		{
+			name:         "requester service account",
+			operation:    admissionv1.Create,
+			username:     slackBotAccount,
+			wantVerified: true,
+		},
+		{name: "human user", operation: admissionv1.Create, username: "alice@company.com"},
+		{
+			name:      "other service account",
+			operation: admissionv1.Create,
+			username:  "system:serviceaccount:default:builder",
+		},
+		{
+			name:         "update keeping the verified email",
+			operation:    admissionv1.Update,
+			username:     "alice@company.com",
+			old:          emailVerifiedTestRequest(t, "engineer@company.com"),
+			wantVerified: true,
+		},
+		{
+			name:      "update changing the verified email",
+			operation: admissionv1.Update,
+			username:  "alice@company.com",
+			old:       emailVerifiedTestRequest(t, "someone-else@company.com"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := mutator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Object:    runtime.RawExtension{Raw: emailVerifiedTestRequest(t, "engineer@company.com")},
+				OldObject: runtime.RawExtension{Raw: tt.old},
+				UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+			}})
+			require.True(t, resp.Allowed)
+
+			stripped := false
+			for _, patch := range resp.Patches {
+				if patch.Path == "/spec/userEmailVerified" {
+					stripped = true
+				}
+			}
+			assert.Equal(t, tt.wantVerified, !stripped)
+		})
+	}
+}
+
+func TestValidatorRequesterVerified(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	validator := &JITAccessRequestValidator{
+		decoder:                  admission.NewDecoder(scheme),
+		RequesterServiceAccounts: []string{slackBotAccount},
+	}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		username    string
+		verified    bool
+		old         []byte
+		wantAllowed bool
+	}{
+		{name: "unverified request", operation: admissionv1.Create, username: "alice@company.com", wantAllowed: true},
+		{
+			name:        "requester service account",
+			operation:   admissionv1.Create,
+			username:    slackBotAccount,
+			verified:    true,
+			wantAllowed: true,
+		},
+		{
+			name:      "user marking their own request",
+			operation: admissionv1.Create,
+			username:  "alice@company.com",
+			verified:  true,
+		},
+		{
+			name:        "controller update keeping the mark",
+			operation:   admissionv1.Update,
+			username:    "system:serviceaccount:jit-system:jit-operator",
+			verified:    true,
+			old:         requesterTestRequest(t, "U0TRUSTED01", true),
+			wantAllowed: true,
+		},
+		{
+			name:      "update adding the mark",
+			operation: admissionv1.Update,
+			username:  "alice@company.com",
+			verified:  true,
+			old:       requesterTestRequest(t, "U0TRUSTED01", false),
+		},
+		{
+			name:      "update changing the verified user",
+			operation: admissionv1.Update,
+			username:  "alice@company.com",
+			verified:  true,
+			old:       requesterTestRequest(t, "U0ORDINARY1", true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := validator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Object:    runtime.RawExtension{Raw: requesterTestRequest(t, "U0TRUSTED01", tt.verified)},
+				OldObject: runtime.RawExtension{Raw: tt.old},
+				UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+			}})
+
+			if tt.wantAllowed {
+				assert.True(t, resp.Allowed, "expected request to be allowed, got: %v", resp.Result)
+				return
+			}
+			require.False(t, resp.Allowed)
+			assert.Contains(t, resp.Result.Message, errUnverifiedRequester.Error())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// RiskScoreAnnotation records the risk score the mutator computed for a request
+const RiskScoreAnnotation = "jit.rebelops.io/risk-score"
+
+// RiskWeights sets how much each factor adds to a request's risk score
+type RiskWeights struct {
+	// Environments is added for the target cluster's environment
+	Environments map[string]int `json:"environments"`
+
+	// Permissions is added for the riskiest requested permission only, so asking for view
+	// alongside exec scores the same as exec alone
+	Permissions map[string]int `json:"permissions"`
+
+	// PerHour is added for every started hour of the requested duration
+	PerHour int `json:"perHour"`
+
+	// PerNamespace is added for every requested namespace
+	PerNamespace int `json:"perNamespace"`
+
+	// ClusterWide is added instead of PerNamespace when no namespace is requested
+	ClusterWide int `json:"clusterWide"`
+
+	// Emergency is added for requests with the emergency annotation
+	Emergency int `json:"emergency"`
+}
+
+// DefaultRiskWeights applies when no risk weights are configured
+var DefaultRiskWeights = RiskWeights{
+	Environments: map[string]int{
+		envProduction: 30,
+		envStaging:    15,
+		"qa":          5,
+		"development": 0,
+	},
+	Permissions: map[string]int{
+		"view":          0,
+		"logs":          5,
+		"edit":          20,
+		"port-forward":  20,
+		"debug":         25,
+		"exec":          30,
+		"admin":         40,
+		"cluster-admin": 60,
+	},
+	PerHour:      2,
+	PerNamespace: 2,
+	ClusterWide:  15,
+	Emergency:    20,
+}
+
+// LoadRiskWeights reads risk weights from a JSON file. Weights the file omits keep their
+// defaults, and map entries are merged with the default environments and permissions.
+func LoadRiskWeights(path string) (*RiskWeights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk weights: %w", err)
+	}
+
+	weights := DefaultRiskWeights
+	weights.Environments = maps.Clone(DefaultRiskWeights.Environments)
+	weights.Permissions = maps.Clone(DefaultRiskWeights.Permissions)
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return nil, fmt.Errorf("failed to parse risk weights: %w", err)
+	}
+
+	for env, weight := range weights.Environments {
+		if weight < 0 {
+			return nil, fmt.Errorf("risk weight for environment %s must not be negative", env)
+		}
+	}
+	for perm, weight := range weights.Permissions {
+		if weight < 0 {
+			return nil, fmt.Errorf("risk weight for permission %s must not be negative", perm)
+		}
+	}
+	if weights.PerHour < 0 || weights.PerNamespace < 0 || weights.ClusterWide < 0 || weights.Emergency < 0 {
+		return nil, fmt.Errorf("risk weights must not be negative")
+	}
+
+	return &weights, nil
+}
+
+func (m *JITAccessRequestMutator) riskWeights() *RiskWeights {
+	if m.RiskWeights != nil {
+		return m.RiskWeights
+	}
+	return &DefaultRiskWeights
+}
+
+// setRiskScore scores the normalized request and records the score as an annotation,
+// replacing any value supplied by the requester
+func (m *JITAccessRequestMutator) setRiskScore(req *controller.JITAccessRequest) {
+	if req.Annotations == nil {
+		req.Annotations = make(map[string]string)
+	}
+	leveled := withPermissionLevels(m.PermissionPolicies, req)
+	req.Annotations[RiskScoreAnnotation] = strconv.Itoa(riskScore(m.riskWeights(), m.clusterEnvironment(req), leveled))
+}
+
+func riskScore(weights *RiskWeights, environment string, req *controller.JITAccessRequest) int {
+	score := weights.Environments[environment]
+
+	permissionScore := 0
+	for _, perm := range req.Spec.Permissions {
+		permissionScore = max(permissionScore, weights.Permissions[strings.ToLower(perm)])
+	}
+	score += permissionScore
+
+	if duration, err := parseDuration(req.Spec.Duration); err == nil {
+		score += weights.PerHour * int(math.Ceil(duration.Hours()))
+	}
+
+	if len(req.Spec.Namespaces) == 0 {
+		score += weights.ClusterWide
+	} else {
+		score += weights.PerNamespace * len(req.Spec.Namespaces)
+	}
+
+	if req.Annotations[EmergencyAnnotation] == "true" {
+		score += weights.Emergency
+	}
+
+	return score
+}
+
+// recordRiskScore counts an admitted request under the risk score the mutator assigned it. Requests
+// are counted once, when their creation passes validation, so denied requests are left out.
+func (v *JITAccessRequestValidator) recordRiskScore(req admission.Request, accessReq *controller.JITAccessRequest) {
+	if req.Operation != admissionv1.Create || (req.DryRun != nil && *req.DryRun) {
+		return
+	}
+	score, _ := strconv.Atoi(accessReq.Annotations[RiskScoreAnnotation])
+	metrics.RecordAccessRequestRiskScore(accessReq.Spec.TargetCluster.Name, v.clusterEnvironment(accessReq), score)
+}
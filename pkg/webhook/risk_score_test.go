@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func riskRequest(cluster, duration string, permissions []string, namespaces ...string) *controller.JITAccessRequest {
+	return &controller.JITAccessRequest{
+		Spec: controller.JITAccessRequestSpec{
+			UserID:        "U123456789A",
+			TargetCluster: controller.TargetCluster{Name: cluster},
+			Reason:        "Investigating an incident",
+			Duration:      duration,
+			Permissions:   permissions,
+			Namespaces:    namespaces,
+		},
+	}
+}
+
+func mutatedRiskScore(t *testing.T, m *JITAccessRequestMutator, req *controller.JITAccessRequest) int {
+	t.Helper()
+
+	m.mutate(req)
+	score, err := strconv.Atoi(req.Annotations[RiskScoreAnnotation])
+	require.NoError(t, err, "risk score annotation should be an integer")
+	return score
+}
+
+func TestRiskScoreIncreasesWithElevatedPermissions(t *testing.T) {
+	mutator := &JITAccessRequestMutator{}
+
+	view := mutatedRiskScore(t, mutator, riskRequest("prod-east-1", "1h", []string{"view"}, "payments"))
+	edit := mutatedRiskScore(t, mutator, riskRequest("prod-east-1", "1h", []string{"edit"}, "payments"))
+	exec := mutatedRiskScore(t, mutator, riskRequest("prod-east-1", "1h", []string{"view", "exec"}, "payments"))
+	clusterAdmin := mutatedRiskScore(t, mutator, riskRequest("prod-east-1", "1h", []string{"cluster-admin"}))
+
+	assert.Greater(t, edit, view)
+	assert.Greater(t, exec, edit)
+	assert.Greater(t, clusterAdmin, exec)
+}
+
+func TestRiskScoreIncreasesWithDuration(t *testing.T) {
+	mutator := &JITAccessRequestMutator{}
+
+	short := mutatedRiskScore(t, mutator, riskRequest("staging-east-1", "30m", []string{"edit"}, "payments"))
+	medium := mutatedRiskScore(t, mutator, riskRequest("staging-east-1", "4h", []string{"edit"}, "payments"))
+	long := mutatedRiskScore(t, mutator, riskRequest("staging-east-1", "1d", []string{"edit"}, "payments"))
+
+	assert.Greater(t, medium, short)
+	assert.Greater(t, long, medium)
+}
+
+func TestRiskScoreFactors(t *testing.T) {
+	weights := &RiskWeights{
+		Environments: map[string]int{envProduction: 100, "development": 0},
+		Permissions:  map[string]int{"view": 1, "exec": 10},
+		PerHour:      1000,
+		PerNamespace: 10000,
+		ClusterWide:  50000,
+		Emergency:    100000,
+	}
+
+	tests := []struct {
+		name    string
+		request *controller.JITAccessRequest
+		want    int
+	}{
+		{
+			name:    "development view in one namespace",
+			request: riskRequest("dev-east-1", "1h", []string{"view"}, "payments"),
+			want:    1 + 1000 + 10000,
+		},
+		{
+			name:    "production takes the riskiest permission",
+			request: riskRequest("prod-east-1", "1h", []string{"view", "exec"}, "payments"),
+			want:    100 + 10 + 1000 + 10000,
+		},
+		{
+			name:    "partial hours round up",
+			request: riskRequest("dev-east-1", "2h30m", []string{"view"}, "payments"),
+			want:    1 + 3*1000 + 10000,
+		},
+		{
+			name:    "each namespace adds to the score",
+			request: riskRequest("dev-east-1", "1h", []string{"view"}, "payments", "billing", "ledger"),
+			want:    1 + 1000 + 3*10000,
+		},
+		{
+			name:    "no namespaces is cluster-wide",
+			request: riskRequest("dev-east-1", "1h", []string{"view"}),
+			want:    1 + 1000 + 50000,
+		},
+		{
+			name: "emergency",
+			request: func() *controller.JITAccessRequest {
+				req := riskRequest("dev-east-1", "1h", []string{"view"}, "payments")
+				req.Annotations = map[string]string{EmergencyAnnotation: "true"}
+				return req
+			}(),
+			want: 1 + 1000 + 10000 + 100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := determineEnvironment(tt.request.Spec.TargetCluster.Name)
+			assert.Equal(t, tt.want, riskScore(weights, env, tt.request))
+		})
+	}
+}
+
+func TestRiskScoreReplacesRequesterValue(t *testing.T) {
+	req := riskRequest("prod-east-1", "8h", []string{"cluster-admin"})
+	req.Annotations = map[string]string{RiskScoreAnnotation: "0"}
+
+	score := mutatedRiskScore(t, &JITAccessRequestMutator{}, req)
+
+	assert.Positive(t, score)
+}
+
+func TestLoadRiskWeights(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "weights.json")
+	data := `{"perHour": 5, "permissions": {"exec": 50, "debug-nodes": 45}}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	weights, err := LoadRiskWeights(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5, weights.PerHour)
+	assert.Equal(t, 50, weights.Permissions["exec"])
+	assert.Equal(t, 45, weights.Permissions["debug-nodes"])
+	// Omitted weights keep their defaults
+	assert.Equal(t, DefaultRiskWeights.Emergency, weights.Emergency)
+	assert.Equal(t, DefaultRiskWeights.Permissions["cluster-admin"], weights.Permissions["cluster-admin"])
+	assert.Equal(t, DefaultRiskWeights.Environments, weights.Environments)
+	// Loading must not modify the defaults
+	assert.Equal(t, 30, DefaultRiskWeights.Permissions["exec"])
+	assert.NotContains(t, DefaultRiskWeights.Permissions, "debug-nodes")
+
+	negative := filepath.Join(dir, "negative.json")
+	require.NoError(t, os.WriteFile(negative, []byte(`{"environments": {"production": -10}}`), 0o600))
+	_, err = LoadRiskWeights(negative)
+	assert.Error(t, err)
+}
+
+// riskScoreCount reads how many requests were counted under the cluster and risk score
+func riskScoreCount(t *testing.T, cluster, score string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "jit_access_request_risk_scores_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["cluster"] == cluster && labels["risk_score"] == score {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestValidatorCountsRiskScoreOfAdmittedRequests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	validator := &JITAccessRequestValidator{decoder: admission.NewDecoder(scheme)}
+
+	create := func(reason string) admission.Response {
+		request := &controller.JITAccessRequest{
+			TypeMeta: metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "risk-request", Namespace: "jit-system",
+				Annotations: map[string]string{RiskScoreAnnotation: "31"},
+			},
+			Spec: controller.JITAccessRequestSpec{
+				UserID:    "U123456789A",
+				UserEmail: "engineer@company.com",
+				TargetCluster: controller.TargetCluster{
+					Name: "risk-east-1", AWSAccount: "123456789012", Region: "us-east-1",
+				},
+				Reason:      reason,
+				Duration:    "1h",
+				Permissions: []string{"edit"},
+			},
+		}
+		raw, err := json.Marshal(request)
+		require.NoError(t, err)
+		return validator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	before := riskScoreCount(t, "risk-east-1", "31")
+
+	// Denied requests are not counted
+	resp := create("test")
+	require.False(t, resp.Allowed)
+	assert.Equal(t, before, riskScoreCount(t, "risk-east-1", "31"))
+
+	resp = create("Investigating checkout latency regression in the payments service")
+	require.True(t, resp.Allowed, resp.Result.Message)
+	assert.Equal(t, before+1, riskScoreCount(t, "risk-east-1", "31"))
+}
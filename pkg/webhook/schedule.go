@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+// EmergencyAnnotation marks a request as an emergency, bypassing cluster access schedules
+const EmergencyAnnotation = controller.EmergencyAnnotation
+
+// LoadAccessSchedules reads per-cluster access schedules from a JSON file keyed by cluster name
+func LoadAccessSchedules(path string) (map[string]*models.AccessSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access schedules: %w", err)
+	}
+
+	var schedules map[string]*models.AccessSchedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to parse access schedules: %w", err)
+	}
+
+	for cluster, schedule := range schedules {
+		if _, err := schedule.Location(); err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", cluster, err)
+		}
+	}
+
+	return schedules, nil
+}
+
+func (v *JITAccessRequestValidator) validateSchedule(req *controller.JITAccessRequest) error {
+	schedule, ok := v.Schedules[req.Spec.TargetCluster.Name]
+	if !ok || schedule == nil {
+		return nil
+	}
+
+	if req.Annotations[EmergencyAnnotation] == "true" {
+		return nil
+	}
+
+	// Scheduled requests must fall in the window when their access starts
+	allowed, err := schedule.Allows(v.startTime(req))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("cluster %s is only accessible during %s; set the %s annotation for emergency access",
+			req.Spec.TargetCluster.Name, schedule.String(), EmergencyAnnotation)
+	}
+
+	return nil
+}
+
+func (v *JITAccessRequestValidator) currentTime() time.Time {
+	if v.now != nil {
+		return v.now()
+	}
+	return time.Now()
+}
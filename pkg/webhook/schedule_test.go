@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	schedules := map[string]*models.AccessSchedule{
+		"prod-us": {
+			Timezone: "America/New_York",
+			Windows:  []models.ScheduleWindow{{Days: weekdays, Start: "09:00", End: "17:00"}},
+		},
+		"prod-apac": {
+			Timezone: "Asia/Tokyo",
+			Windows:  []models.ScheduleWindow{{Days: weekdays, Start: "09:00", End: "17:00"}},
+		},
+		"prod-overnight": {
+			Timezone: "Europe/London",
+			Windows:  []models.ScheduleWindow{{Days: []time.Weekday{time.Friday}, Start: "22:00", End: "02:00"}},
+		},
+		"bad-timezone": {
+			Timezone: "Mars/Olympus_Mons",
+			Windows:  []models.ScheduleWindow{{Days: weekdays, Start: "09:00", End: "17:00"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		cluster   string
+		now       time.Time
+		emergency bool
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:    "cluster without schedule",
+			cluster: "dev",
+			now:     time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC), // Saturday
+			wantErr: false,
+		},
+		{
+			name:    "in window - New York morning",
+			cluster: "prod-us",
+			now:     time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC), // Monday 10:00 EST
+			wantErr: false,
+		},
+		{
+			name:    "out of window - in UTC business hours but before New York opens",
+			cluster: "prod-us",
+			now:     time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC), // Monday 07:00 EST
+			wantErr: true,
+			errMsg:  "Mon,Tue,Wed,Thu,Fri 09:00-17:00 (America/New_York)",
+		},
+		{
+			name:    "out of window - Tokyo Monday before opening, Sunday in UTC",
+			cluster: "prod-apac",
+			now:     time.Date(2024, 1, 7, 23, 30, 0, 0, time.UTC), // Monday 08:30 JST
+			wantErr: true,
+			errMsg:  "(Asia/Tokyo)",
+		},
+		{
+			name:    "in window - Tokyo Monday after opening",
+			cluster: "prod-apac",
+			now:     time.Date(2024, 1, 8, 1, 0, 0, 0, time.UTC), // Monday 10:00 JST
+			wantErr: false,
+		},
+		{
+			name:    "out of window - Tokyo Friday evening",
+			cluster: "prod-apac",
+			now:     time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC), // Friday 18:00 JST
+			wantErr: true,
+		},
+		{
+			name:    "overnight window - after midnight",
+			cluster: "prod-overnight",
+			now:     time.Date(2024, 1, 13, 1, 0, 0, 0, time.UTC), // Saturday 01:00 GMT
+			wantErr: false,
+		},
+		{
+			name:    "overnight window - after end",
+			cluster: "prod-overnight",
+			now:     time.Date(2024, 1, 13, 3, 0, 0, 0, time.UTC), // Saturday 03:00 GMT
+			wantErr: true,
+		},
+		{
+			name:      "emergency bypasses schedule",
+			cluster:   "prod-us",
+			now:       time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC),
+			emergency: true,
+			wantErr:   false,
+		},
+		{
+			name:    "invalid timezone",
+			cluster: "bad-timezone",
+			now:     time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC),
+			wantErr: true,
+			errMsg:  "invalid schedule timezone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &JITAccessRequestValidator{
+				Schedules: schedules,
+				now:       func() time.Time { return tt.now },
+			}
+
+			req := &controller.JITAccessRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-request"},
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+				},
+			}
+			if tt.emergency {
+				req.Annotations = map[string]string{EmergencyAnnotation: "true"}
+			}
+
+			err := validator.validateSchedule(req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// DefaultMaxScheduleAhead is how far ahead a request may set NotBefore when no limit is configured
+const DefaultMaxScheduleAhead = 7 * 24 * time.Hour
+
+func (v *JITAccessRequestValidator) maxScheduleAhead() time.Duration {
+	if v.MaxScheduleAhead > 0 {
+		return v.MaxScheduleAhead
+	}
+	return DefaultMaxScheduleAhead
+}
+
+// validateNotBefore limits how far ahead access can be scheduled, so approvals are not given
+// for sessions long after the circumstances that justified them. A past NotBefore is allowed;
+// the request is provisioned as soon as it is approved.
+func (v *JITAccessRequestValidator) validateNotBefore(req *controller.JITAccessRequest) error {
+	if req.Spec.NotBefore == nil {
+		return nil
+	}
+
+	limit := v.maxScheduleAhead()
+	if req.Spec.NotBefore.Sub(v.currentTime()) > limit {
+		return fmt.Errorf("notBefore %s is more than %s ahead",
+			req.Spec.NotBefore.UTC().Format(time.RFC3339), formatDuration(limit))
+	}
+	return nil
+}
+
+// startTime is when the request's access would begin: its NotBefore, or now if that has passed
+func (v *JITAccessRequestValidator) startTime(req *controller.JITAccessRequest) time.Time {
+	now := v.currentTime()
+	if req.Spec.NotBefore != nil && req.Spec.NotBefore.After(now) {
+		return req.Spec.NotBefore.Time
+	}
+	return now
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
+)
+
+func TestValidateNotBefore(t *testing.T) {
+	now := time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		notBefore *metav1.Time
+		maxAhead  time.Duration
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:    "no start time",
+			wantErr: false,
+		},
+		{
+			name:      "start time in the past",
+			notBefore: &metav1.Time{Time: now.Add(-time.Hour)},
+			wantErr:   false,
+		},
+		{
+			name:      "start time within default limit",
+			notBefore: &metav1.Time{Time: now.Add(6 * 24 * time.Hour)},
+			wantErr:   false,
+		},
+		{
+			name:      "start time beyond default limit",
+			notBefore: &metav1.Time{Time: now.Add(8 * 24 * time.Hour)},
+			wantErr:   true,
+			errMsg:    "more than 168h ahead",
+		},
+		{
+			name:      "start time beyond configured limit",
+			notBefore: &metav1.Time{Time: now.Add(3 * time.Hour)},
+			maxAhead:  2 * time.Hour,
+			wantErr:   true,
+			errMsg:    "2024-01-08T18:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &JITAccessRequestValidator{
+				MaxScheduleAhead: tt.maxAhead,
+				now:              func() time.Time { return now },
+			}
+			req := &controller.JITAccessRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-request"},
+				Spec:       controller.JITAccessRequestSpec{NotBefore: tt.notBefore},
+			}
+
+			err := validator.validateNotBefore(req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatorNotBeforeOnUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	validator := &JITAccessRequestValidator{decoder: admission.NewDecoder(scheme)}
+
+	request := &controller.JITAccessRequest{
+		TypeMeta:   metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+		ObjectMeta: metav1.ObjectMeta{Name: "rescheduled", Namespace: "jit-system"},
+		Spec: controller.JITAccessRequestSpec{
+			UserID:    "U123456789A",
+			UserEmail: "oncall@company.com",
+			TargetCluster: controller.TargetCluster{
+				Name: "dev-east-1", AWSAccount: "123456789012", Region: "us-east-1",
+			},
+			Reason:      "Reviewing the rollout of the payments service for INC-4521",
+			Duration:    "1h",
+			Permissions: []string{"view"},
+			NotBefore:   &metav1.Time{Time: time.Now().Add(30 * 24 * time.Hour)},
+		},
+	}
+	raw, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	resp := validator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Object:    runtime.RawExtension{Raw: raw},
+	}})
+	require.False(t, resp.Allowed, "moving notBefore past the limit on update is denied")
+	assert.Contains(t, resp.Result.Message, "invalid start time")
+}
+
+func TestValidateScheduleUsesStartTime(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	validator := &JITAccessRequestValidator{
+		Schedules: map[string]*models.AccessSchedule{
+			"prod-us": {
+				Timezone: "America/New_York",
+				Windows:  []models.ScheduleWindow{{Days: weekdays, Start: "09:00", End: "17:00"}},
+			},
+		},
+		now: func() time.Time { return time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC) }, // Saturday
+	}
+
+	req := &controller.JITAccessRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-request"},
+		Spec: controller.JITAccessRequestSpec{
+			TargetCluster: controller.TargetCluster{Name: "prod-us"},
+			NotBefore:     &metav1.Time{Time: time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC)}, // Monday 10:00 EST
+		},
+	}
+	assert.NoError(t, validator.validateSchedule(req), "scheduled start falls inside the window")
+
+	req.Spec.NotBefore = &metav1.Time{Time: time.Date(2024, 1, 8, 23, 0, 0, 0, time.UTC)} // Monday 18:00 EST
+	assert.Error(t, validator.validateSchedule(req), "scheduled start falls outside the window")
+}
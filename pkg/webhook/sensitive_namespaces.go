@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// DefaultSensitiveNamespaces are the namespaces the operator treats as sensitive unless its
+// --sensitive-namespaces flag says otherwise
+var DefaultSensitiveNamespaces = []string{"kube-system", "istio-system"}
+
+// SensitiveNamespacesAnnotation lists the sensitive namespaces a request touches; the controller
+// never auto-approves such requests
+const SensitiveNamespacesAnnotation = controller.SensitiveNamespacesAnnotation
+
+// requestedSensitiveNamespaces returns the requested namespaces configured as sensitive
+func (m *JITAccessRequestMutator) requestedSensitiveNamespaces(req *controller.JITAccessRequest) []string {
+	var sensitive []string
+	for _, ns := range req.Spec.Namespaces {
+		for _, candidate := range m.SensitiveNamespaces {
+			if strings.EqualFold(ns, strings.TrimSpace(candidate)) {
+				sensitive = append(sensitive, ns)
+				break
+			}
+		}
+	}
+	return sensitive
+}
+
+// markSensitiveNamespaces records the sensitive namespaces a request touches and makes sure the
+// sensitive namespace approvers are listed, even when the requester chose the approvers
+func (m *JITAccessRequestMutator) markSensitiveNamespaces(req *controller.JITAccessRequest) {
+	sensitive := m.requestedSensitiveNamespaces(req)
+	if len(sensitive) == 0 {
+		delete(req.Annotations, SensitiveNamespacesAnnotation)
+		return
+	}
+	req.Annotations[SensitiveNamespacesAnnotation] = strings.Join(sensitive, ",")
+
+	for _, approver := range controller.SensitiveNamespaceApprovers {
+		if !slices.Contains(req.Spec.Approvers, approver) {
+			req.Spec.Approvers = append(req.Spec.Approvers, approver)
+		}
+	}
+}
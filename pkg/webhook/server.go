@@ -2,39 +2,170 @@ package webhook
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/aws"
 	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
 )
 
+// Options configures the admission webhooks
+type Options struct {
+	// Schedules may be nil when no cluster restricts access to business hours
+	Schedules map[string]*models.AccessSchedule
+
+	// RBAC authorizes delegated requests; without it delegation is rejected
+	RBAC *auth.RBAC
+
+	// MaxActiveSessions caps simultaneous active sessions per user (0 = unlimited)
+	MaxActiveSessions int
+
+	// DenyRules rejects permission/duration combinations outright; none apply when empty
+	DenyRules []DenyRule
+
+	// TicketPolicies requires reasons to reference a ticket, keyed by environment
+	TicketPolicies map[string]*TicketPolicy
+
+	// RequireProdSlackChannel denies production requests that do not record the Slack channel they came from
+	RequireProdSlackChannel bool
+
+	// RequireVerifiedEmail denies elevated permissions to requesters whose email is not verified
+	RequireVerifiedEmail bool
+
+	// NamespaceApprovers maps namespaces to the approver teams that own them
+	NamespaceApprovers map[string][]string
+
+	// NamespacePrefixes maps teams to the namespace prefixes they may request
+	NamespacePrefixes map[string][]string
+
+	// RequireNamespacesEnvironments are the environments where elevated requests must name namespaces
+	RequireNamespacesEnvironments []string
+
+	// Clusters is the registry of known clusters used to complete requests that only name their cluster
+	// and to enforce per-cluster session caps
+	Clusters map[string]RegisteredCluster
+
+	// ClusterConfigs are the configured clusters, keyed by lowercase name, whose RequiredApprovers
+	// set the default approval quorum, e.g. ClusterConfigs(Clusters)
+	ClusterConfigs map[string]*models.Cluster
+
+	// ClusterGroups are the groups of related clusters a request may target together
+	ClusterGroups controller.ClusterGroups
+
+	// HeldUsers are Slack user IDs whose non-emergency requests are denied while under investigation
+	HeldUsers map[string]bool
+
+	// MaxScheduleAhead limits how far ahead a request's NotBefore may be; 0 uses DefaultMaxScheduleAhead
+	MaxScheduleAhead time.Duration
+
+	// MaxDuration caps the requested access duration; 0 uses DefaultMaxDuration
+	MaxDuration time.Duration
+
+	// ReasonValidator judges request reasons; nil uses DefaultReasonValidator
+	ReasonValidator ReasonValidator
+
+	// KillSwitch, when engaged, denies every new request; nil disables it
+	KillSwitch *controller.KillSwitch
+
+	// RiskWeights scores requests for the risk-score annotation; nil uses DefaultRiskWeights
+	RiskWeights *RiskWeights
+
+	// CanonicalDurations has the mutating webhook store durations in canonical form
+	CanonicalDurations bool
+
+	// SensitiveNamespaces always need platform and security approval, whatever the environment
+	SensitiveNamespaces []string
+
+	// DefaultProductionApprovers are assigned to production requests no approver policy matched
+	DefaultProductionApprovers []string
+
+	// SkipMutationServiceAccounts may apply requests unmutated with the skip-mutation annotation
+	SkipMutationServiceAccounts []string
+
+	// RequesterServiceAccounts file requests for the spec.userID they name, e.g. the Slack bot's
+	RequesterServiceAccounts []string
+
+	// MaxBodyBytes caps admission request bodies; 0 uses DefaultMaxBodyBytes
+	MaxBodyBytes int64
+
+	// ApprovalTiers scale the approval quorum with the requested duration; nil disables them
+	ApprovalTiers []controller.ApprovalTier
+
+	// PermissionPolicies are the requestable permissions, which must match the access manager's;
+	// nil uses aws.DefaultPermissionPolicies
+	PermissionPolicies aws.PermissionPolicies
+
+	// AllowedRegions are the regions the operator serves; nil allows every region
+	AllowedRegions aws.AllowedRegions
+
+	// MaxSessionPolicySize caps the generated AssumeRole session policy, in characters; 0 uses
+	// aws.MaxSessionPolicySize
+	MaxSessionPolicySize int
+}
+
 // SetupWebhookWithManager sets up the webhook server with the manager
-func SetupWebhookWithManager(mgr ctrl.Manager) error {
+func SetupWebhookWithManager(mgr ctrl.Manager, opts Options) error {
 	// Setup webhook server
 	hookServer := mgr.GetWebhookServer()
 
 	// Register validation webhook for JITAccessRequest
 	validator := &JITAccessRequestValidator{
-		Client: mgr.GetClient(),
+		Client:                  mgr.GetClient(),
+		Schedules:               opts.Schedules,
+		RBAC:                    opts.RBAC,
+		MaxActiveSessions:       opts.MaxActiveSessions,
+		DenyRules:               opts.DenyRules,
+		TicketPolicies:          opts.TicketPolicies,
+		RequireProdSlackChannel: opts.RequireProdSlackChannel,
+		RequireVerifiedEmail:    opts.RequireVerifiedEmail,
+		NamespacePrefixes:       opts.NamespacePrefixes,
+		Clusters:                opts.Clusters,
+		ClusterGroups:           opts.ClusterGroups,
+		HeldUsers:               opts.HeldUsers,
+		MaxScheduleAhead:        opts.MaxScheduleAhead,
+		MaxDuration:             opts.MaxDuration,
+		ReasonValidator:         opts.ReasonValidator,
+		KillSwitch:              opts.KillSwitch,
+
+		RequireNamespacesEnvironments: opts.RequireNamespacesEnvironments,
+		RequesterServiceAccounts:      opts.RequesterServiceAccounts,
+		PermissionPolicies:            opts.PermissionPolicies,
+		AllowedRegions:                opts.AllowedRegions,
+		MaxSessionPolicySize:          opts.MaxSessionPolicySize,
 	}
 	hookServer.Register("/validate-jit-rebelops-io-v1alpha1-jitaccessrequest",
-		&webhook.Admission{Handler: validator})
+		limitBody("validating", opts.MaxBodyBytes, &webhook.Admission{Handler: validator}))
 
 	// Register mutation webhook for JITAccessRequest
 	mutator := &JITAccessRequestMutator{
-		Client: mgr.GetClient(),
+		Client:             mgr.GetClient(),
+		NamespaceApprovers: opts.NamespaceApprovers,
+		Clusters:           opts.Clusters,
+		ClusterConfigs:     opts.ClusterConfigs,
+		RiskWeights:        opts.RiskWeights,
+		CanonicalDurations: opts.CanonicalDurations,
+		ApprovalTiers:      opts.ApprovalTiers,
+
+		SensitiveNamespaces:         opts.SensitiveNamespaces,
+		DefaultProductionApprovers:  opts.DefaultProductionApprovers,
+		SkipMutationServiceAccounts: opts.SkipMutationServiceAccounts,
+		RequesterServiceAccounts:    opts.RequesterServiceAccounts,
+		PermissionPolicies:          opts.PermissionPolicies,
 	}
 	hookServer.Register("/mutate-jit-rebelops-io-v1alpha1-jitaccessrequest",
-		&webhook.Admission{Handler: mutator})
+		limitBody("mutating", opts.MaxBodyBytes, &webhook.Admission{Handler: mutator}))
 
 	// Register mutation webhook for JITAccessJob
 	jobMutator := &JITAccessJobMutator{
 		Client: mgr.GetClient(),
 	}
 	hookServer.Register("/mutate-jit-rebelops-io-v1alpha1-jitaccessjob",
-		&webhook.Admission{Handler: jobMutator})
+		limitBody("mutating", opts.MaxBodyBytes, &webhook.Admission{Handler: jobMutator}))
 
 	return nil
 }
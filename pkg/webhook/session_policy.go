@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// SessionPolicy re-runs the permission and cluster checks of the validating webhook against active
+// requests, so sessions granted before a policy was tightened are caught. It implements
+// controller.SessionPolicyChecker.
+type SessionPolicy struct {
+	validator *JITAccessRequestValidator
+}
+
+// NewSessionPolicy checks active sessions against the same deny rules and cluster registry as admission
+func NewSessionPolicy(opts Options) *SessionPolicy {
+	return &SessionPolicy{validator: &JITAccessRequestValidator{
+		DenyRules:          opts.DenyRules,
+		Clusters:           opts.Clusters,
+		PermissionPolicies: opts.PermissionPolicies,
+		AllowedRegions:     opts.AllowedRegions,
+	}}
+}
+
+// CheckActiveSession returns why the current policy would deny the request, or nil if it complies
+func (p *SessionPolicy) CheckActiveSession(_ context.Context, req *controller.JITAccessRequest) error {
+	policies := p.validator.PermissionPolicies
+	if err := validatePermissions(policies, req.Spec.Permissions); err != nil {
+		return fmt.Errorf("invalid permissions: %w", err)
+	}
+	if err := validatePermissionSpecs(policies, req); err != nil {
+		return fmt.Errorf("invalid permission specs: %w", err)
+	}
+
+	req = withPermissionLevels(policies, req)
+	if err := p.validator.validateDenyRules(req); err != nil {
+		return fmt.Errorf("denied by policy: %w", err)
+	}
+	if err := validateCluster(req.Spec.TargetCluster, p.validator.AllowedRegions); err != nil {
+		return fmt.Errorf("invalid cluster configuration: %w", err)
+	}
+	if err := p.validator.validateClusterPermissions(req); err != nil {
+		return fmt.Errorf("denied by cluster policy: %w", err)
+	}
+	return nil
+}
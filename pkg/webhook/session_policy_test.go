@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestSessionPolicyCheckActiveSession(t *testing.T) {
+	activeRequest := func(duration string, permissions ...string) *controller.JITAccessRequest {
+		return &controller.JITAccessRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "active-request", Namespace: "default"},
+			Spec: controller.JITAccessRequestSpec{
+				UserID: "U123456789A",
+				TargetCluster: controller.TargetCluster{
+					Name:       "prod-east-1",
+					AWSAccount: "123456789012",
+					Region:     "us-east-1",
+				},
+				Duration:    duration,
+				Permissions: permissions,
+			},
+			Status: controller.JITAccessRequestStatus{Phase: controller.AccessPhaseActive},
+		}
+	}
+	downgraded := map[string]RegisteredCluster{
+		"prod-east-1": {
+			TargetCluster:        controller.TargetCluster{Name: "prod-east-1"},
+			ForbiddenPermissions: []string{"cluster-admin"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		request *controller.JITAccessRequest
+		wantErr string
+	}{
+		{
+			name:    "cluster downgraded to forbid admin",
+			opts:    Options{Clusters: downgraded},
+			request: activeRequest("2h", "cluster-admin"),
+			wantErr: "cluster-admin is not permitted on cluster prod-east-1",
+		},
+		{
+			name:    "permission still allowed on downgraded cluster",
+			opts:    Options{Clusters: downgraded},
+			request: activeRequest("2h", "view"),
+		},
+		{
+			name: "deny rule tightened",
+			opts: Options{DenyRules: []DenyRule{
+				{Permission: "edit", MaxDuration: "1h", Policy: "edit is limited to 1h"},
+			}},
+			request: activeRequest("4h", "edit"),
+			wantErr: "denied by policy",
+		},
+		{
+			name:    "permission removed",
+			request: activeRequest("2h", "retired-permission"),
+			wantErr: "invalid permissions",
+		},
+		{
+			name:    "compliant session",
+			request: activeRequest("2h", "cluster-admin"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSessionPolicy(tt.opts).CheckActiveSession(t.Context(), tt.request)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
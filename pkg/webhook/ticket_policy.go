@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+// TicketPolicy requires the reason of requests in an environment to reference a ticket
+type TicketPolicy struct {
+	// Pattern is the regular expression a ticket reference must match, e.g. `JIRA-\d+`
+	Pattern string `json:"pattern"`
+	// Example is shown to requesters when no reference is found, e.g. "JIRA-1234"
+	Example string `json:"example,omitempty"`
+}
+
+// LoadTicketPolicies reads ticket reference policies from a JSON file keyed by environment
+// (production, staging, development, qa)
+func LoadTicketPolicies(path string) (map[string]*TicketPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket policies: %w", err)
+	}
+
+	var policies map[string]*TicketPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse ticket policies: %w", err)
+	}
+
+	for env, policy := range policies {
+		if policy == nil || policy.Pattern == "" {
+			return nil, fmt.Errorf("environment %s: pattern is required", env)
+		}
+		if _, err := regexp.Compile(policy.Pattern); err != nil {
+			return nil, fmt.Errorf("environment %s: invalid pattern: %w", env, err)
+		}
+	}
+
+	return policies, nil
+}
+
+func (v *JITAccessRequestValidator) validateTicketReference(req *controller.JITAccessRequest) error {
+	env := v.clusterEnvironment(req)
+	policy, ok := v.TicketPolicies[env]
+	if !ok || policy == nil {
+		return nil
+	}
+
+	pattern, err := regexp.Compile(policy.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid ticket policy for %s: %w", env, err)
+	}
+	if pattern.MatchString(req.Spec.Reason) {
+		return nil
+	}
+
+	if policy.Example != "" {
+		return fmt.Errorf("%s requests must reference a ticket matching %s in the reason (e.g. %q)",
+			env, policy.Pattern, policy.Example)
+	}
+	return fmt.Errorf("%s requests must reference a ticket matching %s in the reason", env, policy.Pattern)
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestValidateTicketReference(t *testing.T) {
+	policies := map[string]*TicketPolicy{
+		envProduction: {Pattern: `JIRA-\d+`, Example: "JIRA-1234"},
+		envStaging:    {Pattern: `#\d+`},
+	}
+
+	tests := []struct {
+		name     string
+		policies map[string]*TicketPolicy
+		cluster  string
+		reason   string
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "production reason with ticket is allowed",
+			policies: policies,
+			cluster:  "prod-east-1",
+			reason:   "Investigating JIRA-4521 payment failures",
+			wantErr:  false,
+		},
+		{
+			name:     "production reason without ticket is denied",
+			policies: policies,
+			cluster:  "prod-east-1",
+			reason:   "Investigating payment failures",
+			wantErr:  true,
+			errMsg:   `e.g. "JIRA-1234"`,
+		},
+		{
+			name:     "staging uses its own pattern",
+			policies: policies,
+			cluster:  "staging-west-2",
+			reason:   "Debugging flaky deploy from #812",
+			wantErr:  false,
+		},
+		{
+			name:     "staging reason without ticket is denied",
+			policies: policies,
+			cluster:  "staging-west-2",
+			reason:   "Debugging flaky deploy from JIRA-812",
+			wantErr:  true,
+			errMsg:   `matching #\d+`,
+		},
+		{
+			name:     "environment without a policy is unaffected",
+			policies: policies,
+			cluster:  "dev-west-2",
+			reason:   "Trying out the new ingress controller",
+			wantErr:  false,
+		},
+		{
+			name:    "no policies configured",
+			cluster: "prod-east-1",
+			reason:  "Investigating payment failures",
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &JITAccessRequestValidator{TicketPolicies: tt.policies}
+			req := &controller.JITAccessRequest{
+				Spec: controller.JITAccessRequestSpec{
+					TargetCluster: controller.TargetCluster{Name: tt.cluster},
+					Reason:        tt.reason,
+				},
+			}
+
+			err := v.validateTicketReference(req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadTicketPolicies(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid,
+		[]byte(`{"production":{"pattern":"(JIRA|OPS)-\\d+","example":"OPS-42"}}`), 0o600))
+
+	policies, err := LoadTicketPolicies(valid)
+	require.NoError(t, err)
+	require.Contains(t, policies, envProduction)
+	assert.Equal(t, `(JIRA|OPS)-\d+`, policies[envProduction].Pattern)
+	assert.Equal(t, "OPS-42", policies[envProduction].Example)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"production":{"pattern":"JIRA-("}}`), 0o600))
+
+	_, err = LoadTicketPolicies(invalid)
+	assert.Error(t, err)
+
+	missing := filepath.Join(dir, "missing.json")
+	require.NoError(t, os.WriteFile(missing, []byte(`{"staging":{"example":"#12"}}`), 0o600))
+
+	_, err = LoadTicketPolicies(missing)
+	assert.Error(t, err)
+}
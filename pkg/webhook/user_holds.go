@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/metrics"
+)
+
+// heldUserViolation is the security violation type recorded when a held user requests access
+const heldUserViolation = "held_user_request"
+
+// errUserHeld is deliberately generic so a denial does not reveal that the user is under investigation
+var errUserHeld = errors.New("this request cannot be processed right now; contact the security team")
+
+// LoadUserHolds reads the Slack user IDs whose access is frozen from a JSON list,
+// e.g. ["U0123456789"]
+func LoadUserHolds(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user holds: %w", err)
+	}
+
+	var users []string
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("failed to parse user holds: %w", err)
+	}
+
+	holds := make(map[string]bool, len(users))
+	for i, user := range users {
+		user = strings.TrimSpace(user)
+		if user == "" {
+			return nil, fmt.Errorf("user hold %d: user ID is required", i)
+		}
+		holds[user] = true
+	}
+
+	return holds, nil
+}
+
+// validateUserHold denies non-emergency requests filed by or for a held user. Emergency requests
+// still go through so an incident is never blocked; they remain audited as break-glass access.
+func (v *JITAccessRequestValidator) validateUserHold(req *controller.JITAccessRequest) error {
+	if len(v.HeldUsers) == 0 || req.Annotations[EmergencyAnnotation] == "true" {
+		return nil
+	}
+
+	users := []string{req.Spec.UserID}
+	if req.Spec.OnBehalfOf != nil && req.Spec.OnBehalfOf.UserID != "" {
+		users = append(users, req.Spec.OnBehalfOf.UserID)
+	}
+
+	for _, user := range users {
+		if v.HeldUsers[user] {
+			metrics.RecordSecurityViolation(heldUserViolation, user, req.Spec.TargetCluster.Name)
+			return errUserHeld
+		}
+	}
+	return nil
+}
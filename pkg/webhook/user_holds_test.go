@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/rebelopsio/jit-bot/pkg/controller"
+)
+
+func TestLoadUserHolds(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "holds.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`["U0HELD0001", " U0HELD0002 "]`), 0o600))
+
+	holds, err := LoadUserHolds(valid)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"U0HELD0001": true, "U0HELD0002": true}, holds)
+
+	blank := filepath.Join(dir, "blank.json")
+	require.NoError(t, os.WriteFile(blank, []byte(`["U0HELD0001", ""]`), 0o600))
+	_, err = LoadUserHolds(blank)
+	assert.Error(t, err)
+}
+
+func TestValidatorUserHolds(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, controller.AddToScheme(scheme))
+	validator := &JITAccessRequestValidator{
+		decoder:   admission.NewDecoder(scheme),
+		HeldUsers: map[string]bool{"U0HELD00001": true},
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		emergency   bool
+		wantAllowed bool
+	}{
+		{name: "normal user", userID: "U123456789A", wantAllowed: true},
+		{name: "held user", userID: "U0HELD00001"},
+		{name: "held user in an emergency", userID: "U0HELD00001", emergency: true, wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &controller.JITAccessRequest{
+				TypeMeta:   metav1.TypeMeta{APIVersion: controller.GroupVersion.String(), Kind: "JITAccessRequest"},
+				ObjectMeta: metav1.ObjectMeta{Name: "held-user-request", Namespace: "jit-system"},
+				Spec: controller.JITAccessRequestSpec{
+					UserID:    tt.userID,
+					UserEmail: "engineer@company.com",
+					TargetCluster: controller.TargetCluster{
+						Name: "dev-east-1", AWSAccount: "123456789012", Region: "us-east-1",
+					},
+					Reason:      "Investigating elevated error rates in checkout",
+					Duration:    "1h",
+					Permissions: []string{"view"},
+				},
+			}
+			if tt.emergency {
+				request.Annotations = map[string]string{EmergencyAnnotation: "true"}
+			}
+			raw, err := json.Marshal(request)
+			require.NoError(t, err)
+
+			resp := validator.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+
+			if tt.wantAllowed {
+				assert.True(t, resp.Allowed, "expected request to be allowed, got: %v", resp.Result)
+				return
+			}
+			require.False(t, resp.Allowed)
+			assert.Equal(t, errUserHeld.Error(), resp.Result.Message)
+			assert.NotContains(t, resp.Result.Message, "investigation")
+		})
+	}
+}
+
+func TestValidateUserHoldCoversDelegates(t *testing.T) {
+	validator := &JITAccessRequestValidator{HeldUsers: map[string]bool{"U0HELD00001": true}}
+
+	req := &controller.JITAccessRequest{Spec: controller.JITAccessRequestSpec{
+		UserID:     "U0MANAGER01",
+		OnBehalfOf: &controller.Delegate{UserID: "U0HELD00001", Email: "held@company.com"},
+	}}
+	assert.ErrorIs(t, validator.validateUserHold(req), errUserHeld)
+
+	req.Spec.OnBehalfOf = &controller.Delegate{UserID: "U0OTHER0001", Email: "other@company.com"}
+	assert.NoError(t, validator.validateUserHold(req))
+}
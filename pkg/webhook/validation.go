@@ -5,20 +5,88 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/rebelopsio/jit-bot/pkg/auth"
+	"github.com/rebelopsio/jit-bot/pkg/aws"
 	"github.com/rebelopsio/jit-bot/pkg/controller"
+	"github.com/rebelopsio/jit-bot/pkg/models"
 )
 
 // JITAccessRequestValidator validates JITAccessRequest resources
 type JITAccessRequestValidator struct {
-	Client  client.Client
+	Client client.Client
+
+	// Schedules restricts non-emergency requests to business hours, keyed by cluster name
+	Schedules map[string]*models.AccessSchedule
+
+	// RBAC authorizes requests filed on behalf of another user
+	RBAC *auth.RBAC
+
+	// MaxActiveSessions caps simultaneous active sessions per user (0 = unlimited)
+	MaxActiveSessions int
+
+	// DenyRules rejects permission/duration combinations outright; none apply when empty
+	DenyRules []DenyRule
+
+	// TicketPolicies requires reasons to reference a ticket, keyed by environment
+	TicketPolicies map[string]*TicketPolicy
+
+	// RequireProdSlackChannel denies production requests that do not record the Slack channel they came from
+	RequireProdSlackChannel bool
+
+	// RequireVerifiedEmail denies elevated permissions to requesters whose email is not verified
+	RequireVerifiedEmail bool
+
+	// NamespacePrefixes maps teams to the namespace prefixes they may request
+	NamespacePrefixes map[string][]string
+
+	// RequireNamespacesEnvironments are the environments where elevated requests must name namespaces
+	RequireNamespacesEnvironments []string
+
+	// Clusters is the registry of known clusters, whose per-cluster session caps are enforced
+	Clusters map[string]RegisteredCluster
+
+	// ClusterGroups are the groups of related clusters a request may target together
+	ClusterGroups controller.ClusterGroups
+
+	// HeldUsers are Slack user IDs whose non-emergency requests are denied while under investigation
+	HeldUsers map[string]bool
+
+	// MaxScheduleAhead limits how far ahead NotBefore may be; 0 uses DefaultMaxScheduleAhead
+	MaxScheduleAhead time.Duration
+
+	// MaxDuration caps the requested access duration; 0 uses DefaultMaxDuration
+	MaxDuration time.Duration
+
+	// ReasonValidator judges request reasons; nil uses DefaultReasonValidator
+	ReasonValidator ReasonValidator
+
+	// KillSwitch, when engaged, denies every new request; nil disables it
+	KillSwitch *controller.KillSwitch
+
+	// RequesterServiceAccounts are the only callers allowed to mark requests requester-verified
+	RequesterServiceAccounts []string
+
+	// PermissionPolicies are the requestable permissions; nil uses aws.DefaultPermissionPolicies
+	PermissionPolicies aws.PermissionPolicies
+
+	// AllowedRegions are the regions target clusters may be in; nil allows every region
+	AllowedRegions aws.AllowedRegions
+
+	// MaxSessionPolicySize caps the generated AssumeRole session policy, in characters; 0 uses
+	// aws.MaxSessionPolicySize
+	MaxSessionPolicySize int
+
 	decoder admission.Decoder
+	now     func() time.Time
 }
 
 // Handle validates JITAccessRequest resources
@@ -29,51 +97,160 @@ func (v *JITAccessRequestValidator) Handle(ctx context.Context, req admission.Re
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
+	// Nothing new is admitted while the kill switch is engaged
+	if req.Operation == admissionv1.Create {
+		if validationErr := v.validateKillSwitch(ctx, accessReq); validationErr != nil {
+			return deny("kill_switch", "spec", "kill switch engaged", validationErr)
+		}
+	}
+
 	// Validate user ID format
 	if validationErr := validateUserID(accessReq.Spec.UserID); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid user ID format: %v", validationErr))
+		return deny("user_id", "spec.userID", "invalid user ID format", validationErr)
+	}
+
+	// Only requester service accounts vouch for spec.userID
+	if validationErr := v.validateRequesterVerified(req, accessReq); validationErr != nil {
+		return deny("requester_verified", "metadata.annotations", "unverified requester", validationErr)
 	}
 
 	// Validate email format
 	if validationErr := validateEmail(accessReq.Spec.UserEmail); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid email format: %v", validationErr))
+		return deny("email", "spec.userEmail", "invalid email format", validationErr)
+	}
+
+	// Held users get a generic denial that does not reveal the hold
+	if validationErr := v.validateUserHold(accessReq); validationErr != nil {
+		return deny("user_hold", "spec.userID", "", validationErr)
+	}
+
+	// Validate delegation if requesting on behalf of another user
+	if validationErr := v.validateDelegation(accessReq); validationErr != nil {
+		return deny("delegation", "spec.onBehalfOf", "invalid delegation", validationErr)
 	}
 
 	// Validate duration format
-	if validationErr := validateDuration(accessReq.Spec.Duration); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid duration: %v", validationErr))
+	if validationErr := validateDuration(accessReq.Spec.Duration, v.maxDuration()); validationErr != nil {
+		return deny("duration", "spec.duration", "invalid duration", validationErr)
 	}
 
 	// Validate permissions
-	if validationErr := validatePermissions(accessReq.Spec.Permissions); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid permissions: %v", validationErr))
+	if validationErr := validatePermissions(v.PermissionPolicies, accessReq.Spec.Permissions); validationErr != nil {
+		return deny("permissions", "spec.permissions", "invalid permissions", validationErr)
+	}
+
+	// Structured permissions are held to the same rules as the string form
+	if validationErr := validatePermissionSpecs(v.PermissionPolicies, accessReq); validationErr != nil {
+		return deny("permission_specs", "spec.permissionSpecs", "invalid permission specs", validationErr)
+	}
+
+	// The remaining checks judge custom permissions by the built-in permission they grant
+	accessReq = withPermissionLevels(v.PermissionPolicies, accessReq)
+
+	// Reject permission/duration combinations forbidden by policy
+	if validationErr := v.validateDenyRules(accessReq); validationErr != nil {
+		return deny("deny_rule", "spec.permissions", "denied by policy", validationErr)
 	}
 
 	// Validate cluster configuration
-	if validationErr := validateCluster(accessReq.Spec.TargetCluster); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid cluster configuration: %v", validationErr))
+	if validationErr := validateCluster(accessReq.Spec.TargetCluster, v.AllowedRegions); validationErr != nil {
+		return deny("cluster", "spec.targetCluster", "invalid cluster configuration", validationErr)
+	}
+
+	// Some clusters forbid permissions, such as cluster-admin, outright
+	if validationErr := v.validateClusterPermissions(accessReq); validationErr != nil {
+		return deny("cluster_permission", "spec.permissions", "denied by cluster policy", validationErr)
+	}
+
+	// Approval policy is evaluated for the target cluster, so it must stand for the whole group
+	if validationErr := v.validateClusterGroup(accessReq); validationErr != nil {
+		return deny("cluster_group", "spec.clusterGroup", "invalid cluster group", validationErr)
+	}
+
+	// Fail fast on a session policy AssumeRole would reject for its size
+	if validationErr := v.validateSessionPolicySize(accessReq); validationErr != nil {
+		return deny("session_policy_size", "spec.permissions", "session policy too large", validationErr)
+	}
+
+	// Enforce the per-user active session cap on new requests
+	if req.Operation == admissionv1.Create {
+		if validationErr := v.validateActiveSessions(ctx, accessReq); validationErr != nil {
+			return deny("user_session_limit", "spec.userID", "too many active sessions", validationErr)
+		}
+	}
+
+	// Enforce the per-cluster session cap on new requests and on requests moved to another cluster
+	if req.Operation == admissionv1.Create || v.targetClusterChanged(req, accessReq) {
+		if validationErr := v.validateClusterSessions(ctx, accessReq); validationErr != nil {
+			return deny("cluster_session_limit", "spec.targetCluster", "too many active sessions", validationErr)
+		}
+	}
+
+	// Rescheduling is held to the same limit as scheduling
+	if validationErr := v.validateNotBefore(accessReq); validationErr != nil {
+		return deny("not_before", "spec.notBefore", "invalid start time", validationErr)
+	}
+
+	// Deny non-emergency requests outside the cluster's access schedule
+	if validationErr := v.validateSchedule(accessReq); validationErr != nil {
+		return deny("schedule", "spec.targetCluster", "outside access window", validationErr)
+	}
+
+	// Validate reason is meaningful and sufficient for the requested permissions
+	reasonCtx := WithReasonEnvironment(ctx, v.clusterEnvironment(accessReq))
+	if validationErr := v.reasonValidator().ValidateReason(
+		reasonCtx, accessReq.Spec.Reason, accessReq.Spec.Permissions,
+	); validationErr != nil {
+		return deny("reason", "spec.reason", "invalid reason", validationErr)
 	}
 
-	// Validate reason is provided and meaningful
-	if validationErr := validateReason(accessReq.Spec.Reason); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid reason: %v", validationErr))
+	// Require a ticket reference in the reason where the environment's policy demands one
+	if validationErr := v.validateTicketReference(accessReq); validationErr != nil {
+		return deny("ticket_reference", "spec.reason", "missing ticket reference", validationErr)
 	}
 
-	// Validate reason is sufficient for elevated permissions
-	if validationErr := validateReasonForPermissions(accessReq.Spec.Reason, accessReq.Spec.Permissions); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid reason: %v", validationErr))
+	// Production requests must come from an auditable Slack channel when the policy is enabled
+	if validationErr := v.validateSlackChannel(accessReq); validationErr != nil {
+		return deny("slack_channel", "spec.slackChannel", "missing slack channel", validationErr)
+	}
+
+	// Elevated access is only granted to a verified identity when the policy is enabled
+	if validationErr := v.validateVerifiedEmail(accessReq); validationErr != nil {
+		return deny("unverified_email", "spec.userEmailVerified", "unverified email", validationErr)
 	}
 
 	// Validate approvers if specified
 	if validationErr := validateApprovers(accessReq.Spec.Approvers); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid approvers: %v", validationErr))
+		return deny("approvers", "spec.approvers", "invalid approvers", validationErr)
+	}
+
+	// Validate cost-allocation tags against AWS tag constraints
+	if validationErr := validateCostTags(accessReq.Spec.CostCenter, accessReq.Spec.Team); validationErr != nil {
+		return deny("cost_tags", "spec", "invalid cost-allocation tags", validationErr)
 	}
 
 	// Check if namespaces are valid when specified
 	if validationErr := validateNamespaces(accessReq.Spec.Namespaces, accessReq.Spec.Permissions); validationErr != nil {
-		return admission.Denied(fmt.Sprintf("invalid namespaces: %v", validationErr))
+		return deny("namespaces", "spec.namespaces", "invalid namespaces", validationErr)
+	}
+
+	// A namespace prefix must be within the team's allowlist
+	if validationErr := v.validateNamespacePrefix(accessReq); validationErr != nil {
+		return deny("namespace_prefix", "spec.namespacePrefix", "invalid namespace prefix", validationErr)
+	}
+
+	// Elevated access must be scoped to namespaces where the environment's policy demands it
+	if validationErr := v.validateNamespaceScoping(accessReq); validationErr != nil {
+		return deny("namespace_scoping", "spec.namespaces", "namespaces required", validationErr)
+	}
+
+	// Validate resource scope if specified
+	scope := accessReq.Spec.ResourceScope
+	if validationErr := validateResourceScope(scope, accessReq.Spec.Permissions); validationErr != nil {
+		return deny("resource_scope", "spec.resourceScope", "invalid resource scope", validationErr)
 	}
 
+	v.recordRiskScore(req, accessReq)
 	return admission.Allowed("")
 }
 
@@ -83,18 +260,27 @@ func (v *JITAccessRequestValidator) InjectDecoder(d admission.Decoder) error {
 	return nil
 }
 
+// DefaultMaxDuration is the longest access a request may ask for when no limit is configured
+const DefaultMaxDuration = controller.Week
+
+func (v *JITAccessRequestValidator) maxDuration() time.Duration {
+	if v.MaxDuration > 0 {
+		return v.MaxDuration
+	}
+	return DefaultMaxDuration
+}
+
 // Validation helper functions
 
-func validateDuration(duration string) error {
+func validateDuration(duration string, maxDuration time.Duration) error {
 	// Parse duration to ensure it's valid
 	parsedDuration, err := parseDuration(duration)
 	if err != nil {
-		return fmt.Errorf("invalid duration format - duration must be in format like '1h', '30m', '2h30m', '1d'")
+		return fmt.Errorf("invalid duration format - duration must be in format like '1h', '30m', '2h30m', '1d', '2w'")
 	}
 
 	// Check duration limits
 	minDuration := 15 * time.Minute
-	maxDuration := 7 * 24 * time.Hour // 7 days
 
 	if parsedDuration < minDuration {
 		return fmt.Errorf("duration must be at least %v", minDuration)
@@ -107,85 +293,54 @@ func validateDuration(duration string) error {
 	return nil
 }
 
+// parseDuration parses request durations, which also accept days and weeks, see controller.ParseDuration
 func parseDuration(duration string) (time.Duration, error) {
-	if duration == "" {
-		return 0, fmt.Errorf("duration cannot be empty")
-	}
-
-	// Parse duration components using regexp
-	re := regexp.MustCompile(`(\d+)([dhms])`)
-	matches := re.FindAllStringSubmatch(duration, -1)
-
-	if len(matches) == 0 {
-		return 0, fmt.Errorf("invalid duration format: %s", duration)
-	}
-
-	var total time.Duration
-	for _, match := range matches {
-		value := 0
-		if _, err := fmt.Sscanf(match[1], "%d", &value); err != nil {
-			return 0, fmt.Errorf("invalid duration value: %s", match[1])
-		}
-		unit := match[2]
-
-		switch unit {
-		case "d":
-			total += time.Duration(value) * 24 * time.Hour
-		case "h":
-			total += time.Duration(value) * time.Hour
-		case "m":
-			total += time.Duration(value) * time.Minute
-		case "s":
-			total += time.Duration(value) * time.Second
-		default:
-			return 0, fmt.Errorf("invalid duration unit: %s", unit)
-		}
-	}
-
-	// Verify that the parsed string matches the original (no invalid parts)
-	rebuiltString := ""
-	for _, match := range matches {
-		rebuiltString += match[0]
-	}
-	if rebuiltString != duration {
-		return 0, fmt.Errorf("invalid duration format: %s", duration)
-	}
-
-	return total, nil
+	return controller.ParseDuration(duration)
 }
 
-func validatePermissions(permissions []string) error {
+// validatePermissions checks permissions against the set shared with access entry creation
+func validatePermissions(policies aws.PermissionPolicies, permissions []string) error {
 	if len(permissions) == 0 {
 		return fmt.Errorf("at least one permission must be specified")
 	}
 
-	validPermissions := map[string]bool{
-		"view":          true,
-		"edit":          true,
-		"admin":         true,
-		"cluster-admin": true,
-		"debug":         true,
-		"logs":          true,
-		"exec":          true,
-		"port-forward":  true,
-	}
-
 	for _, perm := range permissions {
-		if !validPermissions[perm] {
+		if _, ok := policies.Lookup(perm); !ok {
 			return fmt.Errorf("invalid permission '%s'. Valid permissions are: %v",
-				perm, getKeys(validPermissions))
+				perm, policies.Names())
 		}
 	}
 
 	// Check for permission escalation
-	if contains(permissions, "cluster-admin") && len(permissions) > 1 {
+	if contains(permissionLevels(policies, permissions), "cluster-admin") && len(permissions) > 1 {
 		return fmt.Errorf("cluster-admin permission cannot be combined with other permissions")
 	}
 
 	return nil
 }
 
-func validateCluster(cluster controller.TargetCluster) error {
+// permissionLevels adds the built-in permission each requested permission grants, see
+// aws.PermissionPolicies.Level, so checks keyed on built-in names also hold for custom permissions
+func permissionLevels(policies aws.PermissionPolicies, permissions []string) []string {
+	levels := slices.Clone(permissions)
+	for _, perm := range permissions {
+		if level := policies.Level(perm); !contains(levels, level) {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// withPermissionLevels returns a copy of the request whose permissions include their levels
+func withPermissionLevels(
+	policies aws.PermissionPolicies, req *controller.JITAccessRequest,
+) *controller.JITAccessRequest {
+	leveled := req.DeepCopy()
+	leveled.Spec.Permissions = permissionLevels(policies, req.Spec.Permissions)
+	return leveled
+}
+
+func validateCluster(cluster controller.TargetCluster, allowedRegions aws.AllowedRegions) error {
 	if cluster.Name == "" {
 		return fmt.Errorf("cluster name is required")
 	}
@@ -210,20 +365,24 @@ func validateCluster(cluster controller.TargetCluster) error {
 		return fmt.Errorf("invalid AWS region format")
 	}
 
+	if err := allowedRegions.Check(cluster.Region); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func validateReason(reason string) error {
+func validateReason(reason string, maxLength int) error {
 	if strings.TrimSpace(reason) == "" {
-		return fmt.Errorf("reason cannot be empty")
+		return ruleErrorf("reason_empty", "reason cannot be empty")
 	}
 
-	if len(reason) < 10 {
-		return fmt.Errorf("reason must be at least 10 characters long")
+	if len(reason) < minReasonLength {
+		return ruleErrorf("reason_too_short", "reason must be at least %d characters long", minReasonLength)
 	}
 
-	if len(reason) > 500 {
-		return fmt.Errorf("reason cannot exceed 500 characters")
+	if len(reason) > maxLength {
+		return ruleErrorf("reason_too_long", "reason cannot exceed %d characters", maxLength)
 	}
 
 	// Check for generic/placeholder reasons
@@ -243,10 +402,13 @@ func validateReason(reason string) error {
 	lowerReason := strings.ToLower(reason)
 	for _, generic := range genericReasons {
 		if lowerReason == generic {
-			return fmt.Errorf("please provide a meaningful business reason for access")
+			return ruleErrorf("reason_placeholder", "please provide a meaningful business reason for access")
 		}
 	}
 
+	if err := validateReasonSecrets(reason); err != nil {
+		return &ruleError{rule: "reason_secret", err: err}
+	}
 	return nil
 }
 
@@ -275,6 +437,17 @@ func validateApprovers(approvers []string) error {
 	return nil
 }
 
+// validateCostTags checks the values that become AWS tags on the granted session and access entry
+func validateCostTags(costCenter, team string) error {
+	if err := aws.ValidateTagValue(costCenter); err != nil {
+		return fmt.Errorf("cost center %w", err)
+	}
+	if err := aws.ValidateTagValue(team); err != nil {
+		return fmt.Errorf("team %w", err)
+	}
+	return nil
+}
+
 func validateNamespaces(namespaces []string, permissions []string) error {
 	// If cluster-admin permission, namespaces should be empty
 	if contains(permissions, "cluster-admin") && len(namespaces) > 0 {
@@ -301,6 +474,255 @@ func validateNamespaces(namespaces []string, permissions []string) error {
 	return nil
 }
 
+func validateResourceScope(scope *controller.ResourceScope, permissions []string) error {
+	// No scope means the managed policies for the requested permissions apply
+	if scope == nil {
+		return nil
+	}
+
+	if contains(permissions, "cluster-admin") {
+		return fmt.Errorf("resource scope cannot be combined with cluster-admin permission")
+	}
+
+	if len(scope.Resources) == 0 {
+		return fmt.Errorf("at least one resource must be specified")
+	}
+
+	if len(scope.Verbs) == 0 {
+		return fmt.Errorf("at least one verb must be specified")
+	}
+
+	apiGroupRegex := regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?)(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	for _, group := range scope.APIGroups {
+		if group == "*" {
+			return fmt.Errorf("wildcard API groups are not allowed")
+		}
+		// The empty string addresses the core API group
+		if group != "" && !apiGroupRegex.MatchString(group) {
+			return fmt.Errorf("invalid API group: %s", group)
+		}
+	}
+
+	resourceRegex := regexp.MustCompile(`^[a-z][a-z0-9]*(/[a-z][a-z0-9]*)?$`)
+	for _, resource := range scope.Resources {
+		if resource == "*" {
+			return fmt.Errorf("wildcard resources are not allowed")
+		}
+		if !resourceRegex.MatchString(resource) {
+			return fmt.Errorf("invalid resource: %s", resource)
+		}
+	}
+
+	validVerbs := map[string]bool{
+		"get":              true,
+		"list":             true,
+		"watch":            true,
+		"create":           true,
+		"update":           true,
+		"patch":            true,
+		"delete":           true,
+		"deletecollection": true,
+	}
+
+	for _, verb := range scope.Verbs {
+		if !validVerbs[verb] {
+			return fmt.Errorf("invalid verb '%s'. Valid verbs are: %v", verb, getKeys(validVerbs))
+		}
+	}
+
+	return validateNamespaces(scope.Namespaces, permissions)
+}
+
+func (v *JITAccessRequestValidator) validateActiveSessions(
+	ctx context.Context, req *controller.JITAccessRequest,
+) error {
+	if v.MaxActiveSessions <= 0 || v.Client == nil {
+		return nil
+	}
+
+	var requests controller.JITAccessRequestList
+	if err := v.Client.List(ctx, &requests); err != nil {
+		return fmt.Errorf("failed to count active sessions: %w", err)
+	}
+
+	// Sessions are counted against whoever they grant access to, not whoever filed them
+	active := 0
+	for _, existing := range requests.Items {
+		if existing.Status.Phase == controller.AccessPhaseActive && sameGrantee(&existing, req) {
+			active++
+		}
+	}
+
+	if active >= v.MaxActiveSessions {
+		userID, email := grantee(req)
+		if userID == "" {
+			userID = email
+		}
+		return fmt.Errorf("user %s already has %d active sessions (maximum %d)",
+			userID, active, v.MaxActiveSessions)
+	}
+
+	return nil
+}
+
+// validateClusterSessions enforces the target cluster's cap on simultaneous sessions across all
+// users, protecting the cluster from too many elevated sessions at once. Pending and approved
+// requests hold a slot too, so a burst of requests cannot all be granted past the cap.
+func (v *JITAccessRequestValidator) validateClusterSessions(
+	ctx context.Context, req *controller.JITAccessRequest,
+) error {
+	cluster, ok := v.Clusters[strings.ToLower(req.Spec.TargetCluster.Name)]
+	if !ok || cluster.MaxActiveSessions <= 0 || v.Client == nil {
+		return nil
+	}
+
+	var requests controller.JITAccessRequestList
+	if err := v.Client.List(ctx, &requests); err != nil {
+		return fmt.Errorf("failed to count active sessions: %w", err)
+	}
+
+	open := 0
+	for _, existing := range requests.Items {
+		if existing.Namespace == req.Namespace && existing.Name == req.Name {
+			continue
+		}
+		if holdsSession(existing.Status.Phase) &&
+			strings.EqualFold(existing.Spec.TargetCluster.Name, req.Spec.TargetCluster.Name) {
+			open++
+		}
+	}
+
+	if open >= cluster.MaxActiveSessions {
+		return fmt.Errorf("cluster %s already has %d pending or active sessions (maximum %d); try again later",
+			req.Spec.TargetCluster.Name, open, cluster.MaxActiveSessions)
+	}
+
+	return nil
+}
+
+// grantee returns the Slack user ID and email of the user a request grants access to
+func grantee(req *controller.JITAccessRequest) (string, string) {
+	if req.Spec.OnBehalfOf != nil {
+		return req.Spec.OnBehalfOf.UserID, req.Spec.OnBehalfOf.Email
+	}
+	return req.Spec.UserID, req.Spec.UserEmail
+}
+
+// sameGrantee reports whether two requests grant access to the same user, by Slack user ID where
+// both have one and by email otherwise
+func sameGrantee(a, b *controller.JITAccessRequest) bool {
+	aUserID, aEmail := grantee(a)
+	bUserID, bEmail := grantee(b)
+	if aUserID != "" && bUserID != "" {
+		return aUserID == bUserID
+	}
+	return aEmail != "" && strings.EqualFold(aEmail, bEmail)
+}
+
+// holdsSession reports whether a request in the phase has, or is on its way to, an active session
+func holdsSession(phase controller.AccessPhase) bool {
+	switch phase {
+	case "", controller.AccessPhasePending, controller.AccessPhaseApproved, controller.AccessPhaseActive:
+		return true
+	}
+	return false
+}
+
+// targetClusterChanged reports whether an update moves the request to another cluster
+func (v *JITAccessRequestValidator) targetClusterChanged(
+	req admission.Request, accessReq *controller.JITAccessRequest,
+) bool {
+	if req.Operation != admissionv1.Update {
+		return false
+	}
+	old := &controller.JITAccessRequest{}
+	if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return true
+	}
+	return !strings.EqualFold(old.Spec.TargetCluster.Name, accessReq.Spec.TargetCluster.Name)
+}
+
+func (v *JITAccessRequestValidator) validateDelegation(req *controller.JITAccessRequest) error {
+	delegate := req.Spec.OnBehalfOf
+	if delegate == nil {
+		return nil
+	}
+
+	if v.RBAC == nil || !v.RBAC.UserHasPermission(req.Spec.UserID, auth.PermissionRequestOnBehalf) {
+		return fmt.Errorf("user %s is not allowed to request access on behalf of others", req.Spec.UserID)
+	}
+
+	if err := validateEmail(delegate.Email); err != nil {
+		return fmt.Errorf("invalid grantee email: %w", err)
+	}
+
+	if delegate.UserID != "" {
+		if err := validateUserID(delegate.UserID); err != nil {
+			return fmt.Errorf("invalid grantee user ID: %w", err)
+		}
+	}
+
+	if delegate.UserID == req.Spec.UserID || strings.EqualFold(delegate.Email, req.Spec.UserEmail) {
+		return fmt.Errorf("grantee must be a different user than the requester")
+	}
+
+	return nil
+}
+
+func (v *JITAccessRequestValidator) validateSlackChannel(req *controller.JITAccessRequest) error {
+	if !v.RequireProdSlackChannel || req.Spec.SlackChannel != "" {
+		return nil
+	}
+
+	if v.clusterEnvironment(req) != envProduction {
+		return nil
+	}
+
+	return fmt.Errorf("production requests must be made from a Slack channel so they can be audited")
+}
+
+// validateClusterGroup checks a group request names a configured group that contains the target
+// cluster, and that every member is in the target's environment so its approvals cover them all
+func (v *JITAccessRequestValidator) validateClusterGroup(req *controller.JITAccessRequest) error {
+	group := req.Spec.ClusterGroup
+	if group == "" {
+		return nil
+	}
+
+	members, ok := v.ClusterGroups[group]
+	if !ok {
+		return fmt.Errorf("cluster group %s is not configured", group)
+	}
+	if !v.ClusterGroups.HasMember(group, req.Spec.TargetCluster.Name) {
+		return fmt.Errorf("target cluster %s is not a member of cluster group %s", req.Spec.TargetCluster.Name, group)
+	}
+
+	environment := v.clusterEnvironment(req)
+	for _, member := range members {
+		if memberEnv := registryEnvironment(v.Clusters, member.Name); memberEnv != environment {
+			return fmt.Errorf("cluster group %s mixes %s cluster %s with %s target cluster %s",
+				group, memberEnv, member.Name, environment, req.Spec.TargetCluster.Name)
+		}
+	}
+	return nil
+}
+
+// validateVerifiedEmail denies elevated permissions unless the requester's email has been verified,
+// since the email identifies the user on the granted session and in audit logs
+func (v *JITAccessRequestValidator) validateVerifiedEmail(req *controller.JITAccessRequest) error {
+	if !v.RequireVerifiedEmail || !hasElevatedPermissions(req.Spec.Permissions) {
+		return nil
+	}
+
+	if req.Spec.UserEmail == "" {
+		return fmt.Errorf("elevated permissions require a verified email, but the request has none")
+	}
+	if !req.Spec.UserEmailVerified {
+		return fmt.Errorf("elevated permissions require a verified email; %s is not verified", req.Spec.UserEmail)
+	}
+	return nil
+}
+
 // Helper functions
 
 func isValidApprover(approver string) bool {
@@ -380,18 +802,21 @@ func validateReasonForPermissions(reason string, permissions []string) error {
 		// Check for generic phrases first for cluster-admin
 		for _, phrase := range genericPhrases {
 			if strings.Contains(lowerReason, phrase) {
-				return fmt.Errorf("cluster-admin permission requires detailed justification")
+				return ruleErrorf("reason_insufficient_justification",
+					"cluster-admin permission requires detailed justification")
 			}
 		}
 
 		if len(reason) < 50 {
-			return fmt.Errorf("cluster-admin permission requires detailed justification (at least 50 characters)")
+			return ruleErrorf("reason_insufficient_justification",
+				"cluster-admin permission requires detailed justification (at least 50 characters)")
 		}
 	} else {
 		// Check for generic reasons for any permission
 		for _, phrase := range genericPhrases {
 			if strings.Contains(lowerReason, phrase) {
-				return fmt.Errorf("reason appears generic - please provide specific business justification")
+				return ruleErrorf("reason_generic",
+					"reason appears generic - please provide specific business justification")
 			}
 		}
 	}
@@ -413,3 +838,14 @@ func (v *JITAccessRequestValidator) ValidateDelete(ctx context.Context, obj runt
 
 	return nil
 }
+
+// validateSessionPolicySize generates the session policy the access manager will send and checks it
+// against the configured limit. Namespaces are scoped on the EKS access entry rather than in the
+// policy, so only the cluster name and permissions count towards it.
+func (v *JITAccessRequestValidator) validateSessionPolicySize(req *controller.JITAccessRequest) error {
+	policy, err := aws.CreateJITPolicy(req.Spec.TargetCluster.Name, "", req.Spec.Permissions)
+	if err != nil {
+		return err
+	}
+	return aws.CheckSessionPolicySize(policy, v.MaxSessionPolicySize)
+}